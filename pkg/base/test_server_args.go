@@ -125,8 +125,8 @@ const (
 	// production default zone config. Replication is performed as in
 	// production, by the replication queue.
 	ReplicationAuto TestClusterReplicationMode = iota
-	// ReplicationManual means that the split and replication queues of all
-	// servers are stopped, and the test must manually control splitting and
-	// replication through the TestServer.
+	// ReplicationManual means that the split, merge and replication queues of
+	// all servers are stopped, and the test must manually control splitting,
+	// merging and replication through the TestServer.
 	ReplicationManual
 )
@@ -11,6 +11,7 @@ package sqlccl
 import (
 	"bytes"
 	"io/ioutil"
+	"os"
 	"sort"
 
 	"github.com/pkg/errors"
@@ -29,6 +30,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -40,10 +43,18 @@ const (
 	// BackupDescriptorName is the file name used for serialized
 	// BackupDescriptor protos.
 	BackupDescriptorName = "BACKUP"
+	// BackupEncryptionInfoName is the file name used for the serialized
+	// EncryptionInfo proto of encrypted backups.
+	BackupEncryptionInfoName = "ENCRYPTION-INFO"
 	// BackupFormatInitialVersion is the first version of backup and its files.
 	BackupFormatInitialVersion uint32 = 0
 )
 
+const (
+	backupOptRevisionHistory = "revision_history"
+	backupOptEncPassphrase   = "encryption_passphrase"
+)
+
 // exportStorageFromURI returns an ExportStorage for the given URI.
 func exportStorageFromURI(ctx context.Context, uri string) (storageccl.ExportStorage, error) {
 	conf, err := storageccl.ExportStorageConfFromURI(uri)
@@ -54,7 +65,10 @@ func exportStorageFromURI(ctx context.Context, uri string) (storageccl.ExportSto
 }
 
 // readBackupDescriptor reads and unmarshals a BackupDescriptor from given base.
-func readBackupDescriptor(ctx context.Context, uri string) (BackupDescriptor, error) {
+// The descriptor is decrypted with encryptionKey if it is not nil.
+func readBackupDescriptor(
+	ctx context.Context, uri string, encryptionKey []byte,
+) (BackupDescriptor, error) {
 	dir, err := exportStorageFromURI(ctx, uri)
 	if err != nil {
 		return BackupDescriptor{}, err
@@ -70,6 +84,14 @@ func readBackupDescriptor(ctx context.Context, uri string) (BackupDescriptor, er
 	if err != nil {
 		return BackupDescriptor{}, err
 	}
+	if encryptionKey != nil {
+		if descBytes, err = storageccl.DecryptFile(descBytes, encryptionKey); err != nil {
+			return BackupDescriptor{}, err
+		}
+	} else if storageccl.AppearsEncrypted(descBytes) {
+		return BackupDescriptor{}, errors.Errorf(
+			"file appears encrypted -- try specifying the %q option", backupOptEncPassphrase)
+	}
 	var backupDesc BackupDescriptor
 	if err := backupDesc.Unmarshal(descBytes); err != nil {
 		return BackupDescriptor{}, err
@@ -80,16 +102,112 @@ func readBackupDescriptor(ctx context.Context, uri string) (BackupDescriptor, er
 	return backupDesc, nil
 }
 
+// readEncryptionInfo reads and unmarshals the EncryptionInfo of the encrypted
+// backup in the given base.
+func readEncryptionInfo(ctx context.Context, uri string) (EncryptionInfo, error) {
+	dir, err := exportStorageFromURI(ctx, uri)
+	if err != nil {
+		return EncryptionInfo{}, err
+	}
+	defer dir.Close()
+	r, err := dir.ReadFile(ctx, BackupEncryptionInfoName)
+	if err != nil {
+		return EncryptionInfo{}, errors.Wrapf(err, "reading %s; %s may not be an encrypted backup",
+			BackupEncryptionInfoName, uri)
+	}
+	defer r.Close()
+	infoBytes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return EncryptionInfo{}, err
+	}
+	var info EncryptionInfo
+	if err := info.Unmarshal(infoBytes); err != nil {
+		return EncryptionInfo{}, err
+	}
+	return info, nil
+}
+
+// backupEncryptionKey derives the key of an encrypted backup from the
+// passphrase in opt. The salt is read from the existing backup in uri or, if
+// uri is empty, generated for a new one. A nil key is returned if no
+// passphrase was given.
+func backupEncryptionKey(
+	ctx context.Context, opt parser.KVOptions, uri string,
+) ([]byte, *EncryptionInfo, error) {
+	passphrase, ok := opt.Get(backupOptEncPassphrase)
+	if !ok {
+		return nil, nil, nil
+	}
+	if passphrase == "" {
+		return nil, nil, errors.Errorf("option %q requires a value", backupOptEncPassphrase)
+	}
+	var info EncryptionInfo
+	if uri == "" {
+		salt, err := storageccl.GenerateSalt()
+		if err != nil {
+			return nil, nil, err
+		}
+		info.Salt = salt
+	} else {
+		var err error
+		if info, err = readEncryptionInfo(ctx, uri); err != nil {
+			return nil, nil, err
+		}
+	}
+	return storageccl.GenerateKey([]byte(passphrase), info.Salt), &info, nil
+}
+
+// resumeEncryptionKey derives the key of a job being resumed that reads or
+// writes the backup in uri. The key is not stored with the job, so the
+// passphrase of an encrypted job must be given again in the options of RESUME
+// JOB, which accepts no other option. A nil key is returned if the job is not
+// encrypted.
+func resumeEncryptionKey(
+	ctx context.Context, jobID int64, encrypted bool, opt parser.KVOptions, uri string,
+) ([]byte, error) {
+	for _, o := range opt {
+		if o.Key != backupOptEncPassphrase {
+			return nil, errors.Errorf("invalid option %q", o.Key)
+		}
+	}
+	if _, ok := opt.Get(backupOptEncPassphrase); ok != encrypted {
+		if encrypted {
+			return nil, errors.Errorf("job %d is encrypted: resuming it requires the %q option",
+				jobID, backupOptEncPassphrase)
+		}
+		return nil, errors.Errorf("job %d is not encrypted", jobID)
+	}
+	encryptionKey, _, err := backupEncryptionKey(ctx, opt, uri)
+	return encryptionKey, err
+}
+
+// redactedOptions returns a copy of opt, for use in job descriptions, with the
+// encryption passphrase hidden.
+func redactedOptions(opt parser.KVOptions) parser.KVOptions {
+	if _, ok := opt.Get(backupOptEncPassphrase); !ok {
+		return opt
+	}
+	redacted := append(parser.KVOptions(nil), opt...)
+	for i := range redacted {
+		if redacted[i].Key == backupOptEncPassphrase {
+			redacted[i].Value = "redacted"
+		}
+	}
+	return redacted
+}
+
 // ValidatePreviousBackups checks that the timestamps of previous backups are
 // consistent. The most recently backed-up time is returned.
-func ValidatePreviousBackups(ctx context.Context, uris []string) (hlc.Timestamp, error) {
+func ValidatePreviousBackups(
+	ctx context.Context, uris []string, encryptionKey []byte,
+) (hlc.Timestamp, error) {
 	if len(uris) == 0 || len(uris) == 1 && uris[0] == "" {
 		// Full backup.
 		return hlc.Timestamp{}, nil
 	}
 	backups := make([]BackupDescriptor, len(uris))
 	for i, uri := range uris {
-		desc, err := readBackupDescriptor(ctx, uri)
+		desc, err := readBackupDescriptor(ctx, uri, encryptionKey)
 		if err != nil {
 			return hlc.Timestamp{}, err
 		}
@@ -205,17 +323,19 @@ func backupJobDescription(
 	backup *parser.Backup, to string, incrementalFrom []string,
 ) (string, error) {
 	b := parser.Backup{
-		AsOf:            backup.AsOf,
-		Options:         backup.Options,
-		Targets:         backup.Targets,
-		IncrementalFrom: make(parser.Exprs, len(incrementalFrom)),
+		AsOf:    backup.AsOf,
+		Options: redactedOptions(backup.Options),
+		Targets: backup.Targets,
+	}
+	if backup.IncrementalFrom != nil {
+		b.IncrementalFrom = make(parser.Exprs, len(incrementalFrom))
 	}
 
 	to, err := storageccl.SanitizeExportStorageURI(to)
 	if err != nil {
 		return "", err
 	}
-	backup.To = parser.NewDString(to)
+	b.To = parser.NewDString(to)
 
 	for i, from := range incrementalFrom {
 		sanitizedFrom, err := storageccl.SanitizeExportStorageURI(from)
@@ -225,7 +345,7 @@ func backupJobDescription(
 		b.IncrementalFrom[i] = parser.NewDString(sanitizedFrom)
 	}
 
-	return backup.String(), nil
+	return b.String(), nil
 }
 
 // clusterNodeCount returns the approximate number of nodes in the cluster.
@@ -263,7 +383,9 @@ func Backup(
 	uri string,
 	targets parser.TargetList,
 	startTime, endTime hlc.Timestamp,
-	_ parser.KVOptions,
+	opt parser.KVOptions,
+	encryption *EncryptionInfo,
+	encryptionKey []byte,
 	jobLogger *sql.JobLogger,
 ) (BackupDescriptor, error) {
 	// TODO(dan): Figure out how permissions should work. #6713 is tracking this
@@ -271,6 +393,14 @@ func Backup(
 
 	var sqlDescs []sqlbase.Descriptor
 
+	revisionHistory := false
+	if val, ok := opt.Get(backupOptRevisionHistory); ok {
+		if val != "" {
+			return BackupDescriptor{}, errors.Errorf("option %q does not take a value", backupOptRevisionHistory)
+		}
+		revisionHistory = true
+	}
+
 	exportStore, err := exportStorageFromURI(ctx, uri)
 	if err != nil {
 		return BackupDescriptor{}, err
@@ -289,6 +419,18 @@ func Backup(
 		}
 	}
 
+	// The salt of an encrypted backup is written in the clear, next to it, so
+	// its key can be derived from the passphrase again.
+	if encryption != nil {
+		infoBuf, err := encryption.Marshal()
+		if err != nil {
+			return BackupDescriptor{}, err
+		}
+		if err := exportStore.WriteFile(ctx, BackupEncryptionInfoName, bytes.NewReader(infoBuf)); err != nil {
+			return BackupDescriptor{}, err
+		}
+	}
+
 	db := p.ExecCfg().DB
 
 	{
@@ -349,17 +491,28 @@ func Backup(
 	// completed requests as a rough measure of progress.
 	spans := splitSpansByRanges(spansForAllTableIndexes(tables), ranges)
 
-	mu := struct {
-		syncutil.Mutex
-		files    []BackupDescriptor_File
-		dataSize int64
-	}{}
-
-	progressLogger := jobProgressLogger{
-		jobLogger:   jobLogger,
-		totalChunks: len(spans),
+	desc := BackupDescriptor{
+		StartTime:       startTime,
+		EndTime:         endTime,
+		Descriptors:     sqlDescs,
+		Spans:           spans,
+		FormatVersion:   BackupFormatInitialVersion,
+		BuildInfo:       build.GetInfo(),
+		NodeID:          p.ExecCfg().NodeID.Get(),
+		ClusterID:       p.ExecCfg().ClusterID(),
+		RevisionHistory: revisionHistory,
+	}
+	descBuf, err := desc.Marshal()
+	if err != nil {
+		return BackupDescriptor{}, err
+	}
+	jobLogger.Job.Details = sql.BackupJobDetails{
+		URI:              uri,
+		StartTime:        startTime,
+		EndTime:          endTime,
+		BackupDescriptor: descBuf,
+		Encrypted:        encryptionKey != nil,
 	}
-
 	for _, desc := range tables {
 		jobLogger.Job.DescriptorIDs = append(jobLogger.Job.DescriptorIDs, desc.GetID())
 	}
@@ -370,6 +523,75 @@ func Backup(
 		return BackupDescriptor{}, err
 	}
 
+	return backup(ctx, p, exportStore, desc, nil /* completed */, encryptionKey, jobLogger)
+}
+
+// remainingBackupSpans returns the spans of desc that are not covered by the
+// completed files. Every file written by an export lies within the span that
+// was exported, and an export that finds no data is recorded as a file without
+// a path, so a span is done if and only if some file starts in it.
+func remainingBackupSpans(
+	desc BackupDescriptor, completed []roachpb.ExportResponse_File,
+) []roachpb.Span {
+	files := append([]roachpb.ExportResponse_File(nil), completed...)
+	sort.Slice(files, func(i, j int) bool {
+		return bytes.Compare(files[i].Span.Key, files[j].Span.Key) < 0
+	})
+	var remaining []roachpb.Span
+	for _, span := range desc.Spans {
+		i := sort.Search(len(files), func(i int) bool {
+			return bytes.Compare(files[i].Span.Key, span.Key) >= 0
+		})
+		if i < len(files) && bytes.Compare(files[i].Span.Key, span.EndKey) < 0 {
+			continue
+		}
+		remaining = append(remaining, span)
+	}
+	return remaining
+}
+
+// backup exports the spans of desc that are not covered by the completed
+// files and writes the completed BackupDescriptor to exportStore. completed is
+// empty for a new job and the files checkpointed by a resumed one, whose
+// RevisionStartTime is then set in desc. If encryptionKey is not nil, every
+// file written is encrypted with it.
+func backup(
+	ctx context.Context,
+	p sql.PlanHookState,
+	exportStore storageccl.ExportStorage,
+	desc BackupDescriptor,
+	completed []roachpb.ExportResponse_File,
+	encryptionKey []byte,
+	jobLogger *sql.JobLogger,
+) (BackupDescriptor, error) {
+	db := p.ExecCfg().DB
+	spans := remainingBackupSpans(desc, completed)
+
+	mu := struct {
+		syncutil.Mutex
+		completed         []roachpb.ExportResponse_File
+		revisionStartTime hlc.Timestamp
+	}{
+		completed:         completed,
+		revisionStartTime: desc.RevisionStartTime,
+	}
+
+	progressLogger := jobProgressLogger{
+		jobLogger:   jobLogger,
+		totalChunks: len(desc.Spans),
+		checkpointFn: func(details interface{}) error {
+			// Only the exported files are checkpointed; the rest of the
+			// descriptor doesn't change while the job runs.
+			mu.Lock()
+			d := details.(*sql.BackupJobDetails)
+			d.CompletedFiles = append([]roachpb.ExportResponse_File(nil), mu.completed...)
+			d.RevisionStartTime = mu.revisionStartTime
+			mu.Unlock()
+			return nil
+		},
+	}
+	progressLogger.mu.completedChunks = len(desc.Spans) - len(spans)
+
 	// We're already limiting these on the server-side, but sending all the
 	// Export requests at once would fill up distsender/grpc/something and cause
 	// all sorts of badness (node liveness timeouts leading to mass leaseholder
@@ -389,7 +611,7 @@ func Backup(
 	maxConcurrentExports := clusterNodeCount(p.ExecCfg().Gossip) * storageccl.ExportRequestLimit
 	exportsSem := make(chan struct{}, maxConcurrentExports)
 
-	header := roachpb.Header{Timestamp: endTime}
+	header := roachpb.Header{Timestamp: desc.EndTime}
 	g, gCtx := errgroup.WithContext(ctx)
 	for i := range spans {
 		select {
@@ -403,57 +625,73 @@ func Backup(
 			defer func() { <-exportsSem }()
 
 			req := &roachpb.ExportRequest{
-				Span:      span,
-				Storage:   exportStore.Conf(),
-				StartTime: startTime,
+				Span:          span,
+				Storage:       exportStore.Conf(),
+				StartTime:     desc.StartTime,
+				AllRevisions:  desc.RevisionHistory,
+				EncryptionKey: encryptionKey,
 			}
 			res, pErr := client.SendWrappedWith(gCtx, db.GetSender(), header, req)
 			if pErr != nil {
 				return pErr.GoError()
 			}
 			mu.Lock()
-			for _, file := range res.(*roachpb.ExportResponse).Files {
-				mu.files = append(mu.files, BackupDescriptor_File{
-					Span:   file.Span,
-					Path:   file.Path,
-					Sha512: file.Sha512,
-				})
-				mu.dataSize += file.DataSize
+			files := res.(*roachpb.ExportResponse).Files
+			mu.revisionStartTime.Forward(res.(*roachpb.ExportResponse).RevisionStartTime)
+			if len(files) == 0 {
+				// Remember that the span was exported, in case the job is resumed.
+				mu.completed = append(mu.completed, roachpb.ExportResponse_File{Span: span})
 			}
+			mu.completed = append(mu.completed, files...)
 			mu.Unlock()
-			if err := progressLogger.chunkFinished(ctx); err != nil {
-				// Errors while updating progress are not important enough to merit
-				// failing the entire backup.
-				log.Errorf(ctx, "BACKUP ignoring error while updating progress on job %d (%s): %+v",
-					jobLogger.JobID(), jobLogger.Job.Description, err)
-			}
-			return nil
+			return progressLogger.chunkFinished(ctx)
 		})
 	}
 
-	if err = g.Wait(); err != nil {
+	if err := g.Wait(); err != nil {
 		return BackupDescriptor{}, errors.Wrapf(err, "exporting %d ranges", len(spans))
 	}
-	files, dataSize := mu.files, mu.dataSize // No more concurrency, so this is safe.
+	// Make sure the job was not paused or canceled before writing the
+	// descriptor that makes the backup usable.
+	if err := progressLogger.checkpoint(ctx); err != nil {
+		return BackupDescriptor{}, err
+	}
 
-	desc := BackupDescriptor{
-		StartTime:     startTime,
-		EndTime:       endTime,
-		Descriptors:   sqlDescs,
-		Spans:         spans,
-		Files:         files,
-		DataSize:      dataSize,
-		FormatVersion: BackupFormatInitialVersion,
-		BuildInfo:     build.GetInfo(),
-		NodeID:        p.ExecCfg().NodeID.Get(),
-		ClusterID:     p.ExecCfg().ClusterID(),
+	// No more concurrency, so this is safe. Files without a path only track
+	// the progress of the job and are left out of the final descriptor.
+	desc.Files = nil
+	desc.DataSize = 0
+	for _, file := range mu.completed {
+		if len(file.Path) > 0 {
+			desc.Files = append(desc.Files, BackupDescriptor_File{
+				Span:   file.Span,
+				Path:   file.Path,
+				Sha512: file.Sha512,
+			})
+		}
+		desc.DataSize += file.DataSize
 	}
 	sort.Sort(backupFileDescriptors(desc.Files))
 
+	if desc.RevisionHistory {
+		desc.RevisionStartTime = mu.revisionStartTime
+		var err error
+		if desc.DescriptorChanges, err = loadDescriptorChanges(
+			ctx, exportStore, desc.Files, encryptionKey,
+		); err != nil {
+			return BackupDescriptor{}, errors.Wrap(err, "loading descriptor revisions")
+		}
+	}
+
 	descBuf, err := desc.Marshal()
 	if err != nil {
 		return BackupDescriptor{}, err
 	}
+	if encryptionKey != nil {
+		if descBuf, err = storageccl.EncryptFile(descBuf, encryptionKey); err != nil {
+			return BackupDescriptor{}, err
+		}
+	}
 
 	if err := exportStore.WriteFile(ctx, BackupDescriptorName, bytes.NewReader(descBuf)); err != nil {
 		return BackupDescriptor{}, err
@@ -461,6 +699,80 @@ func Backup(
 	return desc, nil
 }
 
+// loadDescriptorChanges returns every revision of the SQL descriptors found in
+// the given backup files, which contain the whole descriptor table, sorted by
+// ID and time.
+func loadDescriptorChanges(
+	ctx context.Context,
+	exportStore storageccl.ExportStorage,
+	files []BackupDescriptor_File,
+	encryptionKey []byte,
+) ([]BackupDescriptor_DescriptorRevision, error) {
+	descSpan := sqlbase.DescriptorTable.PrimaryIndexSpan()
+	prefix := sqlbase.MakeAllDescsMetadataKey()
+
+	var changes []BackupDescriptor_DescriptorRevision
+	readFile := func(file BackupDescriptor_File) error {
+		localPath, cleanup, err := storageccl.FetchFile(ctx, os.TempDir(), exportStore, file.Path, encryptionKey)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		readerTempDir, err := ioutil.TempDir(os.TempDir(), "backup-sstreader")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := os.RemoveAll(readerTempDir); err != nil {
+				log.Warning(ctx, err)
+			}
+		}()
+		sst, err := engine.MakeRocksDBSstFileReader(readerTempDir)
+		if err != nil {
+			return err
+		}
+		defer sst.Close()
+		if err := sst.AddFile(localPath); err != nil {
+			return err
+		}
+
+		start, end := engine.MakeMVCCMetadataKey(descSpan.Key), engine.MakeMVCCMetadataKey(descSpan.EndKey)
+		return sst.Iterate(start, end, func(kv engine.MVCCKeyValue) (bool, error) {
+			_, id, err := encoding.DecodeUvarintAscending(kv.Key.Key[len(prefix):])
+			if err != nil {
+				return true, errors.Wrapf(err, "decoding descriptor key %s", kv.Key.Key)
+			}
+			change := BackupDescriptor_DescriptorRevision{Time: kv.Key.Timestamp, ID: sqlbase.ID(id)}
+			// An empty value means the descriptor was deleted.
+			if len(kv.Value) > 0 {
+				var sqlDesc sqlbase.Descriptor
+				if err := (roachpb.Value{RawBytes: kv.Value}).GetProto(&sqlDesc); err != nil {
+					return true, err
+				}
+				change.Desc = &sqlDesc
+			}
+			changes = append(changes, change)
+			return false, nil
+		})
+	}
+	for _, file := range files {
+		if !file.Span.Overlaps(descSpan) {
+			continue
+		}
+		if err := readFile(file); err != nil {
+			return nil, errors.Wrapf(err, "reading %s", file.Path)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].ID != changes[j].ID {
+			return changes[i].ID < changes[j].ID
+		}
+		return changes[i].Time.Less(changes[j].Time)
+	})
+	return changes, nil
+}
+
 func backupPlanHook(
 	baseCtx context.Context, stmt parser.Statement, p sql.PlanHookState,
 ) (func() ([]parser.Datums, error), sqlbase.ResultColumns, error) {
@@ -504,10 +816,21 @@ func backupPlanHook(
 			return nil, err
 		}
 
+		// An incremental backup is encrypted with the same key as the backups
+		// it builds on.
+		var prevURI string
+		if backup.IncrementalFrom != nil && len(incrementalFrom) > 0 {
+			prevURI = incrementalFrom[0]
+		}
+		encryptionKey, encryption, err := backupEncryptionKey(ctx, backup.Options, prevURI)
+		if err != nil {
+			return nil, err
+		}
+
 		var startTime hlc.Timestamp
 		if backup.IncrementalFrom != nil {
 			var err error
-			startTime, err = ValidatePreviousBackups(ctx, incrementalFrom)
+			startTime, err = ValidatePreviousBackups(ctx, incrementalFrom, encryptionKey)
 			if err != nil {
 				return nil, err
 			}
@@ -534,18 +857,13 @@ func backupPlanHook(
 			backup.Targets,
 			startTime, endTime,
 			backup.Options,
+			encryption, encryptionKey,
 			&jobLogger,
 		)
+		finishJob(ctx, &jobLogger, err)
 		if err != nil {
-			jobLogger.Failed(ctx, err)
 			return nil, err
 		}
-		if err := jobLogger.Succeeded(ctx); err != nil {
-			// An error while marking the job as successful is not important enough to
-			// merit failing the entire backup.
-			log.Errorf(ctx, "BACKUP ignoring error while marking job %d (%s) as successful: %+v",
-				jobLogger.JobID(), description, err)
-		}
 		// TODO(benesch): emit periodic progress updates once we have the
 		// infrastructure to stream responses.
 		ret := []parser.Datums{{
@@ -559,6 +877,84 @@ func backupPlanHook(
 	return fn, header, nil
 }
 
+func backupResumeHook(
+	ctx context.Context, p sql.PlanHookState, jobLogger *sql.JobLogger, opt parser.KVOptions,
+) (func(context.Context) error, error) {
+	details, ok := jobLogger.Job.Details.(sql.BackupJobDetails)
+	if !ok {
+		return nil, nil
+	}
+	if err := utilccl.CheckEnterpriseEnabled("BACKUP"); err != nil {
+		return nil, err
+	}
+	var desc BackupDescriptor
+	if err := desc.Unmarshal(details.BackupDescriptor); err != nil {
+		return nil, err
+	}
+	if len(desc.Spans) == 0 {
+		return nil, errors.Errorf("job %d has no checkpoint to resume from", *jobLogger.JobID())
+	}
+	desc.RevisionStartTime = details.RevisionStartTime
+	encryptionKey, err := resumeEncryptionKey(
+		ctx, *jobLogger.JobID(), details.Encrypted, opt, details.URI,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// Check the passphrase against a file exported before the job was paused,
+	// so that the backup is not written with two different keys.
+	if encryptionKey != nil {
+		if err := checkBackupFileKey(ctx, details.URI, details.CompletedFiles, encryptionKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(ctx context.Context) error {
+		exportStore, err := exportStorageFromURI(ctx, details.URI)
+		if err == nil {
+			defer exportStore.Close()
+			_, err = backup(ctx, p, exportStore, desc, details.CompletedFiles, encryptionKey, jobLogger)
+		}
+		finishJob(ctx, jobLogger, err)
+		return err
+	}, nil
+}
+
+// checkBackupFileKey returns an error if the first of the given files of the
+// backup in uri, if any, cannot be decrypted with encryptionKey.
+func checkBackupFileKey(
+	ctx context.Context, uri string, files []roachpb.ExportResponse_File, encryptionKey []byte,
+) error {
+	var path string
+	for _, f := range files {
+		// Spans that had no data to export have no file.
+		if f.Path != "" {
+			path = f.Path
+			break
+		}
+	}
+	if path == "" {
+		return nil
+	}
+	dir, err := exportStorageFromURI(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	r, err := dir.ReadFile(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	_, err = storageccl.DecryptFile(content, encryptionKey)
+	return err
+}
+
 func init() {
 	sql.AddPlanHook(backupPlanHook)
+	sql.AddJobResumeHook(backupResumeHook)
 }
@@ -33,6 +33,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -1119,6 +1120,185 @@ func TestBackupAsOfSystemTime(t *testing.T) {
 	}
 }
 
+func TestRestoreAsOfSystemTime(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 10
+
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts)
+	defer cleanupFn()
+
+	const bankQuery = `SELECT id, balance, payload FROM %s.bank ORDER BY id`
+	var ts []string
+	var expected [][][]string
+	mark := func() {
+		var now string
+		sqlDB.QueryRow(`SELECT cluster_logical_timestamp()`).Scan(&now)
+		ts = append(ts, now)
+		expected = append(expected, sqlDB.QueryStr(fmt.Sprintf(bankQuery, "bench")))
+	}
+
+	full, inc, plain := filepath.Join(dir, "full"), filepath.Join(dir, "inc"), filepath.Join(dir, "plain")
+
+	mark()
+	sqlDB.Exec(`UPDATE bench.bank SET payload = 'a' WHERE id % 2 = 0`)
+	sqlDB.Exec(`CREATE TABLE bench.other (a INT PRIMARY KEY)`)
+	mark()
+	sqlDB.Exec(`BACKUP DATABASE bench TO $1 WITH OPTIONS ('revision_history')`, full)
+	sqlDB.Exec(`UPDATE bench.bank SET payload = 'b' WHERE id % 3 = 0`)
+	mark()
+	sqlDB.Exec(`DELETE FROM bench.bank WHERE id > 7`)
+	mark()
+	sqlDB.Exec(`BACKUP DATABASE bench TO $1 INCREMENTAL FROM $2 WITH OPTIONS ('revision_history')`,
+		inc, full)
+	sqlDB.Exec(`BACKUP DATABASE bench TO $1`, plain)
+	sqlDB.Exec(`INSERT INTO bench.bank VALUES (100, 100, 'after')`)
+	mark()
+
+	for i, from := range [][]string{{full}, {full}, {full, inc}, {full, inc}} {
+		db := fmt.Sprintf("r%d", i)
+		sqlDB.Exec(fmt.Sprintf(`CREATE DATABASE %s`, db))
+		sqlDB.Exec(fmt.Sprintf(`RESTORE bench.* FROM '%s' AS OF SYSTEM TIME %s WITH OPTIONS ('into_db'='%s')`,
+			strings.Join(from, `', '`), ts[i], db))
+		sqlDB.CheckQueryResults(fmt.Sprintf(bankQuery, db), expected[i])
+
+		// The second table is only restored as of a time after its creation.
+		var tables int
+		sqlDB.QueryRow(
+			`SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = $1`, db,
+		).Scan(&tables)
+		expectedTables := 2
+		if i == 0 {
+			expectedTables = 1
+		}
+		if tables != expectedTables {
+			t.Errorf("%s: expected %d tables, got %d", ts[i], expectedTables, tables)
+		}
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.Exec(`CREATE DATABASE errs`)
+		for _, tc := range []struct {
+			query string
+			args  []interface{}
+			err   string
+		}{
+			{fmt.Sprintf(`RESTORE bench.bank FROM $1, $2 AS OF SYSTEM TIME %s WITH OPTIONS ('into_db'='errs')`, ts[4]),
+				[]interface{}{full, inc}, `cannot restore as of .*: the last backup ends at`},
+			{fmt.Sprintf(`RESTORE bench.bank FROM $1 AS OF SYSTEM TIME %s WITH OPTIONS ('into_db'='errs')`, ts[0]),
+				[]interface{}{plain}, `was not taken with the "revision_history" option`},
+			{fmt.Sprintf(`RESTORE bench.bank FROM $1 AS OF SYSTEM TIME %s WITH OPTIONS ('into_db'='errs')`, ts[1]),
+				[]interface{}{inc}, `cannot restore as of .*: the backup from .* only has the revisions after`},
+			{`BACKUP DATABASE bench TO $1 WITH OPTIONS ('revision_history'='yes')`,
+				[]interface{}{filepath.Join(dir, "err")}, `option "revision_history" does not take a value`},
+		} {
+			if _, err := sqlDB.DB.Exec(tc.query, tc.args...); !testutils.IsError(err, tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.query, tc.err, err)
+			}
+		}
+	})
+}
+
+func TestBackupRestoreEncrypted(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 100
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts)
+	defer cleanupFn()
+	rawDir := strings.TrimPrefix(dir, "nodelocal://")
+
+	const passphrase = `abcdefg`
+	const opts = ` WITH OPTIONS ('encryption_passphrase'='` + passphrase + `')`
+	full, inc := filepath.Join(dir, "full"), filepath.Join(dir, "inc")
+
+	sqlDB.Exec(`BACKUP DATABASE bench TO $1`+opts, full)
+	sqlDB.Exec(`UPDATE bench.bank SET payload = 'a' WHERE id % 2 = 0`)
+	sqlDB.Exec(`BACKUP DATABASE bench TO $1 INCREMENTAL FROM $2`+opts, inc, full)
+	expected := sqlDB.QueryStr(`SELECT * FROM bench.bank ORDER BY id`)
+
+	// Every file but the salt, including the descriptor, is encrypted.
+	for _, backupDir := range []string{"full", "inc"} {
+		files, err := ioutil.ReadDir(filepath.Join(rawDir, backupDir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) < 3 {
+			t.Fatalf("%s: expected at least 3 files, got %d", backupDir, len(files))
+		}
+		for _, f := range files {
+			if f.Name() == BackupEncryptionInfoName {
+				continue
+			}
+			content, err := ioutil.ReadFile(filepath.Join(rawDir, backupDir, f.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !storageccl.AppearsEncrypted(content) {
+				t.Errorf("%s: expected %s to be encrypted", backupDir, f.Name())
+			}
+		}
+	}
+
+	sqlDB.Exec(`DROP TABLE bench.bank`)
+	sqlDB.Exec(`RESTORE bench.* FROM $1, $2`+opts, full, inc)
+	sqlDB.CheckQueryResults(`SELECT * FROM bench.bank ORDER BY id`, expected)
+
+	// The passphrase is not recorded in the job descriptions.
+	var leaked int
+	sqlDB.QueryRow(
+		`SELECT COUNT(*) FROM crdb_internal.jobs WHERE description LIKE '%' || $1 || '%'`, passphrase,
+	).Scan(&leaked)
+	if leaked != 0 {
+		t.Fatalf("expected the passphrase to be redacted from job descriptions, found it in %d", leaked)
+	}
+
+	// Nor is the key derived from it stored in the job records.
+	info, err := readEncryptionInfo(context.Background(), full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := storageccl.GenerateKey([]byte(passphrase), info.Salt)
+	rows := sqlDB.Query(`SELECT payload FROM system.jobs`)
+	defer rows.Close()
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(payload, key) {
+			t.Fatal("expected the encryption key not to be stored in the job records")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.Exec(`CREATE DATABASE errs`)
+		const wrongOpts = ` WITH OPTIONS ('encryption_passphrase'='wrong', 'into_db'='errs')`
+		for _, tc := range []struct {
+			query string
+			args  []interface{}
+			err   string
+		}{
+			{`RESTORE bench.* FROM $1, $2` + wrongOpts,
+				[]interface{}{full, inc}, `the encryption key or passphrase is likely incorrect`},
+			{`RESTORE bench.* FROM $1, $2 WITH OPTIONS ('into_db'='errs')`,
+				[]interface{}{full, inc}, `file appears encrypted -- try specifying the "encryption_passphrase" option`},
+			{`BACKUP DATABASE bench TO $1 INCREMENTAL FROM $2`,
+				[]interface{}{filepath.Join(dir, "err"), full}, `file appears encrypted`},
+			{`BACKUP DATABASE bench TO $1 INCREMENTAL FROM $2 WITH OPTIONS ('encryption_passphrase'='wrong')`,
+				[]interface{}{filepath.Join(dir, "err"), full}, `the encryption key or passphrase is likely incorrect`},
+			{`BACKUP DATABASE bench TO $1 WITH OPTIONS ('encryption_passphrase')`,
+				[]interface{}{filepath.Join(dir, "err")}, `option "encryption_passphrase" requires a value`},
+		} {
+			if _, err := sqlDB.DB.Exec(tc.query, tc.args...); !testutils.IsError(err, tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.query, tc.err, err)
+			}
+		}
+	})
+}
+
 func TestBackupRestoreChecksum(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -0,0 +1,1147 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl/intervalccl"
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+const (
+	importOptionComma   = "comma"
+	importOptionComment = "comment"
+	importOptionNullIf  = "nullif"
+	importOptionSkip    = "skip"
+	importOptionSSTSize = "sstsize"
+	importOptionTemp    = "temp"
+)
+
+var importOptionExpectValues = map[string]bool{
+	importOptionComma:   true,
+	importOptionComment: true,
+	importOptionNullIf:  true,
+	importOptionSkip:    true,
+	importOptionSSTSize: true,
+	importOptionTemp:    true,
+}
+
+// exportStorageForFile returns the ExportStorage of the directory that
+// contains the file at uri, and the name of the file in that directory.
+func exportStorageForFile(
+	ctx context.Context, uri string,
+) (storageccl.ExportStorage, string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, "", err
+	}
+	var name string
+	parsed.Path, name = path.Split(parsed.Path)
+	if name == "" {
+		return nil, "", errors.Errorf("%q does not name a file", uri)
+	}
+	store, err := exportStorageFromURI(ctx, parsed.String())
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "export storage for %q", uri)
+	}
+	return store, name, nil
+}
+
+// readCreateTableFromStore reads the CREATE TABLE statement in the file at
+// the given URI.
+func readCreateTableFromStore(ctx context.Context, uri string) (*parser.CreateTable, error) {
+	store, name, err := exportStorageForFile(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	r, err := store.ReadFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	schema, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := parser.ParseOne(string(schema))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing schema in %q", uri)
+	}
+	create, ok := stmt.(*parser.CreateTable)
+	if !ok {
+		return nil, errors.Errorf("expected CREATE TABLE statement in %q", uri)
+	}
+	return create, nil
+}
+
+// makeImportTableDesc creates the descriptor of the table created by an
+// IMPORT, with a newly allocated ID. The descriptor is only written once all
+// of the data has been ingested; if the import fails before that, the ID is
+// leaked.
+func makeImportTableDesc(
+	ctx context.Context, p sql.PlanHookState, create *parser.CreateTable,
+) (*sqlbase.TableDescriptor, error) {
+	if create.As() {
+		return nil, errors.New("IMPORT does not support CREATE TABLE ... AS")
+	}
+	if create.Interleave != nil {
+		return nil, errors.New("IMPORT does not support interleaved tables")
+	}
+	evalCtx := p.EvalContext()
+	var tableDesc sqlbase.TableDescriptor
+	err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		tn, err := create.Table.NormalizeWithDatabaseName(evalCtx.Database)
+		if err != nil {
+			return err
+		}
+		dbDesc, err := sql.MustGetDatabaseDesc(ctx, txn, sql.NilVirtualTabler, tn.Database())
+		if err != nil {
+			return err
+		}
+		if err := p.CheckPrivilege(dbDesc, privilege.CREATE); err != nil {
+			return err
+		}
+
+		// Check that the table name is not in use. This would fail the CPut when
+		// the descriptor is written anyway, but only after the data is ingested.
+		res, err := txn.Get(ctx, sqlbase.MakeNameMetadataKey(dbDesc.ID, tn.Table()))
+		if err != nil {
+			return err
+		}
+		if res.Exists() {
+			return sqlbase.NewRelationAlreadyExistsError(tn.String())
+		}
+
+		id, err := sql.GenerateUniqueDescID(ctx, txn)
+		if err != nil {
+			return err
+		}
+		affected := make(map[sqlbase.ID]*sqlbase.TableDescriptor)
+		tableDesc, err = sql.MakeTableDesc(
+			ctx, txn, sql.NilVirtualTabler, nil /* searchPath */, create,
+			dbDesc.ID, id, dbDesc.GetPrivileges(), affected, dbDesc.Name, &evalCtx,
+		)
+		if err != nil {
+			return err
+		}
+		if len(affected) > 0 {
+			return errors.New("IMPORT does not support foreign keys")
+		}
+		return tableDesc.ValidateTable()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tableDesc, nil
+}
+
+// importCSVColumns returns the columns of the table whose values are read from
+// the CSV files: the visible columns that are not computed.
+func importCSVColumns(tableDesc *sqlbase.TableDescriptor) []sqlbase.ColumnDescriptor {
+	var cols []sqlbase.ColumnDescriptor
+	for _, col := range tableDesc.VisibleColumns() {
+		if !col.IsComputed() {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// parseCSVDatum converts the value of a CSV field into a datum of type t.
+func parseCSVDatum(t parser.Type, s string) (parser.Datum, error) {
+	switch t {
+	case parser.TypeBool:
+		return parser.ParseDBool(s)
+	case parser.TypeInt:
+		return parser.ParseDInt(s)
+	case parser.TypeFloat:
+		return parser.ParseDFloat(s)
+	case parser.TypeDecimal:
+		return parser.ParseDDecimal(s)
+	case parser.TypeString:
+		return parser.NewDString(s), nil
+	case parser.TypeBytes:
+		return parser.NewDBytes(parser.DBytes(s)), nil
+	case parser.TypeDate:
+		return parser.ParseDDate(s, time.UTC)
+	case parser.TypeTimestamp:
+		return parser.ParseDTimestamp(s, time.Microsecond)
+	case parser.TypeTimestampTZ:
+		return parser.ParseDTimestampTZ(s, time.UTC, time.Microsecond)
+	case parser.TypeInterval:
+		return parser.ParseDInterval(s)
+	case parser.TypeUUID:
+		return parser.ParseDUuidFromString(s)
+	case parser.TypeJSON:
+		return parser.ParseDJSON(s)
+	default:
+		return nil, errors.Errorf("IMPORT does not support columns of type %s", t)
+	}
+}
+
+// parseRuneOption returns the single character in the value of a CSV
+// option.
+func parseRuneOption(opt, value string) (rune, error) {
+	r, n := utf8.DecodeRuneInString(value)
+	if r == utf8.RuneError || n != len(value) {
+		return 0, errors.Errorf("%s must be a single character", opt)
+	}
+	return r, nil
+}
+
+func importJobDescription(
+	orig *parser.Import, createFile string, files []string, opts map[string]string,
+) (string, error) {
+	stmt := *orig
+	if stmt.CreateFile != nil {
+		sf, err := storageccl.SanitizeExportStorageURI(createFile)
+		if err != nil {
+			return "", err
+		}
+		stmt.CreateFile = parser.NewDString(sf)
+	}
+	stmt.Files = make(parser.Exprs, len(files))
+	for i, f := range files {
+		sf, err := storageccl.SanitizeExportStorageURI(f)
+		if err != nil {
+			return "", err
+		}
+		stmt.Files[i] = parser.NewDString(sf)
+	}
+	stmt.Options = nil
+	for k, v := range opts {
+		if k == importOptionTemp {
+			var err error
+			if v, err = storageccl.SanitizeExportStorageURI(v); err != nil {
+				return "", err
+			}
+		}
+		stmt.Options = append(stmt.Options, parser.KVOption{Key: k, Value: v})
+	}
+	sort.Slice(stmt.Options, func(i, j int) bool { return stmt.Options[i].Key < stmt.Options[j].Key })
+	return stmt.String(), nil
+}
+
+func importPlanHook(
+	baseCtx context.Context, stmt parser.Statement, p sql.PlanHookState,
+) (func() ([]parser.Datums, error), sqlbase.ResultColumns, error) {
+	importStmt, ok := stmt.(*parser.Import)
+	if !ok {
+		return nil, nil, nil
+	}
+	if err := utilccl.CheckEnterpriseEnabled("IMPORT"); err != nil {
+		return nil, nil, err
+	}
+
+	if err := p.RequireSuperUser("IMPORT"); err != nil {
+		return nil, nil, err
+	}
+
+	if importStmt.FileFormat != "CSV" {
+		return nil, nil, errors.Errorf("unsupported import format: %q", importStmt.FileFormat)
+	}
+
+	filesFn, err := p.TypeAsStringArray(importStmt.Files, "IMPORT")
+	if err != nil {
+		return nil, nil, err
+	}
+	var createFileFn func() (string, error)
+	if importStmt.CreateDefs == nil {
+		createFileFn, err = p.TypeAsString(importStmt.CreateFile, "IMPORT")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	opts := make(map[string]string, len(importStmt.Options))
+	for _, opt := range importStmt.Options {
+		if !importOptionExpectValues[opt.Key] {
+			return nil, nil, errors.Errorf("invalid option %q", opt.Key)
+		}
+		opts[opt.Key] = opt.Value
+	}
+
+	header := sqlbase.ResultColumns{
+		{Name: "job_id", Typ: parser.TypeInt},
+		{Name: "status", Typ: parser.TypeString},
+		{Name: "fraction_completed", Typ: parser.TypeFloat},
+		{Name: "bytes", Typ: parser.TypeInt},
+	}
+	fn := func() ([]parser.Datums, error) {
+		// TODO(dan): Move this span into sql.
+		ctx, span := tracing.ChildSpan(baseCtx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		files, err := filesFn()
+		if err != nil {
+			return nil, err
+		}
+
+		readSpec := distsqlrun.ReadCSVSpec{URI: files}
+		if override, ok := opts[importOptionComma]; ok {
+			comma, err := parseRuneOption(importOptionComma, override)
+			if err != nil {
+				return nil, err
+			}
+			readSpec.Comma = comma
+		}
+		if override, ok := opts[importOptionComment]; ok {
+			comment, err := parseRuneOption(importOptionComment, override)
+			if err != nil {
+				return nil, err
+			}
+			readSpec.Comment = comment
+		}
+		if override, ok := opts[importOptionNullIf]; ok {
+			readSpec.Nullif = &override
+		}
+		if override, ok := opts[importOptionSkip]; ok {
+			skip, err := strconv.ParseUint(override, 10, 32)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid %s value", importOptionSkip)
+			}
+			readSpec.Skip = uint32(skip)
+		}
+
+		temp, ok := opts[importOptionTemp]
+		if !ok {
+			return nil, errors.Errorf("must provide a temporary storage location with the %q option",
+				importOptionTemp)
+		}
+		writeSpec := distsqlrun.SSTWriterSpec{
+			Destination: temp,
+			SplitSize:   config.DefaultZoneConfig().RangeMaxBytes / 2,
+		}
+		if override, ok := opts[importOptionSSTSize]; ok {
+			sstSize, err := strconv.ParseInt(override, 10, 64)
+			if err != nil || sstSize <= 0 {
+				return nil, errors.Errorf("invalid %s value: %q", importOptionSSTSize, override)
+			}
+			writeSpec.SplitSize = sstSize
+		}
+
+		var create *parser.CreateTable
+		var createFile string
+		if importStmt.CreateDefs != nil {
+			create = &parser.CreateTable{Table: importStmt.Table, Defs: importStmt.CreateDefs}
+		} else {
+			createFile, err = createFileFn()
+			if err != nil {
+				return nil, err
+			}
+			create, err = readCreateTableFromStore(ctx, createFile)
+			if err != nil {
+				return nil, err
+			}
+			if named, parsed := importStmt.Table.String(), create.Table.String(); named != parsed {
+				return nil, errors.Errorf("importing table %s, but file specifies a schema for table %s",
+					named, parsed)
+			}
+		}
+		tableDesc, err := makeImportTableDesc(ctx, p, create)
+		if err != nil {
+			return nil, err
+		}
+		readSpec.TableDesc = *tableDesc
+
+		description, err := importJobDescription(importStmt, createFile, files, opts)
+		if err != nil {
+			return nil, err
+		}
+		jobLogger := sql.NewJobLogger(p.ExecCfg().DB, p.LeaseMgr(), sql.JobRecord{
+			Description:   description,
+			Username:      p.User(),
+			DescriptorIDs: sqlbase.IDs{tableDesc.ID},
+			Details: sql.ImportJobDetails{
+				URIs:      files,
+				TableDesc: tableDesc,
+				Temp:      temp,
+			},
+		})
+		dataSize, err := importCSV(ctx, p, tableDesc, readSpec, writeSpec, &jobLogger)
+		finishJob(ctx, &jobLogger, err)
+		if err != nil {
+			return nil, err
+		}
+		ret := []parser.Datums{{
+			parser.NewDInt(parser.DInt(*jobLogger.JobID())),
+			parser.NewDString(string(sql.JobStatusSucceeded)),
+			parser.NewDFloat(parser.DFloat(1.0)),
+			parser.NewDInt(parser.DInt(dataSize)),
+		}}
+		return ret, nil
+	}
+	return fn, header, nil
+}
+
+// csvConversionProgressFraction is the part of the progress of an IMPORT job
+// that is made by converting its CSV files into SSTables; ingesting the
+// SSTables makes the rest.
+const csvConversionProgressFraction = 0.5
+
+// importCSV converts the CSV files into SSTables in the temporary storage
+// location, ingests them into the ranges of the new table and finally writes
+// its descriptor.
+func importCSV(
+	ctx context.Context,
+	p sql.PlanHookState,
+	tableDesc *sqlbase.TableDescriptor,
+	readSpec distsqlrun.ReadCSVSpec,
+	writeSpec distsqlrun.SSTWriterSpec,
+	jobLogger *sql.JobLogger,
+) (int64, error) {
+	db := *p.ExecCfg().DB
+
+	if err := jobLogger.Created(ctx); err != nil {
+		return 0, err
+	}
+	if err := jobLogger.Started(ctx); err != nil {
+		return 0, err
+	}
+
+	walltime := p.ExecCfg().Clock.Now().WallTime
+	readSpec.Walltime = walltime
+	writeSpec.Walltime = walltime
+
+	// The conversion is reported in files read. It is retried if its
+	// transaction is, which reads the files again, so each file only counts
+	// once.
+	conversionLogger := jobProgressLogger{
+		jobLogger:    jobLogger,
+		totalChunks:  len(readSpec.URI),
+		contribution: csvConversionProgressFraction,
+	}
+	filesRead := make(map[string]struct{}, len(readSpec.URI))
+	progressFn := func(progress distsqlrun.ReadCSVProgress) error {
+		if _, ok := filesRead[progress.URI]; ok {
+			return nil
+		}
+		filesRead[progress.URI] = struct{}{}
+		return conversionLogger.chunkFinished(ctx)
+	}
+	rows, err := sql.LoadCSV(ctx, p, readSpec, writeSpec, progressFn)
+	if err != nil {
+		return 0, err
+	}
+
+	tempConf, err := storageccl.ExportStorageConfFromURI(writeSpec.Destination)
+	if err != nil {
+		return 0, err
+	}
+	var files []BackupDescriptor_File
+	for _, row := range rows {
+		files = append(files, BackupDescriptor_File{
+			Path: string(*row[0].(*parser.DString)),
+			Span: roachpb.Span{
+				Key:    roachpb.Key(*row[2].(*parser.DBytes)),
+				EndKey: roachpb.Key(*row[3].(*parser.DBytes)),
+			},
+		})
+	}
+	tables := []*sqlbase.TableDescriptor{tableDesc}
+	importRequests := makeCSVImportRequests(spansForAllTableIndexes(tables), tempConf, files)
+
+	// The data is already in the keyspace of the new table, so the key rewriter
+	// maps the table to itself.
+	descBytes, err := sqlbase.WrapDescriptor(tableDesc).Marshal()
+	if err != nil {
+		return 0, errors.Wrap(err, "marshalling descriptor")
+	}
+	rekeys := []roachpb.ImportRequest_TableRekey{{
+		OldID:   uint32(tableDesc.ID),
+		NewDesc: descBytes,
+	}}
+	kr, err := storageccl.MakeKeyRewriter(rekeys)
+	if err != nil {
+		return 0, err
+	}
+
+	splitKeys := make([]roachpb.Key, len(importRequests))
+	for i, r := range importRequests {
+		splitKeys[i] = r.Key
+	}
+	if err := presplitRanges(ctx, db, splitKeys); err != nil {
+		return 0, errors.Wrapf(err, "presplitting %d ranges", len(importRequests))
+	}
+	if err := scatterSpans(ctx, db, spansForAllTableIndexes(tables)); err != nil {
+		return 0, errors.Wrapf(err, "scattering %d ranges", len(importRequests))
+	}
+
+	dataSize, err := restore(
+		ctx, p, importRequests, hlc.Timestamp{}, nil /* encryptionKey */, tables, kr, rekeys,
+		nil /* completed */, jobLogger, csvConversionProgressFraction,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	// The SSTables are no longer needed once the table is visible.
+	tempStore, err := storageccl.MakeExportStorage(ctx, tempConf)
+	if err != nil {
+		log.Warningf(ctx, "could not clean up temporary files: %+v", err)
+		return dataSize, nil
+	}
+	defer tempStore.Close()
+	for _, f := range files {
+		if err := tempStore.Delete(ctx, f.Path); err != nil {
+			log.Warningf(ctx, "could not delete temporary file %s: %+v", f.Path, err)
+		}
+	}
+	return dataSize, nil
+}
+
+// makeCSVImportRequests groups the SSTables written by an IMPORT into import
+// requests with disjoint spans. The SSTables written by different nodes
+// overlap (but never contain the same key), so the requests are computed with
+// OverlapCoveringMerge, as in makeImportRequests.
+func makeCSVImportRequests(
+	tableSpans []roachpb.Span, dir roachpb.ExportStorage, files []BackupDescriptor_File,
+) []importEntry {
+	var tableSpanCovering intervalccl.Covering
+	for _, span := range tableSpans {
+		tableSpanCovering = append(tableSpanCovering, intervalccl.Range{
+			Start:   span.Key,
+			End:     span.EndKey,
+			Payload: importEntry{Span: span, entryType: tableSpan},
+		})
+	}
+	coverings := []intervalccl.Covering{tableSpanCovering}
+
+	// Each covering must not overlap itself, so pack the files into as few
+	// coverings as possible; the files of a node don't overlap each other.
+	sort.Sort(backupFileDescriptors(files))
+	var ends [][]byte
+	for _, f := range files {
+		r := intervalccl.Range{
+			Start:   f.Span.Key,
+			End:     f.Span.EndKey,
+			Payload: importEntry{Span: f.Span, entryType: backupFile, dir: dir, file: f},
+		}
+		i := 0
+		for ; i < len(ends); i++ {
+			if bytes.Compare(ends[i], f.Span.Key) <= 0 {
+				break
+			}
+		}
+		if i == len(ends) {
+			ends = append(ends, nil)
+			coverings = append(coverings, nil)
+		}
+		ends[i] = f.Span.EndKey
+		coverings[i+1] = append(coverings[i+1], r)
+	}
+
+	var requestEntries []importEntry
+	for _, importRange := range intervalccl.OverlapCoveringMerge(coverings) {
+		needed := false
+		var files []roachpb.ImportRequest_File
+		for _, p := range importRange.Payload.([]interface{}) {
+			ie := p.(importEntry)
+			switch ie.entryType {
+			case tableSpan:
+				needed = true
+			case backupFile:
+				files = append(files, roachpb.ImportRequest_File{Dir: ie.dir, Path: ie.file.Path})
+			}
+		}
+		if needed && len(files) > 0 {
+			requestEntries = append(requestEntries, importEntry{
+				Span:      roachpb.Span{Key: importRange.Start, EndKey: importRange.End},
+				entryType: request,
+				files:     files,
+			})
+		}
+	}
+	return requestEntries
+}
+
+// readCSVProcessor is the implementation of the ReadCSV processor, which
+// converts the rows of CSV files into the KVs of a table.
+type readCSVProcessor struct {
+	flowCtx *distsqlrun.FlowCtx
+	spec    distsqlrun.ReadCSVSpec
+	output  distsqlrun.RowReceiver
+}
+
+var _ distsqlrun.Processor = &readCSVProcessor{}
+
+func newReadCSVProcessor(
+	flowCtx *distsqlrun.FlowCtx, spec distsqlrun.ReadCSVSpec, output distsqlrun.RowReceiver,
+) (distsqlrun.Processor, error) {
+	return &readCSVProcessor{flowCtx: flowCtx, spec: spec, output: output}, nil
+}
+
+// Run is part of the Processor interface.
+func (cp *readCSVProcessor) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx, span := tracing.ChildSpan(ctx, "readCSV")
+	defer tracing.FinishSpan(span)
+
+	err := cp.readFiles(ctx)
+	distsqlrun.DrainAndClose(ctx, cp.output, err)
+}
+
+// readFiles converts the rows of the files into KVs and pushes them to the
+// output. It stops early, without error, if the consumer doesn't need more
+// rows.
+func (cp *readCSVProcessor) readFiles(ctx context.Context) error {
+	tableDesc := &cp.spec.TableDesc
+	evalCtx := *cp.flowCtx.EvalCtx()
+	curTime := time.Unix(0, cp.spec.Walltime).UTC()
+	evalCtx.SetTxnTimestamp(curTime)
+	evalCtx.SetStmtTimestamp(curTime)
+
+	csvCols := importCSVColumns(tableDesc)
+	parse := parser.Parser{}
+	cols, defaultExprs, err := sql.ProcessDefaultColumns(csvCols, tableDesc, &parse, &evalCtx)
+	if err != nil {
+		return errors.Wrap(err, "process default columns")
+	}
+	ri, err := sqlbase.MakeRowInserter(nil /* txn */, tableDesc, nil /* fkTables */, cols, sqlbase.SkipFKs)
+	if err != nil {
+		return errors.Wrap(err, "make row inserter")
+	}
+
+	var alloc sqlbase.EncDatumRowAlloc
+	status := distsqlrun.NeedMoreRows
+	b := inserter(func(kv roachpb.KeyValue) {
+		if status != distsqlrun.NeedMoreRows {
+			return
+		}
+		row := alloc.AllocRow(2)
+		row[0] = sqlbase.DatumToEncDatum(
+			sqlbase.ColumnType{Kind: sqlbase.ColumnType_BYTES}, parser.NewDBytes(parser.DBytes(kv.Key)),
+		)
+		row[1] = sqlbase.DatumToEncDatum(
+			sqlbase.ColumnType{Kind: sqlbase.ColumnType_BYTES}, parser.NewDBytes(parser.DBytes(kv.Value.RawBytes)),
+		)
+		status = cp.output.Push(row, distsqlrun.ProducerMetadata{})
+	})
+
+	for _, uri := range cp.spec.URI {
+		if err := func() error {
+			store, name, err := exportStorageForFile(ctx, uri)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			f, err := store.ReadFile(ctx, name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			r := csv.NewReader(f)
+			if cp.spec.Comma != 0 {
+				r.Comma = cp.spec.Comma
+			}
+			r.Comment = cp.spec.Comment
+			r.FieldsPerRecord = len(csvCols)
+			for i := 1; status == distsqlrun.NeedMoreRows; i++ {
+				record, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return errors.Wrapf(err, "%s: row %d", uri, i)
+				}
+				if i <= int(cp.spec.Skip) {
+					continue
+				}
+				row := make(parser.Datums, len(record))
+				for j, field := range record {
+					if cp.spec.Nullif != nil && field == *cp.spec.Nullif {
+						row[j] = parser.DNull
+						continue
+					}
+					row[j], err = parseCSVDatum(csvCols[j].Type.ToDatumType(), field)
+					if err != nil {
+						return errors.Wrapf(err, "%s: row %d: parse %q as %s",
+							uri, i, csvCols[j].Name, csvCols[j].Type.SQLString())
+					}
+				}
+				row, err = sql.GenerateInsertRow(defaultExprs, ri.InsertColIDtoRowIndex, cols, evalCtx, tableDesc, row)
+				if err != nil {
+					return errors.Wrapf(err, "%s: row %d", uri, i)
+				}
+				if err := ri.InsertRow(ctx, b, row, true /* ignoreConflicts */); err != nil {
+					return errors.Wrapf(err, "%s: row %d", uri, i)
+				}
+			}
+			return nil
+		}(); err != nil {
+			return err
+		}
+		if status == distsqlrun.NeedMoreRows {
+			status = cp.output.Push(nil /* row */, distsqlrun.ProducerMetadata{
+				ReadCSVProgress: &distsqlrun.ReadCSVProgress{URI: uri},
+			})
+		}
+		if status != distsqlrun.NeedMoreRows {
+			return nil
+		}
+	}
+	return nil
+}
+
+// sstWriterProcessor is the implementation of the SSTWriter processor, which
+// writes sorted KVs into SSTables.
+type sstWriterProcessor struct {
+	flowCtx *distsqlrun.FlowCtx
+	spec    distsqlrun.SSTWriterSpec
+	input   distsqlrun.RowSource
+	output  distsqlrun.RowReceiver
+}
+
+var _ distsqlrun.Processor = &sstWriterProcessor{}
+
+func newSSTWriterProcessor(
+	flowCtx *distsqlrun.FlowCtx,
+	spec distsqlrun.SSTWriterSpec,
+	input distsqlrun.RowSource,
+	output distsqlrun.RowReceiver,
+) (distsqlrun.Processor, error) {
+	return &sstWriterProcessor{flowCtx: flowCtx, spec: spec, input: input, output: output}, nil
+}
+
+// Run is part of the Processor interface.
+func (sp *sstWriterProcessor) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx, span := tracing.ChildSpan(ctx, "sstWriter")
+	defer tracing.FinishSpan(span)
+
+	err := sp.writeSSTs(ctx)
+	distsqlrun.DrainAndClose(ctx, sp.output, err, sp.input)
+}
+
+// writeSSTs writes the KVs of the input into SSTables of about
+// spec.SplitSize bytes and pushes a row describing each of them to the
+// output. It stops early, without error, if the consumer doesn't need more
+// rows.
+func (sp *sstWriterProcessor) writeSSTs(ctx context.Context) error {
+	conf, err := storageccl.ExportStorageConfFromURI(sp.spec.Destination)
+	if err != nil {
+		return err
+	}
+	store, err := storageccl.MakeExportStorage(ctx, conf)
+	if err != nil {
+		return errors.Wrap(err, "export storage from URI")
+	}
+	defer store.Close()
+
+	ts := hlc.Timestamp{WallTime: sp.spec.Walltime}
+	var kvs []engine.MVCCKeyValue
+	var kvBytes int64
+	status := distsqlrun.NeedMoreRows
+	flush := func() error {
+		if len(kvs) == 0 {
+			return nil
+		}
+		name := fmt.Sprintf("%d.sst", parser.GenerateUniqueInt(sp.flowCtx.NodeID()))
+		size, err := writeImportSST(ctx, store, name, kvs)
+		if err != nil {
+			return errors.Wrap(err, "writeSST")
+		}
+		row := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(
+				sqlbase.ColumnType{Kind: sqlbase.ColumnType_STRING}, parser.NewDString(name),
+			),
+			sqlbase.DatumToEncDatum(
+				sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}, parser.NewDInt(parser.DInt(size)),
+			),
+			sqlbase.DatumToEncDatum(
+				sqlbase.ColumnType{Kind: sqlbase.ColumnType_BYTES},
+				parser.NewDBytes(parser.DBytes(kvs[0].Key.Key)),
+			),
+			sqlbase.DatumToEncDatum(
+				sqlbase.ColumnType{Kind: sqlbase.ColumnType_BYTES},
+				// The end key is exclusive.
+				parser.NewDBytes(parser.DBytes(kvs[len(kvs)-1].Key.Key.Next())),
+			),
+		}
+		status = sp.output.Push(row, distsqlrun.ProducerMetadata{})
+		kvs = nil
+		kvBytes = 0
+		return nil
+	}
+
+	var alloc sqlbase.DatumAlloc
+	var prevKey roachpb.Key
+	for status == distsqlrun.NeedMoreRows {
+		row, meta := sp.input.Next()
+		if !meta.Empty() {
+			if meta.Err != nil {
+				return meta.Err
+			}
+			status = sp.output.Push(nil /* row */, meta)
+			continue
+		}
+		if row == nil {
+			return flush()
+		}
+		if err := row[0].EnsureDecoded(&alloc); err != nil {
+			return err
+		}
+		if err := row[1].EnsureDecoded(&alloc); err != nil {
+			return err
+		}
+		key := roachpb.Key(*row[0].Datum.(*parser.DBytes))
+		value := []byte(*row[1].Datum.(*parser.DBytes))
+		if prevKey.Equal(key) {
+			return errors.Errorf("duplicate key: %s", key)
+		}
+		prevKey = key
+		kvs = append(kvs, engine.MVCCKeyValue{
+			Key:   engine.MVCCKey{Key: key, Timestamp: ts},
+			Value: value,
+		})
+		kvBytes += int64(len(key) + len(value))
+		if kvBytes >= sp.spec.SplitSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeImportSST writes the given sorted KVs into an SSTable with the given
+// name and returns its size.
+func writeImportSST(
+	ctx context.Context, store storageccl.ExportStorage, name string, kvs []engine.MVCCKeyValue,
+) (int64, error) {
+	sstFile, err := storageccl.MakeExportFileTmpWriter(ctx, os.TempDir(), store, name)
+	if err != nil {
+		return 0, err
+	}
+	defer sstFile.Close(ctx)
+
+	sst := engine.MakeRocksDBSstFileWriter()
+	if err := sst.Open(sstFile.LocalFile()); err != nil {
+		return 0, err
+	}
+	for _, kv := range kvs {
+		if err := sst.Add(kv); err != nil {
+			return 0, err
+		}
+	}
+	if err := sst.Close(); err != nil {
+		return 0, err
+	}
+	if err := sstFile.Finish(ctx); err != nil {
+		return 0, err
+	}
+	return sst.DataSize, nil
+}
+
+const (
+	exportOptionDelimiter = "delimiter"
+	exportOptionFileSize  = "filesize"
+	exportOptionNullAs    = "nullas"
+
+	// exportFilePatternPart is replaced in the names of the files written by
+	// EXPORT by a string that is unique to each file.
+	exportFilePatternPart    = "%part%"
+	exportFilePatternDefault = exportFilePatternPart + ".csv"
+
+	// exportFileSizeDefault is the size after which EXPORT starts a new file.
+	// Each file is buffered in memory before it is written.
+	exportFileSizeDefault = 32 << 20
+	// exportNullAsDefault is the encoding of NULL values. It is the default of
+	// PostgreSQL's COPY and, unlike an empty field, cannot be mistaken for an
+	// empty string.
+	exportNullAsDefault = `\N`
+)
+
+var exportOptionExpectValues = map[string]bool{
+	exportOptionDelimiter: true,
+	exportOptionFileSize:  true,
+	exportOptionNullAs:    true,
+}
+
+func exportPlanHook(
+	baseCtx context.Context, stmt parser.Statement, p sql.PlanHookState,
+) (func() ([]parser.Datums, error), sqlbase.ResultColumns, error) {
+	exportStmt, ok := stmt.(*parser.Export)
+	if !ok {
+		return nil, nil, nil
+	}
+	if err := utilccl.CheckEnterpriseEnabled("EXPORT"); err != nil {
+		return nil, nil, err
+	}
+
+	if err := p.RequireSuperUser("EXPORT"); err != nil {
+		return nil, nil, err
+	}
+
+	if exportStmt.FileFormat != "CSV" {
+		return nil, nil, errors.Errorf("unsupported export format: %q", exportStmt.FileFormat)
+	}
+
+	fileFn, err := p.TypeAsString(exportStmt.File, "EXPORT")
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := make(map[string]string, len(exportStmt.Options))
+	for _, opt := range exportStmt.Options {
+		if !exportOptionExpectValues[opt.Key] {
+			return nil, nil, errors.Errorf("invalid option %q", opt.Key)
+		}
+		opts[opt.Key] = opt.Value
+	}
+
+	header := sqlbase.ResultColumns{
+		{Name: "filename", Typ: parser.TypeString},
+		{Name: "rows", Typ: parser.TypeInt},
+		{Name: "bytes", Typ: parser.TypeInt},
+	}
+	fn := func() ([]parser.Datums, error) {
+		// TODO(dan): Move this span into sql.
+		ctx, span := tracing.ChildSpan(baseCtx, stmt.StatementTag())
+		defer tracing.FinishSpan(span)
+
+		file, err := fileFn()
+		if err != nil {
+			return nil, err
+		}
+		// Check the destination before running the query.
+		if _, err := storageccl.ExportStorageConfFromURI(file); err != nil {
+			return nil, err
+		}
+
+		writeSpec := distsqlrun.CSVWriterSpec{
+			Destination:  file,
+			NamePattern:  exportFilePatternDefault,
+			NullEncoding: exportNullAsDefault,
+			FileSize:     exportFileSizeDefault,
+		}
+		if override, ok := opts[exportOptionDelimiter]; ok {
+			delimiter, err := parseRuneOption(exportOptionDelimiter, override)
+			if err != nil {
+				return nil, err
+			}
+			writeSpec.Delimiter = delimiter
+		}
+		if override, ok := opts[exportOptionNullAs]; ok {
+			writeSpec.NullEncoding = override
+		}
+		if override, ok := opts[exportOptionFileSize]; ok {
+			fileSize, err := strconv.ParseInt(override, 10, 64)
+			if err != nil || fileSize <= 0 {
+				return nil, errors.Errorf("invalid %s value: %q", exportOptionFileSize, override)
+			}
+			writeSpec.FileSize = fileSize
+		}
+
+		return sql.ExportCSV(ctx, p, exportStmt.Query, writeSpec)
+	}
+	return fn, header, nil
+}
+
+// csvWriter is the implementation of the CSVWriter processor, which writes
+// its input rows to CSV files.
+type csvWriter struct {
+	flowCtx *distsqlrun.FlowCtx
+	spec    distsqlrun.CSVWriterSpec
+	input   distsqlrun.RowSource
+	output  distsqlrun.RowReceiver
+}
+
+var _ distsqlrun.Processor = &csvWriter{}
+
+func newCSVWriterProcessor(
+	flowCtx *distsqlrun.FlowCtx,
+	spec distsqlrun.CSVWriterSpec,
+	input distsqlrun.RowSource,
+	output distsqlrun.RowReceiver,
+) (distsqlrun.Processor, error) {
+	return &csvWriter{flowCtx: flowCtx, spec: spec, input: input, output: output}, nil
+}
+
+// Run is part of the Processor interface.
+func (sp *csvWriter) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx, span := tracing.ChildSpan(ctx, "csvWriter")
+	defer tracing.FinishSpan(span)
+
+	err := sp.writeFiles(ctx)
+	distsqlrun.DrainAndClose(ctx, sp.output, err, sp.input)
+}
+
+// writeFiles writes the input rows into files of about spec.FileSize bytes
+// and pushes a row describing each of them to the output. No file is written
+// if there are no input rows. It stops early, without error, if the consumer
+// doesn't need more rows. The file being written is buffered in memory, which
+// is accounted for against the flow's memory monitor.
+func (sp *csvWriter) writeFiles(ctx context.Context) error {
+	conf, err := storageccl.ExportStorageConfFromURI(sp.spec.Destination)
+	if err != nil {
+		return err
+	}
+	store, err := storageccl.MakeExportStorage(ctx, conf)
+	if err != nil {
+		return errors.Wrap(err, "export storage from URI")
+	}
+	defer store.Close()
+
+	// All the files written by this processor share a prefix that is unique
+	// across the cluster.
+	prefix := parser.GenerateUniqueInt(sp.flowCtx.NodeID())
+	var buf bytes.Buffer
+	acc := sp.flowCtx.EvalCtx().Mon.MakeBoundAccount()
+	defer acc.Close(ctx)
+	var bufAccounted int64
+	writer := csv.NewWriter(&buf)
+	if sp.spec.Delimiter != 0 {
+		writer.Comma = sp.spec.Delimiter
+	}
+	record := make([]string, len(sp.input.Types()))
+	var rows int64
+	var chunk int
+	status := distsqlrun.NeedMoreRows
+	flush := func() error {
+		if rows == 0 {
+			return nil
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+		part := fmt.Sprintf("%d.%d", prefix, chunk)
+		name := strings.Replace(sp.spec.NamePattern, exportFilePatternPart, part, 1)
+		size := buf.Len()
+		if err := store.WriteFile(ctx, name, bytes.NewReader(buf.Bytes())); err != nil {
+			return errors.Wrapf(err, "writing %s", name)
+		}
+		row := sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(
+				sqlbase.ColumnType{Kind: sqlbase.ColumnType_STRING}, parser.NewDString(name),
+			),
+			sqlbase.DatumToEncDatum(
+				sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}, parser.NewDInt(parser.DInt(rows)),
+			),
+			sqlbase.DatumToEncDatum(
+				sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}, parser.NewDInt(parser.DInt(size)),
+			),
+		}
+		status = sp.output.Push(row, distsqlrun.ProducerMetadata{})
+		buf.Reset()
+		rows = 0
+		chunk++
+		return nil
+	}
+
+	var alloc sqlbase.DatumAlloc
+	for status == distsqlrun.NeedMoreRows {
+		row, meta := sp.input.Next()
+		if !meta.Empty() {
+			if meta.Err != nil {
+				return meta.Err
+			}
+			status = sp.output.Push(nil /* row */, meta)
+			continue
+		}
+		if row == nil {
+			return flush()
+		}
+		for i := range row {
+			if err := row[i].EnsureDecoded(&alloc); err != nil {
+				return err
+			}
+			record[i] = csvFormatDatum(row[i].Datum, sp.spec.NullEncoding)
+			if row[i].Datum != parser.DNull && record[i] == sp.spec.NullEncoding {
+				return errors.Errorf(
+					"column %d: value %q cannot be distinguished from NULL; use the %q option to encode NULL differently",
+					i+1, record[i], exportOptionNullAs)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		rows++
+		// The writer buffers its output, so flush it to check the size.
+		writer.Flush()
+		if size := int64(buf.Cap()); size != bufAccounted {
+			if err := acc.ResizeItem(ctx, bufAccounted, size); err != nil {
+				return err
+			}
+			bufAccounted = size
+		}
+		if sp.spec.FileSize > 0 && int64(buf.Len()) >= sp.spec.FileSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// csvFormatDatum returns the representation of d in a CSV file written by
+// EXPORT.
+func csvFormatDatum(d parser.Datum, nullEncoding string) string {
+	if d == parser.DNull {
+		return nullEncoding
+	}
+	switch t := d.(type) {
+	case *parser.DString:
+		return string(*t)
+	case *parser.DCollatedString:
+		return t.Contents
+	default:
+		return parser.AsStringWithFlags(d, parser.FmtBareStrings)
+	}
+}
+
+func init() {
+	sql.AddPlanHook(importPlanHook)
+	distsqlrun.NewReadCSVProcessor = newReadCSVProcessor
+	distsqlrun.NewSSTWriterProcessor = newSSTWriterProcessor
+
+	sql.AddPlanHook(exportPlanHook)
+	distsqlrun.NewCSVWriterProcessor = newCSVWriterProcessor
+}
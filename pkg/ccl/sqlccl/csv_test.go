@@ -0,0 +1,213 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package sqlccl
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// writeCSVFiles writes numFiles CSV files of rowsPerFile rows of (a INT, b
+// STRING) in dir, with the given header line if it is not empty, and returns
+// their nodelocal URIs.
+func writeCSVFiles(t *testing.T, dir string, numFiles, rowsPerFile int, header string) []string {
+	var uris []string
+	for f := 0; f < numFiles; f++ {
+		var buf bytes.Buffer
+		if header != "" {
+			buf.WriteString(header + "\n")
+		}
+		for i := 0; i < rowsPerFile; i++ {
+			// Interleave the rows of the files to get overlapping SSTables.
+			a := i*numFiles + f
+			if a%10 == 3 {
+				fmt.Fprintf(&buf, "%d,NULL\n", a)
+			} else {
+				fmt.Fprintf(&buf, "%d,%s\n", a, strings.Repeat("x", a%7))
+			}
+		}
+		path := filepath.Join(dir, fmt.Sprintf("data-%d.csv", f))
+		if err := ioutil.WriteFile(path, buf.Bytes(), 0666); err != nil {
+			t.Fatal(err)
+		}
+		uris = append(uris, "nodelocal://"+path)
+	}
+	return uris
+}
+
+func TestImportCSV(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numFiles, rowsPerFile = 3, 1000
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, multiNode, 0)
+	defer cleanupFn()
+	rawDir := strings.TrimPrefix(dir, "nodelocal://")
+
+	uris := writeCSVFiles(t, rawDir, numFiles, rowsPerFile, "a,b")
+	files := make([]string, len(uris))
+	for i, uri := range uris {
+		files[i] = fmt.Sprintf("'%s'", uri)
+	}
+	schema := filepath.Join(rawDir, "schema.sql")
+	if err := ioutil.WriteFile(
+		schema, []byte(`CREATE TABLE bench.t2 (a INT PRIMARY KEY, b STRING, INDEX (b))`), 0666,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range []struct {
+		name  string
+		table string
+		query string
+	}{
+		{"defs", "bench.t1", `IMPORT TABLE bench.t1 (a INT PRIMARY KEY, b STRING, INDEX (b)) CSV DATA (%s)`},
+		{"schema file", "bench.t2", `IMPORT TABLE bench.t2 CREATE USING 'nodelocal://` + schema + `' CSV DATA (%s)`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query := fmt.Sprintf(tc.query, strings.Join(files, ", ")) +
+				fmt.Sprintf(` WITH OPTIONS ('temp'='%s/temp%d', 'skip'='1', 'nullif'='NULL', 'sstsize'='4096')`, dir, i)
+			if err := verifyBackupRestoreStatementResult(sqlDB, query); err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			var count, nulls, length int
+			sqlDB.QueryRow(
+				fmt.Sprintf(`SELECT COUNT(*), COUNT(*) - COUNT(b), SUM(LENGTH(b)) FROM %s`, tc.table),
+			).Scan(&count, &nulls, &length)
+			if expected := numFiles * rowsPerFile; count != expected {
+				t.Fatalf("expected %d rows, got %d", expected, count)
+			}
+			expectedNulls, expectedLength := 0, 0
+			for a := 0; a < numFiles*rowsPerFile; a++ {
+				if a%10 == 3 {
+					expectedNulls++
+				} else {
+					expectedLength += a % 7
+				}
+			}
+			if nulls != expectedNulls {
+				t.Fatalf("expected %d NULLs, got %d", expectedNulls, nulls)
+			}
+			if length != expectedLength {
+				t.Fatalf("expected total length %d, got %d", expectedLength, length)
+			}
+
+			// The secondary index must have been imported too.
+			sqlDB.QueryRow(
+				fmt.Sprintf(`SELECT COUNT(*) FROM %s@%s_b_idx WHERE b = 'xxx'`, tc.table, strings.TrimPrefix(tc.table, "bench.")),
+			).Scan(&count)
+			if count == 0 {
+				t.Fatal("expected rows in the secondary index")
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			query string
+			err   string
+		}{
+			{`IMPORT TABLE bench.t1 (a INT PRIMARY KEY) CSV DATA ('%[1]s/a.csv') WITH OPTIONS ('temp'='%[1]s')`,
+				`relation "bench.t1" already exists`},
+			{`IMPORT TABLE bench.e (a INT PRIMARY KEY) CSV DATA ('%[1]s/a.csv')`,
+				`must provide a temporary storage location`},
+			{`IMPORT TABLE bench.e (a INT PRIMARY KEY) CSV DATA ('%[1]s/a.csv') WITH OPTIONS ('temp'='%[1]s', 'foo'='bar')`,
+				`invalid option "foo"`},
+			{`IMPORT TABLE bench.e (a INT PRIMARY KEY) CSV DATA ('%[1]s/a.csv') WITH OPTIONS ('temp'='%[1]s', 'comma'='ab')`,
+				`comma must be a single character`},
+			{`IMPORT TABLE bench.e (a INT PRIMARY KEY, b STRING) CSV DATA (` + files[0] + `) WITH OPTIONS ('temp'='%[1]s')`,
+				`parse "a" as INT`},
+		} {
+			if _, err := sqlDB.DB.Exec(fmt.Sprintf(tc.query, dir)); !testutils.IsError(err, tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.query, tc.err, err)
+			}
+		}
+	})
+}
+
+func TestExportCSV(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const numAccounts = 1000
+	_, dir, _, sqlDB, cleanupFn := backupRestoreTestSetup(t, multiNode, numAccounts)
+	defer cleanupFn()
+	sqlDB.Exec(`UPDATE bench.bank SET payload = NULL WHERE id % 10 = 3`)
+
+	rows := sqlDB.Query(fmt.Sprintf(
+		`EXPORT INTO CSV '%s/export' WITH OPTIONS ('filesize'='4096') FROM SELECT * FROM bench.bank`,
+		dir,
+	))
+	defer rows.Close()
+	var files []string
+	var totalRows int
+	for rows.Next() {
+		var name string
+		var numRows, size int
+		if err := rows.Scan(&name, &numRows, &size); err != nil {
+			t.Fatal(err)
+		}
+		if size <= 0 {
+			t.Errorf("%s: expected a positive size, got %d", name, size)
+		}
+		files = append(files, fmt.Sprintf("'%s/export/%s'", dir, name))
+		totalRows += numRows
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if totalRows != numAccounts {
+		t.Fatalf("expected %d rows, got %d", numAccounts, totalRows)
+	}
+	if len(files) < 2 {
+		t.Fatalf("expected at least 2 files, got %d", len(files))
+	}
+
+	// Import the files back and compare with the original table. NULL values
+	// are exported as \N by default.
+	sqlDB.Exec(`IMPORT TABLE bench.bank2 (id INT PRIMARY KEY, balance INT, payload STRING) CSV DATA (` +
+		strings.Join(files, ", ") + fmt.Sprintf(`) WITH OPTIONS ('temp'='%s/temp', 'nullif'='\N')`, dir))
+	var diff int
+	sqlDB.QueryRow(`SELECT COUNT(*) FROM (
+		(SELECT * FROM bench.bank2 EXCEPT SELECT * FROM bench.bank)
+		UNION ALL
+		(SELECT * FROM bench.bank EXCEPT SELECT * FROM bench.bank2)
+	) AS d`).Scan(&diff)
+	if diff != 0 {
+		t.Fatalf("expected the imported rows to match the exported ones, got %d different rows", diff)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			query string
+			err   string
+		}{
+			{`EXPORT INTO CSV 'nope://x' FROM SELECT * FROM bench.bank`,
+				`unsupported storage scheme`},
+			{`EXPORT INTO CSV '%[1]s' WITH OPTIONS ('foo'='bar') FROM SELECT * FROM bench.bank`,
+				`invalid option "foo"`},
+			{`EXPORT INTO CSV '%[1]s' WITH OPTIONS ('delimiter'='ab') FROM SELECT * FROM bench.bank`,
+				`delimiter must be a single character`},
+			{`EXPORT INTO CSV '%[1]s' WITH OPTIONS ('filesize'='-1') FROM SELECT * FROM bench.bank`,
+				`invalid filesize value`},
+			{`EXPORT INTO CSV '%[1]s' WITH OPTIONS ('nullas'='') FROM SELECT ''`,
+				`column 1: value "" cannot be distinguished from NULL`},
+		} {
+			if _, err := sqlDB.DB.Exec(fmt.Sprintf(tc.query, dir)); !testutils.IsError(err, tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.query, tc.err, err)
+			}
+		}
+	})
+}
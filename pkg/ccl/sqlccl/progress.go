@@ -11,9 +11,11 @@ package sqlccl
 import (
 	"time"
 
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
@@ -34,6 +36,15 @@ type jobProgressLogger struct {
 	// These fields must be externally initialized.
 	jobLogger   *sql.JobLogger
 	totalChunks int
+	// The chunks account for the progress of the job from startFraction to
+	// startFraction+contribution, which allows a job made of several phases to
+	// report the progress of each of them. A zero contribution means that the
+	// chunks account for the rest of the job.
+	startFraction float32
+	contribution  float32
+	// checkpointFn, if set, is used to record the progress of the job in its
+	// details whenever the progress is reported.
+	checkpointFn sql.JobCheckpointFn
 
 	// The remaining fields are for internal use only.
 	mu struct {
@@ -44,10 +55,15 @@ type jobProgressLogger struct {
 	}
 }
 
+// chunkFinished records that one more chunk of work is done and reports the
+// progress if enough time or work has passed since the last report. Errors
+// while reporting progress are not important enough to merit failing the job
+// and are only logged, unless the job was paused or canceled, in which case
+// the error is returned and the caller should stop.
 func (jpl *jobProgressLogger) chunkFinished(ctx context.Context) error {
 	jpl.mu.Lock()
 	jpl.mu.completedChunks++
-	fraction := float32(jpl.mu.completedChunks) / float32(jpl.totalChunks)
+	fraction := jpl.fractionLocked()
 	shouldLogProgress := fraction-jpl.mu.lastReportedFraction > progressFractionThreshold ||
 		jpl.mu.lastReportedAt.Add(progressTimeThreshold).Before(timeutil.Now())
 	if shouldLogProgress {
@@ -57,7 +73,69 @@ func (jpl *jobProgressLogger) chunkFinished(ctx context.Context) error {
 	jpl.mu.Unlock()
 
 	if shouldLogProgress {
-		return jpl.jobLogger.Progressed(ctx, fraction)
+		return jpl.progressed(ctx, fraction)
 	}
 	return nil
 }
+
+// checkpoint unconditionally reports the progress of the job, with the same
+// error handling as chunkFinished. It is used before steps that cannot be
+// undone, both to make sure the job was not paused or canceled in the meantime
+// and to record a final checkpoint if it was paused.
+func (jpl *jobProgressLogger) checkpoint(ctx context.Context) error {
+	jpl.mu.Lock()
+	fraction := jpl.fractionLocked()
+	jpl.mu.Unlock()
+	return jpl.progressed(ctx, fraction)
+}
+
+// fractionLocked returns the fraction of the job completed after the chunks
+// completed so far. jpl.mu must be held.
+func (jpl *jobProgressLogger) fractionLocked() float32 {
+	if jpl.totalChunks == 0 {
+		return jpl.startFraction
+	}
+	contribution := jpl.contribution
+	if contribution == 0 {
+		contribution = 1 - jpl.startFraction
+	}
+	return jpl.startFraction +
+		contribution*float32(jpl.mu.completedChunks)/float32(jpl.totalChunks)
+}
+
+func (jpl *jobProgressLogger) progressed(ctx context.Context, fraction float32) error {
+	err := jpl.jobLogger.Progressed(ctx, fraction, jpl.checkpointFn)
+	if isJobInterrupted(err) {
+		return err
+	}
+	if err != nil {
+		log.Errorf(ctx, "ignoring error while updating progress on job %d (%s): %+v",
+			*jpl.jobLogger.JobID(), jpl.jobLogger.Job.Description, err)
+	}
+	return nil
+}
+
+// isJobInterrupted returns whether err signals that the job was paused or
+// canceled.
+func isJobInterrupted(err error) bool {
+	cause := errors.Cause(err)
+	return cause == sql.ErrJobPaused || cause == sql.ErrJobCanceled
+}
+
+// finishJob records the outcome of running the job tracked by jobLogger,
+// which ended with err.
+func finishJob(ctx context.Context, jobLogger *sql.JobLogger, err error) {
+	if err != nil {
+		// A paused job will be resumed later, so it has not failed.
+		if errors.Cause(err) != sql.ErrJobPaused {
+			jobLogger.Failed(ctx, err)
+		}
+		return
+	}
+	if err := jobLogger.Succeeded(ctx); err != nil {
+		// An error while marking the job as successful is not important enough to
+		// merit failing the entire job.
+		log.Errorf(ctx, "ignoring error while marking job %d (%s) as successful: %+v",
+			*jobLogger.JobID(), jobLogger.Job.Description, err)
+	}
+}
@@ -9,6 +9,8 @@
 package sqlccl
 
 import (
+	"sort"
+
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 	"golang.org/x/sync/errgroup"
@@ -38,12 +40,15 @@ const (
 
 // Import loads some data in sstables into an empty range. Only the keys between
 // startKey and endKey are loaded. Every row's key is rewritten to be for
-// newTableID.
+// newTableID. If endTime is set, the data is loaded as of that time. If
+// encryptionKey is set, the files are decrypted with it.
 func Import(
 	ctx context.Context,
 	db client.DB,
 	startKey, endKey roachpb.Key,
 	files []roachpb.ImportRequest_File,
+	endTime hlc.Timestamp,
+	encryptionKey []byte,
 	kr *storageccl.KeyRewriter,
 	rekeys []roachpb.ImportRequest_TableRekey,
 ) (*roachpb.ImportResponse, error) {
@@ -76,8 +81,10 @@ func Import(
 			Key:    startKey,
 			EndKey: endKey,
 		},
-		Files:  files,
-		Rekeys: rekeys,
+		Files:         files,
+		Rekeys:        rekeys,
+		EndTime:       endTime,
+		EncryptionKey: encryptionKey,
 	}
 	res, pErr := client.SendWrapped(ctx, db.GetSender(), req)
 	if pErr != nil {
@@ -87,11 +94,13 @@ func Import(
 	return res.(*roachpb.ImportResponse), nil
 }
 
-func loadBackupDescs(ctx context.Context, uris []string) ([]BackupDescriptor, error) {
+func loadBackupDescs(
+	ctx context.Context, uris []string, encryptionKey []byte,
+) ([]BackupDescriptor, error) {
 	backupDescs := make([]BackupDescriptor, len(uris))
 
 	for i, uri := range uris {
-		desc, err := readBackupDescriptor(ctx, uri)
+		desc, err := readBackupDescriptor(ctx, uri, encryptionKey)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to read backup descriptor")
 		}
@@ -103,6 +112,83 @@ func loadBackupDescs(ctx context.Context, uris []string) ([]BackupDescriptor, er
 	return backupDescs, nil
 }
 
+// selectBackupsAsOf returns the backups, which are ordered by time, needed to
+// restore the data as of asOf, and the SQL descriptors as of that time. A zero
+// asOf selects the end time of the last backup. Restoring as of a time that is
+// not the end time of a backup requires the backup that covers it to have been
+// taken with revision history, and asOf to be after the revisions it has begin.
+func selectBackupsAsOf(
+	backups []BackupDescriptor, asOf hlc.Timestamp,
+) ([]BackupDescriptor, []sqlbase.Descriptor, error) {
+	if asOf == (hlc.Timestamp{}) {
+		return backups, backups[len(backups)-1].Descriptors, nil
+	}
+	for i, b := range backups {
+		if asOf == b.EndTime {
+			return backups[:i+1], b.Descriptors, nil
+		}
+		if !asOf.Less(b.EndTime) {
+			continue
+		}
+		if !b.RevisionHistory {
+			return nil, nil, errors.Errorf(
+				"cannot restore as of %s: the backup from %s to %s was not taken with the %q option",
+				asOf, b.StartTime, b.EndTime, backupOptRevisionHistory)
+		}
+		if !b.RevisionStartTime.Less(asOf) {
+			return nil, nil, errors.Errorf(
+				"cannot restore as of %s: the backup from %s to %s only has the revisions after %s",
+				asOf, b.StartTime, b.EndTime, b.RevisionStartTime)
+		}
+
+		// Start from the descriptors as of the end of the previous backup and
+		// replay the changes made until asOf. Only the descriptors backed up at
+		// either end of the covering backup are considered, and the ones that
+		// did not change in between are the same as at its end.
+		changed := make(map[sqlbase.ID]bool)
+		for _, change := range b.DescriptorChanges {
+			changed[change.ID] = true
+		}
+		byID := make(map[sqlbase.ID]*sqlbase.Descriptor)
+		for j := range b.Descriptors {
+			id := b.Descriptors[j].GetID()
+			if changed[id] {
+				byID[id] = nil
+			} else {
+				byID[id] = &b.Descriptors[j]
+			}
+		}
+		if i > 0 {
+			prev := backups[i-1].Descriptors
+			for j := range prev {
+				byID[prev[j].GetID()] = &prev[j]
+			}
+		}
+		for _, change := range b.DescriptorChanges {
+			if _, ok := byID[change.ID]; !ok || asOf.Less(change.Time) {
+				continue
+			}
+			// The changes are sorted by time, so the last one wins.
+			byID[change.ID] = change.Desc
+		}
+
+		ids := make([]int, 0, len(byID))
+		for id, desc := range byID {
+			if desc != nil {
+				ids = append(ids, int(id))
+			}
+		}
+		sort.Ints(ids)
+		descs := make([]sqlbase.Descriptor, len(ids))
+		for j, id := range ids {
+			descs[j] = *byID[sqlbase.ID(id)]
+		}
+		return backups[:i+1], descs, nil
+	}
+	return nil, nil, errors.Errorf(
+		"cannot restore as of %s: the last backup ends at %s", asOf, backups[len(backups)-1].EndTime)
+}
+
 func reassignParentIDs(
 	ctx context.Context,
 	txn *client.Txn,
@@ -564,6 +650,36 @@ func presplitRanges(baseCtx context.Context, db client.DB, input []roachpb.Key)
 	return g.Wait()
 }
 
+// scatterSpans scatters the ranges in the given spans, which were presplit, so
+// that the Import requests that are about to write their data are balanced
+// among many nodes. Scattering is best-effort, so errors on individual ranges
+// are only logged.
+func scatterSpans(ctx context.Context, db client.DB, spans []roachpb.Span) error {
+	g, gCtx := errgroup.WithContext(ctx)
+	for i := range spans {
+		span := spans[i]
+		g.Go(func() error {
+			req := &roachpb.AdminScatterRequest{
+				Span: roachpb.Span{Key: span.Key, EndKey: span.EndKey},
+			}
+			res, pErr := client.SendWrapped(gCtx, db.GetSender(), req)
+			if pErr != nil {
+				return pErr.GoError()
+			}
+			// Scatter is best-effort, so log why any individual ranges
+			// didn't get scattered.
+			for _, r := range res.(*roachpb.AdminScatterResponse).Ranges {
+				if r.Error != nil {
+					log.Warningf(ctx, "error scattering range [%s,%s): %+v",
+						r.Span.Key, r.Span.EndKey, r.Error.GoError())
+				}
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
 // Write the new descriptors. First the ID -> TableDescriptor for the new table,
 // then flip (or initialize) the name -> ID entry so any new queries will use
 // the new one.
@@ -593,7 +709,7 @@ func restoreTableDescs(ctx context.Context, db client.DB, tables []*sqlbase.Tabl
 func restoreJobDescription(restore *parser.Restore, from []string) (string, error) {
 	r := parser.Restore{
 		AsOf:    restore.AsOf,
-		Options: restore.Options,
+		Options: redactedOptions(restore.Options),
 		Targets: restore.Targets,
 		From:    make(parser.Exprs, len(restore.From)),
 	}
@@ -616,6 +732,7 @@ func Restore(
 	p sql.PlanHookState,
 	uris []string,
 	targets parser.TargetList,
+	endTime hlc.Timestamp,
 	opt parser.KVOptions,
 	jobLogger *sql.JobLogger,
 ) (dataSize int64, err error) {
@@ -627,18 +744,27 @@ func Restore(
 			"(but you can use 'RESTORE somedb.*' to restore all backed up tables for a given DB).")
 	}
 
-	backupDescs, err := loadBackupDescs(ctx, uris)
+	if len(uris) == 0 {
+		return 0, errors.Errorf("no backups found")
+	}
+	encryptionKey, _, err := backupEncryptionKey(ctx, opt, uris[0])
+	if err != nil {
+		return 0, err
+	}
+	backupDescs, err := loadBackupDescs(ctx, uris, encryptionKey)
+	if err != nil {
+		return 0, err
+	}
+	backupDescs, sqlDescs, err := selectBackupsAsOf(backupDescs, endTime)
 	if err != nil {
 		return 0, err
 	}
-	lastBackupDesc := backupDescs[len(backupDescs)-1]
 
 	databasesByID := make(map[sqlbase.ID]*sqlbase.DatabaseDescriptor)
 	var tables []*sqlbase.TableDescriptor
 	{
 		// TODO(dan): Plumb the session database down.
 		sessionDatabase := ""
-		sqlDescs := lastBackupDesc.Descriptors
 		var err error
 		if sqlDescs, err = descriptorsMatchingTargets(sessionDatabase, sqlDescs, targets); err != nil {
 			return 0, err
@@ -687,9 +813,20 @@ func Restore(
 		return 0, errors.Wrapf(err, "making import requests for %d backups", len(backupDescs))
 	}
 
-	for _, desc := range newTableIDs {
-		jobLogger.Job.DescriptorIDs = append(jobLogger.Job.DescriptorIDs, desc)
+	// Record what is needed to resume the job in its details.
+	details := sql.RestoreJobDetails{URIs: uris, EndTime: endTime, Encrypted: encryptionKey != nil}
+	for oldID, newID := range newTableIDs {
+		for _, table := range tables {
+			if table.ID == newID {
+				details.TableRekeys = append(details.TableRekeys, sql.RestoreJobDetails_TableRekey{
+					OldID:   uint32(oldID),
+					NewDesc: table,
+				})
+			}
+		}
+		jobLogger.Job.DescriptorIDs = append(jobLogger.Job.DescriptorIDs, newID)
 	}
+	jobLogger.Job.Details = details
 	if err := jobLogger.Created(ctx); err != nil {
 		return 0, err
 	}
@@ -697,11 +834,6 @@ func Restore(
 		return 0, err
 	}
 
-	progressLogger := jobProgressLogger{
-		jobLogger:   jobLogger,
-		totalChunks: len(importRequests),
-	}
-
 	// The Import (and resulting WriteBatch) requests made below run on
 	// leaseholders, so presplit the ranges to balance the work among many
 	// nodes
@@ -716,35 +848,133 @@ func Restore(
 	if err := presplitRanges(ctx, db, splitKeys); err != nil {
 		return 0, errors.Wrapf(err, "presplitting %d ranges", len(importRequests))
 	}
-	{
-		newSpans := spansForAllTableIndexes(tables)
-		g, gCtx := errgroup.WithContext(ctx)
-		for i := range newSpans {
-			span := newSpans[i]
-			g.Go(func() error {
-				req := &roachpb.AdminScatterRequest{
-					Span: roachpb.Span{Key: span.Key, EndKey: span.EndKey},
-				}
-				res, pErr := client.SendWrapped(gCtx, db.GetSender(), req)
-				if pErr != nil {
-					return pErr.GoError()
-				}
-				// Scatter is best-effort, so log why any individual ranges
-				// didn't get scattered.
-				for _, r := range res.(*roachpb.AdminScatterResponse).Ranges {
-					if r.Error != nil {
-						log.Warningf(ctx, "error scattering range [%s,%s): %+v",
-							r.Span.Key, r.Span.EndKey, r.Error.GoError())
-					}
-				}
-				return nil
-			})
+	if err := scatterSpans(ctx, db, spansForAllTableIndexes(tables)); err != nil {
+		return 0, errors.Wrapf(err, "scattering %d ranges", len(importRequests))
+	}
+
+	return restore(
+		ctx, p, importRequests, endTime, encryptionKey, tables, kr, rekeys,
+		nil /* completed */, jobLogger, 0, /* startFraction */
+	)
+}
+
+// resumeRestore restores the tables of a paused restore job from the
+// checkpoint in its details. The backups are decrypted with encryptionKey if it
+// is not nil.
+func resumeRestore(
+	ctx context.Context,
+	p sql.PlanHookState,
+	details sql.RestoreJobDetails,
+	encryptionKey []byte,
+	jobLogger *sql.JobLogger,
+) (int64, error) {
+	backupDescs, err := loadBackupDescs(ctx, details.URIs, encryptionKey)
+	if err != nil {
+		return 0, err
+	}
+	backupDescs, sqlDescs, err := selectBackupsAsOf(backupDescs, details.EndTime)
+	if err != nil {
+		return 0, err
+	}
+	oldTablesByID := make(map[sqlbase.ID]*sqlbase.TableDescriptor)
+	for _, desc := range sqlDescs {
+		if tableDesc := desc.GetTable(); tableDesc != nil {
+			oldTablesByID[tableDesc.ID] = tableDesc
+		}
+	}
+
+	var oldTables, tables []*sqlbase.TableDescriptor
+	var rekeys []roachpb.ImportRequest_TableRekey
+	for _, r := range details.TableRekeys {
+		oldTable, ok := oldTablesByID[sqlbase.ID(r.OldID)]
+		if !ok {
+			return 0, errors.Errorf("table %d not found in backup", r.OldID)
+		}
+		oldTables = append(oldTables, oldTable)
+		tables = append(tables, r.NewDesc)
+		newDescBytes, err := sqlbase.WrapDescriptor(r.NewDesc).Marshal()
+		if err != nil {
+			return 0, errors.Wrap(err, "marshalling descriptor")
 		}
-		if err := g.Wait(); err != nil {
-			return 0, errors.Wrapf(err, "scattering %d ranges", len(importRequests))
+		rekeys = append(rekeys, roachpb.ImportRequest_TableRekey{
+			OldID:   r.OldID,
+			NewDesc: newDescBytes,
+		})
+	}
+	kr, err := storageccl.MakeKeyRewriter(rekeys)
+	if err != nil {
+		return 0, err
+	}
+
+	// The import requests are computed exactly as they were when the job
+	// started, so the completed spans in the checkpoint identify them.
+	importRequests, _, err := makeImportRequests(spansForAllTableIndexes(oldTables), backupDescs)
+	if err != nil {
+		return 0, errors.Wrapf(err, "making import requests for %d backups", len(backupDescs))
+	}
+	return restore(
+		ctx, p, importRequests, details.EndTime, encryptionKey, tables, kr, rekeys,
+		details.CompletedSpans, jobLogger, 0, /* startFraction */
+	)
+}
+
+// restore runs the import requests whose spans are not in completed, then
+// writes the descriptors of the restored tables. completed holds the spans of
+// the requests that were imported before a resumed job was paused. If endTime
+// is set, the data is imported as of that time. If encryptionKey is set, the
+// backup files are decrypted with it. The import requests account for the
+// progress of the job after startFraction.
+func restore(
+	ctx context.Context,
+	p sql.PlanHookState,
+	importRequests []importEntry,
+	endTime hlc.Timestamp,
+	encryptionKey []byte,
+	tables []*sqlbase.TableDescriptor,
+	kr *storageccl.KeyRewriter,
+	rekeys []roachpb.ImportRequest_TableRekey,
+	completed []roachpb.Span,
+	jobLogger *sql.JobLogger,
+	startFraction float32,
+) (int64, error) {
+	db := *p.ExecCfg().DB
+
+	done := make(map[string]struct{}, len(completed))
+	for _, span := range completed {
+		done[string(span.Key)] = struct{}{}
+	}
+	var remaining []importEntry
+	for _, ir := range importRequests {
+		if _, ok := done[string(ir.Key)]; !ok {
+			remaining = append(remaining, ir)
 		}
 	}
 
+	mu := struct {
+		syncutil.Mutex
+		dataSize  int64
+		completed []roachpb.Span
+	}{
+		completed: completed,
+	}
+
+	progressLogger := jobProgressLogger{
+		jobLogger:     jobLogger,
+		totalChunks:   len(importRequests),
+		startFraction: startFraction,
+		checkpointFn: func(details interface{}) error {
+			mu.Lock()
+			defer mu.Unlock()
+			// IMPORT also uses restore to ingest its data, but it cannot be
+			// resumed, so it has no checkpoint to record.
+			if d, ok := details.(*sql.RestoreJobDetails); ok {
+				d.CompletedSpans = append([]roachpb.Span(nil), mu.completed...)
+			}
+			return nil
+		},
+	}
+	progressLogger.mu.completedChunks = len(importRequests) - len(remaining)
+
 	// We're already limiting these on the server-side, but sending all the
 	// Import requests at once would fill up distsender/grpc/something and cause
 	// all sorts of badness (node liveness timeouts leading to mass leaseholder
@@ -761,37 +991,27 @@ func Restore(
 	maxConcurrentImports := clusterNodeCount(p.ExecCfg().Gossip)
 	importsSem := make(chan struct{}, maxConcurrentImports)
 
-	mu := struct {
-		syncutil.Mutex
-		dataSize int64
-	}{}
-
 	g, gCtx := errgroup.WithContext(ctx)
-	for i := range importRequests {
+	for i := range remaining {
 		select {
 		case importsSem <- struct{}{}:
 		case <-ctx.Done():
 			return 0, ctx.Err()
 		}
 
-		ir := importRequests[i]
+		ir := remaining[i]
 		g.Go(func() error {
 			defer func() { <-importsSem }()
 
-			res, err := Import(gCtx, db, ir.Key, ir.EndKey, ir.files, kr, rekeys)
+			res, err := Import(gCtx, db, ir.Key, ir.EndKey, ir.files, endTime, encryptionKey, kr, rekeys)
 			if err != nil {
 				return err
 			}
 			mu.Lock()
 			mu.dataSize += res.DataSize
+			mu.completed = append(mu.completed, ir.Span)
 			mu.Unlock()
-			if err := progressLogger.chunkFinished(gCtx); err != nil {
-				// Errors while updating progress are not important enough to merit
-				// failing the entire restore.
-				log.Errorf(ctx, "RESTORE ignoring error while updating progress on job %d (%s): %+v",
-					jobLogger.JobID(), jobLogger.Job.Description, err)
-			}
-			return nil
+			return progressLogger.chunkFinished(gCtx)
 		})
 	}
 
@@ -799,7 +1019,12 @@ func Restore(
 		// This leaves the data that did get imported in case the user wants to
 		// retry.
 		// TODO(dan): Build tooling to allow a user to restart a failed restore.
-		return 0, errors.Wrapf(err, "importing %d ranges", len(importRequests))
+		return 0, errors.Wrapf(err, "importing %d ranges", len(remaining))
+	}
+	// Make sure the job was not paused or canceled before making the restored
+	// tables visible.
+	if err := progressLogger.checkpoint(ctx); err != nil {
+		return 0, err
 	}
 
 	// Write the new TableDescriptors and flip the namespace entries over to
@@ -851,6 +1076,14 @@ func restorePlanHook(
 		if err != nil {
 			return nil, err
 		}
+		var endTime hlc.Timestamp
+		if restore.AsOf.Expr != nil {
+			var err error
+			endTime, err = sql.EvalAsOfTimestamp(nil, restore.AsOf, p.ExecCfg().Clock.Now())
+			if err != nil {
+				return nil, err
+			}
+		}
 		description, err := restoreJobDescription(restore, from)
 		if err != nil {
 			return nil, err
@@ -865,19 +1098,14 @@ func restorePlanHook(
 			p,
 			from,
 			restore.Targets,
+			endTime,
 			restore.Options,
 			&jobLogger,
 		)
+		finishJob(ctx, &jobLogger, err)
 		if err != nil {
-			jobLogger.Failed(ctx, err)
 			return nil, err
 		}
-		if err := jobLogger.Succeeded(ctx); err != nil {
-			// An error while marking the job as successful is not important enough to
-			// merit failing the entire restore.
-			log.Errorf(ctx, "RESTORE ignoring error while marking job %d (%s) as successful: %+v",
-				jobLogger.JobID(), description, err)
-		}
 		// TODO(benesch): emit periodic progress updates once we have the
 		// infrastructure to stream responses.
 		ret := []parser.Datums{{
@@ -891,6 +1119,41 @@ func restorePlanHook(
 	return fn, header, nil
 }
 
+func restoreResumeHook(
+	ctx context.Context, p sql.PlanHookState, jobLogger *sql.JobLogger, opt parser.KVOptions,
+) (func(context.Context) error, error) {
+	details, ok := jobLogger.Job.Details.(sql.RestoreJobDetails)
+	if !ok {
+		return nil, nil
+	}
+	if err := utilccl.CheckEnterpriseEnabled("RESTORE"); err != nil {
+		return nil, err
+	}
+	if len(details.TableRekeys) == 0 {
+		return nil, errors.Errorf("job %d has no checkpoint to resume from", *jobLogger.JobID())
+	}
+	if len(details.URIs) == 0 {
+		return nil, errors.Errorf("job %d has no backups to restore", *jobLogger.JobID())
+	}
+	encryptionKey, err := resumeEncryptionKey(
+		ctx, *jobLogger.JobID(), details.Encrypted, opt, details.URIs[0],
+	)
+	if err != nil {
+		return nil, err
+	}
+	// Check the passphrase before the job is marked as running again.
+	if _, err := readBackupDescriptor(ctx, details.URIs[0], encryptionKey); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		_, err := resumeRestore(ctx, p, details, encryptionKey, jobLogger)
+		finishJob(ctx, jobLogger, err)
+		return err
+	}, nil
+}
+
 func init() {
 	sql.AddPlanHook(restorePlanHook)
+	sql.AddJobResumeHook(restoreResumeHook)
 }
@@ -0,0 +1,176 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package storageccl
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// Encrypted files start with encryptionPreamble and a version byte, followed
+// by the nonce and the output of AES-256-GCM, which authenticates the content
+// of the file.
+var encryptionPreamble = []byte("encrypt")
+
+const (
+	encryptionVersion = 1
+	// The nonce is random, which is safe since each key encrypts far fewer
+	// than 2^32 files.
+	encryptionNonceSize = 12
+
+	encryptionKeySize = 32
+	// EncryptionSaltSize is the size of the salts passed to GenerateKey.
+	EncryptionSaltSize = 16
+	// The number of PBKDF2 iterations used to derive keys from passphrases.
+	encryptionKeyIterations = 64000
+)
+
+// GenerateSalt returns a random salt to derive a key from a passphrase with.
+func GenerateSalt() ([]byte, error) {
+	salt := make([]byte, EncryptionSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// GenerateKey derives the key used by EncryptFile and DecryptFile from a
+// passphrase and a salt.
+func GenerateKey(passphrase, salt []byte) []byte {
+	return pbkdf2.Key(passphrase, salt, encryptionKeyIterations, encryptionKeySize, sha256.New)
+}
+
+// AppearsEncrypted returns whether the content of a file looks like it was
+// encrypted by EncryptFile.
+func AppearsEncrypted(content []byte) bool {
+	return bytes.HasPrefix(content, encryptionPreamble)
+}
+
+// EncryptFile encrypts the content of a file with the given key.
+func EncryptFile(plaintext, key []byte) ([]byte, error) {
+	gcm, err := aesGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	header := len(encryptionPreamble) + 1
+	ciphertext := make([]byte, header+encryptionNonceSize, header+encryptionNonceSize+len(plaintext)+gcm.Overhead())
+	copy(ciphertext, encryptionPreamble)
+	ciphertext[len(encryptionPreamble)] = encryptionVersion
+	nonce := ciphertext[header:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(ciphertext, nonce, plaintext, nil), nil
+}
+
+// DecryptFile decrypts the content of a file encrypted by EncryptFile with the
+// given key. It returns an error if the file was not encrypted with this key
+// or was tampered with.
+func DecryptFile(ciphertext, key []byte) ([]byte, error) {
+	if !AppearsEncrypted(ciphertext) {
+		return nil, errors.New("file does not appear to be encrypted")
+	}
+	ciphertext = ciphertext[len(encryptionPreamble):]
+	if len(ciphertext) < 1+encryptionNonceSize {
+		return nil, errors.New("invalid encrypted file: too short")
+	}
+	if version := ciphertext[0]; version != encryptionVersion {
+		return nil, errors.Errorf("unexpected encryption scheme version %d", version)
+	}
+	nonce, ciphertext := ciphertext[1:1+encryptionNonceSize], ciphertext[1+encryptionNonceSize:]
+
+	gcm, err := aesGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("file could not be decrypted: the encryption key or passphrase is likely incorrect")
+	}
+	return plaintext, nil
+}
+
+func aesGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != encryptionKeySize {
+		return nil, errors.Errorf("invalid encryption key size %d, expected %d", len(key), encryptionKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptLocalFile encrypts the file at path in place.
+func encryptLocalFile(path string, key []byte) error {
+	plaintext, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := EncryptFile(plaintext, key)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, ciphertext, 0600)
+}
+
+// fetchEncryptedFile is FetchFile for a file encrypted with key. The returned
+// local file holds the decrypted content.
+func fetchEncryptedFile(
+	ctx context.Context, tempPrefix string, e ExportStorage, basename string, key []byte,
+) (string, func(), error) {
+	cleanup := func() {}
+	if tempPrefix == "" {
+		return "", cleanup, errors.New("must provide tempdir path")
+	}
+
+	encryptedPath, encryptedCleanup, err := FetchFile(ctx, tempPrefix, e, basename, nil)
+	if err != nil {
+		return "", cleanup, err
+	}
+	defer encryptedCleanup()
+	ciphertext, err := ioutil.ReadFile(encryptedPath)
+	if err != nil {
+		return "", cleanup, err
+	}
+	plaintext, err := DecryptFile(ciphertext, key)
+	if err != nil {
+		return "", cleanup, errors.Wrapf(err, "decrypting %q", basename)
+	}
+
+	f, err := ioutil.TempFile(tempPrefix, basename)
+	if err != nil {
+		return "", cleanup, errors.Wrap(err, "creating tmpfile")
+	}
+	cleanup = func() {
+		if err := os.Remove(f.Name()); err != nil {
+			log.Warningf(ctx, "cleaning up tmpfile %s: %+v", f.Name(), err)
+		}
+	}
+	_, err = f.Write(plaintext)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", func() {}, errors.Wrapf(err, "writing decrypted content of %q", basename)
+	}
+	return f.Name(), cleanup, nil
+}
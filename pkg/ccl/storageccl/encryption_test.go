@@ -0,0 +1,93 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/LICENSE
+
+package storageccl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	salt, err := GenerateSalt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := GenerateKey([]byte("passphrase"), salt)
+	if !bytes.Equal(key, GenerateKey([]byte("passphrase"), salt)) {
+		t.Fatal("expected the same passphrase and salt to give the same key")
+	}
+
+	for _, plaintext := range [][]byte{
+		nil,
+		[]byte("a"),
+		bytes.Repeat([]byte("some data "), 1000),
+	} {
+		ciphertext, err := EncryptFile(plaintext, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !AppearsEncrypted(ciphertext) {
+			t.Fatalf("expected %q to appear encrypted", ciphertext)
+		}
+		if len(plaintext) > 0 && bytes.Contains(ciphertext, plaintext) {
+			t.Fatalf("expected the ciphertext not to contain the plaintext")
+		}
+		decrypted, err := DecryptFile(ciphertext, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(plaintext, decrypted) {
+			t.Fatalf("expected %q, got %q", plaintext, decrypted)
+		}
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		ciphertext, err := EncryptFile([]byte("some data"), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherSalt, err := GenerateSalt()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tampered := append([]byte(nil), ciphertext...)
+		tampered[len(tampered)-1] ^= 1
+
+		for _, tc := range []struct {
+			name       string
+			ciphertext []byte
+			key        []byte
+			err        string
+		}{
+			{"wrong passphrase", ciphertext, GenerateKey([]byte("wrong"), salt),
+				"the encryption key or passphrase is likely incorrect"},
+			{"wrong salt", ciphertext, GenerateKey([]byte("passphrase"), otherSalt),
+				"the encryption key or passphrase is likely incorrect"},
+			{"tampered", tampered, key,
+				"the encryption key or passphrase is likely incorrect"},
+			{"truncated", ciphertext[:len(encryptionPreamble)+4], key,
+				"too short"},
+			{"not encrypted", []byte("some data"), key,
+				"does not appear to be encrypted"},
+			{"invalid key", ciphertext, key[:16],
+				"invalid encryption key size"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := DecryptFile(tc.ciphertext, tc.key); !testutils.IsError(err, tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+			})
+		}
+	})
+}
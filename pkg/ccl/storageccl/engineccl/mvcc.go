@@ -167,6 +167,19 @@ func (i *MVCCIncrementalIterator) Next() {
 	}
 }
 
+// NextAllVersions advances the iterator to the next version of the current
+// key in the time range, or to the next key/value in the iteration if there
+// is none. Using it instead of Next iterates over every version written between
+// startTime and endTime, most recent first for each key, instead of only over
+// the most recent one.
+func (i *MVCCIncrementalIterator) NextAllVersions() {
+	if i.valid && i.nextkey {
+		i.nextkey = false
+		i.iter.Next()
+	}
+	i.Next()
+}
+
 // Valid returns true if the iterator is currently valid. An iterator that
 // hasn't had Reset called on it or has gone past the end of the key range is
 // invalid.
@@ -48,12 +48,35 @@ func assertEqualKVs(
 	startKey, endKey roachpb.Key,
 	startTime, endTime hlc.Timestamp,
 	expected []engine.MVCCKeyValue,
+) func(*testing.T) {
+	return assertIteratedKVs(e, startKey, endKey, startTime, endTime, false, expected)
+}
+
+func assertEqualAllVersionsKVs(
+	e engine.Engine,
+	startKey, endKey roachpb.Key,
+	startTime, endTime hlc.Timestamp,
+	expected []engine.MVCCKeyValue,
+) func(*testing.T) {
+	return assertIteratedKVs(e, startKey, endKey, startTime, endTime, true, expected)
+}
+
+func assertIteratedKVs(
+	e engine.Engine,
+	startKey, endKey roachpb.Key,
+	startTime, endTime hlc.Timestamp,
+	allVersions bool,
+	expected []engine.MVCCKeyValue,
 ) func(*testing.T) {
 	return func(t *testing.T) {
 		iter := NewMVCCIncrementalIterator(e, startTime, endTime)
 		defer iter.Close()
+		next := iter.Next
+		if allVersions {
+			next = iter.NextAllVersions
+		}
 		var kvs []engine.MVCCKeyValue
-		for iter.Reset(startKey, endKey); iter.Valid(); iter.Next() {
+		for iter.Reset(startKey, endKey); iter.Valid(); next() {
 			kvs = append(kvs, engine.MVCCKeyValue{Key: iter.Key(), Value: iter.Value()})
 		}
 
@@ -153,6 +176,16 @@ func runMVCCIterateIncremental(t *testing.T) {
 	mustFlush()
 	t.Run("del", assertEqualKVs(e, keyMin, keyMax, ts0, tsMax, kvs(kv1_3Deleted, kv2_2_2)))
 
+	// Exercise iteration over all the versions.
+	t.Run("all ts 0-∞", assertEqualAllVersionsKVs(
+		e, keyMin, keyMax, ts0, tsMax, kvs(kv1_3Deleted, kv1_2_2, kv1_1_1, kv2_2_2)))
+	t.Run("all ts 1-3", assertEqualAllVersionsKVs(
+		e, keyMin, keyMax, ts1, ts3, kvs(kv1_2_2, kv1_1_1, kv2_2_2)))
+	t.Run("all ts 2-3", assertEqualAllVersionsKVs(
+		e, keyMin, keyMax, ts2, ts3, kvs(kv1_2_2, kv2_2_2)))
+	t.Run("all kv 1-2", assertEqualAllVersionsKVs(
+		e, testKey1, testKey2, ts0, tsMax, kvs(kv1_3Deleted, kv1_2_2, kv1_1_1)))
+
 	// Exercise intent handling.
 	txn1ID := uuid.MakeV4()
 	txn1 := roachpb.Transaction{TxnMeta: enginepb.TxnMeta{
@@ -195,6 +228,8 @@ func runMVCCIterateIncremental(t *testing.T) {
 	}
 	mustFlush()
 	t.Run("intents4", assertEqualKVs(e, keyMin, keyMax, ts0, tsMax, kvs(kv1_4_4, kv2_2_2)))
+	t.Run("intents5", assertEqualAllVersionsKVs(
+		e, keyMin, keyMax, ts3, tsMax, kvs(kv1_4_4, kv1_3Deleted)))
 }
 
 func TestMVCCIterateIncremental(t *testing.T) {
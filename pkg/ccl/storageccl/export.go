@@ -79,6 +79,10 @@ func evalExport(
 			return storage.EvalResult{}, errors.Errorf("start timestamp %v must be after replica GC threshold %v", args.StartTime, gcThreshold)
 		}
 	}
+	// Revisions at or before the GC threshold may have been deleted, so the
+	// history of a full export with all revisions only starts there.
+	reply.RevisionStartTime = args.StartTime
+	reply.RevisionStartTime.Forward(gcThreshold)
 
 	if err := exportRequestLimiter.beginLimitedRequest(ctx); err != nil {
 		return storage.EvalResult{}, err
@@ -117,7 +121,11 @@ func evalExport(
 	// TODO(dan): Consider checking ctx periodically during the MVCCIterate call.
 	iter := engineccl.NewMVCCIncrementalIterator(batch, args.StartTime, h.Timestamp)
 	defer iter.Close()
-	for iter.Reset(args.Key, args.EndKey); iter.Valid(); iter.Next() {
+	next := iter.Next
+	if args.AllRevisions {
+		next = iter.NextAllVersions
+	}
+	for iter.Reset(args.Key, args.EndKey); iter.Valid(); next() {
 		if log.V(3) {
 			v := roachpb.Value{RawBytes: iter.UnsafeValue()}
 			log.Infof(ctx, "Export %s %s", iter.UnsafeKey(), v.PrettyPrint())
@@ -149,6 +157,13 @@ func evalExport(
 		return storage.EvalResult{}, err
 	}
 
+	// The checksum covers the plaintext, so it is verified after decryption.
+	if args.EncryptionKey != nil {
+		if err := encryptLocalFile(localPath, args.EncryptionKey); err != nil {
+			return storage.EvalResult{}, err
+		}
+	}
+
 	if err := temp.Finish(ctx); err != nil {
 		return storage.EvalResult{}, err
 	}
@@ -526,9 +526,13 @@ func (s *azureStorage) Close() error {
 
 // FetchFile returns the path to a local file containing the content of
 // the requested filename, and a cleanup func to be called when done reading it.
+// If encryptionKey is set, the file is decrypted with it.
 func FetchFile(
-	ctx context.Context, tempPrefix string, e ExportStorage, basename string,
+	ctx context.Context, tempPrefix string, e ExportStorage, basename string, encryptionKey []byte,
 ) (string, func(), error) {
+	if encryptionKey != nil {
+		return fetchEncryptedFile(ctx, tempPrefix, e, basename, encryptionKey)
+	}
 	cleanup := func() {}
 	// special-case local files to avoid copying to tmp.
 	if loc, ok := e.(*localFileStorage); ok {
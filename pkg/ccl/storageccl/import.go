@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 )
@@ -128,7 +129,7 @@ func evalImport(ctx context.Context, cArgs storage.CommandArgs) (*roachpb.Import
 			}
 		}()
 		tempPrefix := cArgs.EvalCtx.GetTempPrefix()
-		localPath, cleanup, err := FetchFile(ctx, tempPrefix, dir, file.Path)
+		localPath, cleanup, err := FetchFile(ctx, tempPrefix, dir, file.Path, args.EncryptionKey)
 		if err != nil {
 			return nil, err
 		}
@@ -182,7 +183,18 @@ func evalImport(ctx context.Context, cArgs storage.CommandArgs) (*roachpb.Import
 	startKeyMVCC, endKeyMVCC := engine.MVCCKey{Key: args.DataSpan.Key}, engine.MVCCKey{Key: args.DataSpan.EndKey}
 	iter := engineccl.MakeMultiIterator(iters)
 	var keyScratch, valueScratch []byte
-	for iter.Seek(startKeyMVCC); iter.Valid() && iter.UnsafeKey().Less(endKeyMVCC); iter.NextKey() {
+	for iter.Seek(startKeyMVCC); ; iter.NextKey() {
+		if args.EndTime != (hlc.Timestamp{}) {
+			// The files may contain revisions written after the time we're
+			// importing as of, skip them.
+			for iter.Valid() && args.EndTime.Less(iter.UnsafeKey().Timestamp) {
+				iter.Next()
+			}
+		}
+		if !iter.Valid() || !iter.UnsafeKey().Less(endKeyMVCC) {
+			break
+		}
+
 		if len(iter.UnsafeValue()) == 0 {
 			// Value is deleted.
 			continue
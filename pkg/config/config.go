@@ -130,6 +130,25 @@ func (c *Constraints) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+var _ yaml.Marshaler = LeasePreference{}
+var _ yaml.Unmarshaler = &LeasePreference{}
+
+// MarshalYAML implements yaml.Marshaler. A lease preference is marshaled as
+// the list of its constraints in their short form.
+func (l LeasePreference) MarshalYAML() (interface{}, error) {
+	return Constraints{Constraints: l.Constraints}.MarshalYAML()
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *LeasePreference) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var c Constraints
+	if err := c.UnmarshalYAML(unmarshal); err != nil {
+		return err
+	}
+	l.Constraints = c.Constraints
+	return nil
+}
+
 // DefaultZoneConfig is the default zone configuration used when no custom
 // config has been specified.
 func DefaultZoneConfig() ZoneConfig {
@@ -170,6 +189,16 @@ func (z ZoneConfig) Validate() error {
 		return fmt.Errorf("RangeMinBytes %d is greater than or equal to RangeMaxBytes %d",
 			z.RangeMinBytes, z.RangeMaxBytes)
 	}
+	for _, pref := range z.LeasePreferences {
+		if len(pref.Constraints) == 0 {
+			return fmt.Errorf("every lease preference must include at least one constraint")
+		}
+		for _, c := range pref.Constraints {
+			if c.Type == Constraint_POSITIVE {
+				return fmt.Errorf("lease preference constraints must either be required (e.g. '+foo') or prohibited (e.g. '-foo'), not %q", c)
+			}
+		}
+	}
 	return nil
 }
 
@@ -397,6 +397,9 @@ func TestGetZoneConfigForKey(t *testing.T) {
 		{keys.MakeTablePrefix(keys.ZonesTableID), keys.SystemDatabaseID},
 		{keys.MakeTablePrefix(keys.LeaseTableID), keys.SystemDatabaseID},
 		{keys.MakeTablePrefix(keys.JobsTableID), keys.SystemDatabaseID},
+		{keys.MakeTablePrefix(keys.TableStatisticsTableID), keys.SystemDatabaseID},
+		{keys.MakeTablePrefix(keys.RolesTableID), keys.SystemDatabaseID},
+		{keys.MakeTablePrefix(keys.RoleMembersTableID), keys.SystemDatabaseID},
 		{keys.MakeTablePrefix(keys.MaxReservedDescID + 1), keys.MaxReservedDescID + 1},
 		{keys.MakeTablePrefix(keys.MaxReservedDescID + 23), keys.MaxReservedDescID + 23},
 		{roachpb.RKeyMax, keys.RootNamespaceID},
@@ -463,6 +466,35 @@ func TestZoneConfigValidate(t *testing.T) {
 			},
 			"is greater than or equal to RangeMaxBytes",
 		},
+		{
+			config.ZoneConfig{
+				NumReplicas:   1,
+				RangeMaxBytes: config.DefaultZoneConfig().RangeMaxBytes,
+				LeasePreferences: []config.LeasePreference{
+					{Constraints: []config.Constraint{{Type: config.Constraint_REQUIRED, Key: "region", Value: "us-east1"}}},
+					{Constraints: []config.Constraint{{Type: config.Constraint_PROHIBITED, Value: "ssd"}}},
+				},
+			},
+			"",
+		},
+		{
+			config.ZoneConfig{
+				NumReplicas:      1,
+				RangeMaxBytes:    config.DefaultZoneConfig().RangeMaxBytes,
+				LeasePreferences: []config.LeasePreference{{}},
+			},
+			"every lease preference must include at least one constraint",
+		},
+		{
+			config.ZoneConfig{
+				NumReplicas:   1,
+				RangeMaxBytes: config.DefaultZoneConfig().RangeMaxBytes,
+				LeasePreferences: []config.LeasePreference{
+					{Constraints: []config.Constraint{{Type: config.Constraint_POSITIVE, Value: "ssd"}}},
+				},
+			},
+			"lease preference constraints must either be required",
+		},
 	}
 	for i, c := range testCases {
 		err := c.cfg.Validate()
@@ -502,6 +534,30 @@ func TestZoneConfigMarshalYAML(t *testing.T) {
 				},
 			},
 		},
+		LeasePreferences: []config.LeasePreference{
+			{
+				Constraints: []config.Constraint{
+					{
+						Type:  config.Constraint_REQUIRED,
+						Key:   "duck",
+						Value: "foo",
+					},
+				},
+			},
+			{
+				Constraints: []config.Constraint{
+					{
+						Type:  config.Constraint_PROHIBITED,
+						Key:   "duck",
+						Value: "bar",
+					},
+					{
+						Type:  config.Constraint_REQUIRED,
+						Value: "ssd",
+					},
+				},
+			},
+		},
 	}
 
 	expected := `range_min_bytes: 1
@@ -510,6 +566,7 @@ gc:
   ttlseconds: 1
 num_replicas: 1
 constraints: [foo, +duck=foo, -duck=foo]
+lease_preferences: [[+duck=foo], [-duck=bar, +ssd]]
 `
 
 	body, err := yaml.Marshal(original)
@@ -74,6 +74,11 @@ const (
 	// The value if a config.SystemConfig which holds all key/value
 	// pairs in the system DB span.
 	KeySystemConfig = "system-db"
+
+	// KeyTableStatAddedPrefix is the prefix for keys that indicate a new table
+	// statistic was computed. The statistics themselves are not stored in gossip;
+	// the keys are used to notify nodes to invalidate table statistic caches.
+	KeyTableStatAddedPrefix = "table-stat-added"
 )
 
 // MakeKey creates a canonical key under which to gossip a piece of
@@ -130,3 +135,25 @@ func MakeStoreKey(storeID roachpb.StoreID) string {
 func MakeDeadReplicasKey(storeID roachpb.StoreID) string {
 	return MakeKey(KeyDeadReplicasPrefix, storeID.String())
 }
+
+// MakeTableStatAddedKey returns the gossip key used to notify that a new
+// statistic is available for the given table.
+func MakeTableStatAddedKey(tableID uint32) string {
+	return MakeKey(KeyTableStatAddedPrefix, strconv.FormatUint(uint64(tableID), 10 /* base */))
+}
+
+// TableIDFromTableStatAddedKey attempts to extract the table ID from the
+// provided key.
+// The key should have been constructed by MakeTableStatAddedKey.
+// Returns an error if the key is not of the correct type or is not parsable.
+func TableIDFromTableStatAddedKey(key string) (uint32, error) {
+	trimmedKey := strings.TrimPrefix(key, KeyTableStatAddedPrefix+separator)
+	if trimmedKey == key {
+		return 0, errors.Errorf("%q is not a %s key", key, KeyTableStatAddedPrefix)
+	}
+	tableID, err := strconv.ParseUint(trimmedKey, 10 /* base */, 32 /* bitSize */)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed parsing table ID from key %q", key)
+	}
+	return uint32(tableID), nil
+}
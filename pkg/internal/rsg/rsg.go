@@ -26,7 +26,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/rsg/yacc"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 // RSG is a random syntax generator.
@@ -237,6 +239,14 @@ func (r *RSG) GenerateRandomArg(typ parser.Type) string {
 	case parser.TypeInterval:
 		d := duration.Duration{Nanos: r.Int63()}
 		v = fmt.Sprintf(`'%s'`, &parser.DInterval{Duration: d})
+	case parser.TypeUUID:
+		u := uuid.NewPopulatedUUID(r)
+		v = fmt.Sprintf(`'%s'`, u)
+	case parser.TypeJSON:
+		r.lock.Lock()
+		j := json.Random(10, r.src)
+		r.lock.Unlock()
+		v = fmt.Sprintf(`'%s'`, j)
 	case parser.TypeIntArray,
 		parser.TypeStringArray,
 		parser.TypeOid,
@@ -295,6 +295,12 @@ const (
 	UITableID         = 14
 	JobsTableID       = 15
 
+	// TableStatisticsTableID and the IDs after it follow the reserved range
+	// IDs below.
+	TableStatisticsTableID = 19
+	RolesTableID           = 20
+	RoleMembersTableID     = 21
+
 	// Reserved IDs used to refer to certain parts of the system ranges that
 	// come before the system config span and user table ranges.
 	// NOTE: IDs must be <= MaxReservedDescID.
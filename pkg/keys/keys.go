@@ -587,6 +587,31 @@ func MakeRowSentinelKey(key []byte) []byte {
 	return MakeFamilyKey(key, SentinelFamilyID)
 }
 
+// SequenceIndexID is the ID of the single index of a sequence, under which
+// its value is stored.
+const SequenceIndexID = 1
+
+// MakeSequenceKey returns the key used to store the value of the sequence with
+// the given descriptor ID. The key lives inside the sequence's table span, so
+// it is cleaned up along with the rest of the table's data.
+func MakeSequenceKey(tableID uint32) []byte {
+	return MakeRowSentinelKey(makeSequenceRowPrefix(tableID))
+}
+
+// MakeSequenceUncalledKey returns the key used to store the value the next
+// call to nextval() returns on the sequence with the given descriptor ID, when
+// the value preceding it cannot be stored under MakeSequenceKey. The key is in
+// the same row as the one returned by MakeSequenceKey.
+func MakeSequenceUncalledKey(tableID uint32) []byte {
+	return MakeFamilyKey(makeSequenceRowPrefix(tableID), 1)
+}
+
+func makeSequenceRowPrefix(tableID uint32) []byte {
+	key := MakeTablePrefix(tableID)
+	key = encoding.EncodeUvarintAscending(key, SequenceIndexID) // index ID
+	return encoding.EncodeUvarintAscending(key, 0)              // primary key value
+}
+
 // EnsureSafeSplitKey transforms an SQL table key such that it is a valid split key
 // (i.e. does not occur in the middle of a row).
 func EnsureSafeSplitKey(key roachpb.Key) (roachpb.Key, error) {
@@ -468,6 +468,26 @@ func TestMakeFamilyKey(t *testing.T) {
 	}
 }
 
+func TestMakeSequenceKey(t *testing.T) {
+	var expected roachpb.Key
+	for _, v := range []uint64{50, SequenceIndexID, 0, 0} {
+		expected = encoding.EncodeUvarintAscending(expected, v)
+	}
+	// /Table/50/1/0/0
+	if key := roachpb.Key(MakeSequenceKey(50)); !key.Equal(expected) {
+		t.Errorf("expected %s, but got %s", expected, key)
+	}
+
+	expected = nil
+	for _, v := range []uint64{50, SequenceIndexID, 0, 1, 1} {
+		expected = encoding.EncodeUvarintAscending(expected, v)
+	}
+	// /Table/50/1/0/1/1
+	if key := roachpb.Key(MakeSequenceUncalledKey(50)); !key.Equal(expected) {
+		t.Errorf("expected %s, but got %s", expected, key)
+	}
+}
+
 func TestEnsureSafeSplitKey(t *testing.T) {
 	e := func(vals ...uint64) roachpb.Key {
 		var k roachpb.Key
@@ -65,6 +65,18 @@ var backwardCompatibleMigrations = []migrationDescriptor{
 		name:   "enable diagnostics reporting",
 		workFn: optIntToDiagnosticsStatReporting,
 	},
+	{
+		name:           "create system.table_statistics table",
+		workFn:         createTableStatisticsTable,
+		newDescriptors: 1,
+		newRanges:      1,
+	},
+	{
+		name:           "create system.roles and system.role_members tables",
+		workFn:         createRolesTables,
+		newDescriptors: 2,
+		newRanges:      2,
+	},
 }
 
 // migrationDescriptor describes a single migration hook that's used to modify
@@ -342,27 +354,30 @@ func eventlogUniqueIDDefault(ctx context.Context, r runner) error {
 
 // TODO(a-robinson): Write unit test for this.
 func createJobsTable(ctx context.Context, r runner) error {
-	// We install the table at the KV layer so that we can choose a known ID in
-	// the reserved ID space. (The SQL layer doesn't allow this.)
-	return r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
-		b := txn.NewBatch()
-		desc := sqlbase.JobsTable
-		b.CPut(sqlbase.MakeNameMetadataKey(desc.GetParentID(), desc.GetName()), desc.GetID(), nil)
-		b.CPut(sqlbase.MakeDescMetadataKey(desc.GetID()), sqlbase.WrapDescriptor(&desc), nil)
-		if err := txn.SetSystemConfigTrigger(); err != nil {
-			return err
-		}
-		return txn.Run(ctx, b)
-	})
+	return createSystemTable(ctx, r, sqlbase.JobsTable)
 }
 
 // TODO(a-robinson): Write unit test for this.
 func createSettingsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.SettingsTable)
+}
+
+func createTableStatisticsTable(ctx context.Context, r runner) error {
+	return createSystemTable(ctx, r, sqlbase.TableStatisticsTable)
+}
+
+func createRolesTables(ctx context.Context, r runner) error {
+	if err := createSystemTable(ctx, r, sqlbase.RolesTable); err != nil {
+		return err
+	}
+	return createSystemTable(ctx, r, sqlbase.RoleMembersTable)
+}
+
+func createSystemTable(ctx context.Context, r runner, desc sqlbase.TableDescriptor) error {
 	// We install the table at the KV layer so that we can choose a known ID in
 	// the reserved ID space. (The SQL layer doesn't allow this.)
 	return r.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
 		b := txn.NewBatch()
-		desc := sqlbase.SettingsTable
 		b.CPut(sqlbase.MakeNameMetadataKey(desc.GetParentID(), desc.GetName()), desc.GetID(), nil)
 		b.CPut(sqlbase.MakeDescMetadataKey(desc.GetID()), sqlbase.WrapDescriptor(&desc), nil)
 		if err := txn.SetSystemConfigTrigger(); err != nil {
@@ -270,6 +270,7 @@ func (er *ExportResponse) combine(c combinable) error {
 			return err
 		}
 		er.Files = append(er.Files, otherER.Files...)
+		er.RevisionStartTime.Forward(otherER.RevisionStartTime)
 	}
 	return nil
 }
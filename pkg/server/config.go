@@ -21,6 +21,8 @@ import (
 	"io/ioutil"
 	"math"
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -55,6 +57,8 @@ const (
 	defaultMetricsSampleInterval    = 10 * time.Second
 	defaultStorePath                = "cockroach-data"
 	defaultEventLogEnabled          = true
+	tempStorageDirName              = "distsql-temp"
+	tempStorageMaxOpenFiles         = 256
 
 	minimumNetworkFileDescriptors     = 256
 	recommendedNetworkFileDescriptors = 5000
@@ -442,6 +446,26 @@ func (cfg *Config) CreateEngines() (Engines, error) {
 	return enginesCopy, nil
 }
 
+// CreateTempEngine creates the engine that DistSQL processors use to store rows
+// that don't fit in memory. It is an in-memory engine if the first store is in
+// memory. Otherwise, it is stored in a directory under the first store's path,
+// which is wiped every time the engine is created since the rows of previous
+// runs are garbage.
+func (cfg *Config) CreateTempEngine() (engine.Engine, error) {
+	if len(cfg.Stores.Specs) == 0 || cfg.Stores.Specs[0].InMemory {
+		return engine.NewInMem(roachpb.Attributes{}, 0 /* cacheSize */), nil
+	}
+	dir := filepath.Join(cfg.Stores.Specs[0].Path, tempStorageDirName)
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	cache := engine.NewRocksDBCache(0)
+	defer cache.Release()
+	return engine.NewRocksDB(
+		roachpb.Attributes{}, dir, cache, 0 /* maxSize */, tempStorageMaxOpenFiles,
+	)
+}
+
 // InitNode parses node attributes and initializes the gossip bootstrap
 // resolvers.
 func (cfg *Config) InitNode() error {
@@ -56,6 +56,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/ts"
 	"github.com/cockroachdb/cockroach/pkg/ui"
@@ -84,6 +85,10 @@ var (
 	GracefulDrainModes = []serverpb.DrainMode{serverpb.DrainMode_CLIENT, serverpb.DrainMode_LEASES}
 )
 
+// tableStatsCacheSize is the number of tables whose statistics are cached on
+// each node.
+const tableStatsCacheSize = 256
+
 // Server is the cockroach server node.
 type Server struct {
 	nodeIDContainer base.NodeIDContainer
@@ -272,6 +277,11 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	s.registry.AddMetric(distSQLMetrics.MaxBytesHist)
 
 	// Set up the DistSQL server.
+	tempEngine, err := cfg.CreateTempEngine()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temporary storage engine")
+	}
+	s.stopper.AddCloser(tempEngine)
 	distSQLCfg := distsqlrun.ServerConfig{
 		AmbientContext: s.cfg.AmbientCtx,
 		DB:             s.db,
@@ -284,6 +294,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		ParentMemoryMonitor: &rootSQLMemoryMonitor,
 		Counter:             distSQLMetrics.CurBytesCount,
 		Hist:                distSQLMetrics.MaxBytesHist,
+		TempStorage:         tempEngine,
 	}
 	if s.cfg.TestingKnobs.DistSQL != nil {
 		distSQLCfg.TestingKnobs = *s.cfg.TestingKnobs.DistSQL.(*distsqlrun.TestingKnobs)
@@ -310,6 +321,10 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		HistogramWindowInterval: s.cfg.HistogramWindowInterval(),
 		RangeDescriptorCache:    s.distSender.RangeDescriptorCache(),
 		LeaseHolderCache:        s.distSender.LeaseHolderCache(),
+		SessionRegistry:         sql.MakeSessionRegistry(),
+		TableStatsCache: stats.NewTableStatisticsCache(
+			tableStatsCacheSize, s.gossip, s.db, sql.InternalExecutor{LeaseManager: s.leaseMgr},
+		),
 	}
 	if s.cfg.TestingKnobs.SQLExecutor != nil {
 		execCfg.TestingKnobs = s.cfg.TestingKnobs.SQLExecutor.(*sql.ExecutorTestingKnobs)
@@ -332,6 +347,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		&s.internalMemMetrics,
 		&rootSQLMemoryMonitor,
 		s.cfg.HistogramWindowInterval(),
+		s.cancelQueryByKey,
 	)
 	s.registry.AddMetricStruct(s.pgServer.Metrics())
 
@@ -390,6 +406,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		s.rpcContext,
 		s.node.stores,
 		s.stopper,
+		execCfg.SessionRegistry,
 	)
 	for _, gw := range []grpcGatewayServer{s.admin, s.status, &s.tsServer} {
 		gw.RegisterService(s.grpc)
@@ -938,6 +955,17 @@ func (s *Server) Undrain(off []serverpb.DrainMode) []serverpb.DrainMode {
 	return nowActive
 }
 
+// cancelQueryByKey cancels the queries of the session identified by a pgwire
+// backend key. The status server routes the request to the node running the
+// session.
+func (s *Server) cancelQueryByKey(ctx context.Context, nodeID roachpb.NodeID, secret int32) error {
+	_, err := s.status.CancelQueryByKey(ctx, &serverpb.CancelQueryByKeyRequest{
+		NodeID: nodeID.String(),
+		Secret: secret,
+	})
+	return err
+}
+
 // startSampleEnvironment begins a worker that periodically instructs the
 // runtime stat sampler to sample the environment.
 func (s *Server) startSampleEnvironment(frequency time.Duration) {
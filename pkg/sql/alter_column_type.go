@@ -0,0 +1,209 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// alterColumnType changes the type of a column of the table.
+//
+// The column is replaced by a shadow column of the new type, which the schema
+// changer backfills with the values of the column cast to the new type, and
+// the indexes referencing the column are rebuilt as shadow indexes over the
+// shadow column. Once the backfill completes, the shadow column and indexes
+// take the place of the column and indexes they replace, which are dropped.
+func (p *planner) alterColumnType(
+	tableDesc *sqlbase.TableDescriptor, col sqlbase.ColumnDescriptor, toType parser.ColumnType,
+) error {
+	newCol, _, err := sqlbase.MakeColumnDefDescs(
+		&parser.ColumnTableDef{Name: parser.Name(col.Name), Type: toType},
+		p.session.SearchPath, &p.evalCtx,
+	)
+	if err != nil {
+		return err
+	}
+	if proto.Equal(&newCol.Type, &col.Type) {
+		return nil
+	}
+
+	if tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
+		return fmt.Errorf("cannot change the type of column %q: it is referenced by the primary key",
+			col.Name)
+	}
+	if col.IsComputed() {
+		return fmt.Errorf("cannot change the type of computed column %q", col.Name)
+	}
+	if computed, err := tableDesc.FindComputedColumnReferencing(col.Name); err != nil {
+		return err
+	} else if computed != nil {
+		return fmt.Errorf("cannot change the type of column %q: computed column %q depends on it",
+			col.Name, computed.Name)
+	}
+	if idx, err := tableDesc.FindPartialIndexReferencing(col.Name); err != nil {
+		return err
+	} else if idx != nil {
+		return fmt.Errorf("cannot change the type of column %q: partial index %q depends on it",
+			col.Name, idx.Name)
+	}
+	for _, m := range tableDesc.Mutations {
+		if m.ShadowedColumnID == col.ID {
+			return fmt.Errorf("column %q is in the middle of a type change, try again later", col.Name)
+		}
+	}
+	for _, ref := range tableDesc.DependedOnBy {
+		for _, id := range ref.ColumnIDs {
+			if id == col.ID {
+				return fmt.Errorf("cannot change the type of column %q: a view depends on it", col.Name)
+			}
+		}
+	}
+	if usedByCheck, err := checksUseColumn(tableDesc, col); err != nil {
+		return err
+	} else if usedByCheck {
+		return fmt.Errorf("cannot change the type of column %q: it is used by a CHECK constraint",
+			col.Name)
+	}
+	if col.DefaultExpr != nil {
+		defaultExpr, err := parser.ParseExpr(*col.DefaultExpr)
+		if err != nil {
+			return err
+		}
+		if _, err := sqlbase.SanitizeVarFreeExpr(
+			defaultExpr, newCol.Type.ToDatumType(), "DEFAULT", p.session.SearchPath,
+		); err != nil {
+			return errors.Wrapf(err, "cannot change the type of column %q", col.Name)
+		}
+	}
+
+	// The shadow column is nullable until it is swapped in, so that rows
+	// can be written before it is backfilled.
+	shadow := sqlbase.ColumnDescriptor{
+		Name:     shadowColumnName(tableDesc, col.Name),
+		Type:     newCol.Type,
+		Nullable: true,
+		Hidden:   col.Hidden,
+	}
+	if _, err := sqlbase.MakeColumnConverter(col, shadow); err != nil {
+		return err
+	}
+
+	var shadowIndexes []sqlbase.IndexDescriptor
+	var shadowedIndexIDs []sqlbase.IndexID
+	for _, idx := range tableDesc.Indexes {
+		if !idx.ContainsColumnID(col.ID) {
+			continue
+		}
+		if idx.ForeignKey.IsSet() || len(idx.ReferencedBy) > 0 {
+			return fmt.Errorf("cannot change the type of column %q: index %q is used by a foreign key",
+				col.Name, idx.Name)
+		}
+		if len(idx.Interleave.Ancestors) > 0 || len(idx.InterleavedBy) > 0 {
+			return fmt.Errorf("cannot change the type of column %q: index %q is interleaved",
+				col.Name, idx.Name)
+		}
+		shadowIndexes = append(shadowIndexes, sqlbase.IndexDescriptor{
+			Name:             shadowIndexName(tableDesc, idx.Name),
+			Unique:           idx.Unique,
+			ColumnNames:      replaceName(idx.ColumnNames, col.Name, shadow.Name),
+			ColumnDirections: append([]sqlbase.IndexDescriptor_Direction(nil), idx.ColumnDirections...),
+			StoreColumnNames: replaceName(idx.StoreColumnNames, col.Name, shadow.Name),
+		})
+		shadowedIndexIDs = append(shadowedIndexIDs, idx.ID)
+	}
+
+	tableDesc.AddShadowColumnMutation(shadow, col.ID)
+	for i := range shadowIndexes {
+		tableDesc.AddShadowIndexMutation(shadowIndexes[i], shadowedIndexIDs[i])
+	}
+	return nil
+}
+
+// checksUseColumn returns whether a CHECK constraint of the table references
+// the column.
+func checksUseColumn(tableDesc *sqlbase.TableDescriptor, col sqlbase.ColumnDescriptor) (bool, error) {
+	normName := parser.ReNormalizeName(col.Name)
+	found := false
+	preFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		if vBase, ok := expr.(parser.VarName); ok {
+			v, err := vBase.NormalizeVarName()
+			if err != nil {
+				return err, false, nil
+			}
+			if c, ok := v.(*parser.ColumnItem); ok && c.ColumnName.Normalize() == normName {
+				found = true
+			}
+			return nil, false, v
+		}
+		return nil, true, expr
+	}
+	for _, check := range tableDesc.Checks {
+		expr, err := parser.ParseExpr(check.Expr)
+		if err != nil {
+			return false, err
+		}
+		if _, err := parser.SimpleVisit(expr, preFn); err != nil {
+			return false, err
+		}
+	}
+	return found, nil
+}
+
+// shadowColumnName returns a name for the shadow column of the named column
+// that isn't used by another column of the table.
+func shadowColumnName(tableDesc *sqlbase.TableDescriptor, name string) string {
+	for i := 0; ; i++ {
+		shadowName := shadowName(name, i)
+		if _, _, err := tableDesc.FindColumnByName(parser.Name(shadowName)); err != nil {
+			return shadowName
+		}
+	}
+}
+
+// shadowIndexName returns a name for the shadow index of the named index that
+// isn't used by another index of the table.
+func shadowIndexName(tableDesc *sqlbase.TableDescriptor, name string) string {
+	for i := 0; ; i++ {
+		shadowName := shadowName(name, i)
+		if _, _, err := tableDesc.FindIndexByName(parser.Name(shadowName)); err != nil {
+			return shadowName
+		}
+	}
+}
+
+func shadowName(name string, i int) string {
+	if i == 0 {
+		return fmt.Sprintf("%s_shadow", name)
+	}
+	return fmt.Sprintf("%s_shadow%d", name, i)
+}
+
+// replaceName returns a copy of names in which from is replaced by to.
+func replaceName(names []string, from, to string) []string {
+	res := make([]string, len(names))
+	for i, name := range names {
+		if name == from {
+			name = to
+		}
+		res[i] = name
+	}
+	return res
+}
@@ -84,6 +84,13 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
+			if col.IsComputed() {
+				if err := sqlbase.ValidateComputedColumn(
+					n.tableDesc, *col, n.p.session.SearchPath,
+				); err != nil {
+					return err
+				}
+			}
 			normName := parser.ReNormalizeName(col.Name)
 			status, i, err := n.tableDesc.FindColumnByNormalizedName(normName)
 			if err == nil {
@@ -131,6 +138,11 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 				if err := idx.FillColumns(d.Columns); err != nil {
 					return err
 				}
+				if err := setPartialIndexPredicate(
+					n.tableDesc, &idx, d.Predicate, n.p.session.SearchPath,
+				); err != nil {
+					return err
+				}
 				status, i, err := n.tableDesc.FindIndexByName(d.Name)
 				if err == nil {
 					if status == sqlbase.DescriptorIncomplete &&
@@ -215,6 +227,17 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 				if n.tableDesc.PrimaryIndex.ContainsColumnID(col.ID) {
 					return fmt.Errorf("column %q is referenced by the primary key", col.Name)
 				}
+				if computed, err := n.tableDesc.FindComputedColumnReferencing(col.Name); err != nil {
+					return err
+				} else if computed != nil && computed.ID != col.ID {
+					return fmt.Errorf("column %q is referenced by computed column %q",
+						col.Name, computed.Name)
+				}
+				if idx, err := n.tableDesc.FindPartialIndexReferencing(col.Name); err != nil {
+					return err
+				} else if idx != nil {
+					return fmt.Errorf("column %q is referenced by partial index %q", col.Name, idx.Name)
+				}
 				for _, idx := range n.tableDesc.AllNonDropIndexes() {
 					// We automatically drop indexes on that column that only
 					// index that column (and no other columns). If CASCADE is
@@ -405,6 +428,13 @@ func (n *alterTableNode) Start(ctx context.Context) error {
 
 			switch status {
 			case sqlbase.DescriptorActive:
+				if t, ok := t.(*parser.AlterTableAlterColumnType); ok {
+					// Changing the type of a column adds mutations.
+					if err := n.p.alterColumnType(n.tableDesc, n.tableDesc.Columns[i], t.ToType); err != nil {
+						return err
+					}
+					continue
+				}
 				if err := applyColumnMutation(
 					&n.tableDesc.Columns[i], t, n.p.session.SearchPath,
 				); err != nil {
@@ -498,6 +528,9 @@ func applyColumnMutation(
 		if t.Default == nil {
 			col.DefaultExpr = nil
 		} else {
+			if col.IsComputed() {
+				return fmt.Errorf("computed column %q cannot have a default value", col.Name)
+			}
 			colDatumType := col.Type.ToDatumType()
 			if _, err := sqlbase.SanitizeVarFreeExpr(
 				t.Default, colDatumType, "DEFAULT", searchPath,
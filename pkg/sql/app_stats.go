@@ -161,7 +161,9 @@ type sqlStats struct {
 // the cached pointer to per-application statistics. It is meant to be
 // used upon session initialization and upon SET APPLICATION_NAME.
 func (s *Session) resetApplicationName(appName string) {
+	s.mu.Lock()
 	s.ApplicationName = appName
+	s.mu.Unlock()
 	if s.sqlStats != nil {
 		s.appStats = s.sqlStats.getStatsForApplication(appName)
 	}
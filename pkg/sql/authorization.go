@@ -39,22 +39,43 @@ type AuthorizationAccessor interface {
 
 var _ AuthorizationAccessor = &planner{}
 
-// CheckPrivilege implements the AuthorizationAccessor interface.
+// CheckPrivilege implements the AuthorizationAccessor interface. The
+// privilege can also be inherited from one of the roles of the user.
 func (p *planner) CheckPrivilege(
 	descriptor sqlbase.DescriptorProto, privilege privilege.Kind,
 ) error {
-	if descriptor.GetPrivileges().CheckPrivilege(p.session.User, privilege) {
+	privs := descriptor.GetPrivileges()
+	if privs.CheckPrivilege(p.session.User, privilege) {
 		return nil
 	}
+	memberOf, err := p.memberOf(p.session.Ctx())
+	if err != nil {
+		return err
+	}
+	for role := range memberOf {
+		if privs.CheckPrivilege(role, privilege) {
+			return nil
+		}
+	}
 	return fmt.Errorf("user %s does not have %s privilege on %s %s",
 		p.session.User, privilege, descriptor.TypeName(), descriptor.GetName())
 }
 
-// anyPrivilege implements the AuthorizationAccessor interface.
+// anyPrivilege implements the AuthorizationAccessor interface. The privileges
+// of the roles of the user are taken into account.
 func (p *planner) anyPrivilege(descriptor sqlbase.DescriptorProto) error {
 	if userCanSeeDescriptor(descriptor, p.session.User) {
 		return nil
 	}
+	memberOf, err := p.memberOf(p.session.Ctx())
+	if err != nil {
+		return err
+	}
+	for role := range memberOf {
+		if userCanSeeDescriptor(descriptor, role) {
+			return nil
+		}
+	}
 	return fmt.Errorf("user %s has no privileges on %s %s",
 		p.session.User, descriptor.TypeName(), descriptor.GetName())
 }
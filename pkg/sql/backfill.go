@@ -141,7 +141,11 @@ func (sc *SchemaChanger) runBackfill(
 			switch t := m.Descriptor_.(type) {
 			case *sqlbase.DescriptorMutation_Column:
 				desc := m.GetColumn()
-				if desc.DefaultExpr != nil || !desc.Nullable {
+				// The shadow column of a column whose type is changed is
+				// backfilled with the converted values of that column, and
+				// a computed column with its computed values.
+				if desc.DefaultExpr != nil || !desc.Nullable || m.ShadowedColumnID != 0 ||
+					desc.IsComputed() {
 					needColumnBackfill = true
 				}
 			case *sqlbase.DescriptorMutation_Index:
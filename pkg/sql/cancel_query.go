@@ -0,0 +1,75 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// cancelQueryNode implements CANCEL QUERY.
+type cancelQueryNode struct {
+	p       *planner
+	queryID parser.TypedExpr
+}
+
+// CancelQuery cancels a query running on this node. Canceling a query cancels
+// the context of its SQL transaction, including the DistSQL flows it runs on
+// other nodes, and the transaction is aborted.
+// Privileges: None; non-root users can only cancel their own queries.
+func (p *planner) CancelQuery(ctx context.Context, n *parser.CancelQuery) (planNode, error) {
+	typedQueryID, err := p.analyzeExpr(
+		ctx, n.ID, nil, parser.IndexedVarHelper{}, parser.TypeString, true, "CANCEL QUERY",
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &cancelQueryNode{
+		p:       p,
+		queryID: typedQueryID,
+	}, nil
+}
+
+func (n *cancelQueryNode) Start(ctx context.Context) error {
+	queryIDDatum, err := n.queryID.Eval(&n.p.evalCtx)
+	if err != nil {
+		return err
+	}
+	queryID, ok := queryIDDatum.(*parser.DString)
+	if !ok {
+		return errors.Errorf("CANCEL QUERY requires a query ID, got %s", queryIDDatum)
+	}
+	registry := n.p.session.execCfg.SessionRegistry
+	if !registry.cancelQuery(string(*queryID), n.p.session.User) {
+		return errors.Errorf("query ID %s not found on this node", string(*queryID))
+	}
+	return nil
+}
+
+func (*cancelQueryNode) Next(context.Context) (bool, error) { return false, nil }
+func (*cancelQueryNode) Close(context.Context)              {}
+func (*cancelQueryNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*cancelQueryNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*cancelQueryNode) Values() parser.Datums              { return parser.Datums{} }
+func (*cancelQueryNode) DebugValues() debugValues           { return debugValues{} }
+func (*cancelQueryNode) MarkDebug(mode explainMode)         {}
+
+func (*cancelQueryNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
@@ -0,0 +1,146 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// controlJobNode implements PAUSE JOB, RESUME JOB and CANCEL JOB.
+//
+// The status change is written in its own transaction, so it is not undone if
+// the statement's transaction is rolled back.
+type controlJobNode struct {
+	p             *planner
+	op            string
+	jobID         parser.TypedExpr
+	desiredStatus JobStatus
+	// options are the options of RESUME JOB, passed to the job resume hooks.
+	options parser.KVOptions
+}
+
+// PauseJob requests that a running job pause.
+// Privileges: superuser.
+func (p *planner) PauseJob(ctx context.Context, n *parser.PauseJob) (planNode, error) {
+	return p.controlJob(ctx, n.ID, JobStatusPaused, n.StatementTag(), nil /* options */)
+}
+
+// ResumeJob resumes a paused job from its last checkpoint. If the job had
+// already stopped, the statement runs it until it finishes or is paused or
+// canceled again.
+// Privileges: superuser.
+func (p *planner) ResumeJob(ctx context.Context, n *parser.ResumeJob) (planNode, error) {
+	return p.controlJob(ctx, n.ID, JobStatusRunning, n.StatementTag(), n.Options)
+}
+
+// CancelJob cancels a job.
+// Privileges: superuser.
+func (p *planner) CancelJob(ctx context.Context, n *parser.CancelJob) (planNode, error) {
+	return p.controlJob(ctx, n.ID, JobStatusCanceled, n.StatementTag(), nil /* options */)
+}
+
+func (p *planner) controlJob(
+	ctx context.Context,
+	jobID parser.Expr,
+	desiredStatus JobStatus,
+	op string,
+	options parser.KVOptions,
+) (planNode, error) {
+	if err := p.RequireSuperUser(op); err != nil {
+		return nil, err
+	}
+	typedJobID, err := p.analyzeExpr(
+		ctx, jobID, nil, parser.IndexedVarHelper{}, parser.TypeInt, true, op,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &controlJobNode{
+		p:             p,
+		op:            op,
+		jobID:         typedJobID,
+		desiredStatus: desiredStatus,
+		options:       options,
+	}, nil
+}
+
+func (n *controlJobNode) Start(ctx context.Context) error {
+	jobIDDatum, err := n.jobID.Eval(&n.p.evalCtx)
+	if err != nil {
+		return err
+	}
+	jobID, ok := jobIDDatum.(*parser.DInt)
+	if !ok {
+		return errors.Errorf("%s requires a job ID, got %s", n.op, jobIDDatum)
+	}
+	jl, err := GetJobLogger(ctx, n.p.ExecCfg().DB, n.p.LeaseMgr(), int64(*jobID))
+	if err != nil {
+		return err
+	}
+
+	switch n.desiredStatus {
+	case JobStatusPaused:
+		return jl.paused(ctx)
+	case JobStatusCanceled:
+		return jl.canceled(ctx)
+	case JobStatusRunning:
+		return n.p.resumeJob(ctx, jl, n.options)
+	default:
+		return errors.Errorf("unexpected desired status %s", n.desiredStatus)
+	}
+}
+
+// resumeJob marks the job tracked by jl as running and, if the job had
+// stopped, runs it from its last checkpoint. Like the statement that created
+// the job, e.g. BACKUP, the job runs synchronously: RESUME JOB only returns
+// once the job has finished or was paused or canceled again. The options of
+// RESUME JOB are passed to the hook that runs the job.
+func (p *planner) resumeJob(ctx context.Context, jl *JobLogger, options parser.KVOptions) error {
+	var run func(context.Context) error
+	for _, hook := range jobResumeHooks {
+		var err error
+		if run, err = hook(ctx, p, jl, options); err != nil {
+			return err
+		} else if run != nil {
+			break
+		}
+	}
+	if run == nil {
+		return errors.Errorf("job %d cannot be resumed", *jl.JobID())
+	}
+
+	restart, err := jl.resumed(ctx)
+	if err != nil || !restart {
+		return err
+	}
+	return run(ctx)
+}
+
+func (*controlJobNode) Next(context.Context) (bool, error) { return false, nil }
+func (*controlJobNode) Close(context.Context)              {}
+func (*controlJobNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*controlJobNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*controlJobNode) Values() parser.Datums              { return parser.Datums{} }
+func (*controlJobNode) DebugValues() debugValues           { return debugValues{} }
+func (*controlJobNode) MarkDebug(mode explainMode)         {}
+
+func (*controlJobNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
@@ -238,6 +238,18 @@ func (n *copyNode) addRow(ctx context.Context, line []byte) error {
 				break
 			}
 			d, err = parser.ParseDTimestampTZ(s, n.p.session.Location, time.Microsecond)
+		case parser.TypeUUID:
+			s, err = decodeCopy(s)
+			if err != nil {
+				break
+			}
+			d, err = parser.ParseDUuidFromString(s)
+		case parser.TypeJSON:
+			s, err = decodeCopy(s)
+			if err != nil {
+				break
+			}
+			d, err = parser.ParseDJSON(s)
 		default:
 			return fmt.Errorf("unknown type %s", t)
 		}
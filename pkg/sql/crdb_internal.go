@@ -17,6 +17,7 @@
 package sql
 
 import (
+	"bytes"
 	"reflect"
 	"sort"
 	"time"
@@ -39,6 +40,8 @@ var crdbInternal = virtualSchema{
 		crdbInternalSchemaChangesTable,
 		crdbInternalStmtStatsTable,
 		crdbInternalJobsTable,
+		crdbInternalSessionsTable,
+		crdbInternalQueriesTable,
 	},
 }
 
@@ -453,3 +456,121 @@ CREATE TABLE crdb_internal.node_statement_statistics (
 		return nil
 	},
 }
+
+// sessionInfos returns a snapshot of the sessions on this node that are
+// visible to the planner's user.
+func (p *planner) sessionInfos() ([]sessionInfo, error) {
+	if p.session.execCfg == nil || p.session.execCfg.SessionRegistry == nil {
+		return nil, errors.New("cannot access sessions from this context")
+	}
+	return p.session.execCfg.SessionRegistry.sessionInfos(p.session.User), nil
+}
+
+var crdbInternalSessionsTable = virtualSchemaTable{
+	schema: `
+CREATE TABLE crdb_internal.node_sessions (
+  session_id         STRING NOT NULL,
+  node_id            INT NOT NULL,
+  username           STRING NOT NULL,
+  client_address     STRING,
+  application_name   STRING NOT NULL,
+  active_queries     STRING NOT NULL,
+  last_active_query  STRING,
+  session_start      TIMESTAMP NOT NULL,
+  oldest_query_start TIMESTAMP
+);
+`,
+	populate: func(_ context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		sessions, err := p.sessionInfos()
+		if err != nil {
+			return err
+		}
+		nodeID := parser.NewDInt(parser.DInt(int64(p.LeaseMgr().nodeID.Get())))
+		for _, s := range sessions {
+			clientAddr := parser.DNull
+			if s.clientAddr != "" {
+				clientAddr = parser.NewDString(s.clientAddr)
+			}
+			lastActiveQuery := parser.DNull
+			if s.lastActiveQuery != "" {
+				lastActiveQuery = parser.NewDString(s.lastActiveQuery)
+			}
+			var activeQueries bytes.Buffer
+			var oldestStartTime time.Time
+			for i, q := range s.activeQueries {
+				if i > 0 {
+					activeQueries.WriteString("; ")
+				}
+				activeQueries.WriteString(q.sql)
+				if oldestStartTime.IsZero() || q.start.Before(oldestStartTime) {
+					oldestStartTime = q.start
+				}
+			}
+			oldestStart := parser.DNull
+			if !oldestStartTime.IsZero() {
+				oldestStart = parser.MakeDTimestamp(oldestStartTime, time.Microsecond)
+			}
+			if err := addRow(
+				parser.NewDString(s.id),
+				nodeID,
+				parser.NewDString(s.user),
+				clientAddr,
+				parser.NewDString(s.applicationName),
+				parser.NewDString(activeQueries.String()),
+				lastActiveQuery,
+				parser.MakeDTimestamp(s.start, time.Microsecond),
+				oldestStart,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var crdbInternalQueriesTable = virtualSchemaTable{
+	schema: `
+CREATE TABLE crdb_internal.node_queries (
+  query_id         STRING NOT NULL,
+  node_id          INT NOT NULL,
+  session_id       STRING NOT NULL,
+  username         STRING NOT NULL,
+  start            TIMESTAMP NOT NULL,
+  query            STRING NOT NULL,
+  client_address   STRING,
+  application_name STRING NOT NULL,
+  distributed      BOOL NOT NULL,
+  phase            STRING NOT NULL
+);
+`,
+	populate: func(_ context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		sessions, err := p.sessionInfos()
+		if err != nil {
+			return err
+		}
+		nodeID := parser.NewDInt(parser.DInt(int64(p.LeaseMgr().nodeID.Get())))
+		for _, s := range sessions {
+			clientAddr := parser.DNull
+			if s.clientAddr != "" {
+				clientAddr = parser.NewDString(s.clientAddr)
+			}
+			for _, q := range s.activeQueries {
+				if err := addRow(
+					parser.NewDString(q.id),
+					nodeID,
+					parser.NewDString(s.id),
+					parser.NewDString(s.user),
+					parser.MakeDTimestamp(q.start, time.Microsecond),
+					parser.NewDString(q.sql),
+					clientAddr,
+					parser.NewDString(s.applicationName),
+					parser.MakeDBool(parser.DBool(q.isDistributed)),
+					parser.NewDString(q.phase.String()),
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	},
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -178,9 +179,20 @@ func (n *createIndexNode) Start(ctx context.Context) error {
 		Unique:           n.n.Unique,
 		StoreColumnNames: n.n.Storing.ToStrings(),
 	}
+	if n.n.Inverted {
+		if n.n.Interleave != nil {
+			return fmt.Errorf("inverted indexes cannot be interleaved")
+		}
+		indexDesc.Type = sqlbase.IndexDescriptor_INVERTED
+	}
 	if err := indexDesc.FillColumns(n.n.Columns); err != nil {
 		return err
 	}
+	if err := setPartialIndexPredicate(
+		n.tableDesc, &indexDesc, n.n.Predicate, n.p.session.SearchPath,
+	); err != nil {
+		return err
+	}
 
 	mutationIdx := len(n.tableDesc.Mutations)
 	n.tableDesc.AddIndexMutation(indexDesc, sqlbase.DescriptorMutation_ADD)
@@ -317,6 +329,12 @@ func (n *createUserNode) Start(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	// Users and roles share the same namespace.
+	if exists, err := n.p.roleExists(ctx, normalizedUsername); err != nil {
+		return err
+	} else if exists {
+		return errors.Errorf("a role named %s already exists", normalizedUsername)
+	}
 
 	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
 	rowsAffected, err := internalExecutor.ExecuteStatementInTransaction(
@@ -381,6 +399,13 @@ func (p *planner) CreateView(ctx context.Context, n *parser.CreateView) (planNod
 		return nil, err
 	}
 
+	// The names of common table expressions can't be told apart from table
+	// names when the view query is reformatted below.
+	if n.AsSource.With != nil {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"views do not currently support WITH clauses")
+	}
+
 	// To avoid races with ongoing schema changes to tables that the view
 	// depends on, make sure we use the most recent versions of table
 	// descriptors rather than the copies in the lease cache.
@@ -534,6 +559,108 @@ func (*createViewNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
 	panic("unimplemented")
 }
 
+type createSequenceNode struct {
+	p      *planner
+	n      *parser.CreateSequence
+	dbDesc *sqlbase.DatabaseDescriptor
+}
+
+// CreateSequence creates a sequence.
+// Privileges: CREATE on database.
+//
+//	Notes: postgres requires CREATE on database.
+func (p *planner) CreateSequence(ctx context.Context, n *parser.CreateSequence) (planNode, error) {
+	name, err := n.Name.NormalizeWithDatabaseName(p.session.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := MustGetDatabaseDesc(ctx, p.txn, p.getVirtualTabler(), name.Database())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.CheckPrivilege(dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	return &createSequenceNode{p: p, n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *createSequenceNode) Start(ctx context.Context) error {
+	seqName := n.n.Name.TableName().Table()
+	tKey := tableKey{parentID: n.dbDesc.ID, name: seqName}
+	key := tKey.Key()
+	if exists, err := descExists(ctx, n.p.txn, key); err == nil && exists {
+		if n.n.IfNotExists {
+			return nil
+		}
+		return sqlbase.NewRelationAlreadyExistsError(tKey.Name())
+	} else if err != nil {
+		return err
+	}
+
+	id, err := GenerateUniqueDescID(ctx, n.p.txn)
+	if err != nil {
+		return err
+	}
+
+	// Inherit permissions from the database descriptor.
+	privs := n.dbDesc.GetPrivileges()
+
+	desc, err := makeSequenceTableDesc(seqName, n.n.Options, n.dbDesc.ID, id, privs)
+	if err != nil {
+		return err
+	}
+
+	if err := desc.ValidateTable(); err != nil {
+		return err
+	}
+
+	if err := n.p.createDescriptorWithID(ctx, key, id, &desc); err != nil {
+		return err
+	}
+
+	// Initialize the sequence value such that the first call to nextval()
+	// returns the start value.
+	b := n.p.txn.NewBatch()
+	setSequenceValue(b, &desc, desc.SequenceOpts.Start, false /* isCalled */)
+	if err := n.p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	if err := desc.Validate(ctx, n.p.txn); err != nil {
+		return err
+	}
+
+	// Log Create Sequence event. This is an auditable log event and is
+	// recorded in the same transaction as the table descriptor update.
+	return MakeEventLogger(n.p.LeaseMgr()).InsertEventRecord(
+		ctx,
+		n.p.txn,
+		EventLogCreateSequence,
+		int32(desc.ID),
+		int32(n.p.evalCtx.NodeID),
+		struct {
+			SequenceName string
+			Statement    string
+			User         string
+		}{n.n.Name.String(), n.n.String(), n.p.session.User},
+	)
+}
+
+func (*createSequenceNode) Next(context.Context) (bool, error) { return false, nil }
+func (*createSequenceNode) Close(context.Context)              {}
+func (*createSequenceNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*createSequenceNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*createSequenceNode) Values() parser.Datums              { return parser.Datums{} }
+func (*createSequenceNode) DebugValues() debugValues           { return debugValues{} }
+func (*createSequenceNode) MarkDebug(mode explainMode)         {}
+
+func (*createSequenceNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
 type createTableNode struct {
 	p          *planner
 	n          *parser.CreateTable
@@ -782,6 +909,10 @@ const (
 func matchesIndex(
 	cols []sqlbase.ColumnDescriptor, idx sqlbase.IndexDescriptor, exact indexMatch,
 ) bool {
+	// The keys of inverted indexes do not contain the values of their column.
+	if idx.Type == sqlbase.IndexDescriptor_INVERTED {
+		return false
+	}
 	if len(cols) > len(idx.ColumnIDs) || (exact && len(cols) != len(idx.ColumnIDs)) {
 		return false
 	}
@@ -904,7 +1035,7 @@ func resolveFK(
 		found := false
 		// Find the index corresponding to the referenced column.
 		for i, idx := range target.Indexes {
-			if idx.Unique && matchesIndex(targetCols, idx, matchExact) {
+			if idx.Unique && !idx.IsPartial() && matchesIndex(targetCols, idx, matchExact) {
 				targetIdx = &target.Indexes[i]
 				found = true
 				break
@@ -935,6 +1066,11 @@ func resolveFK(
 	} else {
 		found := false
 		for i := range tbl.Indexes {
+			// Partial indexes don't have entries for all the rows, so they can't
+			// be used to check foreign keys.
+			if tbl.Indexes[i].IsPartial() {
+				continue
+			}
 			if matchesIndex(srcCols, tbl.Indexes[i], matchPrefix) {
 				if tbl.Indexes[i].ForeignKey.IsSet() {
 					return fmt.Errorf("columns cannot be used by multiple foreign key constraints")
@@ -1266,9 +1402,6 @@ func MakeTableDesc(
 	for _, def := range n.Defs {
 		if d, ok := def.(*parser.ColumnTableDef); ok {
 			if !desc.IsVirtualTable() {
-				if _, ok := d.Type.(*parser.ArrayColType); ok {
-					return desc, util.UnimplementedWithIssueErrorf(2115, "ARRAY column types are unsupported")
-				}
 				if _, ok := d.Type.(*parser.VectorColType); ok {
 					return desc, util.UnimplementedWithIssueErrorf(2115, "VECTOR column types are unsupported")
 				}
@@ -1296,6 +1429,16 @@ func MakeTableDesc(
 		}
 	}
 
+	// Now that all columns are in place, verify the expressions of the computed
+	// columns, which may reference any of them.
+	for _, col := range desc.Columns {
+		if col.IsComputed() {
+			if err := sqlbase.ValidateComputedColumn(&desc, col, searchPath); err != nil {
+				return desc, err
+			}
+		}
+	}
+
 	var primaryIndexColumnSet map[string]struct{}
 	for _, def := range n.Defs {
 		switch d := def.(type) {
@@ -1307,9 +1450,15 @@ func MakeTableDesc(
 				Name:             string(d.Name),
 				StoreColumnNames: d.Storing.ToStrings(),
 			}
+			if d.Inverted {
+				idx.Type = sqlbase.IndexDescriptor_INVERTED
+			}
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
+			if err := setPartialIndexPredicate(&desc, &idx, d.Predicate, searchPath); err != nil {
+				return desc, err
+			}
 			if err := desc.AddIndex(idx, false); err != nil {
 				return desc, err
 			}
@@ -1325,6 +1474,9 @@ func MakeTableDesc(
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return desc, err
 			}
+			if err := setPartialIndexPredicate(&desc, &idx, d.Predicate, searchPath); err != nil {
+				return desc, err
+			}
 			if err := desc.AddIndex(idx, d.PrimaryKey); err != nil {
 				return desc, err
 			}
@@ -1533,6 +1685,12 @@ func makeCheckConstraint(
 			return fmt.Errorf("column %q not found for constraint %q",
 				c.ColumnName, d.Expr.String()), false, nil
 		}
+		if col.IsComputed() {
+			// CHECK constraints are verified before the values of the computed
+			// columns are computed by the row writers.
+			return fmt.Errorf("constraint %q cannot reference computed column %q",
+				d.Expr.String(), col.Name), false, nil
+		}
 		if generateName {
 			nameBuf.WriteByte('_')
 			nameBuf.WriteString(col.Name)
@@ -0,0 +1,203 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// createStatsNode is a planNode that collects statistics on columns of a
+// table and stores them in system.table_statistics. Each column gets its own
+// statistic.
+type createStatsNode struct {
+	p         *planner
+	tableDesc *sqlbase.TableDescriptor
+	// name is the name of the statistics; it is empty for ANALYZE.
+	name    string
+	columns []sqlbase.ColumnDescriptor
+}
+
+// CreateStats collects statistics on a column of a table.
+// Privileges: SELECT on table.
+func (p *planner) CreateStats(ctx context.Context, n *parser.CreateStats) (planNode, error) {
+	tableDesc, err := p.getTableForStats(ctx, n.Table)
+	if err != nil {
+		return nil, err
+	}
+	if len(n.ColumnNames) != 1 {
+		return nil, pgerror.NewError(pgerror.CodeFeatureNotSupportedError,
+			"multi-column statistics are not supported yet")
+	}
+	col, err := tableDesc.FindActiveColumnByName(n.ColumnNames[0])
+	if err != nil {
+		return nil, err
+	}
+	return &createStatsNode{
+		p:         p,
+		tableDesc: tableDesc,
+		name:      string(n.Name),
+		columns:   []sqlbase.ColumnDescriptor{col},
+	}, nil
+}
+
+// Analyze collects statistics on all the columns of a table.
+// Privileges: SELECT on table.
+func (p *planner) Analyze(ctx context.Context, n *parser.Analyze) (planNode, error) {
+	tableDesc, err := p.getTableForStats(ctx, n.Table)
+	if err != nil {
+		return nil, err
+	}
+	return &createStatsNode{
+		p:         p,
+		tableDesc: tableDesc,
+		columns:   tableDesc.Columns,
+	}, nil
+}
+
+// getTableForStats resolves the table on which statistics are collected.
+func (p *planner) getTableForStats(
+	ctx context.Context, name parser.NormalizableTableName,
+) (*sqlbase.TableDescriptor, error) {
+	tn, err := name.NormalizeTableName()
+	if err != nil {
+		return nil, err
+	}
+	if err := tn.QualifyWithDatabase(p.session.Database); err != nil {
+		return nil, err
+	}
+	tableDesc, err := p.session.leases.getTableLease(ctx, p.txn, p.getVirtualTabler(), tn)
+	if err != nil {
+		return nil, err
+	}
+	if !tableDesc.IsTable() {
+		return nil, errors.Errorf("cannot create statistics on view %q", tn)
+	}
+	if tableDesc.IsVirtualTable() || tableDesc.ID <= keys.MaxReservedDescID {
+		return nil, errors.Errorf("cannot create statistics on system table %q", tn)
+	}
+	return tableDesc, nil
+}
+
+func (n *createStatsNode) Start(ctx context.Context) error {
+	execCfg := n.p.ExecCfg()
+	// The statistics are collected and stored in their own transaction, so
+	// that they become visible to the statistics cache (which reads them in
+	// separate transactions) as soon as they are invalidated below, even if
+	// the statement runs inside an explicit transaction.
+	if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		return n.createStats(ctx, txn)
+	}); err != nil {
+		return err
+	}
+	if execCfg.TableStatsCache != nil {
+		execCfg.TableStatsCache.InvalidateTableStats(n.tableDesc.ID)
+	}
+	// Let the other nodes know that their cached statistics are stale.
+	if execCfg.Gossip != nil {
+		return stats.GossipTableStatAdded(execCfg.Gossip, n.tableDesc.ID)
+	}
+	return nil
+}
+
+// createStats runs the plan that collects the statistics and inserts the
+// results in system.table_statistics.
+func (n *createStatsNode) createStats(ctx context.Context, txn *client.Txn) error {
+	execCfg := n.p.ExecCfg()
+	rows := sqlbase.NewRowContainer(
+		n.p.session.TxnState.makeBoundAccount(),
+		sqlbase.ColTypeInfoFromColTypes(sampleAggregatorOutTypes), 0,
+	)
+	defer rows.Close(ctx)
+
+	recv, err := makeDistSQLReceiver(
+		ctx, rows,
+		execCfg.RangeDescriptorCache, execCfg.LeaseHolderCache,
+		txn,
+		func(ts hlc.Timestamp) {
+			_ = execCfg.Clock.Update(ts)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	dsp := n.p.session.distSQLPlanner
+	planCtx := dsp.NewPlanningCtx(ctx, txn)
+	plan, err := dsp.createStatsPlan(&planCtx, n.p, n.tableDesc, n.columns)
+	if err != nil {
+		return err
+	}
+	dsp.FinalizePlan(&planCtx, &plan)
+	if err := dsp.Run(&planCtx, txn, &plan, &recv, n.p.evalCtx); err != nil {
+		return err
+	}
+	if recv.err != nil {
+		return recv.err
+	}
+
+	name := parser.DNull
+	if n.name != "" {
+		name = parser.NewDString(n.name)
+	}
+	internalExecutor := InternalExecutor{LeaseManager: n.p.LeaseMgr()}
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.At(i)
+		col := n.columns[int(*row[0].(*parser.DInt))]
+		columnIDs := parser.NewDArray(parser.TypeInt)
+		if err := columnIDs.Append(parser.NewDInt(parser.DInt(col.ID))); err != nil {
+			return err
+		}
+		if _, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx,
+			"insert-statistic",
+			txn,
+			`INSERT INTO system.table_statistics (
+					"tableID", name, "columnIDs", "rowCount", "distinctCount", "nullCount", histogram
+				) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			n.tableDesc.ID,
+			name,
+			columnIDs,
+			row[1],
+			row[2],
+			row[3],
+			row[4],
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*createStatsNode) Next(context.Context) (bool, error) { return false, nil }
+func (*createStatsNode) Close(context.Context)              {}
+func (*createStatsNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*createStatsNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*createStatsNode) Values() parser.Datums              { return parser.Datums{} }
+func (*createStatsNode) DebugValues() debugValues           { return debugValues{} }
+func (*createStatsNode) MarkDebug(mode explainMode)         {}
+
+func (*createStatsNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
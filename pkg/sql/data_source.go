@@ -308,8 +308,18 @@ func (p *planner) getDataSource(
 ) (planDataSource, error) {
 	switch t := src.(type) {
 	case *parser.NormalizableTableName:
+		// Is this perhaps the name of a common table expression?
+		tn, err := t.Normalize()
+		if err != nil {
+			return planDataSource{}, err
+		}
+		ds, foundCTE, err := p.getCTEDataSource(ctx, tn)
+		if err != nil || foundCTE {
+			return ds, err
+		}
+
 		// Usual case: a table.
-		tn, err := p.QualifyWithDatabase(ctx, t)
+		tn, err = p.QualifyWithDatabase(ctx, t)
 		if err != nil {
 			return planDataSource{}, err
 		}
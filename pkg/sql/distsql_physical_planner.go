@@ -253,6 +253,9 @@ func (dsp *distSQLPlanner) checkSupportForNode(node planNode) (distRecommendatio
 		return rec, nil
 
 	case *scanNode:
+		if n.index.Type == sqlbase.IndexDescriptor_INVERTED {
+			return 0, errors.Errorf("inverted index scans not supported yet")
+		}
 		rec := canDistribute
 		if n.hardLimit != 0 || n.softLimit != 0 {
 			// We don't yet recommend distributing plans where limits propagate
@@ -326,6 +329,9 @@ type planningCtx struct {
 	// physicalPlan we generate with this context.
 	// Nodes that fail a health check have empty addresses.
 	nodeAddresses map[roachpb.NodeID]string
+	// collectStats is set if the processors of the flows must report their
+	// execution statistics (for EXPLAIN ANALYZE).
+	collectStats bool
 }
 
 // physicalPlan is a partial physical plan which corresponds to a planNode
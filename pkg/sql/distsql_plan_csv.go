@@ -0,0 +1,338 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// csvKVTypes is the schema of the rows produced by the ReadCSV processors and
+// consumed by the SSTWriter processors: the key and the value of a KV.
+var csvKVTypes = []sqlbase.ColumnType{
+	{Kind: sqlbase.ColumnType_BYTES},
+	{Kind: sqlbase.ColumnType_BYTES},
+}
+
+// sstWriterOutTypes is the schema of the rows produced by the SSTWriter
+// processors: the name, the size, the first key and the end key of each
+// SSTable.
+var sstWriterOutTypes = []sqlbase.ColumnType{
+	{Kind: sqlbase.ColumnType_STRING},
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_BYTES},
+	{Kind: sqlbase.ColumnType_BYTES},
+}
+
+// csvWriterOutTypes is the schema of the rows produced by the CSVWriter
+// processors: the name, the number of rows and the size of each file.
+var csvWriterOutTypes = []sqlbase.ColumnType{
+	{Kind: sqlbase.ColumnType_STRING},
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_INT},
+}
+
+// LoadCSV converts the CSV files in the given ReadCSVSpec to the KVs of its
+// table and writes them as SSTables in the destination of the given
+// SSTWriterSpec. The files are read in parallel on all the healthy nodes of
+// the cluster; the KVs are then distributed across these nodes by hash, sorted
+// and written. Since the KVs written by different nodes are interleaved, the
+// key spans of the SSTables overlap, but a given key is in only one of them.
+//
+// The returned rows have the schema produced by the SSTWriter processors: the
+// name, the size, the first key and the end key of each SSTable written.
+//
+// If progressFn is not nil, it is called each time one of the files has been
+// read. If it returns an error, the conversion stops with that error.
+func LoadCSV(
+	ctx context.Context,
+	phs PlanHookState,
+	readSpec distsqlrun.ReadCSVSpec,
+	writeSpec distsqlrun.SSTWriterSpec,
+	progressFn func(distsqlrun.ReadCSVProgress) error,
+) ([]parser.Datums, error) {
+	p := phs.(*planner)
+	execCfg := p.ExecCfg()
+	var res []parser.Datums
+	err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		res = nil
+		rows := sqlbase.NewRowContainer(
+			p.session.TxnState.makeBoundAccount(),
+			sqlbase.ColTypeInfoFromColTypes(sstWriterOutTypes), 0,
+		)
+		defer rows.Close(ctx)
+
+		recv, err := makeDistSQLReceiver(
+			ctx, rows,
+			execCfg.RangeDescriptorCache, execCfg.LeaseHolderCache,
+			txn,
+			func(ts hlc.Timestamp) {
+				_ = execCfg.Clock.Update(ts)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		recv.readCSVProgress = progressFn
+
+		dsp := p.session.distSQLPlanner
+		planCtx := dsp.NewPlanningCtx(ctx, txn)
+		plan, err := dsp.createCSVPlan(&planCtx, execCfg.Gossip, readSpec, writeSpec)
+		if err != nil {
+			return err
+		}
+		dsp.FinalizePlan(&planCtx, &plan)
+		if err := dsp.Run(&planCtx, txn, &plan, &recv, p.evalCtx); err != nil {
+			return err
+		}
+		if recv.err != nil {
+			return recv.err
+		}
+		for i := 0; i < rows.Len(); i++ {
+			res = append(res, append(parser.Datums(nil), rows.At(i)...))
+		}
+		return nil
+	})
+	return res, err
+}
+
+// healthyNodes returns the IDs of the nodes of the cluster that are known
+// through gossip and whose connection is healthy, in increasing order. Their
+// addresses are recorded in the planning context. The gateway is always
+// included.
+func (dsp *distSQLPlanner) healthyNodes(
+	planCtx *planningCtx, g *gossip.Gossip,
+) ([]roachpb.NodeID, error) {
+	nodes := []roachpb.NodeID{dsp.nodeDesc.NodeID}
+	for key := range g.GetInfoStatus().Infos {
+		if !gossip.IsNodeIDKey(key) {
+			continue
+		}
+		var desc roachpb.NodeDescriptor
+		if err := g.GetInfoProto(key, &desc); err != nil {
+			return nil, err
+		}
+		if desc.NodeID == dsp.nodeDesc.NodeID {
+			continue
+		}
+		addr := desc.Address.String()
+		var err error
+		if dsp.testingKnobs.OverrideHealthCheck != nil {
+			err = dsp.testingKnobs.OverrideHealthCheck(desc.NodeID, addr)
+		} else {
+			err = dsp.rpcContext.ConnHealth(addr)
+		}
+		if err != nil && err != rpc.ErrNotConnected && err != rpc.ErrNotHeartbeated {
+			log.VEventf(planCtx.ctx, 1, "not using unhealthy node %d for this plan: %v", desc.NodeID, err)
+			continue
+		}
+		planCtx.nodeAddresses[desc.NodeID] = addr
+		nodes = append(nodes, desc.NodeID)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+	return nodes, nil
+}
+
+// createCSVPlan creates the plan used by LoadCSV: a ReadCSV processor on each
+// node that has files to read, whose output is distributed by hash of the key
+// to a sorter on each node, followed by an SSTWriter.
+func (dsp *distSQLPlanner) createCSVPlan(
+	planCtx *planningCtx,
+	g *gossip.Gossip,
+	readSpec distsqlrun.ReadCSVSpec,
+	writeSpec distsqlrun.SSTWriterSpec,
+) (physicalPlan, error) {
+	if len(readSpec.URI) == 0 {
+		return physicalPlan{}, errors.New("no files to import")
+	}
+	nodes, err := dsp.healthyNodes(planCtx, g)
+	if err != nil {
+		return physicalPlan{}, err
+	}
+
+	// Distribute the files round-robin across the nodes.
+	numReaders := len(nodes)
+	if len(readSpec.URI) < numReaders {
+		numReaders = len(readSpec.URI)
+	}
+	readSpecs := make([]distsqlrun.ReadCSVSpec, numReaders)
+	for i := range readSpecs {
+		readSpecs[i] = readSpec
+		readSpecs[i].URI = nil
+	}
+	for i, uri := range readSpec.URI {
+		readSpecs[i%numReaders].URI = append(readSpecs[i%numReaders].URI, uri)
+	}
+
+	var p physicalPlan
+	p.ResultRouters = make([]distsqlplan.ProcessorIdx, numReaders)
+	for i := range readSpecs {
+		proc := distsqlplan.Processor{
+			Node: nodes[i],
+			Spec: distsqlrun.ProcessorSpec{
+				Core: distsqlrun.ProcessorCoreUnion{ReadCSV: &readSpecs[i]},
+				Output: []distsqlrun.OutputRouterSpec{{
+					Type:        distsqlrun.OutputRouterSpec_BY_HASH,
+					HashColumns: []uint32{0},
+				}},
+			},
+		}
+		p.ResultRouters[i] = p.AddProcessor(proc)
+	}
+	p.ResultTypes = csvKVTypes
+
+	// Add a sorter on each node, each receiving one hash bucket from every
+	// reader.
+	keyOrdering := distsqlrun.Ordering{Columns: []distsqlrun.Ordering_Column{{
+		ColIdx:    0,
+		Direction: distsqlrun.Ordering_Column_ASC,
+	}}}
+	readers := p.ResultRouters
+	p.ResultRouters = make([]distsqlplan.ProcessorIdx, len(nodes))
+	for bucket, node := range nodes {
+		proc := distsqlplan.Processor{
+			Node: node,
+			Spec: distsqlrun.ProcessorSpec{
+				Input: []distsqlrun.InputSyncSpec{{
+					// The other fields will be filled in by MergeResultStreams.
+					ColumnTypes: csvKVTypes,
+				}},
+				Core: distsqlrun.ProcessorCoreUnion{Sorter: &distsqlrun.SorterSpec{
+					OutputOrdering: keyOrdering,
+				}},
+				Output: []distsqlrun.OutputRouterSpec{{
+					Type: distsqlrun.OutputRouterSpec_PASS_THROUGH,
+				}},
+			},
+		}
+		pIdx := p.AddProcessor(proc)
+		p.MergeResultStreams(readers, bucket, distsqlrun.Ordering{}, pIdx, 0)
+		p.ResultRouters[bucket] = pIdx
+	}
+
+	// Write the sorted KVs of each node.
+	p.AddNoGroupingStage(
+		distsqlrun.ProcessorCoreUnion{SSTWriter: &writeSpec},
+		distsqlrun.PostProcessSpec{},
+		sstWriterOutTypes,
+		distsqlrun.Ordering{},
+	)
+
+	p.planToStreamColMap = make([]int, len(sstWriterOutTypes))
+	for i := range p.planToStreamColMap {
+		p.planToStreamColMap[i] = i
+	}
+	return p, nil
+}
+
+// ExportCSV runs the given query with DistSQL and writes its results to CSV
+// files as specified by the given CSVWriterSpec. Each node that produces
+// results of the query writes its part of them; the order of the rows is not
+// preserved across files.
+//
+// The returned rows have the schema produced by the CSVWriter processors: the
+// name, the number of rows and the size of each file written.
+func ExportCSV(
+	ctx context.Context,
+	phs PlanHookState,
+	query *parser.Select,
+	writeSpec distsqlrun.CSVWriterSpec,
+) ([]parser.Datums, error) {
+	p := phs.(*planner)
+	execCfg := p.ExecCfg()
+
+	plan, err := p.makePlan(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer plan.Close(ctx)
+
+	dsp := p.session.distSQLPlanner
+	if _, err := dsp.CheckSupport(plan); err != nil {
+		return nil, errors.Wrap(err, "EXPORT query cannot be run with DistSQL")
+	}
+	// Trigger limit propagation.
+	setUnlimited(plan)
+
+	rows := sqlbase.NewRowContainer(
+		p.session.TxnState.makeBoundAccount(),
+		sqlbase.ColTypeInfoFromColTypes(csvWriterOutTypes), 0,
+	)
+	defer rows.Close(ctx)
+
+	recv, err := makeDistSQLReceiver(
+		ctx, rows,
+		execCfg.RangeDescriptorCache, execCfg.LeaseHolderCache,
+		p.txn,
+		func(ts hlc.Timestamp) {
+			_ = execCfg.Clock.Update(ts)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	planCtx := dsp.NewPlanningCtx(ctx, p.txn)
+	physPlan, err := dsp.createPlanForNode(&planCtx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	// Project the result columns of the query, in order. The files written by
+	// different nodes are not merged, so the ordering is not needed.
+	var cols []uint32
+	for i, col := range plan.Columns() {
+		if col.Hidden {
+			continue
+		}
+		cols = append(cols, uint32(physPlan.planToStreamColMap[i]))
+	}
+	physPlan.MergeOrdering = distsqlrun.Ordering{}
+	physPlan.AddProjection(cols)
+
+	physPlan.AddNoGroupingStage(
+		distsqlrun.ProcessorCoreUnion{CSVWriter: &writeSpec},
+		distsqlrun.PostProcessSpec{},
+		csvWriterOutTypes,
+		distsqlrun.Ordering{},
+	)
+	physPlan.planToStreamColMap = identityMap(physPlan.planToStreamColMap, len(csvWriterOutTypes))
+
+	dsp.FinalizePlan(&planCtx, &physPlan)
+	if err := dsp.Run(&planCtx, p.txn, &physPlan, &recv, p.evalCtx); err != nil {
+		return nil, err
+	}
+	if recv.err != nil {
+		return nil, recv.err
+	}
+	res := make([]parser.Datums, rows.Len())
+	for i := range res {
+		res[i] = append(parser.Datums(nil), rows.At(i)...)
+	}
+	return res, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlrun"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+)
+
+// histogramSamples is the number of rows sampled to build the histograms.
+const histogramSamples = 10000
+
+// histogramBuckets is the maximum number of buckets of the histograms.
+const histogramBuckets = 200
+
+// sampleAggregatorOutTypes is the schema of the rows produced by the plans
+// created by createStatsPlan: one row per column, with the index of the
+// column, the row count, the distinct count, the NULL count and the encoded
+// histogram (NULL if there is none).
+var sampleAggregatorOutTypes = []sqlbase.ColumnType{
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_INT},
+	{Kind: sqlbase.ColumnType_BYTES},
+}
+
+// createStatsPlan creates a plan that collects statistics on the given
+// columns of a table. The table is read by table readers on the nodes that
+// hold its ranges, each followed by a sampler; a single sample aggregator on
+// the gateway merges their results.
+func (dsp *distSQLPlanner) createStatsPlan(
+	planCtx *planningCtx,
+	p *planner,
+	desc *sqlbase.TableDescriptor,
+	columns []sqlbase.ColumnDescriptor,
+) (physicalPlan, error) {
+	scan := p.Scan()
+	if err := scan.initTable(p, desc, nil /* indexHints */, publicColumns, nil /* wantedColumns */); err != nil {
+		return physicalPlan{}, err
+	}
+	// Only the columns on which statistics are collected are read.
+	for i := range scan.valNeededForCol {
+		scan.valNeededForCol[i] = false
+	}
+	for _, c := range columns {
+		scan.valNeededForCol[scan.colIdxMap[c.ID]] = true
+	}
+	scan.index = &scan.desc.PrimaryIndex
+	var err error
+	scan.spans, err = makeSpans(nil /* constraints */, &scan.desc, scan.index)
+	if err != nil {
+		return physicalPlan{}, err
+	}
+
+	plan, err := dsp.createTableReaders(planCtx, scan, nil /* overrideResultColumns */)
+	if err != nil {
+		return physicalPlan{}, err
+	}
+
+	sketchSpecs := make([]distsqlrun.SketchSpec, len(columns))
+	for i, c := range columns {
+		sketchSpecs[i] = distsqlrun.SketchSpec{
+			Columns:           []uint32{uint32(plan.planToStreamColMap[scan.colIdxMap[c.ID]])},
+			GenerateHistogram: stats.HistogramSupported(c.Type),
+		}
+		if sketchSpecs[i].GenerateHistogram {
+			sketchSpecs[i].HistogramMaxBuckets = histogramBuckets
+		}
+	}
+
+	// Add a sampler after each table reader.
+	samplerOutTypes := make([]sqlbase.ColumnType, 0, len(plan.ResultTypes)+5)
+	samplerOutTypes = append(samplerOutTypes, plan.ResultTypes...)
+	samplerOutTypes = append(samplerOutTypes,
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},   // rank
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},   // sketch index
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},   // number of rows
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},   // number of NULLs
+		sqlbase.ColumnType{Kind: sqlbase.ColumnType_BYTES}, // sketch data
+	)
+	plan.AddNoGroupingStage(
+		distsqlrun.ProcessorCoreUnion{Sampler: &distsqlrun.SamplerSpec{
+			Sketches:   sketchSpecs,
+			SampleSize: histogramSamples,
+		}},
+		distsqlrun.PostProcessSpec{},
+		samplerOutTypes,
+		distsqlrun.Ordering{},
+	)
+
+	// Add the sample aggregator on the gateway.
+	plan.AddSingleGroupStage(
+		dsp.nodeDesc.NodeID,
+		distsqlrun.ProcessorCoreUnion{SampleAggregator: &distsqlrun.SampleAggregatorSpec{
+			Sketches:   sketchSpecs,
+			SampleSize: histogramSamples,
+		}},
+		distsqlrun.PostProcessSpec{},
+		sampleAggregatorOutTypes,
+	)
+
+	plan.planToStreamColMap = make([]int, len(sampleAggregatorOutTypes))
+	for i := range plan.planToStreamColMap {
+		plan.planToStreamColMap[i] = i
+	}
+	return plan, nil
+}
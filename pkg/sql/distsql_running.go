@@ -19,6 +19,7 @@ package sql
 import (
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -147,10 +148,11 @@ func (dsp *distSQLPlanner) Run(
 			continue
 		}
 		req := &distsqlrun.SetupFlowRequest{
-			Version:     distsqlrun.Version,
-			Txn:         *txn.Proto(),
-			Flow:        flowSpec,
-			EvalContext: evalCtxProto,
+			Version:      distsqlrun.Version,
+			Txn:          *txn.Proto(),
+			Flow:         flowSpec,
+			EvalContext:  evalCtxProto,
+			CollectStats: planCtx.collectStats,
 		}
 		if err := distsqlrun.SetFlowRequestTrace(ctx, req); err != nil {
 			return err
@@ -187,12 +189,27 @@ func (dsp *distSQLPlanner) Run(
 		return firstErr
 	}
 
+	// The remote flows don't run in a context derived from ctx, so they have to
+	// be canceled explicitly if the query is canceled while they run.
+	if len(flows) > 1 {
+		flowsDone := make(chan struct{})
+		defer close(flowsDone)
+		remoteAddrs := make(map[roachpb.NodeID]string, len(flows)-1)
+		for nodeID := range flows {
+			if nodeID != thisNodeID {
+				remoteAddrs[nodeID] = planCtx.nodeAddresses[nodeID]
+			}
+		}
+		dsp.cancelRemoteFlowsOnCancel(ctx, flowsDone, flows[thisNodeID].FlowID, remoteAddrs)
+	}
+
 	// Set up the flow on this node.
 	localReq := distsqlrun.SetupFlowRequest{
-		Version:     distsqlrun.Version,
-		Txn:         *txn.Proto(),
-		Flow:        flows[thisNodeID],
-		EvalContext: evalCtxProto,
+		Version:      distsqlrun.Version,
+		Txn:          *txn.Proto(),
+		Flow:         flows[thisNodeID],
+		EvalContext:  evalCtxProto,
+		CollectStats: planCtx.collectStats,
 	}
 	if err := distsqlrun.SetFlowRequestTrace(ctx, &localReq); err != nil {
 		return err
@@ -209,6 +226,43 @@ func (dsp *distSQLPlanner) Run(
 	return nil
 }
 
+// cancelRemoteFlowsOnCancel starts a task that waits until either ctx is
+// canceled or doneCh is closed. In the former case, it asks the given nodes to
+// cancel their flows with the given ID.
+func (dsp *distSQLPlanner) cancelRemoteFlowsOnCancel(
+	ctx context.Context,
+	doneCh <-chan struct{},
+	flowID distsqlrun.FlowID,
+	nodeAddresses map[roachpb.NodeID]string,
+) {
+	if ctx.Done() == nil {
+		// The context cannot be canceled.
+		return
+	}
+	if err := dsp.stopper.RunAsyncTask(ctx, func(ctx context.Context) {
+		select {
+		case <-doneCh:
+			return
+		case <-ctx.Done():
+		}
+		req := &distsqlrun.CancelFlowRequest{FlowID: flowID}
+		for nodeID, addr := range nodeAddresses {
+			conn, err := dsp.rpcContext.GRPCDial(addr)
+			if err == nil {
+				// ctx is canceled, so the RPC needs a context of its own.
+				rpcCtx, cancel := context.WithTimeout(context.Background(), base.NetworkTimeout)
+				_, err = distsqlrun.NewDistSQLClient(conn).CancelFlow(rpcCtx, req)
+				cancel()
+			}
+			if err != nil {
+				log.Warningf(ctx, "failed to cancel flow %s on node %d: %s", flowID.Short(), nodeID, err)
+			}
+		}
+	}); err != nil {
+		log.Warningf(ctx, "unable to forward cancellation to remote flows: %s", err)
+	}
+}
+
 // distSQLReceiver is a RowReceiver that stores incoming rows in a RowContainer.
 // This is where the DistSQL execution meets the SQL Session - the RowContainer
 // comes from a client Session.
@@ -234,6 +288,15 @@ type distSQLReceiver struct {
 	// Once set, no more rows are accepted.
 	err error
 
+	// stats accumulates the execution statistics reported by the processors,
+	// keyed by processor ID. Only populated when the flows collect them.
+	stats map[int32]distsqlrun.ProcessorStats
+
+	// readCSVProgress, if set, is called for each file that the ReadCSV
+	// processors finish reading. If it returns an error, the flow is stopped and
+	// the error is returned for the query.
+	readCSVProgress func(distsqlrun.ReadCSVProgress) error
+
 	row    parser.Datums
 	status distsqlrun.ConsumerStatus
 	alloc  sqlbase.DatumAlloc
@@ -312,6 +375,18 @@ func (r *distSQLReceiver) Push(
 				r.err = err
 			}
 		}
+		if meta.Stats != nil {
+			if r.stats == nil {
+				r.stats = make(map[int32]distsqlrun.ProcessorStats)
+			}
+			r.stats[meta.Stats.ProcessorID] = *meta.Stats
+		}
+		if meta.ReadCSVProgress != nil && r.readCSVProgress != nil && r.err == nil {
+			if err := r.readCSVProgress(*meta.ReadCSVProgress); err != nil {
+				r.err = err
+				r.status = distsqlrun.ConsumerClosed
+			}
+		}
 		return r.status
 	}
 	if r.err != nil {
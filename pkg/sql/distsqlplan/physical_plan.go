@@ -680,12 +680,14 @@ func (p *PhysicalPlan) GenerateFlowSpecs() map[roachpb.NodeID]distsqlrun.FlowSpe
 	flowID := distsqlrun.FlowID{UUID: uuid.MakeV4()}
 	flows := make(map[roachpb.NodeID]distsqlrun.FlowSpec)
 
-	for _, proc := range p.Processors {
+	for i, proc := range p.Processors {
 		flowSpec, ok := flows[proc.Node]
 		if !ok {
 			flowSpec = distsqlrun.FlowSpec{FlowID: flowID}
 		}
-		flowSpec.Processors = append(flowSpec.Processors, proc.Spec)
+		spec := proc.Spec
+		spec.ProcessorID = int32(i)
+		flowSpec.Processors = append(flowSpec.Processors, spec)
 		flows[proc.Node] = flowSpec
 	}
 	return flows
@@ -84,7 +84,7 @@ type aggregator struct {
 	out procOutputHelper
 }
 
-var _ processor = &aggregator{}
+var _ Processor = &aggregator{}
 
 func newAggregator(
 	flowCtx *FlowCtx,
@@ -135,7 +135,7 @@ func newAggregator(
 	return ag, nil
 }
 
-// Run is part of the processor interface.
+// Run is part of the Processor interface.
 func (ag *aggregator) Run(ctx context.Context, wg *sync.WaitGroup) {
 	if wg != nil {
 		defer wg.Done()
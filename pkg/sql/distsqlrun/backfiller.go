@@ -63,7 +63,7 @@ type backfiller struct {
 	fetcher sqlbase.RowFetcher
 }
 
-// Run is part of the processor interface.
+// Run is part of the Processor interface.
 func (b *backfiller) Run(ctx context.Context, wg *sync.WaitGroup) {
 	if wg != nil {
 		defer wg.Done()
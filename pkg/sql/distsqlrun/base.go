@@ -262,11 +262,18 @@ type ProducerMetadata struct {
 	Ranges []roachpb.RangeInfo
 	// TODO(vivek): change to type Error
 	Err error
+	// Stats are the execution statistics of a processor; only sent when the
+	// flow collects them (see SetupFlowRequest.CollectStats).
+	Stats *ProcessorStats
+	// ReadCSVProgress is sent by ReadCSV processors to report the files they
+	// finished reading.
+	ReadCSVProgress *ReadCSVProgress
 }
 
 // Empty returns true if none of the fields in metadata are populated.
 func (meta ProducerMetadata) Empty() bool {
-	return meta.Ranges == nil && meta.Err == nil
+	return meta.Ranges == nil && meta.Err == nil && meta.Stats == nil &&
+		meta.ReadCSVProgress == nil
 }
 
 // RowChannel is a thin layer over a RowChannelMsg channel, which can be used to
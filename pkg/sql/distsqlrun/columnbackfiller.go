@@ -35,9 +35,24 @@ type columnBackfiller struct {
 	// updateCols is a slice of all column descriptors that are being modified.
 	updateCols  []sqlbase.ColumnDescriptor
 	updateExprs []parser.TypedExpr
+	// conversions maps the positions in added of the shadow columns of columns
+	// whose type is changed to the conversions that compute their values.
+	conversions map[int]columnConversion
+	// computed maps the positions in added of the computed columns to the
+	// evaluators that compute their values from the fetched rows, whose layout
+	// is given by colIdxMap.
+	computed  map[int]*sqlbase.ComputedColumnEvaluator
+	colIdxMap map[sqlbase.ColumnID]int
 }
 
-var _ processor = &columnBackfiller{}
+// columnConversion computes the value of a shadow column from the value of
+// the column it replaces, at position srcIdx in the fetched rows.
+type columnConversion struct {
+	srcIdx int
+	conv   *sqlbase.ColumnConverter
+}
+
+var _ Processor = &columnBackfiller{}
 var _ chunkBackfiller = &columnBackfiller{}
 
 // ColumnMutationFilter is a filter that allows mutations that add or drop
@@ -70,9 +85,6 @@ func newColumnBackfiller(
 func (cb *columnBackfiller) init() error {
 	desc := cb.spec.Table
 
-	// colIdxMap maps ColumnIDs to indices into desc.Columns and desc.Mutations.
-	var colIdxMap map[sqlbase.ColumnID]int
-
 	// Note if there is a new non nullable column with no default value.
 	// If that's the case, and we end up reading a non-zero amount of data,
 	// we need a throw an error since the old columns will already violate the
@@ -84,11 +96,29 @@ func (cb *columnBackfiller) init() error {
 			if ColumnMutationFilter(m) {
 				switch m.Direction {
 				case sqlbase.DescriptorMutation_ADD:
-					desc := *m.GetColumn()
-					cb.added = append(cb.added, desc)
-					if desc.DefaultExpr == nil && !desc.Nullable {
+					col := *m.GetColumn()
+					if m.ShadowedColumnID != 0 {
+						conversion, err := makeColumnConversion(&desc, m.ShadowedColumnID, col)
+						if err != nil {
+							return err
+						}
+						if cb.conversions == nil {
+							cb.conversions = make(map[int]columnConversion)
+						}
+						cb.conversions[len(cb.added)] = conversion
+					} else if col.IsComputed() {
+						eval, err := sqlbase.MakeComputedColumnEvaluator(&desc, col)
+						if err != nil {
+							return err
+						}
+						if cb.computed == nil {
+							cb.computed = make(map[int]*sqlbase.ComputedColumnEvaluator)
+						}
+						cb.computed[len(cb.added)] = eval
+					} else if col.DefaultExpr == nil && !col.Nullable {
 						addingNonNullableColumn = true
 					}
+					cb.added = append(cb.added, col)
 				case sqlbase.DescriptorMutation_DROP:
 					cb.dropped = append(cb.dropped, *m.GetColumn())
 				}
@@ -101,7 +131,8 @@ func (cb *columnBackfiller) init() error {
 	}
 
 	cb.updateCols = append(cb.added, cb.dropped...)
-	if len(cb.dropped) > 0 || addingNonNullableColumn || len(defaultExprs) > 0 {
+	if len(cb.dropped) > 0 || addingNonNullableColumn || len(defaultExprs) > 0 ||
+		len(cb.conversions) > 0 || len(cb.computed) > 0 {
 		// Populate default values.
 		cb.updateExprs = make([]parser.TypedExpr, len(cb.updateCols))
 		for j := range cb.added {
@@ -122,15 +153,29 @@ func (cb *columnBackfiller) init() error {
 		valNeededForCol[i] = true
 	}
 
-	colIdxMap = make(map[sqlbase.ColumnID]int, len(desc.Columns))
+	// colIdxMap maps ColumnIDs to indices into desc.Columns.
+	cb.colIdxMap = make(map[sqlbase.ColumnID]int, len(desc.Columns))
 	for i, c := range desc.Columns {
-		colIdxMap[c.ID] = i
+		cb.colIdxMap[c.ID] = i
 	}
 	return cb.fetcher.Init(
-		&desc, colIdxMap, &desc.PrimaryIndex, false, false, desc.Columns, valNeededForCol, false,
+		&desc, cb.colIdxMap, &desc.PrimaryIndex, false, false, desc.Columns, valNeededForCol, false,
 	)
 }
 
+func makeColumnConversion(
+	desc *sqlbase.TableDescriptor, srcID sqlbase.ColumnID, shadow sqlbase.ColumnDescriptor,
+) (columnConversion, error) {
+	for i, col := range desc.Columns {
+		if col.ID == srcID {
+			conv, err := sqlbase.MakeColumnConverter(col, shadow)
+			return columnConversion{srcIdx: i, conv: conv}, err
+		}
+	}
+	return columnConversion{}, errors.Errorf(
+		"column %d replaced by shadow column %q not found", srcID, shadow.Name)
+}
+
 // runChunk implements the chunkBackfiller interface.
 func (cb *columnBackfiller) runChunk(
 	ctx context.Context, mutations []sqlbase.DescriptorMutation, sp roachpb.Span, chunkSize int64,
@@ -211,6 +256,25 @@ func (cb *columnBackfiller) runChunk(
 			// Evaluate the new values. This must be done separately for
 			// each row so as to handle impure functions correctly.
 			for j, e := range cb.updateExprs {
+				if c, ok := cb.conversions[j]; ok {
+					val, err := c.conv.Convert(row[c.srcIdx])
+					if err != nil {
+						return err
+					}
+					updateValues[j] = val
+					continue
+				}
+				if eval, ok := cb.computed[j]; ok {
+					val, err := eval.Eval(row, cb.colIdxMap)
+					if err != nil {
+						if !sqlbase.IsPermanentSchemaChangeError(err) {
+							err = sqlbase.NewInvalidSchemaDefinitionError(err)
+						}
+						return err
+					}
+					updateValues[j] = val
+					continue
+				}
 				val, err := e.Eval(&cb.flowCtx.evalCtx)
 				if err != nil {
 					return sqlbase.NewInvalidSchemaDefinitionError(err)
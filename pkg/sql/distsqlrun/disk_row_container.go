@@ -0,0 +1,194 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"bytes"
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// diskRowContainerBatchSize is the size (in bytes) of the writes that a
+// diskRowContainer accumulates before committing them to the engine.
+const diskRowContainerBatchSize = 1 << 20
+
+// diskRowContainerID is used to generate the key prefixes of diskRowContainers;
+// every container gets its own prefix in the temporary storage engine.
+var diskRowContainerID uint64
+
+// diskRowContainer is an append-only list of rows stored in a temporary
+// storage engine. It is used by processors that run out of memory to spill
+// their rows to disk. The rows are read back, in the order in which they were
+// added, with an iterator.
+//
+// Each row is stored under a key made of the container's prefix and the row's
+// index, and its value is the concatenation of the VALUE encodings of its
+// columns.
+type diskRowContainer struct {
+	engine engine.Engine
+	types  []sqlbase.ColumnType
+	prefix roachpb.Key
+
+	// batch accumulates the writes that haven't been committed yet.
+	batch     engine.Batch
+	batchSize int
+	numRows   int
+
+	scratchKey []byte
+	scratchVal []byte
+	datumAlloc sqlbase.DatumAlloc
+}
+
+func makeDiskRowContainer(types []sqlbase.ColumnType, e engine.Engine) diskRowContainer {
+	id := atomic.AddUint64(&diskRowContainerID, 1)
+	return diskRowContainer{
+		engine: e,
+		types:  types,
+		prefix: roachpb.Key(encoding.EncodeUvarintAscending(nil, id)),
+	}
+}
+
+// Len reports the number of rows in the container.
+func (d *diskRowContainer) Len() int {
+	return d.numRows
+}
+
+// AddRow appends a row to the container.
+func (d *diskRowContainer) AddRow(ctx context.Context, row sqlbase.EncDatumRow) error {
+	if len(row) != len(d.types) {
+		log.Fatalf(ctx, "invalid row length %d, expected %d", len(row), len(d.types))
+	}
+	d.scratchKey = encoding.EncodeUvarintAscending(
+		append(d.scratchKey[:0], d.prefix...), uint64(d.numRows),
+	)
+	d.scratchVal = d.scratchVal[:0]
+	for i := range row {
+		var err error
+		d.scratchVal, err = row[i].Encode(&d.datumAlloc, sqlbase.DatumEncoding_VALUE, d.scratchVal)
+		if err != nil {
+			return err
+		}
+	}
+	if d.batch == nil {
+		d.batch = d.engine.NewWriteOnlyBatch()
+	}
+	if err := d.batch.Put(engine.MakeMVCCMetadataKey(d.scratchKey), d.scratchVal); err != nil {
+		return err
+	}
+	d.numRows++
+	d.batchSize += len(d.scratchKey) + len(d.scratchVal)
+	if d.batchSize >= diskRowContainerBatchSize {
+		return d.flush()
+	}
+	return nil
+}
+
+// flush commits the pending writes to the engine.
+func (d *diskRowContainer) flush() error {
+	if d.batch == nil {
+		return nil
+	}
+	err := d.batch.Commit(false /* sync */)
+	d.batch.Close()
+	d.batch = nil
+	d.batchSize = 0
+	return err
+}
+
+// newIterator returns an iterator over the rows of the container. Rows must
+// not be added to the container while the iterator is in use.
+func (d *diskRowContainer) newIterator() (*diskRowIterator, error) {
+	if err := d.flush(); err != nil {
+		return nil, err
+	}
+	it := &diskRowIterator{
+		container: d,
+		iter:      d.engine.NewIterator(false /* prefix */),
+		row:       make(sqlbase.EncDatumRow, len(d.types)),
+	}
+	it.iter.Seek(engine.MakeMVCCMetadataKey(d.prefix))
+	return it, nil
+}
+
+// Close releases the resources of the container and deletes its rows.
+func (d *diskRowContainer) Close(ctx context.Context) {
+	if d.batch != nil {
+		d.batch.Close()
+		d.batch = nil
+	}
+	if d.numRows == 0 {
+		return
+	}
+	if err := d.engine.ClearRange(
+		engine.MakeMVCCMetadataKey(d.prefix), engine.MakeMVCCMetadataKey(d.prefix.PrefixEnd()),
+	); err != nil {
+		// The rows are garbage that will be wiped when the node restarts.
+		log.Warningf(ctx, "could not clear temporary rows: %s", err)
+	}
+	d.numRows = 0
+}
+
+// diskRowIterator iterates over the rows of a diskRowContainer.
+type diskRowIterator struct {
+	container *diskRowContainer
+	iter      engine.Iterator
+	row       sqlbase.EncDatumRow
+}
+
+// Next returns the next row of the container, or nil once all the rows have
+// been returned. The row is only valid until the next call to Next.
+func (it *diskRowIterator) Next() (sqlbase.EncDatumRow, error) {
+	if ok, err := it.iter.Valid(); err != nil || !ok {
+		return nil, err
+	}
+	if !bytes.HasPrefix(it.iter.UnsafeKey().Key, it.container.prefix) {
+		return nil, nil
+	}
+	// The datums may reference the value, so it is copied.
+	buf := it.iter.Value()
+	for i, typ := range it.container.types {
+		var err error
+		it.row[i], buf, err = sqlbase.EncDatumFromBuffer(typ, sqlbase.DatumEncoding_VALUE, buf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	it.iter.Next()
+	return it.row, nil
+}
+
+// Close releases the resources of the iterator.
+func (it *diskRowIterator) Close() {
+	it.iter.Close()
+}
+
+// shouldSpillToDisk returns whether a processor that got the given error while
+// accumulating rows in memory should instead store them in the flow's
+// temporary storage.
+func shouldSpillToDisk(flowCtx *FlowCtx, err error) bool {
+	if flowCtx.tempStorage == nil {
+		return false
+	}
+	pgErr, ok := pgerror.GetPGCause(err)
+	return ok && pgErr.Code == pgerror.CodeOutOfMemoryError
+}
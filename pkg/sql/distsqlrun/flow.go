@@ -27,7 +27,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
@@ -68,6 +70,10 @@ type FlowCtx struct {
 	// run.
 	nodeID       roachpb.NodeID
 	testingKnobs TestingKnobs
+	// tempStorage is used by the processors that accumulate rows (e.g. sorters
+	// and hash joiners) to spill them to disk when they don't fit in memory. If
+	// nil, those processors fail once they run out of memory.
+	tempStorage engine.Engine
 }
 
 func (flowCtx *FlowCtx) setupTxn() *client.Txn {
@@ -78,6 +84,17 @@ func (flowCtx *FlowCtx) setupTxn() *client.Txn {
 	return txn
 }
 
+// EvalCtx returns the context used to evaluate expressions in the flow. It is
+// used by the processors implemented outside of this package.
+func (flowCtx *FlowCtx) EvalCtx() *parser.EvalContext {
+	return &flowCtx.evalCtx
+}
+
+// NodeID returns the ID of the node on which the flow runs.
+func (flowCtx *FlowCtx) NodeID() roachpb.NodeID {
+	return flowCtx.nodeID
+}
+
 type flowStatus int
 
 // Flow status indicators.
@@ -92,7 +109,7 @@ type Flow struct {
 	FlowCtx
 
 	flowRegistry *flowRegistry
-	processors   []processor
+	processors   []Processor
 	outboxes     []*outbox
 	// syncFlowConsumer is a special outbox which instead of sending rows to
 	// another host, returns them directly (as a result to a SetupSyncFlow RPC,
@@ -101,6 +118,13 @@ type Flow struct {
 
 	localStreams map[StreamID]RowReceiver
 
+	// collectStats is set if the processors report their execution statistics
+	// (see SetupFlowRequest.CollectStats).
+	collectStats bool
+	// processorMonitors are the memory monitors of the individual processors,
+	// used when collecting statistics. They are stopped in Cleanup.
+	processorMonitors []*mon.MemoryMonitor
+
 	// inboundStreams are streams that receive data from other hosts; this map
 	// is to be passed to flowRegistry.RegisterFlow.
 	inboundStreams map[StreamID]*inboundStreamInfo
@@ -113,6 +137,11 @@ type Flow struct {
 
 	doneFn func()
 
+	// ctxCancel, if set, cancels the context that the flow runs in. It is set
+	// for flows set up through the SetupFlow RPC, whose context is not derived
+	// from the context of the query on the gateway.
+	ctxCancel context.CancelFunc
+
 	status flowStatus
 }
 
@@ -219,7 +248,9 @@ func checkNumInOut(inputs []RowSource, outputs []RowReceiver, numIn, numOut int)
 	return nil
 }
 
-func (f *Flow) makeProcessor(ps *ProcessorSpec, inputs []RowSource) (processor, error) {
+func (f *Flow) makeProcessor(
+	ctx context.Context, ps *ProcessorSpec, inputs []RowSource,
+) (Processor, error) {
 	if len(ps.Output) != 1 {
 		return nil, errors.Errorf("only single-output processors supported")
 	}
@@ -231,6 +262,14 @@ func (f *Flow) makeProcessor(ps *ProcessorSpec, inputs []RowSource) (processor,
 			return nil, err
 		}
 	}
+	if f.collectStats {
+		proc, procMon, err := f.makeStatsProcessor(ctx, ps, inputs, outputs)
+		if err != nil {
+			return nil, err
+		}
+		f.processorMonitors = append(f.processorMonitors, procMon)
+		return proc, nil
+	}
 	return newProcessor(&f.FlowCtx, &ps.Core, &ps.Post, inputs, outputs)
 }
 
@@ -286,11 +325,11 @@ func (f *Flow) setupFlow(ctx context.Context, spec *FlowSpec) error {
 		}
 	}
 
-	f.processors = make([]processor, len(spec.Processors))
+	f.processors = make([]Processor, len(spec.Processors))
 
 	for i := range spec.Processors {
 		var err error
-		f.processors[i], err = f.makeProcessor(&spec.Processors[i], inputSyncs[i])
+		f.processors[i], err = f.makeProcessor(ctx, &spec.Processors[i], inputSyncs[i])
 		if err != nil {
 			return err
 		}
@@ -333,6 +372,9 @@ func (f *Flow) Cleanup(ctx context.Context) {
 	if f.status == FlowFinished {
 		panic("flow cleanup called twice")
 	}
+	for _, m := range f.processorMonitors {
+		m.Stop(ctx)
+	}
 	// This closes the account and monitor opened in ServerImpl.setupFlow
 	f.evalCtx.Mon.Stop(ctx)
 	if log.V(1) {
@@ -346,6 +388,9 @@ func (f *Flow) Cleanup(ctx context.Context) {
 	f.status = FlowFinished
 	f.doneFn()
 	f.doneFn = nil
+	if f.ctxCancel != nil {
+		f.ctxCancel()
+	}
 }
 
 // RunSync runs the processors in the flow in order (serially), in the same
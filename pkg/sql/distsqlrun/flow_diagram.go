@@ -132,6 +132,20 @@ func (hj *HashJoinerSpec) summary() (string, []string) {
 	return "HashJoiner", details
 }
 
+func (mj *MergeJoinerSpec) summary() (string, []string) {
+	details := []string{fmt.Sprintf(
+		"left(%s)=right(%s)", mj.LeftOrdering.diagramString(), mj.RightOrdering.diagramString(),
+	)}
+	if mj.OnExpr.Expr != "" {
+		details = append(details, fmt.Sprintf("ON %s", mj.OnExpr.Expr))
+	}
+	return "MergeJoiner", details
+}
+
+func (s *AlgebraicSetOpSpec) summary() (string, []string) {
+	return "SetOp", []string{s.OpType.String(), s.Ordering.diagramString()}
+}
+
 func (s *SorterSpec) summary() (string, []string) {
 	details := []string{s.OutputOrdering.diagramString()}
 	if s.OrderingMatchLen != 0 {
@@ -158,6 +172,40 @@ func (d *DistinctSpec) summary() (string, []string) {
 	return "Distinct", details
 }
 
+func (s *SamplerSpec) summary() (string, []string) {
+	details := []string{fmt.Sprintf("SampleSize: %d", s.SampleSize)}
+	for _, sk := range s.Sketches {
+		details = append(details, fmt.Sprintf("Stat: %s", colListStr(sk.Columns)))
+	}
+	return "Sampler", details
+}
+
+func (s *SampleAggregatorSpec) summary() (string, []string) {
+	details := []string{fmt.Sprintf("SampleSize: %d", s.SampleSize)}
+	for _, sk := range s.Sketches {
+		details = append(details, fmt.Sprintf("Stat: %s", colListStr(sk.Columns)))
+	}
+	return "SampleAggregator", details
+}
+
+func (r *ReadCSVSpec) summary() (string, []string) {
+	return "ReadCSV", []string{r.TableDesc.Name}
+}
+
+func (s *SSTWriterSpec) summary() (string, []string) {
+	return "SSTWriter", []string{s.Destination}
+}
+
+func (c *CSVWriterSpec) summary() (string, []string) {
+	return "CSVWriter", []string{c.Destination}
+}
+
+// Title returns the name of the processor core, as shown in plan diagrams.
+func (pcu *ProcessorCoreUnion) Title() string {
+	title, _ := pcu.GetValue().(diagramCellType).summary()
+	return title
+}
+
 func (is *InputSyncSpec) summary() (string, []string) {
 	switch is.Type {
 	case InputSyncSpec_UNORDERED:
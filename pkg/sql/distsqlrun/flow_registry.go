@@ -205,6 +205,21 @@ func (fr *flowRegistry) UnregisterFlow(id FlowID) {
 	fr.Unlock()
 }
 
+// CancelFlow cancels the context of the flow with the given ID. The flow's
+// processors notice the cancellation and shut down with an error. It returns
+// false if the flow is not registered (for example because it finished, or is
+// still queued in the flowScheduler) or if its context cannot be canceled.
+func (fr *flowRegistry) CancelFlow(id FlowID) bool {
+	fr.Lock()
+	defer fr.Unlock()
+	entry, ok := fr.flows[id]
+	if !ok || entry.flow == nil || entry.flow.ctxCancel == nil {
+		return false
+	}
+	entry.flow.ctxCancel()
+	return true
+}
+
 // waitForFlowLocked returns the flowEntry of a registered flow with the given
 // ID. If no such flow is registered, waits until it gets registered - up to the
 // given timeout. If the timeout elapses, returns nil. It should only be called
@@ -179,6 +179,38 @@ func TestFlowRegistry(t *testing.T) {
 
 // Test that, if inbound streams are not connected within the timeout, errors
 // are propagated to their consumers and future attempts to connect them fail.
+func TestFlowRegistryCancelFlow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	reg := makeFlowRegistry()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	id := FlowID{uuid.MakeV4()}
+	f := &Flow{ctxCancel: cancel}
+
+	if reg.CancelFlow(id) {
+		t.Fatalf("canceled unregistered flow %s", id)
+	}
+	reg.RegisterFlow(context.TODO(), id, f, nil /* inboundStreams */, 0)
+	if !reg.CancelFlow(id) {
+		t.Fatalf("failed to cancel flow %s", id)
+	}
+	if ctx.Err() != context.Canceled {
+		t.Fatalf("expected flow context to be canceled, got %v", ctx.Err())
+	}
+	reg.UnregisterFlow(id)
+	if reg.CancelFlow(id) {
+		t.Fatalf("canceled unregistered flow %s", id)
+	}
+
+	// Flows without a cancelable context (e.g. sync flows) are left alone.
+	syncID := FlowID{uuid.MakeV4()}
+	reg.RegisterFlow(context.TODO(), syncID, &Flow{}, nil /* inboundStreams */, 0)
+	if reg.CancelFlow(syncID) {
+		t.Fatalf("canceled flow %s without a cancelable context", syncID)
+	}
+	reg.UnregisterFlow(syncID)
+}
+
 func TestStreamConnectionTimeout(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	reg := makeFlowRegistry()
@@ -17,6 +17,8 @@
 package distsqlrun
 
 import (
+	"hash"
+	"hash/fnv"
 	"sync"
 	"unsafe"
 
@@ -24,6 +26,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 )
@@ -50,9 +53,17 @@ const sizeOfRowIdx = int64(unsafe.Sizeof(int(0)))
 // guaranteed that results that involve the left stream preserve the ordering;
 // i.e. all results that stem from left row (i) precede results that stem from
 // left row (i+1).
+//
+// If the right stream doesn't fit in memory and the flow has temporary storage,
+// the hash joiner falls back to a grace hash join: both streams are split into
+// partitions on disk by the hash of their equality columns, and each pair of
+// matching partitions is then joined in memory. The ordering guarantee above
+// does not hold in that case.
 type hashJoiner struct {
 	joinerBase
 
+	flowCtx *FlowCtx
+
 	// All the rows are stored in this container. The buckets reference these rows
 	// by index.
 	rows rowContainer
@@ -65,9 +76,12 @@ type hashJoiner struct {
 	rightEqCols columns
 	buckets     map[string]bucket
 	datumAlloc  sqlbase.DatumAlloc
+
+	// rightPartitions is set once the right stream has been spilled to disk.
+	rightPartitions *hashPartitions
 }
 
-var _ processor = &hashJoiner{}
+var _ Processor = &hashJoiner{}
 
 func newHashJoiner(
 	flowCtx *FlowCtx,
@@ -78,6 +92,7 @@ func newHashJoiner(
 	output RowReceiver,
 ) (*hashJoiner, error) {
 	h := &hashJoiner{
+		flowCtx:     flowCtx,
 		leftEqCols:  columns(spec.LeftEqColumns),
 		rightEqCols: columns(spec.RightEqColumns),
 		buckets:     make(map[string]bucket),
@@ -97,7 +112,7 @@ func newHashJoiner(
 const sizeOfBoolSlice = unsafe.Sizeof([]bool{})
 const sizeOfBool = unsafe.Sizeof(true)
 
-// Run is part of the processor interface.
+// Run is part of the Processor interface.
 func (h *hashJoiner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	if wg != nil {
 		defer wg.Done()
@@ -114,6 +129,11 @@ func (h *hashJoiner) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 	defer h.bucketsAcc.Close(ctx)
 	defer h.rows.Close(ctx)
+	defer func() {
+		if h.rightPartitions != nil {
+			h.rightPartitions.Close(ctx)
+		}
+	}()
 
 	moreRows, err := h.buildPhase(ctx)
 	if err != nil {
@@ -128,17 +148,21 @@ func (h *hashJoiner) Run(ctx context.Context, wg *sync.WaitGroup) {
 		return
 	}
 
-	if h.joinType == rightOuter || h.joinType == fullOuter {
-		for k, bucket := range h.buckets {
-			if err := h.bucketsAcc.Grow(
-				ctx, int64(sizeOfBoolSlice+uintptr(len(bucket.rows))*sizeOfBool),
-			); err != nil {
-				DrainAndClose(ctx, h.out.output, err, h.leftSource)
-				return
+	if h.rightPartitions != nil {
+		log.VEventf(ctx, 1, "build phase complete, right stream spilled to disk")
+		moreRows, err = h.graceJoin(ctx)
+		if moreRows || err != nil {
+			if err != nil {
+				log.VEventf(ctx, 1, "grace join error %s", err)
 			}
-			bucket.seen = make([]bool, len(bucket.rows))
-			h.buckets[k] = bucket
+			DrainAndClose(ctx, h.out.output, err /* cause */, h.leftSource)
 		}
+		return
+	}
+
+	if err := h.initSeen(ctx); err != nil {
+		DrainAndClose(ctx, h.out.output, err, h.leftSource)
+		return
 	}
 	log.VEventf(ctx, 1, "build phase complete")
 	moreRows, err = h.probePhase(ctx)
@@ -209,27 +233,93 @@ func (h *hashJoiner) buildPhase(ctx context.Context) (bool, error) {
 			continue
 		}
 
-		rowIdx := h.rows.Len()
-		if err := h.rows.AddRow(ctx, rrow); err != nil {
-			return false, err
+		if h.rightPartitions != nil {
+			if err := h.rightPartitions.AddRow(ctx, rrow, encoded); err != nil {
+				return false, err
+			}
+			continue
 		}
+		if err := h.addToTable(ctx, rrow, encoded); err != nil {
+			if !shouldSpillToDisk(h.flowCtx, err) {
+				return false, err
+			}
+			log.VEventf(ctx, 2, "spilling %d rows of the right stream to disk", h.rows.Len())
+			if err := h.spillTable(ctx); err != nil {
+				return false, err
+			}
+			if err := h.rightPartitions.AddRow(ctx, rrow, encoded); err != nil {
+				return false, err
+			}
+		}
+	}
+}
 
-		b, bucketExists := h.buckets[string(encoded)]
+// addToTable adds a right row to the in-memory hash table, in the bucket for
+// the given encoding of its equality columns.
+func (h *hashJoiner) addToTable(
+	ctx context.Context, rrow sqlbase.EncDatumRow, encoded []byte,
+) error {
+	rowIdx := h.rows.Len()
+	if err := h.rows.AddRow(ctx, rrow); err != nil {
+		return err
+	}
 
-		// Acount for the memory usage of rowIdx, map key, and bucket.
-		usage := sizeOfRowIdx
-		if !bucketExists {
-			usage += int64(len(encoded))
-			usage += sizeOfBucket
-		}
+	b, bucketExists := h.buckets[string(encoded)]
 
-		if err := h.bucketsAcc.Grow(ctx, usage); err != nil {
-			return false, err
+	// Acount for the memory usage of rowIdx, map key, and bucket.
+	usage := sizeOfRowIdx
+	if !bucketExists {
+		usage += int64(len(encoded))
+		usage += sizeOfBucket
+	}
+
+	if err := h.bucketsAcc.Grow(ctx, usage); err != nil {
+		return err
+	}
+
+	b.rows = append(b.rows, rowIdx)
+	h.buckets[string(encoded)] = b
+	return nil
+}
+
+// initSeen allocates the seen slices of the buckets, for outer joins that
+// output the unmatched right rows.
+func (h *hashJoiner) initSeen(ctx context.Context) error {
+	if h.joinType != rightOuter && h.joinType != fullOuter {
+		return nil
+	}
+	for k, bucket := range h.buckets {
+		if err := h.bucketsAcc.Grow(
+			ctx, int64(sizeOfBoolSlice+uintptr(len(bucket.rows))*sizeOfBool),
+		); err != nil {
+			return err
 		}
+		bucket.seen = make([]bool, len(bucket.rows))
+		h.buckets[k] = bucket
+	}
+	return nil
+}
 
-		b.rows = append(b.rows, rowIdx)
-		h.buckets[string(encoded)] = b
+// clearTable empties the in-memory hash table and releases its memory.
+func (h *hashJoiner) clearTable(ctx context.Context) {
+	h.rows.Clear(ctx)
+	h.buckets = make(map[string]bucket)
+	h.bucketsAcc.Clear(ctx)
+}
+
+// spillTable moves the rows of the in-memory hash table to new partitions on
+// disk; the rest of the right stream is added to these partitions as well.
+func (h *hashJoiner) spillTable(ctx context.Context) error {
+	h.rightPartitions = makeHashPartitions(h.rightSource.Types(), h.flowCtx.tempStorage, 0 /* seed */)
+	for encoded, b := range h.buckets {
+		for _, rowIdx := range b.rows {
+			if err := h.rightPartitions.AddRow(ctx, h.rows.EncRow(rowIdx), []byte(encoded)); err != nil {
+				return err
+			}
+		}
 	}
+	h.clearTable(ctx)
+	return nil
 }
 
 // probePhase uses our constructed hash map of rows seen from the right stream,
@@ -245,22 +335,6 @@ func (h *hashJoiner) buildPhase(ctx context.Context) (bool, error) {
 func (h *hashJoiner) probePhase(ctx context.Context) (bool, error) {
 	var scratch []byte
 
-	// If moreRowsNeeded is returned false, then both the input and the output
-	// have been drained and closed.
-	// If an error is returned, the input/output have not been drained and closed.
-	renderAndEmit := func(lrow sqlbase.EncDatumRow, rrow sqlbase.EncDatumRow,
-	) (moreRowsNeeded bool, failedOnCond bool, err error) {
-		row, failedOnCond, err := h.render(lrow, rrow)
-		if err != nil {
-			return false, false, err
-		}
-		if row != nil {
-			moreRowsNeeded := emitHelper(ctx, &h.out, row, ProducerMetadata{}, h.leftSource)
-			return moreRowsNeeded, failedOnCond, nil
-		}
-		return true, failedOnCond, nil
-	}
-
 	for {
 		lrow, meta := h.leftSource.Next()
 		if !meta.Empty() {
@@ -288,7 +362,7 @@ func (h *hashJoiner) probePhase(ctx context.Context) (bool, error) {
 			// A row that has a NULL in an equality column will not match anything.
 			// Output it or throw it away.
 			if h.joinType == leftOuter || h.joinType == fullOuter {
-				moreRowsNeeded, _, err := renderAndEmit(lrow, nil)
+				moreRowsNeeded, _, err := h.renderAndEmit(ctx, lrow, nil)
 				if !moreRowsNeeded || err != nil {
 					return moreRowsNeeded, err
 				}
@@ -296,44 +370,354 @@ func (h *hashJoiner) probePhase(ctx context.Context) (bool, error) {
 			continue
 		}
 
-		if b, ok := h.buckets[string(encoded)]; ok {
-			for i, rrowIdx := range b.rows {
-				rrow := h.rows.EncRow(rrowIdx)
-				moreRowsNeeded, failedOnCond, err := renderAndEmit(lrow, rrow)
+		if moreRowsNeeded, err := h.probeRow(ctx, lrow, encoded); !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+	}
 
-				if !moreRowsNeeded || err != nil {
+	if moreRowsNeeded, err := h.emitUnmatchedRight(ctx); !moreRowsNeeded || err != nil {
+		return moreRowsNeeded, err
+	}
+	h.out.close()
+	return false, nil
+}
+
+// renderAndEmit renders the result of joining the given rows (either of which
+// can be nil for outer joins) and emits it.
+//
+// If moreRowsNeeded is returned false, then both the input and the output
+// have been drained and closed.
+// If an error is returned, the input/output have not been drained and closed.
+func (h *hashJoiner) renderAndEmit(
+	ctx context.Context, lrow sqlbase.EncDatumRow, rrow sqlbase.EncDatumRow,
+) (moreRowsNeeded bool, failedOnCond bool, err error) {
+	row, failedOnCond, err := h.render(lrow, rrow)
+	if err != nil {
+		return false, false, err
+	}
+	if row != nil {
+		moreRowsNeeded := emitHelper(ctx, &h.out, row, ProducerMetadata{}, h.leftSource)
+		return moreRowsNeeded, failedOnCond, nil
+	}
+	return true, failedOnCond, nil
+}
+
+// probeRow joins a left row with the matching rows of the in-memory hash
+// table. The return values are those of renderAndEmit.
+func (h *hashJoiner) probeRow(
+	ctx context.Context, lrow sqlbase.EncDatumRow, encoded []byte,
+) (bool, error) {
+	if b, ok := h.buckets[string(encoded)]; ok {
+		for i, rrowIdx := range b.rows {
+			rrow := h.rows.EncRow(rrowIdx)
+			moreRowsNeeded, failedOnCond, err := h.renderAndEmit(ctx, lrow, rrow)
+
+			if !moreRowsNeeded || err != nil {
+				return moreRowsNeeded, err
+			}
+			if !failedOnCond && (h.joinType == rightOuter || h.joinType == fullOuter) {
+				b.seen[i] = true
+			}
+		}
+	} else {
+		if h.joinType == leftOuter || h.joinType == fullOuter {
+			if moreRowsNeeded, _, err := h.renderAndEmit(ctx, lrow, nil); !moreRowsNeeded || err != nil {
+				return moreRowsNeeded, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// emitUnmatchedRight produces results for the unmatched right rows of the
+// in-memory hash table (for RIGHT OUTER or FULL OUTER). The return values are
+// those of renderAndEmit.
+func (h *hashJoiner) emitUnmatchedRight(ctx context.Context) (bool, error) {
+	if h.joinType != rightOuter && h.joinType != fullOuter {
+		return true, nil
+	}
+	for _, b := range h.buckets {
+		for i, seen := range b.seen {
+			if !seen {
+				rrow := h.rows.EncRow(b.rows[i])
+				if moreRowsNeeded, _, err := h.renderAndEmit(ctx, nil, rrow); !moreRowsNeeded || err != nil {
 					return moreRowsNeeded, err
 				}
-				if !failedOnCond && (h.joinType == rightOuter || h.joinType == fullOuter) {
-					b.seen[i] = true
-				}
 			}
-		} else {
+		}
+	}
+	return true, nil
+}
+
+// graceJoinPartitions is the number of partitions in which the grace hash join
+// splits its inputs.
+const graceJoinPartitions = 16
+
+// graceJoinMaxLevel is the number of times the grace hash join splits a pair of
+// partitions whose right side doesn't fit in memory before giving up. Rows
+// that are still together after that many splits likely all have the same
+// equality columns.
+const graceJoinMaxLevel = 4
+
+// graceJoin runs the probe phase once the right stream has been spilled to
+// disk: the left stream is split into partitions in the same way as the right
+// one, and each pair of matching partitions is then joined in memory.
+//
+// The return values are symmetric with probePhase().
+func (h *hashJoiner) graceJoin(ctx context.Context) (bool, error) {
+	leftPartitions := makeHashPartitions(
+		h.leftSource.Types(), h.flowCtx.tempStorage, h.rightPartitions.level,
+	)
+	defer leftPartitions.Close(ctx)
+
+	var scratch []byte
+	for {
+		lrow, meta := h.leftSource.Next()
+		if !meta.Empty() {
+			if meta.Err != nil {
+				return true, meta.Err
+			}
+			if !emitHelper(
+				ctx, &h.out, nil /* row */, meta, h.leftSource, h.rightSource) {
+				return false, nil
+			}
+			continue
+		}
+
+		if lrow == nil {
+			break
+		}
+
+		encoded, hasNull, err := encodeColumnsOfRow(&h.datumAlloc, scratch, lrow, h.leftEqCols, false /* encodeNull */)
+		if err != nil {
+			return true, err
+		}
+		scratch = encoded[:0]
+
+		if hasNull {
+			// A row that has a NULL in an equality column will not match anything.
+			// Output it or throw it away.
 			if h.joinType == leftOuter || h.joinType == fullOuter {
-				if moreRowsNeeded, _, err := renderAndEmit(lrow, nil); !moreRowsNeeded || err != nil {
+				moreRowsNeeded, _, err := h.renderAndEmit(ctx, lrow, nil)
+				if !moreRowsNeeded || err != nil {
 					return moreRowsNeeded, err
 				}
 			}
+			continue
+		}
+
+		if err := leftPartitions.AddRow(ctx, lrow, encoded); err != nil {
+			return true, err
 		}
 	}
 
-	if h.joinType == rightOuter || h.joinType == fullOuter {
-		// Produce results for unmatched right rows (for RIGHT OUTER or FULL OUTER).
-		for _, b := range h.buckets {
-			for i, seen := range b.seen {
-				if !seen {
-					rrow := h.rows.EncRow(b.rows[i])
-					if moreRowsNeeded, _, err := renderAndEmit(nil, rrow); !moreRowsNeeded || err != nil {
-						return moreRowsNeeded, err
-					}
-				}
-			}
+	for i := range leftPartitions.parts {
+		moreRowsNeeded, err := h.joinPartitions(
+			ctx, &leftPartitions.parts[i], &h.rightPartitions.parts[i], leftPartitions.level,
+		)
+		if !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
 		}
 	}
 	h.out.close()
 	return false, nil
 }
 
+// joinPartitions joins a pair of matching partitions by building the in-memory
+// hash table from the right partition and probing it with the rows of the left
+// one. If the right partition doesn't fit in memory either, both partitions are
+// split again with a different hash function.
+//
+// The return values are those of renderAndEmit.
+func (h *hashJoiner) joinPartitions(
+	ctx context.Context, left, right *diskRowContainer, level int,
+) (bool, error) {
+	if left.Len() == 0 && (h.joinType == innerJoin || h.joinType == leftOuter) {
+		return true, nil
+	}
+	if right.Len() == 0 && (h.joinType == innerJoin || h.joinType == rightOuter) {
+		return true, nil
+	}
+	defer h.clearTable(ctx)
+
+	if err := h.buildTableFromPartition(ctx, right); err != nil {
+		if !shouldSpillToDisk(h.flowCtx, err) || level+1 >= graceJoinMaxLevel {
+			return false, err
+		}
+		h.clearTable(ctx)
+		log.VEventf(ctx, 2, "splitting partition of %d rows", right.Len())
+		return h.splitAndJoinPartitions(ctx, left, right, level+1)
+	}
+	if err := h.initSeen(ctx); err != nil {
+		return false, err
+	}
+
+	it, err := left.newIterator()
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+	var scratch []byte
+	for {
+		lrow, err := it.Next()
+		if err != nil {
+			return false, err
+		}
+		if lrow == nil {
+			break
+		}
+		encoded, _, err := encodeColumnsOfRow(&h.datumAlloc, scratch, lrow, h.leftEqCols, false /* encodeNull */)
+		if err != nil {
+			return false, err
+		}
+		scratch = encoded[:0]
+		if moreRowsNeeded, err := h.probeRow(ctx, lrow, encoded); !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+	}
+	return h.emitUnmatchedRight(ctx)
+}
+
+// buildTableFromPartition fills the in-memory hash table with the rows of a
+// right partition.
+func (h *hashJoiner) buildTableFromPartition(ctx context.Context, right *diskRowContainer) error {
+	it, err := right.newIterator()
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	var scratch []byte
+	for {
+		rrow, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if rrow == nil {
+			return nil
+		}
+		encoded, _, err := encodeColumnsOfRow(&h.datumAlloc, scratch, rrow, h.rightEqCols, false /* encodeNull */)
+		if err != nil {
+			return err
+		}
+		scratch = encoded[:0]
+		if err := h.addToTable(ctx, rrow, encoded); err != nil {
+			return err
+		}
+	}
+}
+
+// splitAndJoinPartitions splits a pair of matching partitions into smaller ones
+// using the hash function of the given level, and joins them.
+func (h *hashJoiner) splitAndJoinPartitions(
+	ctx context.Context, left, right *diskRowContainer, level int,
+) (bool, error) {
+	leftPartitions := makeHashPartitions(left.types, h.flowCtx.tempStorage, level)
+	defer leftPartitions.Close(ctx)
+	if err := h.splitPartition(ctx, left, h.leftEqCols, leftPartitions); err != nil {
+		return false, err
+	}
+	rightPartitions := makeHashPartitions(right.types, h.flowCtx.tempStorage, level)
+	defer rightPartitions.Close(ctx)
+	if err := h.splitPartition(ctx, right, h.rightEqCols, rightPartitions); err != nil {
+		return false, err
+	}
+
+	for i := range leftPartitions.parts {
+		moreRowsNeeded, err := h.joinPartitions(
+			ctx, &leftPartitions.parts[i], &rightPartitions.parts[i], level,
+		)
+		if !moreRowsNeeded || err != nil {
+			return moreRowsNeeded, err
+		}
+	}
+	return true, nil
+}
+
+// splitPartition adds the rows of a partition to the given partitions.
+func (h *hashJoiner) splitPartition(
+	ctx context.Context, src *diskRowContainer, eqCols columns, dst *hashPartitions,
+) error {
+	it, err := src.newIterator()
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	var scratch []byte
+	for {
+		row, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			return nil
+		}
+		encoded, _, err := encodeColumnsOfRow(&h.datumAlloc, scratch, row, eqCols, false /* encodeNull */)
+		if err != nil {
+			return err
+		}
+		scratch = encoded[:0]
+		if err := dst.AddRow(ctx, row, encoded); err != nil {
+			return err
+		}
+	}
+}
+
+// hashPartitions splits rows into partitions stored on disk, according to the
+// hash of the encoding of their equality columns. Each level seeds the hash
+// differently, so that rows that ended up in the same partition at one level
+// can be split at the next one.
+type hashPartitions struct {
+	level  int
+	seed   uint64
+	parts  []diskRowContainer
+	hasher hash.Hash64
+}
+
+func makeHashPartitions(types []sqlbase.ColumnType, e engine.Engine, level int) *hashPartitions {
+	p := &hashPartitions{
+		level: level,
+		// Any odd multiplier gives a distinct seed to each level.
+		seed:   uint64(level+1) * 0x9e3779b97f4a7c15,
+		parts:  make([]diskRowContainer, graceJoinPartitions),
+		hasher: fnv.New64a(),
+	}
+	for i := range p.parts {
+		p.parts[i] = makeDiskRowContainer(types, e)
+	}
+	return p
+}
+
+// AddRow adds a row to the partition corresponding to the given encoding of
+// its equality columns.
+func (p *hashPartitions) AddRow(
+	ctx context.Context, row sqlbase.EncDatumRow, encoded []byte,
+) error {
+	p.hasher.Reset()
+	_, _ = p.hasher.Write(encoded)
+	// The low bits of an FNV hash, which the modulo keeps, depend poorly on
+	// the seed, so the seeded hash is mixed before picking the partition.
+	h := mix64(p.hasher.Sum64() ^ p.seed)
+	return p.parts[h%uint64(len(p.parts))].AddRow(ctx, row)
+}
+
+// mix64 is the finalizer of MurmurHash3: every bit of its input affects every
+// bit of its output.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// Close deletes the rows of all the partitions.
+func (p *hashPartitions) Close(ctx context.Context) {
+	for i := range p.parts {
+		p.parts[i].Close(ctx)
+	}
+}
+
 // encodeColumnsOfRow returns the encoding for the grouping columns. This is
 // then used as our group key to determine which bucket to add to.
 // If the row contains any NULLs and encodeNull is false, hasNull is true and
@@ -24,9 +24,11 @@ import (
 
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/pkg/errors"
@@ -744,3 +746,79 @@ func TestHashJoinerDrainAfterBuildPhaseError(t *testing.T) {
 		t.Fatalf("expected %q, got: %v", "Test error", out.mu.records[0].Meta.Err)
 	}
 }
+
+// TestHashJoinerSpilling verifies that a hash joiner whose right input doesn't
+// fit in memory falls back to a grace hash join, with the same results as an
+// in-memory join.
+func TestHashJoinerSpilling(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	tempEngine := engine.NewInMem(roachpb.Attributes{}, 1<<20 /* cacheSize */)
+	defer tempEngine.Close()
+
+	columnTypeInt := sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
+	types := []sqlbase.ColumnType{columnTypeInt, columnTypeInt}
+	// makeRows returns n rows whose first column, on which the inputs are
+	// joined, has mod distinct values and some NULLs.
+	makeRows := func(n, mod int) sqlbase.EncDatumRows {
+		rows := make(sqlbase.EncDatumRows, n)
+		for i := range rows {
+			key := sqlbase.EncDatum{Datum: parser.DNull}
+			if i%97 != 0 {
+				key = sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i%mod)))
+			}
+			rows[i] = sqlbase.EncDatumRow{
+				key, sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i))),
+			}
+		}
+		return rows
+	}
+	left := makeRows(300, 150)
+	right := makeRows(1000, 100)
+
+	for _, joinType := range []JoinType{
+		JoinType_INNER, JoinType_LEFT_OUTER, JoinType_RIGHT_OUTER, JoinType_FULL_OUTER,
+	} {
+		t.Run(joinType.String(), func(t *testing.T) {
+			spec := HashJoinerSpec{
+				LeftEqColumns:  []uint32{0},
+				RightEqColumns: []uint32{0},
+				Type:           joinType,
+			}
+			run := func(flowCtx *FlowCtx) *RowBuffer {
+				leftInput := NewRowBuffer(types, left, RowBufferArgs{})
+				rightInput := NewRowBuffer(types, right, RowBufferArgs{})
+				out := &RowBuffer{}
+				h, err := newHashJoiner(flowCtx, &spec, leftInput, rightInput, &PostProcessSpec{}, out)
+				if err != nil {
+					t.Fatal(err)
+				}
+				h.Run(ctx, nil)
+				if !out.ProducerClosed {
+					t.Fatalf("output RowReceiver not closed")
+				}
+				return out
+			}
+
+			unlimited := mon.MakeUnlimitedMonitor(ctx, "test", nil, nil, math.MaxInt64)
+			defer unlimited.Stop(ctx)
+			expected := getRowsFromBuffer(t, run(&FlowCtx{evalCtx: parser.EvalContext{Mon: &unlimited}}))
+
+			limited := mon.MakeMonitor("test", nil, nil, 1 /* increment */, math.MaxInt64)
+			limited.Start(ctx, nil, mon.MakeStandaloneBudget(8<<10))
+			defer limited.Stop(ctx)
+
+			// Without temporary storage, the hash joiner runs out of memory.
+			out := run(&FlowCtx{evalCtx: parser.EvalContext{Mon: &limited}})
+			if err := drainForError(out); !testutils.IsError(err, "memory budget exceeded") {
+				t.Fatalf("expected memory budget error, got %v", err)
+			}
+
+			out = run(&FlowCtx{evalCtx: parser.EvalContext{Mon: &limited}, tempStorage: tempEngine})
+			if err := checkExpectedRows(expected, out); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
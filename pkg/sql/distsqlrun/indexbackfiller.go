@@ -32,12 +32,15 @@ type indexBackfiller struct {
 
 	// colIdxMap maps ColumnIDs to indices into desc.Columns and desc.Mutations.
 	colIdxMap map[sqlbase.ColumnID]int
+	// predicates are the predicates of the partial indexes being backfilled,
+	// which only get entries for the rows that satisfy them.
+	predicates map[sqlbase.IndexID]*sqlbase.PartialIndexPredicate
 
 	rowVals parser.Datums
 	da      sqlbase.DatumAlloc
 }
 
-var _ processor = &indexBackfiller{}
+var _ Processor = &indexBackfiller{}
 var _ chunkBackfiller = &indexBackfiller{}
 
 // IndexMutationFilter is a filter that allows mutations that add indexes.
@@ -97,6 +100,19 @@ func (ib *indexBackfiller) init() error {
 			for i, col := range cols {
 				valNeededForCol[i] = valNeededForCol[i] || idx.ContainsColumnID(col.ID)
 			}
+			if idx.IsPartial() {
+				pred, err := sqlbase.MakePartialIndexPredicate(&desc, idx)
+				if err != nil {
+					return err
+				}
+				for _, id := range pred.ReferencedColumnIDs() {
+					valNeededForCol[ib.colIdxMap[id]] = true
+				}
+				if ib.predicates == nil {
+					ib.predicates = make(map[sqlbase.IndexID]*sqlbase.PartialIndexPredicate)
+				}
+				ib.predicates[idx.ID] = pred
+			}
 		}
 	}
 
@@ -158,7 +174,26 @@ func (ib *indexBackfiller) runChunk(
 				ib.rowVals, secondaryIndexEntries); err != nil {
 				return err
 			}
-			for _, secondaryIndexEntry := range secondaryIndexEntries {
+			for j, secondaryIndexEntry := range secondaryIndexEntries {
+				if pred, ok := ib.predicates[added[j].ID]; ok {
+					if ok, err := pred.Eval(ib.rowVals, ib.colIdxMap); err != nil {
+						return err
+					} else if !ok {
+						continue
+					}
+				}
+				if added[j].Type == sqlbase.IndexDescriptor_INVERTED {
+					entries, err := sqlbase.EncodeInvertedIndexKeys(
+						&ib.spec.Table, &added[j], ib.colIdxMap, ib.rowVals)
+					if err != nil {
+						return err
+					}
+					for i := range entries {
+						log.VEventf(ctx, 3, "InitPut %s -> %v", entries[i].Key, entries[i].Value)
+						b.InitPut(entries[i].Key, &entries[i].Value)
+					}
+					continue
+				}
 				log.VEventf(ctx, 3, "InitPut %s -> %v", secondaryIndexEntry.Key,
 					secondaryIndexEntry.Value)
 				b.InitPut(secondaryIndexEntry.Key, &secondaryIndexEntry.Value)
@@ -45,7 +45,7 @@ type joinReader struct {
 	out   procOutputHelper
 }
 
-var _ processor = &joinReader{}
+var _ Processor = &joinReader{}
 
 func newJoinReader(
 	flowCtx *FlowCtx,
@@ -186,7 +186,7 @@ func (jr *joinReader) mainLoop(ctx context.Context) error {
 	}
 }
 
-// Run is part of the processor interface.
+// Run is part of the Processor interface.
 func (jr *joinReader) Run(ctx context.Context, wg *sync.WaitGroup) {
 	if wg != nil {
 		defer wg.Done()
@@ -438,6 +438,13 @@ func (ds *MockDistSQLServer) SetupFlow(
 	return nil, nil
 }
 
+// CancelFlow is part of the DistSQLServer interface.
+func (ds *MockDistSQLServer) CancelFlow(
+	_ context.Context, req *CancelFlowRequest,
+) (*SimpleResponse, error) {
+	return nil, nil
+}
+
 // FlowStream is part of the DistSQLServer interface.
 func (ds *MockDistSQLServer) FlowStream(stream DistSQL_FlowStreamServer) error {
 	donec := make(chan error)
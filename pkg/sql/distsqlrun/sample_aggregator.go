@@ -0,0 +1,257 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// sampleAggregator is a processor that aggregates the results of multiple
+// sampler processors. See SampleAggregatorSpec for more details.
+type sampleAggregator struct {
+	flowCtx  *FlowCtx
+	input    RowSource
+	out      procOutputHelper
+	sketches []sampleAggregatorSketch
+	sr       stats.SampleReservoir
+
+	// Input column indices for the special columns.
+	rankCol      int
+	sketchIdxCol int
+	numRowsCol   int
+	numNullsCol  int
+	sketchCol    int
+}
+
+// sampleAggregatorSketch is the state of a sketch merged from the sketches of
+// all the samplers.
+type sampleAggregatorSketch struct {
+	spec     SketchSpec
+	sketch   *stats.DistinctSketch
+	numRows  int64
+	numNulls int64
+}
+
+var _ Processor = &sampleAggregator{}
+
+var sampleAggregatorOutCols = []sqlbase.ColumnType{
+	{Kind: sqlbase.ColumnType_INT},   // sketch index
+	{Kind: sqlbase.ColumnType_INT},   // row count
+	{Kind: sqlbase.ColumnType_INT},   // distinct count
+	{Kind: sqlbase.ColumnType_INT},   // NULL count
+	{Kind: sqlbase.ColumnType_BYTES}, // histogram
+}
+
+func newSampleAggregator(
+	flowCtx *FlowCtx,
+	spec *SampleAggregatorSpec,
+	input RowSource,
+	post *PostProcessSpec,
+	output RowReceiver,
+) (*sampleAggregator, error) {
+	for _, s := range spec.Sketches {
+		if len(s.Columns) == 0 {
+			return nil, errors.Errorf("sketch without columns")
+		}
+		if s.GenerateHistogram && s.HistogramMaxBuckets == 0 {
+			return nil, errors.Errorf("histogram max buckets not specified")
+		}
+	}
+
+	// The input schema is the schema of the samplers: the sampled columns
+	// followed by the special columns.
+	inTypes := input.Types()
+	rankCol := len(inTypes) - len(samplerOutCols)
+	if rankCol < 0 {
+		return nil, errors.Errorf("invalid sample aggregator input with %d columns", len(inTypes))
+	}
+
+	s := &sampleAggregator{
+		flowCtx:      flowCtx,
+		input:        input,
+		sketches:     make([]sampleAggregatorSketch, len(spec.Sketches)),
+		rankCol:      rankCol,
+		sketchIdxCol: rankCol + 1,
+		numRowsCol:   rankCol + 2,
+		numNullsCol:  rankCol + 3,
+		sketchCol:    rankCol + 4,
+	}
+	for i := range spec.Sketches {
+		s.sketches[i] = sampleAggregatorSketch{
+			spec:   spec.Sketches[i],
+			sketch: stats.NewDistinctSketch(sketchSize),
+		}
+	}
+	s.sr.Init(int(spec.SampleSize))
+
+	if err := s.out.init(post, sampleAggregatorOutCols, &flowCtx.evalCtx, output); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run is part of the Processor interface.
+func (s *sampleAggregator) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx = log.WithLogTag(ctx, "SampleAggregator", nil)
+	ctx, span := tracing.ChildSpan(ctx, "sample aggregator")
+	defer tracing.FinishSpan(span)
+
+	if log.V(2) {
+		log.Infof(ctx, "starting sample aggregator")
+		defer log.Infof(ctx, "exiting sample aggregator")
+	}
+
+	earlyExit, err := s.mainLoop(ctx)
+	if err != nil {
+		DrainAndClose(ctx, s.out.output, err, s.input)
+	} else if !earlyExit {
+		s.out.close()
+	}
+}
+
+// mainLoop merges the samples and the sketches of the input, then emits the
+// statistics. earlyExit is set if the consumer stopped accepting rows, in
+// which case emitHelper() already took care of closing the input and the
+// output.
+func (s *sampleAggregator) mainLoop(ctx context.Context) (earlyExit bool, _ error) {
+	var da sqlbase.DatumAlloc
+	for {
+		row, meta := s.input.Next()
+		if !meta.Empty() {
+			if meta.Err != nil {
+				return false, meta.Err
+			}
+			if !emitHelper(ctx, &s.out, nil /* row */, meta, s.input) {
+				return true, nil
+			}
+			continue
+		}
+		if row == nil {
+			break
+		}
+
+		if !row[s.rankCol].IsNull() {
+			// This is a sampled row; sample it again.
+			if err := row[s.rankCol].EnsureDecoded(&da); err != nil {
+				return false, err
+			}
+			rank := uint64(*row[s.rankCol].Datum.(*parser.DInt))
+			s.sr.SampleRow(row[:s.rankCol], rank)
+			continue
+		}
+
+		// This is a sketch row.
+		for _, col := range []int{s.sketchIdxCol, s.numRowsCol, s.numNullsCol, s.sketchCol} {
+			if err := row[col].EnsureDecoded(&da); err != nil {
+				return false, err
+			}
+		}
+		sketchIdx := int(*row[s.sketchIdxCol].Datum.(*parser.DInt))
+		if sketchIdx < 0 || sketchIdx >= len(s.sketches) {
+			return false, errors.Errorf("invalid sketch index %d", sketchIdx)
+		}
+		info := &s.sketches[sketchIdx]
+		info.numRows += int64(*row[s.numRowsCol].Datum.(*parser.DInt))
+		info.numNulls += int64(*row[s.numNullsCol].Datum.(*parser.DInt))
+		var sketch stats.DistinctSketch
+		if err := sketch.UnmarshalBinary(
+			[]byte(*row[s.sketchCol].Datum.(*parser.DBytes)),
+		); err != nil {
+			return false, err
+		}
+		info.sketch.Merge(&sketch)
+	}
+
+	outRow := make(sqlbase.EncDatumRow, len(sampleAggregatorOutCols))
+	for i := range s.sketches {
+		info := &s.sketches[i]
+		distinctCount := int64(info.sketch.Estimate())
+		// The estimate can exceed the number of values.
+		if nonNullRows := info.numRows - info.numNulls; distinctCount > nonNullRows {
+			distinctCount = nonNullRows
+		}
+
+		histogram := parser.Datum(parser.DNull)
+		if info.spec.GenerateHistogram {
+			h, err := s.generateHistogram(&da, info)
+			if err != nil {
+				return false, err
+			}
+			if len(h.Buckets) > 0 {
+				encoded, err := h.Marshal()
+				if err != nil {
+					return false, err
+				}
+				histogram = parser.NewDBytes(parser.DBytes(encoded))
+			}
+		}
+
+		outRow[0] = sqlbase.DatumToEncDatum(sampleAggregatorOutCols[0], parser.NewDInt(parser.DInt(i)))
+		outRow[1] = sqlbase.DatumToEncDatum(
+			sampleAggregatorOutCols[1], parser.NewDInt(parser.DInt(info.numRows)),
+		)
+		outRow[2] = sqlbase.DatumToEncDatum(
+			sampleAggregatorOutCols[2], parser.NewDInt(parser.DInt(distinctCount)),
+		)
+		outRow[3] = sqlbase.DatumToEncDatum(
+			sampleAggregatorOutCols[3], parser.NewDInt(parser.DInt(info.numNulls)),
+		)
+		outRow[4] = sqlbase.DatumToEncDatum(sampleAggregatorOutCols[4], histogram)
+		if !emitHelper(ctx, &s.out, outRow, ProducerMetadata{}, s.input) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// generateHistogram builds a histogram of the first column of a sketch from
+// the sampled rows.
+func (s *sampleAggregator) generateHistogram(
+	da *sqlbase.DatumAlloc, info *sampleAggregatorSketch,
+) (stats.HistogramData, error) {
+	colIdx := info.spec.Columns[0]
+	var values parser.Datums
+	for _, sample := range s.sr.Get() {
+		ed := &sample.Row[colIdx]
+		if ed.IsNull() {
+			continue
+		}
+		if err := ed.EnsureDecoded(da); err != nil {
+			return stats.HistogramData{}, err
+		}
+		values = append(values, ed.Datum)
+	}
+	evalCtx := &s.flowCtx.evalCtx
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Compare(evalCtx, values[j]) < 0
+	})
+	return stats.EquiDepthHistogram(
+		evalCtx, values, info.numRows-info.numNulls, int(info.spec.HistogramMaxBuckets),
+	)
+}
@@ -0,0 +1,229 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+)
+
+// sketchSize is the number of hashes retained by the distinct count sketches.
+// The standard error of the estimates is about 1/sqrt(sketchSize).
+const sketchSize = 1024
+
+// sketchInfo contains the specification and run-time state for each sketch.
+type sketchInfo struct {
+	spec     SketchSpec
+	sketch   *stats.DistinctSketch
+	numNulls int64
+}
+
+// sampler is a processor that returns a random sample of its input rows and
+// computes distinct count sketches on sets of columns. See SamplerSpec for
+// the schema of its output.
+type sampler struct {
+	flowCtx  *FlowCtx
+	input    RowSource
+	out      procOutputHelper
+	outTypes []sqlbase.ColumnType
+	sketches []sketchInfo
+	sr       stats.SampleReservoir
+	numRows  int64
+
+	// Output column indices for the special columns.
+	rankCol      int
+	sketchIdxCol int
+	numRowsCol   int
+	numNullsCol  int
+	sketchCol    int
+}
+
+var _ Processor = &sampler{}
+
+var samplerOutCols = []sqlbase.ColumnType{
+	{Kind: sqlbase.ColumnType_INT},   // rank
+	{Kind: sqlbase.ColumnType_INT},   // sketch index
+	{Kind: sqlbase.ColumnType_INT},   // number of rows
+	{Kind: sqlbase.ColumnType_INT},   // number of NULLs
+	{Kind: sqlbase.ColumnType_BYTES}, // sketch data
+}
+
+func newSampler(
+	flowCtx *FlowCtx, spec *SamplerSpec, input RowSource, post *PostProcessSpec, output RowReceiver,
+) (*sampler, error) {
+	for _, s := range spec.Sketches {
+		if len(s.Columns) == 0 {
+			return nil, errors.Errorf("sketch without columns")
+		}
+	}
+
+	s := &sampler{
+		flowCtx:  flowCtx,
+		input:    input,
+		sketches: make([]sketchInfo, len(spec.Sketches)),
+	}
+	for i := range spec.Sketches {
+		s.sketches[i] = sketchInfo{
+			spec:   spec.Sketches[i],
+			sketch: stats.NewDistinctSketch(sketchSize),
+		}
+	}
+	s.sr.Init(int(spec.SampleSize))
+
+	inTypes := input.Types()
+	outTypes := make([]sqlbase.ColumnType, 0, len(inTypes)+len(samplerOutCols))
+	outTypes = append(outTypes, inTypes...)
+	s.rankCol = len(outTypes)
+	s.sketchIdxCol = s.rankCol + 1
+	s.numRowsCol = s.rankCol + 2
+	s.numNullsCol = s.rankCol + 3
+	s.sketchCol = s.rankCol + 4
+	outTypes = append(outTypes, samplerOutCols...)
+	s.outTypes = outTypes
+
+	if err := s.out.init(post, outTypes, &flowCtx.evalCtx, output); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Run is part of the Processor interface.
+func (s *sampler) Run(ctx context.Context, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx = log.WithLogTag(ctx, "Sampler", nil)
+	ctx, span := tracing.ChildSpan(ctx, "sampler")
+	defer tracing.FinishSpan(span)
+
+	if log.V(2) {
+		log.Infof(ctx, "starting sampler")
+		defer log.Infof(ctx, "exiting sampler")
+	}
+
+	earlyExit, err := s.mainLoop(ctx)
+	if err != nil {
+		DrainAndClose(ctx, s.out.output, err, s.input)
+	} else if !earlyExit {
+		s.out.close()
+	}
+}
+
+// mainLoop consumes the input, then emits the sampled rows and the sketches.
+// earlyExit is set if the consumer stopped accepting rows, in which case
+// emitHelper() already took care of closing the input and the output.
+func (s *sampler) mainLoop(ctx context.Context) (earlyExit bool, _ error) {
+	var da sqlbase.DatumAlloc
+	var buf []byte
+	for {
+		row, meta := s.input.Next()
+		if !meta.Empty() {
+			if meta.Err != nil {
+				return false, meta.Err
+			}
+			if !emitHelper(ctx, &s.out, nil /* row */, meta, s.input) {
+				return true, nil
+			}
+			continue
+		}
+		if row == nil {
+			break
+		}
+		s.numRows++
+
+		for i := range s.sketches {
+			info := &s.sketches[i]
+			if row[info.spec.Columns[0]].IsNull() {
+				info.numNulls++
+				continue
+			}
+			// The values are hashed in their value encoding, which is the same on
+			// all the nodes regardless of how the rows were read. The encodings
+			// produced by the row fetcher may contain column IDs, so they are not
+			// reused.
+			buf = buf[:0]
+			for _, col := range info.spec.Columns {
+				if err := row[col].EnsureDecoded(&da); err != nil {
+					return false, err
+				}
+				var err error
+				buf, err = sqlbase.EncodeTableValue(
+					buf, sqlbase.ColumnID(encoding.NoColumnID), row[col].Datum,
+				)
+				if err != nil {
+					return false, err
+				}
+			}
+			info.sketch.Add(buf)
+		}
+
+		// Use Int63 so we don't have headaches converting to DInt.
+		rank := uint64(rand.Int63())
+		s.sr.SampleRow(row, rank)
+	}
+
+	outRow := make(sqlbase.EncDatumRow, len(s.outTypes))
+	for _, sample := range s.sr.Get() {
+		copy(outRow, sample.Row)
+		outRow[s.rankCol] = sqlbase.DatumToEncDatum(
+			samplerOutCols[0], parser.NewDInt(parser.DInt(sample.Rank)),
+		)
+		for i := s.sketchIdxCol; i < len(outRow); i++ {
+			outRow[i] = sqlbase.DatumToEncDatum(s.outTypes[i], parser.DNull)
+		}
+		if !emitHelper(ctx, &s.out, outRow, ProducerMetadata{}, s.input) {
+			return true, nil
+		}
+	}
+
+	// Emit the sketch rows.
+	for i := 0; i <= s.rankCol; i++ {
+		outRow[i] = sqlbase.DatumToEncDatum(s.outTypes[i], parser.DNull)
+	}
+	for i := range s.sketches {
+		info := &s.sketches[i]
+		data, err := info.sketch.MarshalBinary()
+		if err != nil {
+			return false, err
+		}
+		outRow[s.sketchIdxCol] = sqlbase.DatumToEncDatum(
+			samplerOutCols[1], parser.NewDInt(parser.DInt(i)),
+		)
+		outRow[s.numRowsCol] = sqlbase.DatumToEncDatum(
+			samplerOutCols[2], parser.NewDInt(parser.DInt(s.numRows)),
+		)
+		outRow[s.numNullsCol] = sqlbase.DatumToEncDatum(
+			samplerOutCols[3], parser.NewDInt(parser.DInt(info.numNulls)),
+		)
+		outRow[s.sketchCol] = sqlbase.DatumToEncDatum(
+			samplerOutCols[4], parser.NewDBytes(parser.DBytes(data)),
+		)
+		if !emitHelper(ctx, &s.out, outRow, ProducerMetadata{}, s.input) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -90,6 +91,10 @@ type ServerConfig struct {
 	Hist                *metric.Histogram
 	// NodeID is the id of the node on which this Server is running.
 	NodeID *base.NodeIDContainer
+	// TempStorage is a local engine used by processors to store rows that don't
+	// fit in memory. It can be nil, in which case processors don't spill to
+	// disk. Its contents are not expected to survive a restart.
+	TempStorage engine.Engine
 }
 
 // ServerImpl implements the server for the distributed SQL APIs.
@@ -198,11 +203,13 @@ func (ds *ServerImpl) setupFlow(
 		remoteTxnDB:    ds.FlowDB,
 		testingKnobs:   ds.TestingKnobs,
 		nodeID:         nodeID,
+		tempStorage:    ds.TempStorage,
 	}
 
 	ctx = flowCtx.AnnotateCtx(ctx)
 
 	f := newFlow(flowCtx, ds.flowRegistry, syncFlowConsumer)
+	f.collectStats = req.CollectStats
 	flowCtx.AddLogTagStr("f", f.id.Short())
 	if err := f.setupFlow(ctx, &req.Flow); err != nil {
 		log.Errorf(ctx, "error setting up flow: %s", err)
@@ -258,13 +265,16 @@ func (ds *ServerImpl) RunSyncFlow(stream DistSQL_RunSyncFlowServer) error {
 // SetupFlow is part of the DistSQLServer interface.
 func (ds *ServerImpl) SetupFlow(_ context.Context, req *SetupFlowRequest) (*SimpleResponse, error) {
 	// Note: the passed context will be canceled when this RPC completes, so we
-	// can't associate it with the flow.
-	ctx := ds.AnnotateCtx(context.TODO())
+	// can't associate it with the flow. The flow gets its own context, which the
+	// gateway can cancel through the CancelFlow RPC.
+	ctx, cancel := context.WithCancel(ds.AnnotateCtx(context.TODO()))
 	ctx, f, err := ds.setupFlow(ctx, req, nil)
 	if err == nil {
+		f.ctxCancel = cancel
 		err = ds.flowScheduler.ScheduleFlow(ctx, f)
 	}
 	if err != nil {
+		cancel()
 		// We return flow deployment errors in the response so that they are
 		// packaged correctly over the wire. If we return them directly to this
 		// function, they become part of an rpc error.
@@ -273,6 +283,16 @@ func (ds *ServerImpl) SetupFlow(_ context.Context, req *SetupFlowRequest) (*Simp
 	return &SimpleResponse{}, nil
 }
 
+// CancelFlow is part of the DistSQLServer interface.
+func (ds *ServerImpl) CancelFlow(
+	ctx context.Context, req *CancelFlowRequest,
+) (*SimpleResponse, error) {
+	if !ds.flowRegistry.CancelFlow(req.FlowID) {
+		log.VEventf(ds.AnnotateCtx(ctx), 1, "flow %s to cancel not found", req.FlowID.Short())
+	}
+	return &SimpleResponse{}, nil
+}
+
 func (ds *ServerImpl) flowStreamInt(ctx context.Context, stream DistSQL_FlowStreamServer) error {
 	// Receive the first message.
 	msg, err := stream.Recv()
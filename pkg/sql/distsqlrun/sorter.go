@@ -42,7 +42,7 @@ type sorter struct {
 	limit    int64
 }
 
-var _ processor = &sorter{}
+var _ Processor = &sorter{}
 
 func newSorter(
 	flowCtx *FlowCtx, spec *SorterSpec, input RowSource, post *PostProcessSpec, output RowReceiver,
@@ -61,7 +61,7 @@ func newSorter(
 	return s, nil
 }
 
-// Run is part of the processor interface.
+// Run is part of the Processor interface.
 func (s *sorter) Run(ctx context.Context, wg *sync.WaitGroup) {
 	if wg != nil {
 		defer wg.Done()
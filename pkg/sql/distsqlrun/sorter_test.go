@@ -18,11 +18,15 @@ package distsqlrun
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 
@@ -213,3 +217,78 @@ func TestSorter(t *testing.T) {
 		}
 	}
 }
+
+// TestSorterSpilling verifies that a sorter that runs out of memory spills
+// sorted runs to disk and merges them, with the same results as an in-memory
+// sort.
+func TestSorterSpilling(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	tempEngine := engine.NewInMem(roachpb.Attributes{}, 1<<20 /* cacheSize */)
+	defer tempEngine.Close()
+
+	columnTypeInt := sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT}
+	types := []sqlbase.ColumnType{columnTypeInt, columnTypeInt}
+	rng := rand.New(rand.NewSource(1))
+	var input sqlbase.EncDatumRows
+	for _, i := range rng.Perm(1000) {
+		input = append(input, sqlbase.EncDatumRow{
+			sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i%50))),
+			sqlbase.DatumToEncDatum(columnTypeInt, parser.NewDInt(parser.DInt(i))),
+		})
+	}
+	spec := SorterSpec{
+		OutputOrdering: convertToSpecOrdering(sqlbase.ColumnOrdering{
+			{ColIdx: 0, Direction: encoding.Ascending},
+			{ColIdx: 1, Direction: encoding.Descending},
+		}),
+	}
+
+	run := func(flowCtx *FlowCtx) *RowBuffer {
+		in := NewRowBuffer(types, input, RowBufferArgs{})
+		out := &RowBuffer{}
+		s, err := newSorter(flowCtx, &spec, in, &PostProcessSpec{}, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Run(ctx, nil)
+		if !out.ProducerClosed {
+			t.Fatalf("output RowReceiver not closed")
+		}
+		return out
+	}
+
+	unlimited := mon.MakeUnlimitedMonitor(ctx, "test", nil, nil, math.MaxInt64)
+	defer unlimited.Stop(ctx)
+	expected := getRowsFromBuffer(t, run(&FlowCtx{evalCtx: parser.EvalContext{Mon: &unlimited}}))
+
+	limited := mon.MakeMonitor("test", nil, nil, 1 /* increment */, math.MaxInt64)
+	limited.Start(ctx, nil, mon.MakeStandaloneBudget(8<<10))
+	defer limited.Stop(ctx)
+
+	// Without temporary storage, the sorter runs out of memory.
+	out := run(&FlowCtx{evalCtx: parser.EvalContext{Mon: &limited}})
+	if err := drainForError(out); !testutils.IsError(err, "memory budget exceeded") {
+		t.Fatalf("expected memory budget error, got %v", err)
+	}
+
+	out = run(&FlowCtx{evalCtx: parser.EvalContext{Mon: &limited}, tempStorage: tempEngine})
+	if expStr, retStr := expected.String(), getRowsFromBuffer(t, out).String(); expStr != retStr {
+		t.Errorf("invalid results; expected:\n   %s\ngot:\n   %s", expStr, retStr)
+	}
+}
+
+// drainForError reads the results until it finds an error, which it returns.
+// It returns nil if there are no errors.
+func drainForError(out *RowBuffer) error {
+	for {
+		row, meta := out.Next()
+		if meta.Err != nil {
+			return meta.Err
+		}
+		if row == nil && meta.Empty() {
+			return nil
+		}
+	}
+}
@@ -17,9 +17,13 @@
 package distsqlrun
 
 import (
+	"container/heap"
+
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
 )
@@ -41,9 +45,16 @@ type sorterStrategy interface {
 // uses sort.Sort to sort all values in-place. It has a worst-case time
 // complexity of O(n*log(n)) and a worst-case space complexity of O(n).
 //
+// If the rows don't fit in memory and the flow has temporary storage, the
+// strategy falls back to an external merge sort: every time the memory budget
+// runs out, the rows accumulated so far are sorted and written to disk as a
+// sorted run; once the input is exhausted, the runs are merged.
+//
 // The strategy is intended to be used when all values need to be sorted.
 type sortAllStrategy struct {
 	rows rowContainer
+	// runs are the sorted runs spilled to disk, if any.
+	runs []diskRowContainer
 }
 
 var _ sorterStrategy = &sortAllStrategy{}
@@ -55,11 +66,17 @@ func newSortAllStrategy(rows rowContainer) sorterStrategy {
 }
 
 // The execution loop for the SortAll strategy:
-//  - loads all rows into memory;
+//  - loads all rows into memory, spilling sorted runs to disk if needed;
 //  - runs sort.Sort to sort rows in place;
-//  - sends each row out to the output stream.
+//  - sends each row out to the output stream, merging the sorted runs if
+//    there are any.
 func (ss *sortAllStrategy) Execute(ctx context.Context, s *sorter) error {
 	defer ss.rows.Close(ctx)
+	defer func() {
+		for i := range ss.runs {
+			ss.runs[i].Close(ctx)
+		}
+	}()
 	for {
 		row, err := s.input.NextRow()
 		if err != nil {
@@ -69,9 +86,27 @@ func (ss *sortAllStrategy) Execute(ctx context.Context, s *sorter) error {
 			break
 		}
 		if err := ss.rows.AddRow(ctx, row); err != nil {
+			if ss.rows.Len() == 0 || !shouldSpillToDisk(s.flowCtx, err) {
+				return err
+			}
+			log.VEventf(ctx, 2, "spilling %d rows to disk", ss.rows.Len())
+			if err := ss.spillRun(ctx, s.flowCtx.tempStorage); err != nil {
+				return err
+			}
+			if err := ss.rows.AddRow(ctx, row); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(ss.runs) > 0 {
+		// Spill the last run too; all the runs are then merged from disk.
+		if err := ss.spillRun(ctx, s.flowCtx.tempStorage); err != nil {
 			return err
 		}
+		return ss.mergeRuns(ctx, s)
 	}
+
 	ss.rows.Sort()
 
 	for ss.rows.Len() > 0 {
@@ -85,6 +120,112 @@ func (ss *sortAllStrategy) Execute(ctx context.Context, s *sorter) error {
 	return nil
 }
 
+// spillRun sorts the rows held in memory, writes them to disk as a new sorted
+// run and clears them from memory.
+func (ss *sortAllStrategy) spillRun(ctx context.Context, e engine.Engine) error {
+	ss.rows.Sort()
+	ss.runs = append(ss.runs, makeDiskRowContainer(ss.rows.types, e))
+	run := &ss.runs[len(ss.runs)-1]
+	for i := 0; i < ss.rows.Len(); i++ {
+		if err := run.AddRow(ctx, ss.rows.EncRow(i)); err != nil {
+			return err
+		}
+	}
+	ss.rows.Clear(ctx)
+	return nil
+}
+
+// mergeRuns merges the sorted runs and sends the resulting rows to the output
+// stream.
+func (ss *sortAllStrategy) mergeRuns(ctx context.Context, s *sorter) error {
+	m := runMerger{ordering: ss.rows.ordering, evalCtx: ss.rows.evalCtx}
+	defer m.close()
+	for i := range ss.runs {
+		it, err := ss.runs[i].newIterator()
+		if err != nil {
+			return err
+		}
+		m.iters = append(m.iters, it)
+		row, err := it.Next()
+		if err != nil {
+			return err
+		}
+		if row != nil {
+			m.heads = append(m.heads, runHead{row: row, iter: it})
+		}
+	}
+	heap.Init(&m)
+	for len(m.heads) > 0 {
+		if m.err != nil {
+			return m.err
+		}
+		head := &m.heads[0]
+		consumerStatus, err := s.out.emitRow(ctx, head.row)
+		if err != nil || consumerStatus != NeedMoreRows {
+			return err
+		}
+		if head.row, err = head.iter.Next(); err != nil {
+			return err
+		}
+		if head.row == nil {
+			heap.Pop(&m)
+		} else {
+			heap.Fix(&m, 0)
+		}
+	}
+	return m.err
+}
+
+// runHead is the current row of a sorted run being merged.
+type runHead struct {
+	row  sqlbase.EncDatumRow
+	iter *diskRowIterator
+}
+
+// runMerger is a min-heap of the current rows of the sorted runs being merged.
+type runMerger struct {
+	heads    []runHead
+	iters    []*diskRowIterator
+	ordering sqlbase.ColumnOrdering
+	evalCtx  *parser.EvalContext
+	alloc    sqlbase.DatumAlloc
+	// err is set if comparing two rows failed.
+	err error
+}
+
+var _ heap.Interface = &runMerger{}
+
+// Len is part of heap.Interface.
+func (m *runMerger) Len() int { return len(m.heads) }
+
+// Less is part of heap.Interface.
+func (m *runMerger) Less(i, j int) bool {
+	cmp, err := m.heads[i].row.Compare(&m.alloc, m.ordering, m.evalCtx, m.heads[j].row)
+	if err != nil {
+		m.err = err
+	}
+	return cmp < 0
+}
+
+// Swap is part of heap.Interface.
+func (m *runMerger) Swap(i, j int) { m.heads[i], m.heads[j] = m.heads[j], m.heads[i] }
+
+// Push is part of heap.Interface.
+func (m *runMerger) Push(x interface{}) { m.heads = append(m.heads, x.(runHead)) }
+
+// Pop is part of heap.Interface.
+func (m *runMerger) Pop() interface{} {
+	x := m.heads[len(m.heads)-1]
+	m.heads = m.heads[:len(m.heads)-1]
+	return x
+}
+
+func (m *runMerger) close() {
+	for _, it := range m.iters {
+		it.Close()
+	}
+}
+
 // sortTopKStrategy creates a max-heap in its wrapped rows and keeps
 // this heap populated with only the top k values seen. It accomplishes this
 // by comparing new values (before the deep copy) with the top of the heap.
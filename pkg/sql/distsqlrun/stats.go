@@ -0,0 +1,129 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package distsqlrun
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// kvBytesReader is implemented by the processors that read from the KV layer.
+type kvBytesReader interface {
+	// kvBytesRead returns the number of bytes of keys and values read so far.
+	kvBytesRead() int64
+}
+
+var _ kvBytesReader = &tableReader{}
+var _ kvBytesReader = &joinReader{}
+
+func (tr *tableReader) kvBytesRead() int64 {
+	return tr.fetcher.BytesRead()
+}
+
+func (jr *joinReader) kvBytesRead() int64 {
+	return jr.fetcher.BytesRead()
+}
+
+// statsCollector records the execution statistics of a processor in a flow
+// that collects them. It is interposed between the processor and its output:
+// it counts the rows the processor produces and, once the processor is done,
+// sends its statistics to the consumer as a metadata record.
+type statsCollector struct {
+	// output is the processor's output.
+	output RowReceiver
+	// proc is the processor whose statistics are collected.
+	proc Processor
+	// mon is the memory monitor used by the processor (and only by it).
+	mon mon.MemoryMonitor
+
+	start time.Time
+	stats ProcessorStats
+}
+
+var _ RowReceiver = &statsCollector{}
+
+// Push is part of the RowReceiver interface.
+func (sc *statsCollector) Push(row sqlbase.EncDatumRow, meta ProducerMetadata) ConsumerStatus {
+	if row != nil {
+		sc.stats.RowsProduced++
+	}
+	return sc.output.Push(row, meta)
+}
+
+// ProducerDone is part of the RowReceiver interface.
+func (sc *statsCollector) ProducerDone() {
+	sc.stats.WallTime = timeutil.Since(sc.start)
+	sc.stats.MaxAllocatedMem = sc.mon.MaximumBytes()
+	if r, ok := sc.proc.(kvBytesReader); ok {
+		sc.stats.KVBytesRead = r.kvBytesRead()
+	}
+	stats := sc.stats
+	// The consumer may not accept the record if it no longer needs anything
+	// from us, in which case the statistics are lost.
+	_ = sc.output.Push(nil /* row */, ProducerMetadata{Stats: &stats})
+	sc.output.ProducerDone()
+}
+
+// statsProcessor is a processor that starts the wall time measurement of its
+// statsCollector when it runs.
+type statsProcessor struct {
+	Processor
+	collector *statsCollector
+}
+
+// Run is part of the Processor interface.
+func (sp *statsProcessor) Run(ctx context.Context, wg *sync.WaitGroup) {
+	sp.collector.start = timeutil.Now()
+	sp.Processor.Run(ctx, wg)
+}
+
+// makeStatsProcessor creates a processor using newProcessor whose statistics
+// are collected. The processor uses a memory monitor of its own, whose
+// maximum usage is reported; the monitor is returned and must be stopped
+// once the processor is done.
+func (f *Flow) makeStatsProcessor(
+	ctx context.Context,
+	ps *ProcessorSpec,
+	inputs []RowSource,
+	outputs []RowReceiver,
+) (Processor, *mon.MemoryMonitor, error) {
+	sc := &statsCollector{
+		output: outputs[0],
+		stats: ProcessorStats{
+			ProcessorID: ps.ProcessorID,
+			NodeID:      f.nodeID,
+		},
+	}
+	sc.mon = mon.MakeMonitor("processor",
+		nil /* curCount */, nil /* maxHist */, -1 /* use default block size */, noteworthyMemoryUsageBytes)
+	sc.mon.Start(ctx, f.evalCtx.Mon, mon.BoundAccount{})
+
+	flowCtx := f.FlowCtx
+	flowCtx.evalCtx.Mon = &sc.mon
+	outputs[0] = sc
+	proc, err := newProcessor(&flowCtx, &ps.Core, &ps.Post, inputs, outputs)
+	if err != nil {
+		sc.mon.Stop(ctx)
+		return nil, nil, err
+	}
+	sc.proc = proc
+	return &statsProcessor{Processor: proc, collector: sc}, &sc.mon, nil
+}
@@ -107,6 +107,10 @@ func (sd *StreamDecoder) AddMessage(msg *ProducerMessage) error {
 				meta.Ranges = rangeInfo.RangeInfo
 			} else if pErr := md.GetError(); pErr != nil {
 				meta.Err = pErr.ErrorDetail()
+			} else if stats := md.GetStats(); stats != nil {
+				meta.Stats = stats
+			} else if progress := md.GetReadCSVProgress(); progress != nil {
+				meta.ReadCSVProgress = progress
 			}
 			sd.metadata = append(sd.metadata, meta)
 		}
@@ -82,6 +82,14 @@ func (se *StreamEncoder) AddMetadata(meta ProducerMetadata) {
 				RangeInfo: meta.Ranges,
 			},
 		}
+	} else if meta.Stats != nil {
+		enc.Value = &RemoteProducerMetadata_Stats{
+			Stats: meta.Stats,
+		}
+	} else if meta.ReadCSVProgress != nil {
+		enc.Value = &RemoteProducerMetadata_ReadCSVProgress{
+			ReadCSVProgress: meta.ReadCSVProgress,
+		}
 	} else {
 		enc.Value = &RemoteProducerMetadata_Error{
 			Error: NewError(meta.Err),
@@ -100,8 +108,10 @@ func (se *StreamEncoder) AddRow(row sqlbase.EncDatumRow) error {
 			if !ok {
 				enc = preferredEncoding
 			}
-			if enc != sqlbase.DatumEncoding_VALUE && sqlbase.HasCompositeKeyEncoding(row[i].Type.Kind) {
-				// Force VALUE encoding for composite types (key encodings may lose data).
+			if enc != sqlbase.DatumEncoding_VALUE &&
+				(sqlbase.HasCompositeKeyEncoding(row[i].Type.Kind) || !sqlbase.ColumnTypeIsIndexable(row[i].Type)) {
+				// Force VALUE encoding for composite types (key encodings may lose data)
+				// and for types that have no key encoding.
 				enc = sqlbase.DatumEncoding_VALUE
 			}
 			se.infos[i].Encoding = enc
@@ -45,7 +45,7 @@ type tableReader struct {
 	out procOutputHelper
 }
 
-var _ processor = &tableReader{}
+var _ Processor = &tableReader{}
 
 // newTableReader creates a tableReader.
 func newTableReader(
@@ -172,7 +172,7 @@ func (tr *tableReader) sendMisplannedRangesMetadata(ctx context.Context) {
 	}
 }
 
-// Run is part of the processor interface.
+// Run is part of the Processor interface.
 func (tr *tableReader) Run(ctx context.Context, wg *sync.WaitGroup) {
 	if wg != nil {
 		defer wg.Done()
@@ -24,6 +24,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -152,6 +153,10 @@ func (n *dropDatabaseNode) Start(ctx context.Context) error {
 				return err
 			}
 			tbNameStrings = append(tbNameStrings, cascadedViews...)
+		} else if tbDesc.IsSequence() {
+			if err := n.p.dropSequenceImpl(ctx, tbDesc); err != nil {
+				return err
+			}
 		} else {
 			cascadedViews, err := n.p.dropTableImpl(ctx, tbDesc)
 			if err != nil {
@@ -529,6 +534,88 @@ func (*dropViewNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
 	panic("unimplemented")
 }
 
+type dropSequenceNode struct {
+	p  *planner
+	n  *parser.DropSequence
+	td []*sqlbase.TableDescriptor
+}
+
+// DropSequence drops a sequence.
+// Privileges: DROP on sequence.
+//
+//	Notes: postgres allows only the sequence owner to DROP a sequence.
+func (p *planner) DropSequence(ctx context.Context, n *parser.DropSequence) (planNode, error) {
+	td := make([]*sqlbase.TableDescriptor, 0, len(n.Names))
+	for _, name := range n.Names {
+		tn, err := name.NormalizeTableName()
+		if err != nil {
+			return nil, err
+		}
+		if err := tn.QualifyWithDatabase(p.session.Database); err != nil {
+			return nil, err
+		}
+
+		droppedDesc, err := p.dropTableOrViewPrepare(ctx, tn)
+		if err != nil {
+			return nil, err
+		}
+		if droppedDesc == nil {
+			if n.IfExists {
+				continue
+			}
+			// Sequence does not exist, but we want it to: error out.
+			return nil, sqlbase.NewUndefinedSequenceError(name.String())
+		}
+		if !droppedDesc.IsSequence() {
+			return nil, sqlbase.NewWrongObjectTypeError(name.String(), "sequence")
+		}
+		td = append(td, droppedDesc)
+	}
+
+	if len(td) == 0 {
+		return &emptyNode{}, nil
+	}
+	return &dropSequenceNode{p: p, n: n, td: td}, nil
+}
+
+func (n *dropSequenceNode) Start(ctx context.Context) error {
+	for _, droppedDesc := range n.td {
+		if err := n.p.dropSequenceImpl(ctx, droppedDesc); err != nil {
+			return err
+		}
+		// Log a Drop Sequence event for this sequence. This is an auditable log
+		// event and is recorded in the same transaction as the table descriptor
+		// update.
+		if err := MakeEventLogger(n.p.LeaseMgr()).InsertEventRecord(
+			ctx,
+			n.p.txn,
+			EventLogDropSequence,
+			int32(droppedDesc.ID),
+			int32(n.p.evalCtx.NodeID),
+			struct {
+				SequenceName string
+				Statement    string
+				User         string
+			}{droppedDesc.Name, n.n.String(), n.p.session.User},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropSequenceNode) Next(context.Context) (bool, error) { return false, nil }
+func (*dropSequenceNode) Close(context.Context)              {}
+func (*dropSequenceNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*dropSequenceNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*dropSequenceNode) Values() parser.Datums              { return parser.Datums{} }
+func (*dropSequenceNode) DebugValues() debugValues           { return debugValues{} }
+func (*dropSequenceNode) MarkDebug(mode explainMode)         {}
+
+func (*dropSequenceNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
+
 type dropTableNode struct {
 	p  *planner
 	n  *parser.DropTable
@@ -992,6 +1079,19 @@ func (p *planner) dropViewImpl(
 	return cascadeDroppedViews, nil
 }
 
+// dropSequenceImpl does the work of dropping a sequence. The sequence value is
+// deleted asynchronously by the schema changer along with the descriptor.
+func (p *planner) dropSequenceImpl(ctx context.Context, seqDesc *sqlbase.TableDescriptor) error {
+	if err := p.initiateDropTable(ctx, seqDesc); err != nil {
+		return err
+	}
+
+	p.session.setTestingVerifyMetadata(func(systemConfig config.SystemConfig) error {
+		return verifyDropTableMetadata(systemConfig, seqDesc.ID, "sequence")
+	})
+	return nil
+}
+
 // truncateAndDropTable batches all the commands required for truncating and
 // deleting the table descriptor. It is called from a mutation, async wrt the
 // DROP statement. Before this method is called, the table has already been
@@ -1033,7 +1133,14 @@ func truncateAndDropTable(
 		}
 	}
 
-	if err := truncateTableInChunks(ctx, tableDesc, db); err != nil {
+	if tableDesc.IsSequence() {
+		// A sequence has no rows; its value lives in at most two keys.
+		if err := db.Del(
+			ctx, keys.MakeSequenceKey(uint32(tableDesc.ID)), keys.MakeSequenceUncalledKey(uint32(tableDesc.ID)),
+		); err != nil {
+			return err
+		}
+	} else if err := truncateTableInChunks(ctx, tableDesc, db); err != nil {
 		return err
 	}
 
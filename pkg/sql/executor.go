@@ -42,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -251,6 +252,14 @@ type ExecutorConfig struct {
 	// Caches updated by DistSQL.
 	RangeDescriptorCache *kv.RangeDescriptorCache
 	LeaseHolderCache     *kv.LeaseHolderCache
+
+	// SessionRegistry tracks the sessions running on this node.
+	SessionRegistry *SessionRegistry
+
+	// TableStatsCache caches the statistics of the tables, which are used by
+	// the planner to estimate the selectivity of filters. If nil, the planner
+	// does not use statistics.
+	TableStatsCache *stats.TableStatisticsCache
 }
 
 var _ base.ModuleTestingKnobs = &ExecutorTestingKnobs{}
@@ -1343,6 +1352,10 @@ func (e *Executor) execStmtInOpenTxn(
 	planner.avoidCachedDescriptors = avoidCachedDescriptors
 	planner.phaseTimes[plannerStartExecStmt] = timeutil.Now()
 
+	// Register the query so that it can be listed and canceled. Parallelized
+	// statements deregister themselves once they are done running.
+	planner.queryMeta = session.addActiveQuery(e.generateID(), stmt)
+
 	var result Result
 	if parallelize && !implicitTxn {
 		// Only run statements asynchronously through the parallelize queue if the
@@ -1355,9 +1368,19 @@ func (e *Executor) execStmtInOpenTxn(
 		planner.autoCommit = implicitTxn && !e.cfg.TestingKnobs.DisableAutoCommit
 		result, err = e.execStmt(stmt, planner,
 			automaticRetryCount, parallelize /* mockResults */)
+		session.removeActiveQuery(planner.queryMeta)
 	}
 
 	if err != nil {
+		if session.Ctx().Err() == context.Canceled {
+			// The query (or another query of the same txn) was canceled; report
+			// that instead of the error the cancellation caused.
+			if session.queryTimedOut(planner.queryMeta) {
+				err = sqlbase.NewQueryTimeoutError()
+			} else {
+				err = sqlbase.NewQueryCanceledError()
+			}
+		}
 		if independentFromParallelStmts {
 			// If the statement run was independent from parallelized execution, it
 			// might have been run concurrently with parallelized statements. Make
@@ -1519,7 +1542,7 @@ func (e *Executor) execClassic(planner *planner, plan planNode, result *Result)
 
 // shouldUseDistSQL determines whether we should use DistSQL for a plan, based
 // on the session settings.
-func (e *Executor) shouldUseDistSQL(planner *planner, plan planNode) (bool, error) {
+func shouldUseDistSQL(dsp *distSQLPlanner, planner *planner, plan planNode) (bool, error) {
 	distSQLMode := planner.session.DistSQLMode
 	if distSQLMode == DistSQLOff {
 		return false, nil
@@ -1543,7 +1566,7 @@ func (e *Executor) shouldUseDistSQL(planner *planner, plan planNode) (bool, erro
 	} else {
 		// Trigger limit propagation.
 		setUnlimited(plan)
-		distribute, err = e.distSQLPlanner.CheckSupport(plan)
+		distribute, err = dsp.CheckSupport(plan)
 	}
 
 	if err != nil {
@@ -1607,11 +1630,12 @@ func (e *Executor) execStmt(
 		return Result{}, err
 	}
 
-	useDistSQL, err := e.shouldUseDistSQL(planner, plan)
+	useDistSQL, err := shouldUseDistSQL(e.distSQLPlanner, planner, plan)
 	if err != nil {
 		result.Close(session.Ctx())
 		return Result{}, err
 	}
+	session.setQueryExecutionMode(planner.queryMeta, useDistSQL)
 
 	planner.phaseTimes[plannerStartExecStmt] = timeutil.Now()
 	if useDistSQL {
@@ -1649,15 +1673,18 @@ func (e *Executor) execStmtInParallel(stmt parser.Statement, planner *planner) (
 
 	plan, err := planner.makePlan(ctx, stmt)
 	if err != nil {
+		session.removeActiveQuery(planner.queryMeta)
 		return Result{}, err
 	}
 
 	mockResult, err := makeRes(stmt, planner, plan)
 	if err != nil {
+		session.removeActiveQuery(planner.queryMeta)
 		return Result{}, err
 	}
 
 	session.parallelizeQueue.Add(ctx, plan, func(plan planNode) error {
+		defer session.removeActiveQuery(planner.queryMeta)
 		defer plan.Close(ctx)
 
 		result, err := makeRes(stmt, planner, plan)
@@ -1666,6 +1693,7 @@ func (e *Executor) execStmtInParallel(stmt parser.Statement, planner *planner) (
 		}
 		defer result.Close(ctx)
 
+		session.setQueryExecutionMode(planner.queryMeta, false /* isDistributed */)
 		planner.phaseTimes[plannerStartExecStmt] = timeutil.Now()
 		err = e.execClassic(planner, plan, &result)
 		planner.phaseTimes[plannerEndExecStmt] = timeutil.Now()
@@ -1733,6 +1761,8 @@ func golangFillQueryArguments(pinfo *parser.PlaceholderInfo, args []interface{})
 			dd := &parser.DDecimal{}
 			dd.Set(t)
 			d = dd
+		case uuid.UUID:
+			d = parser.NewDUuid(parser.DUuid{UUID: t})
 		}
 		if d == nil {
 			// Handle all types which have an underlying type that can be stored in the
@@ -1780,6 +1810,8 @@ func checkResultType(typ parser.Type) error {
 	case parser.TypeTimestamp:
 	case parser.TypeTimestampTZ:
 	case parser.TypeInterval:
+	case parser.TypeUUID:
+	case parser.TypeJSON:
 	case parser.TypeStringArray:
 	case parser.TypeNameArray:
 	case parser.TypeIntArray:
@@ -1797,6 +1829,13 @@ func checkResultType(typ parser.Type) error {
 		case istype(parser.TypeTuple):
 		case istype(parser.TypePlaceholder):
 			return errors.Errorf("could not determine data type of %s", typ)
+		case istype(parser.TypeAnyArray):
+			// Arrays of arrays are not supported.
+			elemTyp := parser.UnwrapType(typ).(parser.TArray).Typ
+			if elemTyp.FamilyEqual(parser.TypeAnyArray) {
+				return errors.Errorf("unsupported result type: %s", typ)
+			}
+			return checkResultType(elemTyp)
 		default:
 			return errors.Errorf("unsupported result type: %s", typ)
 		}
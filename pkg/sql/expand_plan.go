@@ -84,6 +84,12 @@ func doExpandPlan(
 			return plan, err
 		}
 
+	case *explainAnalyzeNode:
+		n.plan, err = doExpandPlan(ctx, p, noParams, n.plan)
+		if err != nil {
+			return plan, err
+		}
+
 	case *explainTraceNode:
 		n.plan, err = doExpandPlan(ctx, p, noParams, n.plan)
 		if err != nil {
@@ -131,6 +137,13 @@ func doExpandPlan(
 			return plan, err
 		}
 		n.right.plan, err = doExpandPlan(ctx, p, noParams, n.right.plan)
+		if err != nil {
+			return plan, err
+		}
+		n.chooseBuildSide(ctx)
+
+	case *recursiveCTENode:
+		n.initial, err = doExpandPlan(ctx, p, noParams, n.initial)
 
 	case *ordinalityNode:
 		// If there's a desired ordering on the ordinality column, drop it.
@@ -257,11 +270,17 @@ func doExpandPlan(
 	case *valuesNode:
 	case *alterTableNode:
 	case *copyNode:
+	case *cancelQueryNode:
+	case *controlJobNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
+	case *roleNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *emptyNode:
@@ -427,6 +446,9 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 	case *explainDistSQLNode:
 		n.plan = simplifyOrderings(n.plan, nil)
 
+	case *explainAnalyzeNode:
+		n.plan = simplifyOrderings(n.plan, nil)
+
 	case *explainTraceNode:
 		n.plan = simplifyOrderings(n.plan, nil)
 
@@ -450,6 +472,9 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 		n.left.plan = simplifyOrderings(n.left.plan, nil)
 		n.right.plan = simplifyOrderings(n.right.plan, nil)
 
+	case *recursiveCTENode:
+		n.initial = simplifyOrderings(n.initial, nil)
+
 	case *ordinalityNode:
 		// The ordinality node either passes through the source ordering, or if
 		// there is none it creates an ordering on the ordinality column (see the
@@ -541,11 +566,17 @@ func simplifyOrderings(plan planNode, usefulOrdering sqlbase.ColumnOrdering) pla
 	case *valuesNode:
 	case *alterTableNode:
 	case *copyNode:
+	case *cancelQueryNode:
+	case *controlJobNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
+	case *roleNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *emptyNode:
@@ -40,6 +40,9 @@ const (
 	// query would be run in "auto" DISTSQL mode. See explainDistSQLNode for
 	// details.
 	explainDistSQL
+	// explainAnalyze runs a query and shows the execution statistics of its
+	// operators. See explainAnalyzeNode for details.
+	explainAnalyze
 )
 
 var explainStrings = map[explainMode]string{
@@ -47,6 +50,7 @@ var explainStrings = map[explainMode]string{
 	explainPlan:    "plan",
 	explainTrace:   "trace",
 	explainDistSQL: "distsql",
+	explainAnalyze: "analyze",
 }
 
 // Explain executes the explain statement, providing debugging and analysis
@@ -158,6 +162,9 @@ func (p *planner) Explain(ctx context.Context, n *parser.Explain) (planNode, err
 	case explainTrace:
 		return p.makeTraceNode(plan), nil
 
+	case explainAnalyze:
+		return p.makeExplainAnalyzeNode(plan), nil
+
 	default:
 		return nil, fmt.Errorf("unsupported EXPLAIN mode: %d", mode)
 	}
@@ -0,0 +1,434 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/distsqlplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/mon"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// explainAnalyzeNode is a planNode that runs the plan it wraps, discarding
+// its results, and returns the execution statistics of the operators of the
+// plan. It is used as the top-level node for EXPLAIN (ANALYZE) statements.
+//
+// If the session would run the plan with DistSQL, the operators are the
+// processors of the physical plan, whose statistics are reported by the
+// nodes that run them. Otherwise, the operators are the planNodes of the
+// plan.
+type explainAnalyzeNode struct {
+	p    *planner
+	plan planNode
+
+	// results is the container for the statistics, populated by Start.
+	results *valuesNode
+}
+
+var explainAnalyzeColumns = sqlbase.ResultColumns{
+	// Level is the depth of the operator in the plan.
+	{Name: "Level", Typ: parser.TypeInt},
+	// Type is the name of the planNode or of the processor core.
+	{Name: "Type", Typ: parser.TypeString},
+	// Node is the node on which the operator ran.
+	{Name: "Node", Typ: parser.TypeInt},
+	// Rows is the number of rows produced by the operator.
+	{Name: "Rows", Typ: parser.TypeInt},
+	// KV Bytes is the number of bytes of keys and values that the operator
+	// itself read from the KV layer.
+	{Name: "KV Bytes", Typ: parser.TypeInt},
+	// Wall Time is the time spent running the operator, including the time
+	// spent in its inputs.
+	{Name: "Wall Time", Typ: parser.TypeInterval},
+	// Max Memory is the maximum number of bytes the operator had allocated at
+	// any one time. For planNodes, it is measured when the node returns
+	// control to its parent, so short-lived allocations are not seen.
+	{Name: "Max Memory", Typ: parser.TypeInt},
+}
+
+func (p *planner) makeExplainAnalyzeNode(plan planNode) planNode {
+	return &explainAnalyzeNode{
+		p:       p,
+		plan:    plan,
+		results: p.newContainerValuesNode(explainAnalyzeColumns, 0),
+	}
+}
+
+func (n *explainAnalyzeNode) Columns() sqlbase.ResultColumns         { return n.results.Columns() }
+func (n *explainAnalyzeNode) Ordering() orderingInfo                 { return n.results.Ordering() }
+func (n *explainAnalyzeNode) Next(ctx context.Context) (bool, error) { return n.results.Next(ctx) }
+func (n *explainAnalyzeNode) Values() parser.Datums                  { return n.results.Values() }
+func (*explainAnalyzeNode) MarkDebug(_ explainMode)                  {}
+func (*explainAnalyzeNode) DebugValues() debugValues                 { return debugValues{} }
+
+func (n *explainAnalyzeNode) Spans(ctx context.Context) (_, _ roachpb.Spans, _ error) {
+	return n.plan.Spans(ctx)
+}
+
+func (n *explainAnalyzeNode) Close(ctx context.Context) {
+	n.plan.Close(ctx)
+	n.results.Close(ctx)
+}
+
+func (n *explainAnalyzeNode) Start(ctx context.Context) error {
+	// Trigger limit propagation.
+	setUnlimited(n.plan)
+
+	useDistSQL, err := shouldUseDistSQL(n.p.session.distSQLPlanner, n.p, n.plan)
+	if err != nil {
+		return err
+	}
+	if useDistSQL {
+		return n.runDistSQL(ctx)
+	}
+	return n.runLocal(ctx)
+}
+
+// addRow adds a row of statistics to the results. The statistics that are
+// not known are passed as negative values and are reported as NULL.
+func (n *explainAnalyzeNode) addRow(
+	ctx context.Context,
+	level int,
+	typ string,
+	nodeID roachpb.NodeID,
+	rows, kvBytes int64,
+	wallTime time.Duration,
+	maxMemory int64,
+) error {
+	intOrNull := func(v int64) parser.Datum {
+		if v < 0 {
+			return parser.DNull
+		}
+		return parser.NewDInt(parser.DInt(v))
+	}
+	wallTimeDatum := parser.DNull
+	if wallTime >= 0 {
+		wallTimeDatum = &parser.DInterval{Duration: duration.Duration{Nanos: wallTime.Nanoseconds()}}
+	}
+	_, err := n.results.rows.AddRow(ctx, parser.Datums{
+		parser.NewDInt(parser.DInt(level)),
+		parser.NewDString(typ),
+		parser.NewDInt(parser.DInt(nodeID)),
+		intOrNull(rows),
+		intOrNull(kvBytes),
+		wallTimeDatum,
+		intOrNull(maxMemory),
+	})
+	return err
+}
+
+// runDistSQL runs the plan with DistSQL and reports the statistics of its
+// processors, in the order of a depth-first traversal of the physical plan
+// that starts from the processor producing the results.
+func (n *explainAnalyzeNode) runDistSQL(ctx context.Context) error {
+	execCfg := n.p.ExecCfg()
+	recv, err := makeDistSQLReceiver(
+		ctx, nil, /* sink */
+		execCfg.RangeDescriptorCache, execCfg.LeaseHolderCache,
+		n.p.txn,
+		func(ts hlc.Timestamp) {
+			_ = execCfg.Clock.Update(ts)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	dsp := n.p.session.distSQLPlanner
+	planCtx := dsp.NewPlanningCtx(ctx, n.p.txn)
+	planCtx.collectStats = true
+	plan, err := dsp.createPlanForNode(&planCtx, n.plan)
+	if err != nil {
+		return err
+	}
+	dsp.FinalizePlan(&planCtx, &plan)
+	if err := dsp.Run(&planCtx, n.p.txn, &plan, &recv, n.p.evalCtx); err != nil {
+		return err
+	}
+	if recv.err != nil {
+		return recv.err
+	}
+
+	// inputs[i] lists the processors whose output is consumed by processor i.
+	inputs := make([][]distsqlplan.Stream, len(plan.Processors))
+	for _, s := range plan.Streams {
+		inputs[s.DestProcessor] = append(inputs[s.DestProcessor], s)
+	}
+	for _, in := range inputs {
+		sort.Slice(in, func(i, j int) bool {
+			if in[i].DestInput != in[j].DestInput {
+				return in[i].DestInput < in[j].DestInput
+			}
+			return in[i].SourceProcessor < in[j].SourceProcessor
+		})
+	}
+	visited := make([]bool, len(plan.Processors))
+	var visit func(idx distsqlplan.ProcessorIdx, level int) error
+	visit = func(idx distsqlplan.ProcessorIdx, level int) error {
+		if visited[idx] {
+			return nil
+		}
+		visited[idx] = true
+		proc := &plan.Processors[idx]
+		// The statistics are missing if the processor could not send them,
+		// for example because its consumer stopped early.
+		rows, kvBytes, wallTime, maxMemory := int64(-1), int64(-1), time.Duration(-1), int64(-1)
+		if stats, ok := recv.stats[int32(idx)]; ok {
+			rows, kvBytes = stats.RowsProduced, stats.KVBytesRead
+			wallTime, maxMemory = stats.WallTime, stats.MaxAllocatedMem
+		}
+		if err := n.addRow(
+			ctx, level, proc.Spec.Core.Title(), proc.Node, rows, kvBytes, wallTime, maxMemory,
+		); err != nil {
+			return err
+		}
+		for _, s := range inputs[idx] {
+			if err := visit(s.SourceProcessor, level+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, idx := range plan.ResultRouters {
+		if err := visit(idx, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runLocal runs the plan locally and reports the statistics of its planNodes,
+// in the order in which EXPLAIN shows them.
+func (n *explainAnalyzeNode) runLocal(ctx context.Context) error {
+	stats := make(map[planNode]*planNodeStats)
+	mem := &planMemTracker{mon: &n.p.session.TxnState.mon}
+	instrumentPlan(n.plan, stats, mem)
+	root := &instrumentedNode{planNode: n.plan, stats: &planNodeStats{}, mem: mem}
+	stats[n.plan] = root.stats
+
+	if err := root.Start(ctx); err != nil {
+		return err
+	}
+	for {
+		next, err := root.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+	}
+	if fp, ok := n.plan.(planNodeFastPath); ok {
+		if count, ok := fp.FastPathResults(); ok {
+			root.stats.rows = int64(count)
+		}
+	}
+
+	nodeID := n.p.evalCtx.NodeID
+	level := 0
+	var err error
+	observer := planObserver{
+		enterNode: func(ctx context.Context, name string, plan planNode) bool {
+			if err != nil {
+				return false
+			}
+			rows, wallTime, maxMemory := int64(-1), time.Duration(-1), int64(-1)
+			if s, ok := stats[plan]; ok {
+				rows, wallTime, maxMemory = s.rows, s.wallTime, s.maxMemory
+			}
+			kvBytes := int64(-1)
+			if r, ok := plan.(kvBytesReader); ok {
+				kvBytes = r.kvBytesRead()
+			}
+			err = n.addRow(ctx, level, name, nodeID, rows, kvBytes, wallTime, maxMemory)
+			level++
+			return true
+		},
+		leaveNode: func(string) {
+			level--
+		},
+	}
+	if walkErr := walkPlan(ctx, n.plan, observer); walkErr != nil {
+		return walkErr
+	}
+	return err
+}
+
+// planNodeStats are the execution statistics of a planNode.
+type planNodeStats struct {
+	rows     int64
+	wallTime time.Duration
+	// curMemory is the number of bytes the node currently has allocated, and
+	// maxMemory the maximum it had at the end of a call to Start or Next.
+	curMemory, maxMemory int64
+}
+
+// planMemTracker attributes the memory allocated from the transaction's
+// monitor, which the planNodes of a local plan share, to the individual
+// nodes. The plan runs in a single goroutine, so the bytes allocated during a
+// call to Start or Next belong to the called node, minus those attributed to
+// its descendants during the same call.
+type planMemTracker struct {
+	mon *mon.MemoryMonitor
+	// attributed is the number of bytes attributed to the nodes that returned
+	// since the start of the outermost call in progress.
+	attributed int64
+}
+
+// track calls fn and attributes the memory it allocated to stats.
+func (t *planMemTracker) track(stats *planNodeStats, fn func()) {
+	startAlloc, startAttributed := t.mon.AllocBytes(), t.attributed
+	fn()
+	delta := t.mon.AllocBytes() - startAlloc
+	stats.curMemory += delta - (t.attributed - startAttributed)
+	if stats.curMemory > stats.maxMemory {
+		stats.maxMemory = stats.curMemory
+	}
+	t.attributed = startAttributed + delta
+}
+
+// instrumentedNode wraps a planNode to record its execution statistics. The
+// time spent in the node includes the time spent in its children, but its
+// memory does not include the memory of its children.
+//
+// instrumentedNodes are transparent to walkPlan.
+type instrumentedNode struct {
+	planNode
+	stats *planNodeStats
+	mem   *planMemTracker
+}
+
+func (n *instrumentedNode) Start(ctx context.Context) error {
+	var err error
+	n.mem.track(n.stats, func() {
+		start := timeutil.Now()
+		err = n.planNode.Start(ctx)
+		n.stats.wallTime += timeutil.Since(start)
+	})
+	return err
+}
+
+func (n *instrumentedNode) Next(ctx context.Context) (bool, error) {
+	var next bool
+	var err error
+	n.mem.track(n.stats, func() {
+		start := timeutil.Now()
+		next, err = n.planNode.Next(ctx)
+		n.stats.wallTime += timeutil.Since(start)
+	})
+	if next {
+		n.stats.rows++
+	}
+	return next, err
+}
+
+// instrumentPlan wraps the descendants of plan in instrumentedNodes, which
+// record the statistics of the wrapped nodes in stats. It must be called once
+// the plan is fully optimized.
+//
+// The nodes that their parent cannot reference through a planNode (such as
+// the scans of an index join) are not instrumented.
+func instrumentPlan(plan planNode, stats map[planNode]*planNodeStats, mem *planMemTracker) {
+	wrap := func(child planNode) planNode {
+		instrumentPlan(child, stats, mem)
+		s := &planNodeStats{}
+		stats[child] = s
+		return &instrumentedNode{planNode: child, stats: s, mem: mem}
+	}
+
+	switch n := plan.(type) {
+	case *filterNode:
+		n.source.plan = wrap(n.source.plan)
+
+	case *renderNode:
+		n.source.plan = wrap(n.source.plan)
+
+	case *joinNode:
+		n.left.plan = wrap(n.left.plan)
+		n.right.plan = wrap(n.right.plan)
+
+	case *limitNode:
+		n.plan = wrap(n.plan)
+
+	case *distinctNode:
+		n.plan = wrap(n.plan)
+
+	case *sortNode:
+		n.plan = wrap(n.plan)
+
+	case *groupNode:
+		n.plan = wrap(n.plan)
+
+	case *windowNode:
+		n.plan = wrap(n.plan)
+
+	case *unionNode:
+		n.left = wrap(n.left)
+		n.right = wrap(n.right)
+
+	case *ordinalityNode:
+		n.source = wrap(n.source)
+
+	case *recursiveCTENode:
+		n.initial = wrap(n.initial)
+
+	case *splitNode:
+		n.rows = wrap(n.rows)
+
+	case *relocateNode:
+		n.rows = wrap(n.rows)
+
+	case *insertNode:
+		n.run.rows = wrap(n.run.rows)
+
+	case *updateNode:
+		n.run.rows = wrap(n.run.rows)
+
+	case *deleteNode:
+		// The fast path of DELETE looks for a scan, possibly under a render,
+		// so neither of them is instrumented.
+		rows := n.run.rows
+		if r, ok := rows.(*renderNode); ok {
+			rows = r.source.plan
+		}
+		instrumentPlan(rows, stats, mem)
+
+	case *createTableNode:
+		if n.n.As() {
+			n.sourcePlan = wrap(n.sourcePlan)
+		}
+	}
+}
+
+// kvBytesReader is implemented by the planNodes that read from the KV layer.
+type kvBytesReader interface {
+	// kvBytesRead returns the number of bytes of keys and values read so far.
+	kvBytesRead() int64
+}
+
+var _ kvBytesReader = &scanNode{}
+
+func (n *scanNode) kvBytesRead() int64 {
+	return n.fetcher.BytesRead()
+}
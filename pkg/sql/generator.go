@@ -45,10 +45,16 @@ type valueGenerator struct {
 	// columns is the signature of this generator.
 	columns sqlbase.ResultColumns
 
-	// rowCount is used for DebugValues() only.
+	// rowCount is used for DebugValues() and to periodically check whether
+	// the query was canceled.
 	rowCount int
 }
 
+// generatorCancelCheckInterval is the number of rows a valueGenerator produces
+// between checks of its context for cancellation. Generators do not otherwise
+// block on anything that notices a canceled query.
+const generatorCancelCheckInterval = 1024
+
 // makeGenerator creates a valueGenerator instance that wraps a call to a
 // generator function.
 func (p *planner) makeGenerator(ctx context.Context, t *parser.FuncExpr) (planNode, error) {
@@ -106,8 +112,13 @@ func (n *valueGenerator) Start(context.Context) error {
 	return nil
 }
 
-func (n *valueGenerator) Next(context.Context) (bool, error) {
+func (n *valueGenerator) Next(ctx context.Context) (bool, error) {
 	n.rowCount++
+	if n.rowCount%generatorCancelCheckInterval == 0 {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+	}
 	return n.gen.Next()
 }
 
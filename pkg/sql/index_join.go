@@ -108,6 +108,11 @@ type indexJoinNode struct {
 	// uses more columns than the PK.
 	primaryKeyColumns []bool
 
+	// seenKeys records the primary keys looked up in the table when the index
+	// scanNode can produce the same row several times, which happens when it
+	// scans several spans of an inverted index.
+	seenKeys map[string]struct{}
+
 	explain   explainMode
 	debugVals debugValues
 }
@@ -162,11 +167,17 @@ func (p *planner) makeIndexJoin(
 	// Then, in case the index-specific part, post-split, actually
 	// refers to any additional column, we also need to prepare the
 	// mapping for these columns in colIDtoRowIndex.
+	inverted := indexScan.index.Type == sqlbase.IndexDescriptor_INVERTED
 	for _, colID := range indexScan.index.ColumnIDs {
 		idx, ok := indexScan.colIdxMap[colID]
 		if !ok {
 			panic(fmt.Sprintf("Unknown column %d in index!", colID))
 		}
+		if inverted {
+			// The keys of inverted indexes contain the elements of the column
+			// instead of its values.
+			continue
+		}
 		valProvidedIndex[idx] = true
 		colIDtoRowIndex[colID] = idx
 	}
@@ -207,6 +218,9 @@ func (p *planner) makeIndexJoin(
 		colIDtoRowIndex:   colIDtoRowIndex,
 		primaryKeyColumns: primaryKeyColumns,
 	}
+	if inverted && len(indexScan.spans) > 1 {
+		node.seenKeys = make(map[string]struct{})
+	}
 
 	return node, indexScan
 }
@@ -314,6 +328,12 @@ func (n *indexJoinNode) Next(ctx context.Context) (bool, error) {
 			if err != nil {
 				return false, err
 			}
+			if n.seenKeys != nil {
+				if _, ok := n.seenKeys[string(primaryIndexKey)]; ok {
+					continue
+				}
+				n.seenKeys[string(primaryIndexKey)] = struct{}{}
+			}
 			key := roachpb.Key(primaryIndexKey)
 			n.table.spans = append(n.table.spans, roachpb.Span{
 				Key:    key,
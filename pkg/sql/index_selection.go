@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/pkg/errors"
@@ -90,6 +91,9 @@ func (p *planner) selectIndex(
 		return s, nil
 	}
 
+	colStats := p.getColumnStats(ctx, &s.desc)
+	s.estimatedRowCount = colStats.rowCount()
+
 	if s.filter == nil && analyzeOrdering == nil && s.specifiedIndex == nil {
 		// No where-clause, no ordering, and no specified index.
 		s.initOrdering(0)
@@ -122,7 +126,51 @@ func (p *planner) selectIndex(
 		}
 	}
 
+	// Partial indexes only contain the rows that satisfy their predicate, so
+	// they can only be used if the filter implies the predicate.
+	for i := 0; i < len(candidates); {
+		usable, err := p.partialIndexUsable(ctx, s, candidates[i].index)
+		if err != nil {
+			return nil, err
+		}
+		if usable {
+			i++
+			continue
+		}
+		if s.specifiedIndex != nil {
+			return nil, fmt.Errorf("partial index %q cannot be used: the WHERE clause does not imply "+
+				"its predicate", s.specifiedIndex.Name)
+		}
+		candidates = append(candidates[:i], candidates[i+1:]...)
+	}
+
+	// Inverted indexes can only be used if the filter constrains the elements
+	// of their column.
+	for i := 0; i < len(candidates); {
+		c := candidates[i]
+		if c.index.Type != sqlbase.IndexDescriptor_INVERTED {
+			i++
+			continue
+		}
+		spans, usable, err := invertedIndexSpans(&p.evalCtx, s, c.index)
+		if err != nil {
+			return nil, err
+		}
+		if usable {
+			c.invertedSpans = spans
+			i++
+			continue
+		}
+		if s.specifiedIndex != nil {
+			return nil, fmt.Errorf("inverted index %q cannot be used: the WHERE clause does not "+
+				"constrain the elements of its column", s.specifiedIndex.Name)
+		}
+		candidates = append(candidates[:i], candidates[i+1:]...)
+	}
+
 	for _, c := range candidates {
+		c.stats = colStats
+		c.evalCtx = &p.evalCtx
 		c.init(s)
 	}
 
@@ -209,14 +257,21 @@ func (p *planner) selectIndex(
 	// After sorting, candidates[0] contains the best index. Copy its info into
 	// the scanNode.
 	c := candidates[0]
+	if s.estimatedRowCount >= 0 {
+		s.estimatedRowCount = int64(float64(s.estimatedRowCount) * c.selectivity)
+	}
 	s.index = c.index
 	s.specifiedIndex = nil
 	s.isSecondaryIndex = (c.index != &s.desc.PrimaryIndex)
-	var err error
-	s.spans, err = makeSpans(c.constraints, c.desc, c.index)
-	if err != nil {
-		return nil, errors.Wrapf(err, "constraints = %v, table ID = %d, index ID = %d",
-			c.constraints, s.desc.ID, s.index.ID)
+	if c.index.Type == sqlbase.IndexDescriptor_INVERTED {
+		s.spans = c.invertedSpans
+	} else {
+		var err error
+		s.spans, err = makeSpans(c.constraints, c.desc, c.index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "constraints = %v, table ID = %d, index ID = %d",
+				c.constraints, s.desc.ID, s.index.ID)
+		}
 	}
 	if len(s.spans) == 0 {
 		// There are no spans to scan.
@@ -342,10 +397,21 @@ type indexInfo struct {
 	covering    bool // Does the index cover the required IndexedVars?
 	reverse     bool
 	exactPrefix int
+	// invertedSpans are the spans to scan if the index is an inverted index.
+	invertedSpans roachpb.Spans
+	// stats are the statistics of the columns of the table, if any were
+	// collected. When they are available, the cost of the index is scaled by
+	// the estimated fraction of the rows it scans.
+	stats   columnStats
+	evalCtx *parser.EvalContext
+	// selectivity is the estimated fraction of the rows of the table that
+	// the constraints select.
+	selectivity float64
 }
 
 func (v *indexInfo) init(s *scanNode) {
 	v.covering = v.isCoveringIndex(s)
+	v.selectivity = 1
 
 	// The base cost is the number of keys per row.
 	if v.index == &v.desc.PrimaryIndex {
@@ -360,12 +426,20 @@ func (v *indexInfo) init(s *scanNode) {
 			// indexes.
 			v.cost *= nonCoveringIndexPenalty
 		}
+		if v.index.Type == sqlbase.IndexDescriptor_INVERTED && len(v.invertedSpans) > 1 {
+			// Each span of an inverted index is a separate lookup.
+			v.cost *= float64(len(v.invertedSpans))
+		}
 	}
 }
 
 // analyzeExprs examines the range map to determine the cost of using the
 // index.
 func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
+	if v.index.Type == sqlbase.IndexDescriptor_INVERTED {
+		// The spans of inverted indexes are not derived from constraints.
+		return
+	}
 	if err := v.makeOrConstraints(exprs); err != nil {
 		panic(err)
 	}
@@ -377,6 +451,12 @@ func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
 		// The index isn't being restricted at all, bump the cost significantly to
 		// make any index which does restrict the keys more desirable.
 		v.cost *= 1000
+	} else if v.stats != nil {
+		// With statistics, the cost is scaled by the estimated fraction of the
+		// rows that the constraints select; an index whose constraints select
+		// all the rows costs as much as an unrestricted one.
+		v.selectivity = v.constraintsSelectivity()
+		v.cost *= 1000 * v.selectivity
 	} else {
 		// When we have multiple indexConstraints, each one is for a top-level
 		// disjunction (OR); together they are no more restrictive than any one of
@@ -400,6 +480,122 @@ func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
 	}
 }
 
+// constraintsSelectivity estimates the fraction of the rows of the table that
+// satisfy the constraints of the index. The constraints on different columns
+// are assumed to be independent, and the disjunctions to be disjoint.
+// Constraints on columns without statistics, including tuple constraints,
+// are assumed to select stats.DefaultRangeSelectivity of the rows.
+func (v *indexInfo) constraintsSelectivity() float64 {
+	var selectivity float64
+	for _, cset := range v.constraints {
+		csetSelectivity := 1.0
+		colIdx := 0
+		for _, c := range cset {
+			stat, ok := v.stats[v.index.ColumnIDs[colIdx]]
+			if ok && c.tupleMap == nil {
+				csetSelectivity *= constraintSelectivity(v.evalCtx, stat, c)
+			} else {
+				csetSelectivity *= stats.DefaultRangeSelectivity
+			}
+			colIdx += c.numColumns()
+		}
+		selectivity += csetSelectivity
+	}
+	if selectivity > 1 {
+		return 1
+	}
+	return selectivity
+}
+
+// constraintSelectivity estimates the fraction of the rows of the table that
+// satisfy a constraint on a single column.
+func constraintSelectivity(
+	evalCtx *parser.EvalContext, stat *stats.TableStatistic, c indexConstraint,
+) float64 {
+	var lo, hi stats.Bound
+	for _, e := range []*parser.ComparisonExpr{c.start, c.end} {
+		if e == nil {
+			continue
+		}
+		d, ok := e.Right.(parser.Datum)
+		if !ok {
+			return stats.DefaultRangeSelectivity
+		}
+		switch e.Operator {
+		case parser.EQ:
+			return stat.EqSelectivity(evalCtx, d)
+		case parser.In:
+			tuple, ok := d.(*parser.DTuple)
+			if !ok {
+				return stats.DefaultRangeSelectivity
+			}
+			var selectivity float64
+			for _, val := range tuple.D {
+				selectivity += stat.EqSelectivity(evalCtx, val)
+			}
+			if selectivity > 1 {
+				return 1
+			}
+			return selectivity
+		case parser.Is:
+			return stat.NullSelectivity()
+		case parser.GE, parser.GT:
+			lo = stats.Bound{Datum: d, Inclusive: e.Operator == parser.GE}
+		case parser.LE, parser.LT:
+			hi = stats.Bound{Datum: d, Inclusive: e.Operator == parser.LE}
+		}
+	}
+	return stat.RangeSelectivity(evalCtx, lo, hi)
+}
+
+// columnStats maps the columns of a table to their most recent single-column
+// statistic.
+type columnStats map[sqlbase.ColumnID]*stats.TableStatistic
+
+// getColumnStats returns the statistics of the columns of a table, or nil if
+// none were collected. An error reading the statistics is not fatal: the
+// plan is then chosen without them.
+func (p *planner) getColumnStats(ctx context.Context, desc *sqlbase.TableDescriptor) columnStats {
+	execCfg := p.ExecCfg()
+	if execCfg == nil || execCfg.TableStatsCache == nil || desc.IsVirtualTable() {
+		return nil
+	}
+	tableStats, err := execCfg.TableStatsCache.GetTableStats(ctx, desc.ID)
+	if err != nil {
+		log.VEventf(ctx, 1, "unable to read the statistics of table %d: %v", desc.ID, err)
+		return nil
+	}
+	var res columnStats
+	// The statistics are ordered from the most recent.
+	for _, stat := range tableStats {
+		if len(stat.ColumnIDs) != 1 {
+			continue
+		}
+		if res == nil {
+			res = make(columnStats)
+		}
+		if _, ok := res[stat.ColumnIDs[0]]; !ok {
+			res[stat.ColumnIDs[0]] = stat
+		}
+	}
+	return res
+}
+
+// rowCount returns the row count of the most recent statistic, or -1 if there
+// are no statistics.
+func (cs columnStats) rowCount() int64 {
+	var latest *stats.TableStatistic
+	for _, stat := range cs {
+		if latest == nil || stat.CreatedAt.After(latest.CreatedAt) {
+			latest = stat
+		}
+	}
+	if latest == nil {
+		return -1
+	}
+	return latest.RowCount
+}
+
 // analyzeOrdering analyzes the ordering provided by the index and determines
 // if it matches the ordering requested by the query. Non-matching orderings
 // increase the cost of using the index.
@@ -409,6 +605,13 @@ func (v *indexInfo) analyzeExprs(exprs []parser.TypedExprs) {
 func (v *indexInfo) analyzeOrdering(
 	ctx context.Context, scan *scanNode, analyzeOrdering analyzeOrderingFn, preferOrderMatching bool,
 ) {
+	if v.index.Type == sqlbase.IndexDescriptor_INVERTED {
+		// Inverted indexes provide no ordering.
+		_, orderCols := analyzeOrdering(orderingInfo{})
+		v.cost *= float64(orderCols + 1)
+		return
+	}
+
 	// Compute the prefix of the index for which we have exact constraints. This
 	// prefix is inconsequential for ordering because the values are identical.
 	v.exactPrefix = v.constraints.exactPrefix(&scan.p.evalCtx)
@@ -795,6 +998,11 @@ func (v *indexInfo) isCoveringIndex(scan *scanNode) bool {
 		// The primary key index always covers all of the columns.
 		return true
 	}
+	if v.index.Type == sqlbase.IndexDescriptor_INVERTED {
+		// The keys of inverted indexes contain the elements of their column
+		// instead of its values.
+		return false
+	}
 
 	for i, needed := range scan.valNeededForCol {
 		if needed {
@@ -786,7 +786,40 @@ func forEachColumnInIndex(
 }
 
 func forEachUser(ctx context.Context, p *planner, fn func(username string) error) error {
-	query := `SELECT username FROM system.users`
+	// TODO(cuongdo/asubiotto): Get rid of root user special-casing if/when a row
+	// for "root" exists in system.user.
+	if err := fn(security.RootUser); err != nil {
+		return err
+	}
+	return forEachRow(ctx, p, `SELECT username FROM system.users`, func(row parser.Datums) error {
+		return fn(string(parser.MustBeDString(row[0])))
+	})
+}
+
+func forEachRole(ctx context.Context, p *planner, fn func(role string) error) error {
+	return forEachRow(ctx, p, `SELECT name FROM system.roles`, func(row parser.Datums) error {
+		return fn(string(parser.MustBeDString(row[0])))
+	})
+}
+
+func forEachRoleMembership(
+	ctx context.Context, p *planner, fn func(role, member string, isAdmin bool) error,
+) error {
+	query := `SELECT role, member, "isAdmin" FROM system.role_members`
+	return forEachRow(ctx, p, query, func(row parser.Datums) error {
+		return fn(
+			string(parser.MustBeDString(row[0])),
+			string(parser.MustBeDString(row[1])),
+			bool(*row[2].(*parser.DBool)),
+		)
+	})
+}
+
+// forEachRow runs a query and calls fn on each of its result rows. Errors
+// while planning the query are ignored.
+func forEachRow(
+	ctx context.Context, p *planner, query string, fn func(row parser.Datums) error,
+) error {
 	plan, err := p.query(ctx, query)
 	if err != nil {
 		return nil
@@ -796,12 +829,6 @@ func forEachUser(ctx context.Context, p *planner, fn func(username string) error
 		return err
 	}
 
-	// TODO(cuongdo/asubiotto): Get rid of root user special-casing if/when a row
-	// for "root" exists in system.user.
-	if err := fn(security.RootUser); err != nil {
-		return err
-	}
-
 	for {
 		next, err := plan.Next(ctx)
 		if err != nil {
@@ -810,9 +837,7 @@ func forEachUser(ctx context.Context, p *planner, fn func(username string) error
 		if !next {
 			break
 		}
-		row := plan.Values()
-		username := parser.MustBeDString(row[0])
-		if err := fn(string(username)); err != nil {
+		if err := fn(plan.Values()); err != nil {
 			return err
 		}
 	}
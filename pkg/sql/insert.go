@@ -178,6 +178,9 @@ func (p *planner) Insert(
 				} else {
 					updateCols[i] = *en.tableDesc.Mutations[idx].GetColumn()
 				}
+				if updateCols[i].IsComputed() {
+					return nil, sqlbase.CannotWriteToComputedColError(updateCols[i])
+				}
 			}
 
 			helper, err := p.makeUpsertHelper(
@@ -236,13 +239,17 @@ func ProcessDefaultColumns(
 		colIDSet[col.ID] = struct{}{}
 	}
 
-	// Add the column if it has a DEFAULT expression.
+	addColumn := func(col sqlbase.ColumnDescriptor) {
+		if _, ok := colIDSet[col.ID]; !ok {
+			colIDSet[col.ID] = struct{}{}
+			cols = append(cols, col)
+		}
+	}
+	// Add the column if it has a DEFAULT expression or is a computed
+	// column: the row inserter fills in the values of computed columns.
 	addIfDefault := func(col sqlbase.ColumnDescriptor) {
-		if col.DefaultExpr != nil {
-			if _, ok := colIDSet[col.ID]; !ok {
-				colIDSet[col.ID] = struct{}{}
-				cols = append(cols, col)
-			}
+		if col.DefaultExpr != nil || col.IsComputed() {
+			addColumn(col)
 		}
 	}
 
@@ -251,13 +258,19 @@ func ProcessDefaultColumns(
 		addIfDefault(col)
 	}
 	// Also add any column in a mutation that is WRITE_ONLY and has
-	// a DEFAULT expression.
+	// a DEFAULT expression, as well as the WRITE_ONLY shadow columns of
+	// columns whose type is being changed: the row inserter fills these in
+	// with the converted values of the columns they replace.
 	for _, m := range tableDesc.Mutations {
 		if m.State != sqlbase.DescriptorMutation_WRITE_ONLY {
 			continue
 		}
 		if col := m.GetColumn(); col != nil {
-			addIfDefault(*col)
+			if m.ShadowedColumnID != 0 {
+				addColumn(*col)
+			} else {
+				addIfDefault(*col)
+			}
 		}
 	}
 
@@ -379,8 +392,10 @@ func GenerateInsertRow(
 	}
 
 	// Check to see if NULL is being inserted into any non-nullable column.
+	// The values of computed columns are checked by the row inserter, which
+	// computes them.
 	for _, col := range tableDesc.Columns {
-		if !col.Nullable {
+		if !col.Nullable && !col.IsComputed() {
 			if i, ok := insertColIDtoRowIndex[col.ID]; !ok || rowVals[i] == parser.DNull {
 				return nil, sqlbase.NewNonNullViolationError(col.Name)
 			}
@@ -404,7 +419,15 @@ func (p *planner) processColumns(
 		// (as opposed to INSERT INTO <table> (...) VALUES (...)) from writing
 		// hidden columns. At present, the only hidden column is the implicit rowid
 		// primary key column.
-		return tableDesc.VisibleColumns(), nil
+		// Computed columns cannot be written directly, so they are skipped
+		// as well.
+		var cols []sqlbase.ColumnDescriptor
+		for _, col := range tableDesc.VisibleColumns() {
+			if !col.IsComputed() {
+				cols = append(cols, col)
+			}
+		}
+		return cols, nil
 	}
 
 	cols := make([]sqlbase.ColumnDescriptor, len(node))
@@ -423,6 +446,9 @@ func (p *planner) processColumns(
 		if err != nil {
 			return nil, err
 		}
+		if col.IsComputed() {
+			return nil, sqlbase.CannotWriteToComputedColError(col)
+		}
 
 		if _, ok := colIDSet[col.ID]; ok {
 			return nil, fmt.Errorf("multiple assignments to the same column %q", n)
@@ -0,0 +1,136 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// invertedIndexSpans returns the spans of the inverted index idx that contain
+// the entries of all the rows that can satisfy the filter of the scan. ok is
+// false if the filter does not constrain the elements of the indexed column,
+// in which case the index cannot be used.
+//
+// The index can be used if a conjunct of the filter is either:
+//   - `col @> array` (or `array <@ col`) with a non-empty constant array, in
+//     which case a single element of the array is looked up, or
+//   - `col && array` with a constant array, in which case all the elements of
+//     the array are looked up.
+//
+// The rows found through the index must still be filtered by the original
+// filter.
+func invertedIndexSpans(
+	evalCtx *parser.EvalContext, s *scanNode, idx *sqlbase.IndexDescriptor,
+) (spans roachpb.Spans, ok bool, err error) {
+	if s.filter == nil {
+		return nil, false, nil
+	}
+	var overlaps *parser.DArray
+	for _, e := range splitAndExpr(evalCtx, s.filter, nil) {
+		op, array, ok := invertedIndexComparison(s, idx, e)
+		if !ok {
+			continue
+		}
+		if op == parser.Contains {
+			// Every row that contains the array contains any of its elements, so
+			// it is enough to look up one of them. Elements that are NULL never
+			// match.
+			for _, elem := range array.Array {
+				if elem == parser.DNull {
+					continue
+				}
+				span, err := sqlbase.InvertedIndexSpan(&s.desc, idx, elem)
+				if err != nil {
+					return nil, false, err
+				}
+				return roachpb.Spans{span}, true, nil
+			}
+			continue
+		}
+		if overlaps == nil || array.Len() < overlaps.Len() {
+			overlaps = array
+		}
+	}
+	if overlaps == nil {
+		return nil, false, nil
+	}
+	spans = make(roachpb.Spans, 0, overlaps.Len())
+	for _, elem := range overlaps.Array {
+		if elem == parser.DNull {
+			continue
+		}
+		span, err := sqlbase.InvertedIndexSpan(&s.desc, idx, elem)
+		if err != nil {
+			return nil, false, err
+		}
+		spans = append(spans, span)
+	}
+	// The spans are not merged even when they are adjacent: the index join
+	// relies on their number to know whether it can find a row several times.
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Key.Compare(spans[j].Key) < 0
+	})
+	deduped := spans[:0]
+	for _, span := range spans {
+		if n := len(deduped); n > 0 && deduped[n-1].Key.Equal(span.Key) {
+			continue
+		}
+		deduped = append(deduped, span)
+	}
+	return deduped, true, nil
+}
+
+// invertedIndexComparison decomposes an expression comparing the column of
+// the inverted index idx with a constant array, returning the comparison as
+// either `col @> array` or `col && array`.
+func invertedIndexComparison(
+	s *scanNode, idx *sqlbase.IndexDescriptor, e parser.TypedExpr,
+) (parser.ComparisonOperator, *parser.DArray, bool) {
+	cmp, ok := e.(*parser.ComparisonExpr)
+	if !ok {
+		return 0, nil, false
+	}
+	left, right := cmp.Left, cmp.Right
+	op := cmp.Operator
+	switch op {
+	case parser.Contains:
+	case parser.ContainedBy:
+		left, right, op = right, left, parser.Contains
+	case parser.Overlaps:
+		if _, ok := left.(*parser.IndexedVar); !ok {
+			left, right = right, left
+		}
+	default:
+		return 0, nil, false
+	}
+	v, ok := left.(*parser.IndexedVar)
+	if !ok || v.Idx >= len(s.cols) || s.cols[v.Idx].ID != idx.ColumnIDs[0] {
+		return 0, nil, false
+	}
+	array, ok := right.(*parser.DArray)
+	if !ok {
+		return 0, nil, false
+	}
+	// The elements must have the key encoding of the elements of the column.
+	elemType := s.cols[v.Idx].Type.ElementType()
+	if array.ParamTyp != parser.TypeNull && !array.ParamTyp.Equivalent(elemType.ToDatumType()) {
+		return 0, nil, false
+	}
+	return op, array, true
+}
@@ -17,6 +17,7 @@
 package sql
 
 import (
+	"fmt"
 	"time"
 
 	"golang.org/x/net/context"
@@ -62,12 +63,42 @@ const (
 	JobStatusPending JobStatus = "pending"
 	// JobStatusRunning is for jobs that are currently in progress.
 	JobStatusRunning JobStatus = "running"
+	// JobStatusPauseRequested is for running jobs that were asked to pause but
+	// have not yet noticed the request.
+	JobStatusPauseRequested JobStatus = "pause-requested"
+	// JobStatusPaused is for jobs that stopped in response to a pause request.
+	// They can be resumed from their last checkpoint.
+	JobStatusPaused JobStatus = "paused"
+	// JobStatusCanceled is for jobs that were canceled. The process running a
+	// canceled job stops once it notices the cancellation.
+	JobStatusCanceled JobStatus = "canceled"
 	// JobStatusFailed is for jobs that failed.
 	JobStatusFailed JobStatus = "failed"
 	// JobStatusSucceeded is for jobs that have successfully completed.
 	JobStatusSucceeded JobStatus = "succeeded"
 )
 
+var (
+	// ErrJobPaused is returned by JobLogger methods to signal to a running job
+	// that it has been paused and should stop.
+	ErrJobPaused = errors.New("job paused")
+	// ErrJobCanceled is returned by JobLogger methods to signal to a running
+	// job that it has been canceled and should stop.
+	ErrJobCanceled = errors.New("job canceled")
+)
+
+// InvalidStatusError is the error returned when a job cannot be moved from its
+// current status by the requested operation.
+type InvalidStatusError struct {
+	id     int64
+	status JobStatus
+	op     string
+}
+
+func (e *InvalidStatusError) Error() string {
+	return fmt.Sprintf("cannot %s %s job (id %d)", e.op, e.status, e.id)
+}
+
 // NewJobLogger creates a new JobLogger.
 func NewJobLogger(db *client.DB, leaseMgr *LeaseManager, job JobRecord) JobLogger {
 	return JobLogger{
@@ -77,6 +108,45 @@ func NewJobLogger(db *client.DB, leaseMgr *LeaseManager, job JobRecord) JobLogge
 	}
 }
 
+// GetJobLogger returns a JobLogger that tracks the existing job with the given
+// ID.
+func GetJobLogger(
+	ctx context.Context, db *client.DB, leaseMgr *LeaseManager, jobID int64,
+) (*JobLogger, error) {
+	jl := NewJobLogger(db, leaseMgr, JobRecord{})
+	var payload *JobPayload
+	if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		const stmt = "SELECT payload FROM system.jobs WHERE id = $1"
+		row, err := jl.ex.QueryRowInTransaction(ctx, "job-get", txn, stmt, jobID)
+		if err != nil {
+			return err
+		}
+		if row == nil {
+			return errors.Errorf("job %d does not exist", jobID)
+		}
+		payload, err = unmarshalJobPayload(row[0])
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	jl.jobID = &jobID
+	jl.Job = JobRecord{
+		Description:   payload.Description,
+		Username:      payload.Username,
+		DescriptorIDs: payload.DescriptorIDs,
+	}
+	switch d := payload.details().(type) {
+	case *BackupJobDetails:
+		jl.Job.Details = *d
+	case *RestoreJobDetails:
+		jl.Job.Details = *d
+	case *ImportJobDetails:
+		jl.Job.Details = *d
+	}
+	return &jl, nil
+}
+
 // JobID returns the ID of the job that this JobLogger is currently tracking.
 // This will be nil if Created has not yet been called.
 func (jl *JobLogger) JobID() *int64 {
@@ -97,46 +167,104 @@ func (jl *JobLogger) Created(ctx context.Context) error {
 		payload.Details = &JobPayload_Backup{Backup: &d}
 	case RestoreJobDetails:
 		payload.Details = &JobPayload_Restore{Restore: &d}
+	case ImportJobDetails:
+		payload.Details = &JobPayload_Import{Import: &d}
 	default:
 		return errors.Errorf("JobLogger: unsupported job details type %T", d)
 	}
 	return jl.insertJobRecord(ctx, payload)
 }
 
-// Started marks the tracked job as started.
+// Started marks the tracked job as started. If the job was paused or canceled
+// before it started, Started returns ErrJobPaused or ErrJobCanceled.
 func (jl *JobLogger) Started(ctx context.Context) error {
-	return jl.updateJobRecord(ctx, JobStatusRunning, func(payload *JobPayload) (bool, error) {
+	var interruptErr error
+	if err := jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		interruptErr = nil
 		if payload.StartedMicros != 0 {
 			return false, errors.Errorf("JobLogger: job %d already started", jl.jobID)
 		}
+		switch *status {
+		case JobStatusPending:
+			*status = JobStatusRunning
+		case JobStatusPauseRequested:
+			*status = JobStatusPaused
+			interruptErr = ErrJobPaused
+		case JobStatusCanceled:
+			return false, ErrJobCanceled
+		default:
+			return false, &InvalidStatusError{*jl.jobID, *status, "start"}
+		}
 		payload.StartedMicros = jobTimestamp(timeutil.Now())
 		return true, nil
-	})
+	}); err != nil {
+		return err
+	}
+	return interruptErr
 }
 
+// JobCheckpointFn is called by Progressed to record how far a job has gotten.
+// It receives a pointer to the job's details, e.g. a *BackupJobDetails, and
+// should update them to allow the job to be resumed from this point. It may be
+// called more than once for a single call to Progressed.
+type JobCheckpointFn func(details interface{}) error
+
 // Progressed updates the progress of the tracked job to fractionCompleted. A
 // fractionCompleted that is less than the currently-recorded fractionCompleted
-// will be silently ignored.
-func (jl *JobLogger) Progressed(ctx context.Context, fractionCompleted float32) error {
+// will be silently ignored. If checkpointFn is non-nil, it is used to update
+// the job's details in the same transaction.
+//
+// Progressed is also where a running job learns that it should stop: if the
+// job was canceled, it returns ErrJobCanceled, and if it was asked to pause,
+// it records the checkpoint, marks the job paused and returns ErrJobPaused.
+func (jl *JobLogger) Progressed(
+	ctx context.Context, fractionCompleted float32, checkpointFn JobCheckpointFn,
+) error {
 	if fractionCompleted < 0.0 || fractionCompleted > 1.0 {
 		return errors.Errorf(
 			"JobLogger: fractionCompleted %f is outside allowable range [0.0, 1.0] (job %d)",
 			fractionCompleted, jl.jobID,
 		)
 	}
-	return jl.updateJobRecord(ctx, JobStatusRunning, func(payload *JobPayload) (bool, error) {
+	var interruptErr error
+	if err := jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		interruptErr = nil
 		if payload.StartedMicros == 0 {
 			return false, errors.Errorf("JobLogger: job %d not started", jl.jobID)
 		}
+		// The status is checked first since a job canceled while it was running
+		// has not finished yet.
+		if *status == JobStatusCanceled {
+			return false, ErrJobCanceled
+		}
 		if payload.FinishedMicros != 0 {
 			return false, errors.Errorf("JobLogger: job %d already finished", jl.jobID)
 		}
-		if fractionCompleted <= payload.FractionCompleted {
-			return false, nil
+		doUpdate := false
+		switch *status {
+		case JobStatusRunning:
+		case JobStatusPauseRequested:
+			*status = JobStatusPaused
+			interruptErr = ErrJobPaused
+			doUpdate = true
+		default:
+			return false, &InvalidStatusError{*jl.jobID, *status, "update progress on"}
 		}
-		payload.FractionCompleted = fractionCompleted
-		return true, nil
-	})
+		if checkpointFn != nil {
+			if err := checkpointFn(payload.details()); err != nil {
+				return false, err
+			}
+			doUpdate = true
+		}
+		if fractionCompleted > payload.FractionCompleted {
+			payload.FractionCompleted = fractionCompleted
+			doUpdate = true
+		}
+		return doUpdate, nil
+	}); err != nil {
+		return err
+	}
+	return interruptErr
 }
 
 // Failed marks the tracked job as having failed with the given error. Any
@@ -149,10 +277,20 @@ func (jl *JobLogger) Failed(ctx context.Context, err error) {
 	if jl.jobID == nil {
 		return
 	}
-	internalErr := jl.updateJobRecord(ctx, JobStatusFailed, func(payload *JobPayload) (bool, error) {
+	internalErr := jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		if *status == JobStatusCanceled {
+			// A canceled job keeps its status, but was still running until now if
+			// it was canceled after it started.
+			if payload.FinishedMicros != 0 {
+				return false, nil
+			}
+			payload.FinishedMicros = jobTimestamp(timeutil.Now())
+			return true, nil
+		}
 		if payload.FinishedMicros != 0 {
 			return false, errors.Errorf("JobLogger: job %d already finished", jl.jobID)
 		}
+		*status = JobStatusFailed
 		payload.Error = err.Error()
 		payload.FinishedMicros = jobTimestamp(timeutil.Now())
 		return true, nil
@@ -164,15 +302,85 @@ func (jl *JobLogger) Failed(ctx context.Context, err error) {
 }
 
 // Succeeded marks the tracked job as having succeeded and sets its fraction
-// completed to 1.0.
+// completed to 1.0. If the job was canceled while it was running, it keeps its
+// status, is marked as finished and Succeeded returns ErrJobCanceled.
 func (jl *JobLogger) Succeeded(ctx context.Context) error {
-	return jl.updateJobRecord(ctx, JobStatusSucceeded, func(payload *JobPayload) (bool, error) {
+	var canceledErr error
+	if err := jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		canceledErr = nil
 		if payload.FinishedMicros != 0 {
 			return false, errors.Errorf("JobLogger: job %d already finished", jl.jobID)
 		}
 		payload.FinishedMicros = jobTimestamp(timeutil.Now())
+		if *status == JobStatusCanceled {
+			canceledErr = ErrJobCanceled
+			return true, nil
+		}
+		*status = JobStatusSucceeded
 		payload.FractionCompleted = 1.0
 		return true, nil
+	}); err != nil {
+		return err
+	}
+	return canceledErr
+}
+
+// paused requests that the tracked job pause. A running job keeps running until
+// it notices the request the next time it reports progress.
+func (jl *JobLogger) paused(ctx context.Context) error {
+	return jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		switch *status {
+		case JobStatusPending, JobStatusRunning:
+			*status = JobStatusPauseRequested
+			return true, nil
+		case JobStatusPauseRequested, JobStatusPaused:
+			return false, nil
+		default:
+			return false, &InvalidStatusError{*jl.jobID, *status, "pause"}
+		}
+	})
+}
+
+// resumed marks the tracked job as running again. It returns true if the job
+// had stopped and must be restarted from its last checkpoint, and false if the
+// job never noticed the pause request and is still running.
+func (jl *JobLogger) resumed(ctx context.Context) (restart bool, err error) {
+	err = jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		switch *status {
+		case JobStatusPauseRequested:
+			if payload.StartedMicros == 0 {
+				*status = JobStatusPending
+			} else {
+				*status = JobStatusRunning
+			}
+			return true, nil
+		case JobStatusPaused:
+			restart = true
+			*status = JobStatusRunning
+			return true, nil
+		default:
+			return false, &InvalidStatusError{*jl.jobID, *status, "resume"}
+		}
+	})
+	return restart, err
+}
+
+// canceled marks the tracked job as canceled. A running job keeps running until
+// it notices the cancellation the next time it reports progress, and is only
+// marked as finished once it has stopped.
+func (jl *JobLogger) canceled(ctx context.Context) error {
+	return jl.updateJobRecord(ctx, func(status *JobStatus, payload *JobPayload) (bool, error) {
+		switch *status {
+		case JobStatusPending, JobStatusPaused:
+			*status = JobStatusCanceled
+			payload.FinishedMicros = jobTimestamp(timeutil.Now())
+			return true, nil
+		case JobStatusRunning, JobStatusPauseRequested:
+			*status = JobStatusCanceled
+			return true, nil
+		default:
+			return false, &InvalidStatusError{*jl.jobID, *status, "cancel"}
+		}
 	})
 }
 
@@ -200,25 +408,36 @@ func (jl *JobLogger) insertJobRecord(ctx context.Context, payload *JobPayload) e
 	return nil
 }
 
+// updateJobRecord reads the status and payload of the tracked job and passes
+// them to updateFn. If updateFn returns true, the possibly modified status and
+// payload are written back in the same transaction.
 func (jl *JobLogger) updateJobRecord(
-	ctx context.Context, newStatus JobStatus, updateFn func(*JobPayload) (doUpdate bool, err error),
+	ctx context.Context, updateFn func(*JobStatus, *JobPayload) (doUpdate bool, err error),
 ) error {
 	if jl.jobID == nil {
 		return errors.New("JobLogger cannot update job: job not created")
 	}
 
 	return jl.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
-		const selectStmt = "SELECT payload FROM system.jobs WHERE id = $1"
+		const selectStmt = "SELECT status, payload FROM system.jobs WHERE id = $1"
 		row, err := jl.ex.QueryRowInTransaction(ctx, "log-job", txn, selectStmt, *jl.jobID)
 		if err != nil {
 			return err
 		}
+		if row == nil {
+			return errors.Errorf("job %d does not exist", *jl.jobID)
+		}
 
-		payload, err := unmarshalJobPayload(row[0])
+		statusString, ok := row[0].(*parser.DString)
+		if !ok {
+			return errors.Errorf("JobLogger: expected string status, but got %T", row[0])
+		}
+		status := JobStatus(*statusString)
+		payload, err := unmarshalJobPayload(row[1])
 		if err != nil {
 			return err
 		}
-		doUpdate, err := updateFn(payload)
+		doUpdate, err := updateFn(&status, payload)
 		if err != nil {
 			return err
 		}
@@ -233,7 +452,7 @@ func (jl *JobLogger) updateJobRecord(
 
 		const updateStmt = "UPDATE system.jobs SET status = $1, payload = $2 WHERE id = $3"
 		n, err := jl.ex.ExecuteStatementInTransaction(
-			ctx, "job-update", txn, updateStmt, newStatus, payloadBytes, *jl.jobID)
+			ctx, "job-update", txn, updateStmt, status, payloadBytes, *jl.jobID)
 		if err != nil {
 			return err
 		}
@@ -249,6 +468,7 @@ func (jl *JobLogger) updateJobRecord(
 const (
 	JobTypeBackup  string = "BACKUP"
 	JobTypeRestore string = "RESTORE"
+	JobTypeImport  string = "IMPORT"
 )
 
 func (jp *JobPayload) typ() string {
@@ -257,11 +477,27 @@ func (jp *JobPayload) typ() string {
 		return JobTypeBackup
 	case *JobPayload_Restore:
 		return JobTypeRestore
+	case *JobPayload_Import:
+		return JobTypeImport
 	default:
 		panic("JobPayload.typ called on a payload with an unknown details type")
 	}
 }
 
+// details returns a pointer to the type-specific details of the job.
+func (jp *JobPayload) details() interface{} {
+	switch d := jp.Details.(type) {
+	case *JobPayload_Backup:
+		return d.Backup
+	case *JobPayload_Restore:
+		return d.Restore
+	case *JobPayload_Import:
+		return d.Import
+	default:
+		panic("JobPayload.details called on a payload with an unknown details type")
+	}
+}
+
 func unmarshalJobPayload(datum parser.Datum) (*JobPayload, error) {
 	payload := &JobPayload{}
 	bytes, ok := datum.(*parser.DBytes)
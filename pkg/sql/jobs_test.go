@@ -26,6 +26,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
@@ -136,6 +137,23 @@ func verifyJobRecord(
 	return verifyModifiedAgainst("finished", finished.Time)
 }
 
+// resumableJobDescription is the description of the jobs that the resume hook
+// below knows how to run.
+const resumableJobDescription = "resumable"
+
+// Add a placeholder implementation to test RESUME JOB. It resumes the jobs
+// with resumableJobDescription by marking them as successful.
+func init() {
+	sql.AddJobResumeHook(func(
+		_ context.Context, _ sql.PlanHookState, jl *sql.JobLogger, _ parser.KVOptions,
+	) (func(context.Context) error, error) {
+		if jl.Job.Description != resumableJobDescription {
+			return nil, nil
+		}
+		return jl.Succeeded, nil
+	})
+}
+
 func TestJobLogger(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	ctx := context.TODO()
@@ -184,7 +202,7 @@ func TestJobLogger(t *testing.T) {
 			{0.0, 0.0}, {0.5, 0.5}, {0.5, 0.5}, {0.4, 0.5}, {0.8, 0.8}, {1.0, 1.0},
 		}
 		for _, f := range progresses {
-			if err := woodyLogger.Progressed(ctx, f.actual); err != nil {
+			if err := woodyLogger.Progressed(ctx, f.actual, nil); err != nil {
 				t.Fatal(err)
 			}
 			woodyExpectation.FractionCompleted = f.expected
@@ -230,7 +248,7 @@ func TestJobLogger(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if err := buzzLogger.Progressed(ctx, .42); err != nil {
+		if err := buzzLogger.Progressed(ctx, .42, nil); err != nil {
 			t.Fatal(err)
 		}
 		buzzExpectation.FractionCompleted = .42
@@ -334,10 +352,10 @@ func TestJobLogger(t *testing.T) {
 		if err := logger.Started(ctx); err != nil {
 			t.Fatal(err)
 		}
-		if err := logger.Progressed(ctx, -0.1); !testutils.IsError(err, "outside allowable range") {
+		if err := logger.Progressed(ctx, -0.1, nil); !testutils.IsError(err, "outside allowable range") {
 			t.Fatalf("expected 'outside allowable range' error, but got %v", err)
 		}
-		if err := logger.Progressed(ctx, 1.1); !testutils.IsError(err, "outside allowable range") {
+		if err := logger.Progressed(ctx, 1.1, nil); !testutils.IsError(err, "outside allowable range") {
 			t.Fatalf("expected 'outside allowable range' error, but got %v", err)
 		}
 	})
@@ -349,7 +367,7 @@ func TestJobLogger(t *testing.T) {
 		if err := logger.Created(ctx); err != nil {
 			t.Fatal(err)
 		}
-		if err := logger.Progressed(ctx, 0.5); !testutils.IsError(err, `job \d+ not started`) {
+		if err := logger.Progressed(ctx, 0.5, nil); !testutils.IsError(err, `job \d+ not started`) {
 			t.Fatalf("expected 'job not started' error, but got %v", err)
 		}
 	})
@@ -367,11 +385,148 @@ func TestJobLogger(t *testing.T) {
 		if err := logger.Succeeded(ctx); err != nil {
 			t.Fatal(err)
 		}
-		if err := logger.Progressed(ctx, 0.5); !testutils.IsError(err, `job \d+ already finished`) {
+		if err := logger.Progressed(ctx, 0.5, nil); !testutils.IsError(err, `job \d+ already finished`) {
 			t.Fatalf("expected 'job already finished' error, but got %v", err)
 		}
 	})
 
+	jobStatus := func(db *sqlutils.SQLRunner, jobID int64) sql.JobStatus {
+		var status string
+		db.QueryRow(`SELECT status FROM system.jobs WHERE id = $1`, jobID).Scan(&status)
+		return sql.JobStatus(status)
+	}
+	jobFinished := func(db *sqlutils.SQLRunner, jobID int64) bool {
+		var finished pq.NullTime
+		db.QueryRow(`SELECT finished FROM crdb_internal.jobs WHERE id = $1`, jobID).Scan(&finished)
+		return finished.Valid
+	}
+	startJob := func(t *testing.T, description string) *sql.JobLogger {
+		logger := sql.NewJobLogger(kvDB, s.LeaseManager().(*sql.LeaseManager), sql.JobRecord{
+			Description: description,
+			Details:     sql.BackupJobDetails{},
+		})
+		if err := logger.Created(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := logger.Started(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return &logger
+	}
+
+	t.Run("paused job stops and resumes from its checkpoint", func(t *testing.T) {
+		db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+		logger := startJob(t, resumableJobDescription)
+		jobID := *logger.JobID()
+
+		db.Exec(`PAUSE JOB $1`, jobID)
+		if e, a := sql.JobStatusPauseRequested, jobStatus(db, jobID); e != a {
+			t.Fatalf("expected status %s, but got %s", e, a)
+		}
+		checkpointFn := func(details interface{}) error {
+			details.(*sql.BackupJobDetails).URI = "checkpoint"
+			return nil
+		}
+		if err := logger.Progressed(ctx, 0.5, checkpointFn); err != sql.ErrJobPaused {
+			t.Fatalf("expected %v, but got %v", sql.ErrJobPaused, err)
+		}
+		if e, a := sql.JobStatusPaused, jobStatus(db, jobID); e != a {
+			t.Fatalf("expected status %s, but got %s", e, a)
+		}
+		paused, err := sql.GetJobLogger(ctx, kvDB, s.LeaseManager().(*sql.LeaseManager), jobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e, a := "checkpoint", paused.Job.Details.(sql.BackupJobDetails).URI; e != a {
+			t.Fatalf("expected checkpointed URI %q, but got %q", e, a)
+		}
+
+		db.Exec(`RESUME JOB $1`, jobID)
+		if e, a := sql.JobStatusSucceeded, jobStatus(db, jobID); e != a {
+			t.Fatalf("expected status %s, but got %s", e, a)
+		}
+	})
+
+	t.Run("job resumed before noticing the pause keeps running", func(t *testing.T) {
+		db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+		logger := startJob(t, resumableJobDescription)
+		jobID := *logger.JobID()
+
+		db.Exec(`PAUSE JOB $1`, jobID)
+		db.Exec(`RESUME JOB $1`, jobID)
+		if e, a := sql.JobStatusRunning, jobStatus(db, jobID); e != a {
+			t.Fatalf("expected status %s, but got %s", e, a)
+		}
+		if err := logger.Progressed(ctx, 0.5, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rawSQLDB.Exec(`RESUME JOB $1`, jobID); !testutils.IsError(
+			err, `cannot resume running job`,
+		) {
+			t.Fatalf("expected 'cannot resume running job' error, but got %v", err)
+		}
+	})
+
+	t.Run("canceled job stops", func(t *testing.T) {
+		db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+		logger := startJob(t, "canceled")
+		jobID := *logger.JobID()
+
+		db.Exec(`CANCEL JOB $1`, jobID)
+		if e, a := sql.JobStatusCanceled, jobStatus(db, jobID); e != a {
+			t.Fatalf("expected status %s, but got %s", e, a)
+		}
+		// The job is only finished once it noticed the cancellation and stopped.
+		if jobFinished(db, jobID) {
+			t.Fatal("expected the canceled job not to be finished while it is running")
+		}
+		if err := logger.Progressed(ctx, 0.5, nil); err != sql.ErrJobCanceled {
+			t.Fatalf("expected %v, but got %v", sql.ErrJobCanceled, err)
+		}
+		logger.Failed(ctx, sql.ErrJobCanceled)
+		if e, a := sql.JobStatusCanceled, jobStatus(db, jobID); e != a {
+			t.Fatalf("expected status %s, but got %s", e, a)
+		}
+		if !jobFinished(db, jobID) {
+			t.Fatal("expected the canceled job to be finished once it stopped")
+		}
+		if _, err := rawSQLDB.Exec(`PAUSE JOB $1`, jobID); !testutils.IsError(
+			err, `cannot pause canceled job`,
+		) {
+			t.Fatalf("expected 'cannot pause canceled job' error, but got %v", err)
+		}
+	})
+
+	t.Run("canceled job cannot succeed", func(t *testing.T) {
+		db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+		logger := startJob(t, "canceled")
+		jobID := *logger.JobID()
+
+		db.Exec(`CANCEL JOB $1`, jobID)
+		if err := logger.Succeeded(ctx); err != sql.ErrJobCanceled {
+			t.Fatalf("expected %v, but got %v", sql.ErrJobCanceled, err)
+		}
+		if e, a := sql.JobStatusCanceled, jobStatus(db, jobID); e != a {
+			t.Fatalf("expected status %s, but got %s", e, a)
+		}
+		if !jobFinished(db, jobID) {
+			t.Fatal("expected the canceled job to be finished once it stopped")
+		}
+	})
+
+	t.Run("job without resume hook cannot be resumed", func(t *testing.T) {
+		db := sqlutils.MakeSQLRunner(t, rawSQLDB)
+		logger := startJob(t, "not resumable")
+		jobID := *logger.JobID()
+
+		db.Exec(`PAUSE JOB $1`, jobID)
+		if _, err := rawSQLDB.Exec(`RESUME JOB $1`, jobID); !testutils.IsError(
+			err, `job \d+ cannot be resumed`,
+		) {
+			t.Fatalf("expected 'cannot be resumed' error, but got %v", err)
+		}
+	})
+
 	t.Run("succeeded forces fraction completed to 1.0", func(t *testing.T) {
 		db := sqlutils.MakeSQLRunner(t, rawSQLDB)
 		job := sql.JobRecord{Details: sql.BackupJobDetails{}}
@@ -388,7 +543,7 @@ func TestJobLogger(t *testing.T) {
 		if err := logger.Started(ctx); err != nil {
 			t.Fatal(err)
 		}
-		if err := logger.Progressed(ctx, 0.2); err != nil {
+		if err := logger.Progressed(ctx, 0.2, nil); err != nil {
 			t.Fatal(err)
 		}
 		if err := logger.Succeeded(ctx); err != nil {
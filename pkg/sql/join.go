@@ -149,6 +149,12 @@ type joinNode struct {
 	buckets       buckets
 	bucketsMemAcc WrappableMemoryAccount
 
+	// buildLeft is set if the hash table is built from the rows of the left
+	// side and probed with the rows of the right side, instead of the
+	// reverse. It is only set for inner joins whose left side is estimated
+	// to produce fewer rows than their right side; see chooseBuildSide().
+	buildLeft bool
+
 	// emptyRight contain tuples of NULL values to use on the right for left and
 	// full outer joins when the on condition fails.
 	emptyRight parser.Datums
@@ -287,6 +293,48 @@ func (p *planner) makeJoin(
 	}, nil
 }
 
+// chooseBuildSide decides which side of the join the hash table is built
+// from. It must be called after index selection was performed on both sides,
+// since the row count estimates come from the scans of tables with
+// statistics. By default the hash table is built from the right side; for
+// inner joins, it is built from the left side instead if that side is
+// estimated to be smaller.
+func (n *joinNode) chooseBuildSide(ctx context.Context) {
+	if n.joinType != joinTypeInner {
+		return
+	}
+	leftRows := estimatedRowCount(n.left.plan)
+	rightRows := estimatedRowCount(n.right.plan)
+	if leftRows < 0 || rightRows < 0 || leftRows >= rightRows {
+		return
+	}
+	n.buildLeft = true
+	n.buckets.rowContainer.Close(ctx)
+	n.buckets.rowContainer = sqlbase.NewRowContainer(
+		n.planner.session.TxnState.makeBoundAccount(),
+		sqlbase.ColTypeInfoFromResCols(n.left.plan.Columns()),
+		0,
+	)
+}
+
+// estimatedRowCount returns the number of rows that a plan is estimated to
+// produce, or -1 if there is no estimate. Only the plans that scan a table
+// on which statistics were collected have estimates.
+func estimatedRowCount(plan planNode) int64 {
+	switch n := plan.(type) {
+	case *scanNode:
+		return n.estimatedRowCount
+	case *indexJoinNode:
+		return n.index.estimatedRowCount
+	case *renderNode:
+		return estimatedRowCount(n.source.plan)
+	case *filterNode:
+		// The filter can only reduce the number of rows.
+		return estimatedRowCount(n.source.plan)
+	}
+	return -1
+}
+
 // Columns implements the planNode interface.
 func (n *joinNode) Columns() sqlbase.ResultColumns { return n.columns }
 
@@ -353,18 +401,23 @@ func (n *joinNode) Start(ctx context.Context) error {
 
 func (n *joinNode) hashJoinStart(ctx context.Context) error {
 	var scratch []byte
-	// Load all the rows from the right side and build our hashmap.
+	// Load all the rows from the build side (usually the right side) and
+	// build our hashmap.
+	build, buildEqualityIndices := n.right.plan, n.pred.rightEqualityIndices
+	if n.buildLeft {
+		build, buildEqualityIndices = n.left.plan, n.pred.leftEqualityIndices
+	}
 	acc := n.bucketsMemAcc.Wtxn(n.planner.session)
 	for {
-		hasRow, err := n.right.plan.Next(ctx)
+		hasRow, err := build.Next(ctx)
 		if err != nil {
 			return err
 		}
 		if !hasRow {
 			break
 		}
-		row := n.right.plan.Values()
-		encoding, _, err := n.pred.encode(scratch, row, n.pred.rightEqualityIndices)
+		row := build.Values()
+		encoding, _, err := n.pred.encode(scratch, row, buildEqualityIndices)
 		if err != nil {
 			return err
 		}
@@ -423,24 +476,32 @@ func (n *joinNode) Next(ctx context.Context) (res bool, err error) {
 		}
 	}
 
+	// The rows of the probe side (usually the left side) are looked up in the
+	// hashmap. The right side is only the probe side of inner joins, so the
+	// handling of unmatched rows below can assume that it is the left side.
+	probe, probeEqualityIndices := n.left.plan, n.pred.leftEqualityIndices
+	if n.buildLeft {
+		probe, probeEqualityIndices = n.right.plan, n.pred.rightEqualityIndices
+	}
+
 	// Compute next batch of matching rows.
 	var scratch []byte
 	for {
-		leftHasRow, err := n.left.plan.Next(ctx)
+		probeHasRow, err := probe.Next(ctx)
 		if err != nil {
 			return false, nil
 		}
-		if !leftHasRow {
+		if !probeHasRow {
 			break
 		}
 
-		lrow := n.left.plan.Values()
-		encoding, containsNull, err := n.pred.encode(scratch, lrow, n.pred.leftEqualityIndices)
+		prow := probe.Values()
+		encoding, containsNull, err := n.pred.encode(scratch, prow, probeEqualityIndices)
 		if err != nil {
 			return false, err
 		}
 
-		// We make the explicit check for whether or not lrow contained a NULL
+		// We make the explicit check for whether or not prow contained a NULL
 		// tuple. The reasoning here is because of the way we expect NULL
 		// equality checks to behave (i.e. NULL != NULL) and the fact that we
 		// use the encoding of any given row as key into our bucket. Thus if we
@@ -491,7 +552,7 @@ func (n *joinNode) Next(ctx context.Context) (res bool, err error) {
 			}
 			// We append an empty right row to the left row, adding the result
 			// to our buffer for the subsequent call to Next().
-			n.pred.prepareRow(n.output, lrow, n.emptyRight)
+			n.pred.prepareRow(n.output, prow, n.emptyRight)
 			if _, err := n.buffer.AddRow(ctx, n.output); err != nil {
 				return false, err
 			}
@@ -508,7 +569,7 @@ func (n *joinNode) Next(ctx context.Context) (res bool, err error) {
 			// Given that we did not find a matching right row we append an
 			// empty right row to the left row, adding the result to our buffer
 			// for the subsequent call to Next().
-			n.pred.prepareRow(n.output, lrow, n.emptyRight)
+			n.pred.prepareRow(n.output, prow, n.emptyRight)
 			if _, err := n.buffer.AddRow(ctx, n.output); err != nil {
 				return false, err
 			}
@@ -518,7 +579,11 @@ func (n *joinNode) Next(ctx context.Context) (res bool, err error) {
 		// We iterate through all the rows in the bucket attempting to match the
 		// on condition, if the on condition passes we add it to the buffer.
 		foundMatch := false
-		for idx, rrow := range b.Rows() {
+		for idx, brow := range b.Rows() {
+			lrow, rrow := prow, brow
+			if n.buildLeft {
+				lrow, rrow = brow, prow
+			}
 			passesOnCond, err := n.pred.eval(&n.planner.evalCtx, n.output, lrow, rrow)
 			if err != nil {
 				return false, err
@@ -543,7 +608,7 @@ func (n *joinNode) Next(ctx context.Context) (res bool, err error) {
 			// If none of the rows matched the on condition and we are computing a
 			// left or full outer join, we need to add a row with an empty
 			// right side.
-			n.pred.prepareRow(n.output, lrow, n.emptyRight)
+			n.pred.prepareRow(n.output, prow, n.emptyRight)
 			if _, err := n.buffer.AddRow(ctx, n.output); err != nil {
 				return false, err
 			}
@@ -128,6 +128,9 @@ func applyLimit(plan planNode, numRows int64, soft bool) {
 	case *ordinalityNode:
 		applyLimit(n.source, numRows, soft)
 
+	case *recursiveCTENode:
+		setUnlimited(n.initial)
+
 	case *deleteNode:
 		setUnlimited(n.run.rows)
 	case *updateNode:
@@ -144,6 +147,10 @@ func applyLimit(plan planNode, numRows int64, soft bool) {
 		setUnlimited(n.plan)
 	case *explainDistSQLNode:
 		setUnlimited(n.plan)
+	case *explainAnalyzeNode:
+		setUnlimited(n.plan)
+	case *instrumentedNode:
+		applyLimit(n.planNode, numRows, soft)
 	case *explainTraceNode:
 		setUnlimited(n.plan)
 	case *explainPlanNode:
@@ -165,11 +172,17 @@ func applyLimit(plan planNode, numRows int64, soft bool) {
 	case *valuesNode:
 	case *alterTableNode:
 	case *copyNode:
+	case *cancelQueryNode:
+	case *controlJobNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
+	case *roleNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *emptyNode:
@@ -357,6 +357,21 @@ func (mm *MemoryMonitor) Stop(ctx context.Context) {
 	mm.reserved.Close(ctx)
 }
 
+// MaximumBytes returns the maximum number of bytes that were allocated by
+// this monitor at one time since it was started.
+func (mm *MemoryMonitor) MaximumBytes() int64 {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	return mm.mu.maxAllocated
+}
+
+// AllocBytes returns the number of bytes currently allocated by this monitor.
+func (mm *MemoryMonitor) AllocBytes() int64 {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	return mm.mu.curAllocated
+}
+
 // MemoryAccount tracks the cumulated allocations for one client of
 // MemoryPool or MemoryMonitor. MemoryMonitor has an account
 // to its pool; MemoryMonitor clients have an account to the
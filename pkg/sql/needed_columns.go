@@ -41,6 +41,9 @@ func setNeededColumns(plan planNode, needed []bool) {
 	case *explainDistSQLNode:
 		setNeededColumns(n.plan, allColumns(n.plan))
 
+	case *explainAnalyzeNode:
+		setNeededColumns(n.plan, allColumns(n.plan))
+
 	case *explainTraceNode:
 		setNeededColumns(n.plan, allColumns(n.plan))
 
@@ -84,6 +87,10 @@ func setNeededColumns(plan planNode, needed []bool) {
 		setNeededColumns(n.source, needed[:len(needed)-1])
 		markOmitted(n.columns[:len(needed)-1], needed[:len(needed)-1])
 
+	case *recursiveCTENode:
+		// All the columns of the non-recursive term feed the recursive term.
+		setNeededColumns(n.initial, allColumns(n.initial))
+
 	case *valuesNode:
 		markOmitted(n.columns, needed)
 
@@ -192,12 +199,18 @@ func setNeededColumns(plan planNode, needed []bool) {
 
 	case *alterTableNode:
 	case *copyNode:
+	case *cancelQueryNode:
+	case *controlJobNode:
 	case *createDatabaseNode:
 	case *createIndexNode:
+	case *createSequenceNode:
+	case *createStatsNode:
 	case *createUserNode:
+	case *roleNode:
 	case *delayedNode:
 	case *dropDatabaseNode:
 	case *dropIndexNode:
+	case *dropSequenceNode:
 	case *dropTableNode:
 	case *dropViewNode:
 	case *emptyNode:
@@ -44,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
@@ -84,6 +85,7 @@ const (
 	categoryDateAndTime   = "Date and Time"
 	categoryIDGeneration  = "ID Generation"
 	categoryMath          = "Math and Numeric"
+	categorySequences     = "Sequence"
 	categoryString        = "String and Byte"
 	categorySystemInfo    = "System Info"
 )
@@ -281,7 +283,7 @@ var Builtins = map[string][]Builtin{
 	// NULL arguments are ignored.
 	"concat": {
 		Builtin{
-			Types:      VariadicType{TypeString},
+			Types:      VariadicType{VarType: TypeString},
 			ReturnType: fixedReturnType(TypeString),
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
 				var buffer bytes.Buffer
@@ -299,7 +301,7 @@ var Builtins = map[string][]Builtin{
 
 	"concat_ws": {
 		Builtin{
-			Types:      VariadicType{TypeString},
+			Types:      VariadicType{VarType: TypeString},
 			ReturnType: fixedReturnType(TypeString),
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
 				if len(args) == 0 {
@@ -842,9 +844,133 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	// Sequence functions.
+
+	"nextval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				seqName, err := evalSequenceName(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				res, err := ctx.Planner.IncrementSequence(ctx.Ctx(), seqName)
+				if err != nil {
+					return nil, err
+				}
+				return NewDInt(DInt(res)), nil
+			},
+			Info: "Advances the given sequence and returns its new value.",
+		},
+	},
+
+	"currval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				seqName, err := evalSequenceName(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				res, err := ctx.Planner.GetLatestValueInSessionForSequence(ctx.Ctx(), seqName)
+				if err != nil {
+					return nil, err
+				}
+				return NewDInt(DInt(res)), nil
+			},
+			Info: "Returns the latest value obtained with nextval for this sequence in this session.",
+		},
+	},
+
+	"lastval": {
+		Builtin{
+			Types:            ArgTypes{},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				res, err := ctx.Planner.GetLastSequenceValue(ctx.Ctx())
+				if err != nil {
+					return nil, err
+				}
+				return NewDInt(DInt(res)), nil
+			},
+			Info: "Return value most recently obtained with nextval in this session.",
+		},
+	},
+
+	// Note that setval() returns the value it was passed, as in PostgreSQL.
+	"setval": {
+		Builtin{
+			Types:            ArgTypes{{"sequence_name", TypeString}, {"value", TypeInt}},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				seqName, err := evalSequenceName(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				newVal := MustBeDInt(args[1])
+				if err := ctx.Planner.SetSequenceValue(ctx.Ctx(), seqName, int64(newVal), true); err != nil {
+					return nil, err
+				}
+				return args[1], nil
+			},
+			Info: "Set the given sequence's current value. The next call to nextval will return " +
+				"`value + Increment`.",
+		},
+		Builtin{
+			Types: ArgTypes{
+				{"sequence_name", TypeString}, {"value", TypeInt}, {"is_called", TypeBool},
+			},
+			ReturnType:       fixedReturnType(TypeInt),
+			category:         categorySequences,
+			impure:           true,
+			distsqlBlacklist: true,
+			fn: func(ctx *EvalContext, args Datums) (Datum, error) {
+				seqName, err := evalSequenceName(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				isCalled := bool(*args[2].(*DBool))
+				newVal := MustBeDInt(args[1])
+				if err := ctx.Planner.SetSequenceValue(ctx.Ctx(), seqName, int64(newVal), isCalled); err != nil {
+					return nil, err
+				}
+				return args[1], nil
+			},
+			Info: "Set the given sequence's current value. If is_called is false, the next call " +
+				"to nextval will return `value`; otherwise `value + Increment`.",
+		},
+	},
+
 	"experimental_uuid_v4": {uuidV4Impl},
 	"uuid_v4":              {uuidV4Impl},
 
+	"gen_random_uuid": {
+		Builtin{
+			Types:      ArgTypes{},
+			ReturnType: fixedReturnType(TypeUUID),
+			category:   categoryIDGeneration,
+			impure:     true,
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDUuid(DUuid{uuid.MakeV4()}), nil
+			},
+			Info: "Generates a random UUID and returns it as a value of UUID type.",
+		},
+	},
+
 	"greatest": {
 		Builtin{
 			Types:      HomogeneousType{},
@@ -1469,6 +1595,61 @@ var Builtins = map[string][]Builtin{
 		},
 	},
 
+	// JSON functions.
+
+	"jsonb_extract_path": {
+		Builtin{
+			Types:      VariadicType{FixedTypes: []Type{TypeJSON}, VarType: TypeString},
+			ReturnType: fixedReturnType(TypeJSON),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				j := jsonFetchPath(args[0].(*DJSON).JSON, args[1:])
+				if j == nil {
+					return DNull, nil
+				}
+				return NewDJSON(j), nil
+			},
+			Info: "Returns the JSON value pointed to by the variadic arguments.",
+		},
+	},
+
+	"jsonb_extract_path_text": {
+		Builtin{
+			Types:      VariadicType{FixedTypes: []Type{TypeJSON}, VarType: TypeString},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return jsonAsDString(jsonFetchPath(args[0].(*DJSON).JSON, args[1:])), nil
+			},
+			Info: "Returns the JSON value as text pointed to by the variadic arguments.",
+		},
+	},
+
+	"jsonb_typeof": {
+		Builtin{
+			Types:      ArgTypes{{"val", TypeJSON}},
+			ReturnType: fixedReturnType(TypeString),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				return NewDString(json.TypeName(args[0].(*DJSON).JSON)), nil
+			},
+			Info: "Returns the type of the outermost JSON value as a text string.",
+		},
+	},
+
+	"jsonb_array_length": {
+		Builtin{
+			Types:      ArgTypes{{"json", TypeJSON}},
+			ReturnType: fixedReturnType(TypeInt),
+			fn: func(_ *EvalContext, args Datums) (Datum, error) {
+				j := args[0].(*DJSON).JSON
+				if j.Type() != json.ArrayJSONType {
+					return nil, pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+						"cannot get array length of a non-array")
+				}
+				return NewDInt(DInt(j.Len())), nil
+			},
+			Info: "Returns the number of elements in the outermost JSON array.",
+		},
+	},
+
 	// Array functions.
 
 	"array_length": {
@@ -1792,6 +1973,16 @@ var uuidV4Impl = Builtin{
 	Info: "Returns a UUID.",
 }
 
+// evalSequenceName parses the name of a sequence passed as a string argument
+// to one of the sequence builtins and qualifies it with a database name.
+func evalSequenceName(ctx *EvalContext, arg Datum) (*TableName, error) {
+	tn, err := ParseTableName(string(MustBeDString(arg)))
+	if err != nil {
+		return nil, err
+	}
+	return ctx.Planner.QualifyWithDatabase(ctx.Ctx(), &NormalizableTableName{TableNameReference: tn})
+}
+
 var ceilImpl = []Builtin{
 	floatBuiltin1(func(x float64) (Datum, error) {
 		return NewDFloat(DFloat(math.Ceil(x))), nil
@@ -1983,7 +2174,7 @@ func feedHash(h hash.Hash, args Datums) {
 func hashBuiltin(newHash func() hash.Hash, info string) []Builtin {
 	return []Builtin{
 		{
-			Types:      VariadicType{TypeString},
+			Types:      VariadicType{VarType: TypeString},
 			ReturnType: fixedReturnType(TypeString),
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
 				h := newHash()
@@ -1993,7 +2184,7 @@ func hashBuiltin(newHash func() hash.Hash, info string) []Builtin {
 			Info: info,
 		},
 		{
-			Types:      VariadicType{TypeBytes},
+			Types:      VariadicType{VarType: TypeBytes},
 			ReturnType: fixedReturnType(TypeString),
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
 				h := newHash()
@@ -2008,7 +2199,7 @@ func hashBuiltin(newHash func() hash.Hash, info string) []Builtin {
 func hash32Builtin(newHash func() hash.Hash32, info string) []Builtin {
 	return []Builtin{
 		{
-			Types:      VariadicType{TypeString},
+			Types:      VariadicType{VarType: TypeString},
 			ReturnType: fixedReturnType(TypeInt),
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
 				h := newHash()
@@ -2018,7 +2209,7 @@ func hash32Builtin(newHash func() hash.Hash32, info string) []Builtin {
 			Info: info,
 		},
 		{
-			Types:      VariadicType{TypeBytes},
+			Types:      VariadicType{VarType: TypeBytes},
 			ReturnType: fixedReturnType(TypeInt),
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
 				h := newHash()
@@ -2032,7 +2223,7 @@ func hash32Builtin(newHash func() hash.Hash32, info string) []Builtin {
 func hash64Builtin(newHash func() hash.Hash64, info string) []Builtin {
 	return []Builtin{
 		{
-			Types:      VariadicType{TypeString},
+			Types:      VariadicType{VarType: TypeString},
 			ReturnType: fixedReturnType(TypeInt),
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
 				h := newHash()
@@ -2042,7 +2233,7 @@ func hash64Builtin(newHash func() hash.Hash64, info string) []Builtin {
 			Info: info,
 		},
 		{
-			Types:      VariadicType{TypeBytes},
+			Types:      VariadicType{VarType: TypeBytes},
 			ReturnType: fixedReturnType(TypeInt),
 			fn: func(_ *EvalContext, args Datums) (Datum, error) {
 				h := newHash()
@@ -2413,6 +2604,31 @@ func arrayLength(arr *DArray, dim int64) Datum {
 
 var intOne = NewDInt(DInt(1))
 
+// jsonFetchPath walks down j following path, where each element of path is
+// either an object key or, for arrays, an integer index. It returns nil if
+// the path does not exist.
+func jsonFetchPath(j json.JSON, path Datums) json.JSON {
+	for _, d := range path {
+		if j == nil {
+			return nil
+		}
+		step := string(MustBeDString(d))
+		switch j.Type() {
+		case json.ObjectJSONType:
+			j = j.FetchValKey(step)
+		case json.ArrayJSONType:
+			idx, err := strconv.Atoi(step)
+			if err != nil {
+				return nil
+			}
+			j = j.FetchValIdx(idx)
+		default:
+			return nil
+		}
+	}
+	return j
+}
+
 func arrayLower(arr *DArray, dim int64) Datum {
 	if arr.Len() == 0 || dim < 1 {
 		return DNull
@@ -52,6 +52,8 @@ func (*IntervalColType) columnType()       {}
 func (*StringColType) columnType()         {}
 func (*NameColType) columnType()           {}
 func (*BytesColType) columnType()          {}
+func (*UUIDColType) columnType()           {}
+func (*JSONColType) columnType()           {}
 func (*CollatedStringColType) columnType() {}
 func (*ArrayColType) columnType()          {}
 func (*VectorColType) columnType()         {}
@@ -69,6 +71,8 @@ func (*IntervalColType) castTargetType()       {}
 func (*StringColType) castTargetType()         {}
 func (*NameColType) castTargetType()           {}
 func (*BytesColType) castTargetType()          {}
+func (*UUIDColType) castTargetType()           {}
+func (*JSONColType) castTargetType()           {}
 func (*CollatedStringColType) castTargetType() {}
 func (*ArrayColType) castTargetType()          {}
 func (*VectorColType) castTargetType()         {}
@@ -328,6 +332,34 @@ func (node *BytesColType) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(node.Name)
 }
 
+// Pre-allocated immutable uuid column type.
+var uuidColTypeUUID = &UUIDColType{}
+
+// UUIDColType represents a UUID type.
+type UUIDColType struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *UUIDColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("UUID")
+}
+
+// Pre-allocated immutable JSON column types.
+var (
+	jsonColTypeJSON  = &JSONColType{Name: "JSON"}
+	jsonColTypeJSONB = &JSONColType{Name: "JSONB"}
+)
+
+// JSONColType represents the JSON column type.
+type JSONColType struct {
+	Name string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *JSONColType) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString(node.Name)
+}
+
 // CollatedStringColType represents a STRING, CHAR or VARCHAR type with a
 // collation locale.
 type CollatedStringColType struct {
@@ -360,14 +392,15 @@ func (node *ArrayColType) Format(buf *bytes.Buffer, f FmtFlags) {
 }
 
 func arrayOf(colType ColumnType, boundsExprs Exprs) (ColumnType, error) {
-	switch colType {
-	case intColTypeInt:
-		return &ArrayColType{Name: "INT[]", ParamType: intColTypeInt, BoundsExprs: boundsExprs}, nil
-	case stringColTypeString:
-		return &ArrayColType{Name: "STRING[]", ParamType: stringColTypeString, BoundsExprs: boundsExprs}, nil
-	default:
+	switch t := colType.(type) {
+	case *ArrayColType, *VectorColType:
 		return nil, errors.Errorf("cannot make array for column type %s", colType)
+	case *IntColType:
+		if t.IsSerial() {
+			return nil, errors.Errorf("cannot make array for column type %s", colType)
+		}
 	}
+	return &ArrayColType{Name: AsString(colType) + "[]", ParamType: colType, BoundsExprs: boundsExprs}, nil
 }
 
 // VectorColType is the base for VECTOR column types, which are Postgres's
@@ -464,6 +497,8 @@ func (node *IntervalColType) String() string       { return AsString(node) }
 func (node *StringColType) String() string         { return AsString(node) }
 func (node *NameColType) String() string           { return AsString(node) }
 func (node *BytesColType) String() string          { return AsString(node) }
+func (node *UUIDColType) String() string           { return AsString(node) }
+func (node *JSONColType) String() string           { return AsString(node) }
 func (node *CollatedStringColType) String() string { return AsString(node) }
 func (node *ArrayColType) String() string          { return AsString(node) }
 func (node *VectorColType) String() string         { return AsString(node) }
@@ -496,6 +531,10 @@ func DatumTypeToColumnType(t Type) (ColumnType, error) {
 		return nameColTypeName, nil
 	case TypeBytes:
 		return bytesColTypeBytes, nil
+	case TypeUUID:
+		return uuidColTypeUUID, nil
+	case TypeJSON:
+		return jsonColTypeJSONB, nil
 	case TypeOid,
 		TypeRegClass,
 		TypeRegNamespace,
@@ -536,6 +575,10 @@ func CastTargetToDatumType(t CastTargetType) Type {
 		return TypeName
 	case *BytesColType:
 		return TypeBytes
+	case *UUIDColType:
+		return TypeUUID
+	case *JSONColType:
+		return TypeJSON
 	case *DateColType:
 		return TypeDate
 	case *TimestampColType:
@@ -58,6 +58,9 @@ func TestParseColumnType(t *testing.T) {
 		{"BLOB", &BytesColType{Name: "BLOB"}},
 		{"BYTES", &BytesColType{Name: "BYTES"}},
 		{"BYTEA", &BytesColType{Name: "BYTEA"}},
+		{"UUID", &UUIDColType{}},
+		{"JSON", &JSONColType{Name: "JSON"}},
+		{"JSONB", &JSONColType{Name: "JSONB"}},
 		{"STRING COLLATE da", &CollatedStringColType{Name: "STRING", Locale: "da"}},
 		{"CHAR COLLATE de", &CollatedStringColType{Name: "CHAR", Locale: "de"}},
 		{"VARCHAR COLLATE en", &CollatedStringColType{Name: "VARCHAR", Locale: "en"}},
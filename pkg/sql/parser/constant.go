@@ -368,6 +368,8 @@ var (
 		TypeTimestamp,
 		TypeTimestampTZ,
 		TypeInterval,
+		TypeUUID,
+		TypeJSON,
 	}
 	strValAvailBytesString = []Type{TypeBytes, TypeString}
 	strValAvailBytes       = []Type{TypeBytes}
@@ -434,6 +436,10 @@ func (expr *StrVal) ResolveAsType(ctx *SemaContext, typ Type) (Datum, error) {
 		return ParseDTimestampTZ(expr.s, ctx.getLocation(), time.Microsecond)
 	case TypeInterval:
 		return ParseDInterval(expr.s)
+	case TypeUUID:
+		return ParseDUuidFromString(expr.s)
+	case TypeJSON:
+		return ParseDJSON(expr.s)
 	default:
 		return nil, fmt.Errorf("could not resolve %T %v into a %T", expr, expr, typ)
 	}
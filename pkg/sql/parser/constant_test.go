@@ -216,6 +216,21 @@ func mustParseDInterval(t *testing.T, s string) Datum {
 	}
 	return d
 }
+func mustParseDUuid(t *testing.T, s string) Datum {
+	d, err := ParseDUuidFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func mustParseDJSON(t *testing.T, s string) Datum {
+	d, err := ParseDJSON(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
 
 var parseFuncs = map[Type]func(*testing.T, string) Datum{
 	TypeString:      func(t *testing.T, s string) Datum { return NewDString(s) },
@@ -225,6 +240,8 @@ var parseFuncs = map[Type]func(*testing.T, string) Datum{
 	TypeTimestamp:   mustParseDTimestamp,
 	TypeTimestampTZ: mustParseDTimestampTZ,
 	TypeInterval:    mustParseDInterval,
+	TypeUUID:        mustParseDUuid,
+	TypeJSON:        mustParseDJSON,
 }
 
 func typeSet(types ...Type) map[Type]struct{} {
@@ -251,7 +268,7 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 		},
 		{
 			c:            &StrVal{s: "true", bytesEsc: false},
-			parseOptions: typeSet(TypeString, TypeBytes, TypeBool),
+			parseOptions: typeSet(TypeString, TypeBytes, TypeBool, TypeJSON),
 		},
 		{
 			c:            &StrVal{s: "2010-09-28", bytesEsc: false},
@@ -265,6 +282,14 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 			c:            &StrVal{s: "PT12H2M", bytesEsc: false},
 			parseOptions: typeSet(TypeString, TypeBytes, TypeInterval),
 		},
+		{
+			c:            &StrVal{s: "63616665-6630-3064-6465-616462656566", bytesEsc: false},
+			parseOptions: typeSet(TypeString, TypeBytes, TypeUUID),
+		},
+		{
+			c:            &StrVal{s: `{"a": [1, "b"]}`, bytesEsc: false},
+			parseOptions: typeSet(TypeString, TypeBytes, TypeJSON),
+		},
 		{
 			c:            &StrVal{s: "abc 世界", bytesEsc: true},
 			parseOptions: typeSet(TypeString, TypeBytes),
@@ -106,6 +106,11 @@ type CreateIndex struct {
 	// for improved reading performance.
 	Storing    NameList
 	Interleave *InterleaveDef
+	// Predicate, if set, restricts the index to the rows that satisfy it.
+	Predicate Expr
+	// Inverted is set for inverted indexes, which index the elements of an
+	// array column.
+	Inverted bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -114,6 +119,9 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Unique {
 		buf.WriteString("UNIQUE ")
 	}
+	if node.Inverted {
+		buf.WriteString("INVERTED ")
+	}
 	buf.WriteString("INDEX ")
 	if node.IfNotExists {
 		buf.WriteString("IF NOT EXISTS ")
@@ -135,6 +143,10 @@ func (node *CreateIndex) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.Predicate != nil {
+		buf.WriteString(" WHERE ")
+		FormatNode(buf, f, node.Predicate)
+	}
 }
 
 // TableDef represents a column, index or constraint definition within a CREATE
@@ -202,6 +214,10 @@ type ColumnTableDef struct {
 		Create      bool
 		IfNotExists bool
 	}
+	Computed struct {
+		Computed bool
+		Expr     Expr
+	}
 }
 
 // ColumnTableDefCheckExpr represents a check constraint on a column definition
@@ -278,10 +294,19 @@ func newColumnTableDef(
 			d.Family.Name = t.Family
 			d.Family.Create = t.Create
 			d.Family.IfNotExists = t.IfNotExists
+		case *ColumnComputedDef:
+			if d.IsComputed() {
+				return nil, errors.Errorf("multiple computed expressions specified for column %q", name)
+			}
+			d.Computed.Computed = true
+			d.Computed.Expr = t.Expr
 		default:
 			panic(fmt.Sprintf("unexpected column qualification: %T", c))
 		}
 	}
+	if d.IsComputed() && d.HasDefaultExpr() {
+		return nil, errors.Errorf("computed column %q cannot also have a DEFAULT expression", name)
+	}
 	return d, nil
 }
 
@@ -299,6 +324,11 @@ func (node *ColumnTableDef) HasFKConstraint() bool {
 	return node.References.Table.TableNameReference != nil
 }
 
+// IsComputed returns if the ColumnTableDef is a computed column.
+func (node *ColumnTableDef) IsComputed() bool {
+	return node.Computed.Computed
+}
+
 // HasColumnFamily returns if the ColumnTableDef has a column family.
 func (node *ColumnTableDef) HasColumnFamily() bool {
 	return node.Family.Name != "" || node.Family.Create
@@ -332,6 +362,11 @@ func (node *ColumnTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 		buf.WriteString(" DEFAULT ")
 		FormatNode(buf, f, node.DefaultExpr.Expr)
 	}
+	if node.IsComputed() {
+		buf.WriteString(" AS (")
+		FormatNode(buf, f, node.Computed.Expr)
+		buf.WriteString(") STORED")
+	}
 	for _, checkExpr := range node.CheckExprs {
 		if checkExpr.ConstraintName != "" {
 			buf.WriteString(" CONSTRAINT ")
@@ -389,10 +424,16 @@ func (UniqueConstraint) columnQualification()        {}
 func (*ColumnCheckConstraint) columnQualification()  {}
 func (*ColumnFKConstraint) columnQualification()     {}
 func (*ColumnFamilyConstraint) columnQualification() {}
+func (*ColumnComputedDef) columnQualification()      {}
 
 // ColumnCollation represents a COLLATE clause for a column.
 type ColumnCollation string
 
+// ColumnComputedDef represents the description of a computed column.
+type ColumnComputedDef struct {
+	Expr Expr
+}
+
 // ColumnDefault represents a DEFAULT clause for a column.
 type ColumnDefault struct {
 	Expr Expr
@@ -435,6 +476,8 @@ type IndexTableDef struct {
 	Columns    IndexElemList
 	Storing    NameList
 	Interleave *InterleaveDef
+	Predicate  Expr
+	Inverted   bool
 }
 
 func (node *IndexTableDef) setName(name Name) {
@@ -443,6 +486,9 @@ func (node *IndexTableDef) setName(name Name) {
 
 // Format implements the NodeFormatter interface.
 func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.Inverted {
+		buf.WriteString("INVERTED ")
+	}
 	buf.WriteString("INDEX ")
 	if node.Name != "" {
 		FormatNode(buf, f, node.Name)
@@ -459,6 +505,10 @@ func (node *IndexTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.Predicate != nil {
+		buf.WriteString(" WHERE ")
+		FormatNode(buf, f, node.Predicate)
+	}
 }
 
 // ConstraintTableDef represents a constraint definition within a CREATE TABLE
@@ -502,6 +552,10 @@ func (node *UniqueConstraintTableDef) Format(buf *bytes.Buffer, f FmtFlags) {
 	if node.Interleave != nil {
 		FormatNode(buf, f, node.Interleave)
 	}
+	if node.Predicate != nil {
+		buf.WriteString(" WHERE ")
+		FormatNode(buf, f, node.Predicate)
+	}
 }
 
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
@@ -655,6 +709,23 @@ func (node *CreateTable) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// CreateStats represents a CREATE STATISTICS statement.
+type CreateStats struct {
+	Name        Name
+	ColumnNames NameList
+	Table       NormalizableTableName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateStats) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE STATISTICS ")
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" ON ")
+	FormatNode(buf, f, node.ColumnNames)
+	buf.WriteString(" FROM ")
+	FormatNode(buf, f, node.Table)
+}
+
 // CreateUser represents a CREATE USER statement.
 type CreateUser struct {
 	Name     Name
@@ -680,6 +751,17 @@ func (node *CreateUser) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// CreateRole represents a CREATE ROLE statement.
+type CreateRole struct {
+	Name Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE ROLE ")
+	FormatNode(buf, f, node.Name)
+}
+
 // CreateView represents a CREATE VIEW statement.
 type CreateView struct {
 	Name        NormalizableTableName
@@ -702,3 +784,57 @@ func (node *CreateView) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(" AS ")
 	FormatNode(buf, f, node.AsSource)
 }
+
+// CreateSequence represents a CREATE SEQUENCE statement.
+type CreateSequence struct {
+	IfNotExists bool
+	Name        NormalizableTableName
+	Options     SequenceOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSequence) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CREATE SEQUENCE ")
+	if node.IfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	FormatNode(buf, f, node.Name)
+	FormatNode(buf, f, node.Options)
+}
+
+// SequenceOptions represents a list of sequence options.
+type SequenceOptions []SequenceOption
+
+// Format implements the NodeFormatter interface.
+func (node SequenceOptions) Format(buf *bytes.Buffer, f FmtFlags) {
+	for _, option := range node {
+		buf.WriteByte(' ')
+		switch option.Name {
+		case SeqOptIncrement:
+			fmt.Fprintf(buf, "%s BY %d", option.Name, *option.IntVal)
+		case SeqOptStart:
+			fmt.Fprintf(buf, "%s WITH %d", option.Name, *option.IntVal)
+		default:
+			if option.IntVal == nil {
+				fmt.Fprintf(buf, "NO %s", option.Name)
+			} else {
+				fmt.Fprintf(buf, "%s %d", option.Name, *option.IntVal)
+			}
+		}
+	}
+}
+
+// SequenceOption represents an option on a CREATE SEQUENCE statement.
+type SequenceOption struct {
+	Name string
+	// IntVal is nil for the NO MINVALUE and NO MAXVALUE forms.
+	IntVal *int64
+}
+
+// Names of options on CREATE SEQUENCE.
+const (
+	SeqOptIncrement = "INCREMENT"
+	SeqOptMinValue  = "MINVALUE"
+	SeqOptMaxValue  = "MAXVALUE"
+	SeqOptStart     = "START"
+)
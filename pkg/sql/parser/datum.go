@@ -37,6 +37,8 @@ import (
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 var (
@@ -947,6 +949,216 @@ func (d *DBytes) Size() uintptr {
 	return unsafe.Sizeof(*d) + uintptr(len(*d))
 }
 
+// DUuid is the UUID Datum.
+type DUuid struct {
+	uuid.UUID
+}
+
+// NewDUuid is a helper routine to create a *DUuid initialized from its
+// argument.
+func NewDUuid(d DUuid) *DUuid {
+	return &d
+}
+
+// ParseDUuidFromString parses and returns the *DUuid Datum value represented
+// by the provided input string, or an error.
+func ParseDUuidFromString(s string) (*DUuid, error) {
+	uv, err := uuid.FromString(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeUUID, err)
+	}
+	return NewDUuid(DUuid{uv}), nil
+}
+
+// ParseDUuidFromBytes parses and returns the *DUuid Datum value represented
+// by the provided input bytes, or an error.
+func ParseDUuidFromBytes(b []byte) (*DUuid, error) {
+	uv, err := uuid.FromBytes(b)
+	if err != nil {
+		return nil, makeParseError(string(b), TypeUUID, err)
+	}
+	return NewDUuid(DUuid{uv}), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DUuid) ResolvedType() Type {
+	return TypeUUID
+}
+
+// Compare implements the Datum interface.
+func (d *DUuid) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DUuid)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return bytes.Compare(d.GetBytes(), v.GetBytes())
+}
+
+// Prev implements the Datum interface.
+func (d *DUuid) Prev() (Datum, bool) {
+	u := d.UUID
+	for i := len(u.UUID) - 1; i >= 0; i-- {
+		u.UUID[i]--
+		if u.UUID[i] != 0xff {
+			break
+		}
+	}
+	return NewDUuid(DUuid{u}), true
+}
+
+// Next implements the Datum interface.
+func (d *DUuid) Next() (Datum, bool) {
+	u := d.UUID
+	for i := len(u.UUID) - 1; i >= 0; i-- {
+		u.UUID[i]++
+		if u.UUID[i] != 0 {
+			break
+		}
+	}
+	return NewDUuid(DUuid{u}), true
+}
+
+// IsMax implements the Datum interface.
+func (d *DUuid) IsMax() bool {
+	return d.UUID == dMaxUUID.UUID
+}
+
+// IsMin implements the Datum interface.
+func (d *DUuid) IsMin() bool {
+	return d.UUID == dMinUUID.UUID
+}
+
+var dMinUUID = NewDUuid(DUuid{})
+
+var dMaxUUID = func() *DUuid {
+	var d DUuid
+	for i := range d.UUID.UUID {
+		d.UUID.UUID[i] = 0xff
+	}
+	return &d
+}()
+
+// min implements the Datum interface.
+func (*DUuid) min() (Datum, bool) {
+	return dMinUUID, true
+}
+
+// max implements the Datum interface.
+func (*DUuid) max() (Datum, bool) {
+	return dMaxUUID, true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DUuid) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DUuid) Format(buf *bytes.Buffer, f FmtFlags) {
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+	buf.WriteString(d.UUID.String())
+	if !f.bareStrings {
+		buf.WriteByte('\'')
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DUuid) Size() uintptr {
+	return unsafe.Sizeof(*d)
+}
+
+// DJSON is the JSON Datum.
+type DJSON struct {
+	json.JSON
+}
+
+// NewDJSON is a helper routine to create a DJSON initialized from its
+// argument.
+func NewDJSON(j json.JSON) *DJSON {
+	return &DJSON{j}
+}
+
+// ParseDJSON takes a string of JSON and returns a DJSON value.
+func ParseDJSON(s string) (*DJSON, error) {
+	j, err := json.ParseJSON(s)
+	if err != nil {
+		return nil, makeParseError(s, TypeJSON, err)
+	}
+	return NewDJSON(j), nil
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (*DJSON) ResolvedType() Type {
+	return TypeJSON
+}
+
+// Compare implements the Datum interface.
+func (d *DJSON) Compare(ctx *EvalContext, other Datum) int {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1
+	}
+	v, ok := other.(*DJSON)
+	if !ok {
+		panic(makeUnsupportedComparisonMessage(d, other))
+	}
+	return d.JSON.Compare(v.JSON)
+}
+
+// Prev implements the Datum interface.
+func (d *DJSON) Prev() (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DJSON) Next() (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DJSON) IsMax() bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DJSON) IsMin() bool {
+	return d.JSON == json.NullJSONValue
+}
+
+var dMinJSON = NewDJSON(json.NullJSONValue)
+
+// min implements the Datum interface.
+func (*DJSON) min() (Datum, bool) {
+	return dMinJSON, true
+}
+
+// max implements the Datum interface.
+func (*DJSON) max() (Datum, bool) {
+	return nil, false
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DJSON) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DJSON) Format(buf *bytes.Buffer, f FmtFlags) {
+	s := d.JSON.String()
+	if f.bareStrings {
+		buf.WriteString(s)
+	} else {
+		encodeSQLString(buf, s)
+	}
+}
+
+// Size implements the Datum interface.
+func (d *DJSON) Size() uintptr {
+	return unsafe.Sizeof(*d) + d.JSON.Size()
+}
+
 // DDate is the date Datum represented as the number of days after
 // the Unix epoch.
 type DDate int64
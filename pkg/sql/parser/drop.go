@@ -99,6 +99,21 @@ func (node *DropTable) Format(buf *bytes.Buffer, f FmtFlags) {
 	}
 }
 
+// DropRole represents a DROP ROLE statement.
+type DropRole struct {
+	Names    NameList
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP ROLE ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Names)
+}
+
 // DropView represents a DROP VIEW statement.
 type DropView struct {
 	Names        TableNameReferences
@@ -118,3 +133,23 @@ func (node *DropView) Format(buf *bytes.Buffer, f FmtFlags) {
 		buf.WriteString(node.DropBehavior.String())
 	}
 }
+
+// DropSequence represents a DROP SEQUENCE statement.
+type DropSequence struct {
+	Names        TableNameReferences
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropSequence) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("DROP SEQUENCE ")
+	if node.IfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	FormatNode(buf, f, node.Names)
+	if node.DropBehavior != DropDefault {
+		buf.WriteByte(' ')
+		buf.WriteString(node.DropBehavior.String())
+	}
+}
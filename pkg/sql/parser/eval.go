@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
@@ -896,6 +897,54 @@ var BinOps = map[BinaryOperator]binOpOverload{
 		},
 	},
 
+	JSONFetchVal: {
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeString,
+			ReturnType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				j := left.(*DJSON).FetchValKey(string(MustBeDString(right)))
+				if j == nil {
+					return DNull, nil
+				}
+				return NewDJSON(j), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeInt,
+			ReturnType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				j := left.(*DJSON).FetchValIdx(int(MustBeDInt(right)))
+				if j == nil {
+					return DNull, nil
+				}
+				return NewDJSON(j), nil
+			},
+		},
+	},
+
+	JSONFetchText: {
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeString,
+			ReturnType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				j := left.(*DJSON).FetchValKey(string(MustBeDString(right)))
+				return jsonAsDString(j), nil
+			},
+		},
+		BinOp{
+			LeftType:   TypeJSON,
+			RightType:  TypeInt,
+			ReturnType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				j := left.(*DJSON).FetchValIdx(int(MustBeDInt(right)))
+				return jsonAsDString(j), nil
+			},
+		},
+	},
+
 	Pow: {
 		BinOp{
 			LeftType:   TypeInt,
@@ -1129,6 +1178,16 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarEQFn,
 		},
+		CmpOp{
+			LeftType:  TypeUUID,
+			RightType: TypeUUID,
+			fn:        cmpOpScalarEQFn,
+		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarEQFn,
+		},
 		CmpOp{
 			LeftType:  TypeOid,
 			RightType: TypeOid,
@@ -1259,6 +1318,16 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarLTFn,
 		},
+		CmpOp{
+			LeftType:  TypeUUID,
+			RightType: TypeUUID,
+			fn:        cmpOpScalarLTFn,
+		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarLTFn,
+		},
 		CmpOp{
 			LeftType:  TypeTuple,
 			RightType: TypeTuple,
@@ -1384,6 +1453,16 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			RightType: TypeInterval,
 			fn:        cmpOpScalarLEFn,
 		},
+		CmpOp{
+			LeftType:  TypeUUID,
+			RightType: TypeUUID,
+			fn:        cmpOpScalarLEFn,
+		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn:        cmpOpScalarLEFn,
+		},
 		CmpOp{
 			LeftType:  TypeTuple,
 			RightType: TypeTuple,
@@ -1405,6 +1484,8 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 		makeEvalTupleIn(TypeTimestamp),
 		makeEvalTupleIn(TypeTimestampTZ),
 		makeEvalTupleIn(TypeInterval),
+		makeEvalTupleIn(TypeUUID),
+		makeEvalTupleIn(TypeJSON),
 		makeEvalTupleIn(TypeTuple),
 	},
 
@@ -1460,6 +1541,108 @@ var CmpOps = map[ComparisonOperator]cmpOpOverload{
 			},
 		},
 	},
+
+	Contains: {
+		CmpOp{
+			LeftType:  TypeAnyArray,
+			RightType: TypeAnyArray,
+			fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return arrayContains(ctx, MustBeDArray(left), MustBeDArray(right))
+			},
+		},
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeJSON,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DJSON).Contains(right.(*DJSON).JSON))), nil
+			},
+		},
+	},
+
+	JSONExists: {
+		CmpOp{
+			LeftType:  TypeJSON,
+			RightType: TypeString,
+			fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(left.(*DJSON).Exists(string(MustBeDString(right))))), nil
+			},
+		},
+	},
+
+	Overlaps: {
+		CmpOp{
+			LeftType:  TypeAnyArray,
+			RightType: TypeAnyArray,
+			fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return arrayOverlaps(ctx, MustBeDArray(left), MustBeDArray(right))
+			},
+		},
+	},
+}
+
+// checkArrayElementTypes returns an error if the elements of the arrays
+// cannot be compared.
+func checkArrayElementTypes(left, right *DArray) error {
+	if left.ParamTyp == TypeNull || right.ParamTyp == TypeNull ||
+		left.ParamTyp.Equivalent(right.ParamTyp) {
+		return nil
+	}
+	return errors.Errorf("unsupported comparison: %s to %s", left.ResolvedType(), right.ResolvedType())
+}
+
+// arrayContainsElem returns whether the array has a non-NULL element equal to
+// elem.
+func arrayContainsElem(ctx *EvalContext, array *DArray, elem Datum) bool {
+	if elem == DNull {
+		return false
+	}
+	for _, d := range array.Array {
+		if d != DNull && d.Compare(ctx, elem) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// arrayContains implements `left @> right` for arrays: it is true if every
+// element of right is an element of left. NULL elements are never contained.
+func arrayContains(ctx *EvalContext, left, right *DArray) (Datum, error) {
+	if err := checkArrayElementTypes(left, right); err != nil {
+		return nil, err
+	}
+	for _, elem := range right.Array {
+		if !arrayContainsElem(ctx, left, elem) {
+			return DBoolFalse, nil
+		}
+	}
+	return DBoolTrue, nil
+}
+
+// arrayOverlaps implements `left && right` for arrays: it is true if the
+// arrays have a non-NULL element in common.
+func arrayOverlaps(ctx *EvalContext, left, right *DArray) (Datum, error) {
+	if err := checkArrayElementTypes(left, right); err != nil {
+		return nil, err
+	}
+	for _, elem := range right.Array {
+		if arrayContainsElem(ctx, left, elem) {
+			return DBoolTrue, nil
+		}
+	}
+	return DBoolFalse, nil
+}
+
+// jsonAsDString returns the text of a JSON value fetched by one of the JSON
+// operators, or NULL if the value does not exist or is JSON null.
+func jsonAsDString(j json.JSON) Datum {
+	if j == nil {
+		return DNull
+	}
+	text := j.AsText()
+	if text == nil {
+		return DNull
+	}
+	return NewDString(*text)
 }
 
 func isNaN(d Datum) bool {
@@ -1680,6 +1863,24 @@ type EvalPlanner interface {
 	// QualifyWithDatabase resolves a possibly unqualified table name into a
 	// table name that is qualified by database.
 	QualifyWithDatabase(ctx context.Context, t *NormalizableTableName) (*TableName, error)
+
+	// IncrementSequence advances the given sequence and returns its new value.
+	// It returns an error if the name does not designate a sequence. The name
+	// must already be qualified by database.
+	IncrementSequence(ctx context.Context, seqName *TableName) (int64, error)
+
+	// GetLatestValueInSessionForSequence returns the value most recently
+	// obtained by nextval() for the given sequence in the current session.
+	GetLatestValueInSessionForSequence(ctx context.Context, seqName *TableName) (int64, error)
+
+	// GetLastSequenceValue returns the value most recently obtained by
+	// nextval() for any sequence in the current session.
+	GetLastSequenceValue(ctx context.Context) (int64, error)
+
+	// SetSequenceValue sets the value of the given sequence. If isCalled is
+	// false, the next call to nextval() returns newVal; otherwise it returns
+	// newVal advanced by the sequence's increment.
+	SetSequenceValue(ctx context.Context, seqName *TableName, newVal int64, isCalled bool) error
 }
 
 // contextHolder is a wrapper that returns a Context.
@@ -2214,7 +2415,7 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 		switch t := d.(type) {
 		case *DBool, *DInt, *DFloat, *DDecimal, dNull:
 			s = d.String()
-		case *DTimestamp, *DTimestampTZ, *DDate:
+		case *DTimestamp, *DTimestampTZ, *DDate, *DUuid, *DJSON:
 			s = AsStringWithFlags(d, FmtBareStrings)
 		case *DInterval:
 			// When converting an interval to string, we need a string representation
@@ -2260,6 +2461,30 @@ func (expr *CastExpr) Eval(ctx *EvalContext) (Datum, error) {
 			return NewDBytes(DBytes(t.Contents)), nil
 		case *DBytes:
 			return d, nil
+		case *DUuid:
+			return NewDBytes(DBytes(t.GetBytes())), nil
+		}
+
+	case *UUIDColType:
+		switch t := d.(type) {
+		case *DString:
+			return ParseDUuidFromString(string(*t))
+		case *DCollatedString:
+			return ParseDUuidFromString(t.Contents)
+		case *DBytes:
+			return ParseDUuidFromBytes([]byte(*t))
+		case *DUuid:
+			return d, nil
+		}
+
+	case *JSONColType:
+		switch t := d.(type) {
+		case *DString:
+			return ParseDJSON(string(*t))
+		case *DCollatedString:
+			return ParseDJSON(t.Contents)
+		case *DJSON:
+			return d, nil
 		}
 
 	case *DateColType:
@@ -2781,6 +3006,16 @@ func (t *DBytes) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DUuid) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t *DDate) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
@@ -2908,6 +3143,9 @@ func foldComparisonExpr(
 	case NotRegIMatch:
 		// NotRegIMatch(left, right) is implemented as !RegIMatch(left, right)
 		return RegIMatch, left, right, false, true
+	case ContainedBy:
+		// ContainedBy(left, right) is implemented as Contains(right, left)
+		return Contains, right, left, true, false
 	case IsDistinctFrom:
 		// IsDistinctFrom(left, right) is implemented as !EQ(left, right)
 		//
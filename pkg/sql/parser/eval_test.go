@@ -662,6 +662,36 @@ func TestEval(t *testing.T) {
 		{`10::int::timestamp`, `'1970-01-01 00:00:10+00:00'`},
 		{`10::int::timestamptz`, `'1970-01-01 00:00:10+00:00'`},
 		{`10123456::int::interval`, `'10s123ms456µs'`},
+		{`'{"b": [1, "c"],"a":1.50}'::jsonb`, `'{"a": 1.50, "b": [1, "c"]}'`},
+		{`'{"a": 1}'::jsonb::string`, `'{"a": 1}'`},
+		// JSON operators.
+		{`'{"a": 1}'::jsonb -> 'a'`, `'1'`},
+		{`'{"a": 1}'::jsonb -> 'b'`, `NULL`},
+		{`'{"a": {"b": "c"}}'::jsonb -> 'a' ->> 'b'`, `'c'`},
+		{`'{"a": null}'::jsonb ->> 'a'`, `NULL`},
+		{`'[1, 2, 3]'::jsonb -> 1`, `'2'`},
+		{`'[1, 2, 3]'::jsonb -> -1`, `'3'`},
+		{`'[1, 2, 3]'::jsonb ->> 3`, `NULL`},
+		{`'{"a": 1, "b": [2, 3]}'::jsonb @> '{"b": [3]}'`, `true`},
+		{`'{"a": 1, "b": [2, 3]}'::jsonb @> '{"a": 2}'`, `false`},
+		{`'{"a": 1}'::jsonb <@ '{"a": 1, "b": 2}'`, `true`},
+		// Array containment operators.
+		{`ARRAY[1, 2, 3] @> ARRAY[3, 1]`, `true`},
+		{`ARRAY[1, 2, 3] @> ARRAY[1, 4]`, `false`},
+		{`ARRAY[1, 2] @> ARRAY[]:::INT[]`, `true`},
+		{`ARRAY[1, NULL] @> ARRAY[NULL::INT]`, `false`},
+		{`ARRAY['a'] <@ ARRAY['a', 'b']`, `true`},
+		{`ARRAY[1, 2] && ARRAY[2, 3]`, `true`},
+		{`ARRAY[1, 2] && ARRAY[3, 4]`, `false`},
+		{`ARRAY[1, NULL] && ARRAY[NULL::INT]`, `false`},
+		{`'{"a": 1}'::jsonb ? 'a'`, `true`},
+		{`'["a", "b"]'::jsonb ? 'c'`, `false`},
+		{`'{"a": 1}'::jsonb = '{"a": 1.0}'`, `true`},
+		{`jsonb_extract_path('{"a": [{"b": 1}]}', 'a', '0', 'b')`, `'1'`},
+		{`jsonb_extract_path_text('{"a": [{"b": "c"}]}', 'a', '0', 'b')`, `'c'`},
+		{`jsonb_extract_path('{"a": 1}', 'a', 'b')`, `NULL`},
+		{`jsonb_typeof('[1]')`, `'array'`},
+		{`jsonb_array_length('[1, [2, 3]]')`, `2`},
 		// Type annotation expressions.
 		{`ANNOTATE_TYPE('s', string)`, `'s'`},
 		{`ANNOTATE_TYPE('s', bytes)`, `b's'`},
@@ -35,6 +35,13 @@ type Grant struct {
 	Grantees   NameList
 }
 
+// GrantRole represents a GRANT <role> statement.
+type GrantRole struct {
+	Roles       NameList
+	Members     NameList
+	AdminOption bool
+}
+
 // TargetList represents a list of targets.
 // Only one field may be non-nil.
 type TargetList struct {
@@ -61,3 +68,14 @@ func (node *Grant) Format(buf *bytes.Buffer, f FmtFlags) {
 	buf.WriteString(" TO ")
 	FormatNode(buf, f, node.Grantees)
 }
+
+// Format implements the NodeFormatter interface.
+func (node *GrantRole) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("GRANT ")
+	FormatNode(buf, f, node.Roles)
+	buf.WriteString(" TO ")
+	FormatNode(buf, f, node.Members)
+	if node.AdminOption {
+		buf.WriteString(" WITH ADMIN OPTION")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package parser
+
+import "bytes"
+
+// ShowJobs represents a SHOW JOBS statement.
+type ShowJobs struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowJobs) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW JOBS")
+}
+
+// PauseJob represents a PAUSE JOB statement.
+type PauseJob struct {
+	ID Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PauseJob) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("PAUSE JOB ")
+	FormatNode(buf, f, node.ID)
+}
+
+// ResumeJob represents a RESUME JOB statement. The resumed job runs as part of
+// the statement, which returns once the job stops. Options holds what the job
+// needs to run that is not stored with it, e.g. the passphrase of an encrypted
+// backup.
+type ResumeJob struct {
+	ID      Expr
+	Options KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ResumeJob) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("RESUME JOB ")
+	FormatNode(buf, f, node.ID)
+	if node.Options != nil {
+		buf.WriteString(" WITH OPTIONS (")
+		FormatNode(buf, f, node.Options)
+		buf.WriteString(")")
+	}
+}
+
+// CancelJob represents a CANCEL JOB statement.
+type CancelJob struct {
+	ID Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CancelJob) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CANCEL JOB ")
+	FormatNode(buf, f, node.ID)
+}
@@ -5,6 +5,7 @@ package parser
 var keywords = map[string]int{
 	"ACTION":            ACTION,
 	"ADD":               ADD,
+	"ADMIN":             ADMIN,
 	"ALL":               ALL,
 	"ALTER":             ALTER,
 	"ANALYSE":           ANALYSE,
@@ -30,6 +31,7 @@ var keywords = map[string]int{
 	"BY":                BY,
 	"BYTEA":             BYTEA,
 	"BYTES":             BYTES,
+	"CANCEL":            CANCEL,
 	"CASCADE":           CASCADE,
 	"CASE":              CASE,
 	"CAST":              CAST,
@@ -52,6 +54,7 @@ var keywords = map[string]int{
 	"COVERING":          COVERING,
 	"CREATE":            CREATE,
 	"CROSS":             CROSS,
+	"CSV":               CSV,
 	"CUBE":              CUBE,
 	"CURRENT":           CURRENT,
 	"CURRENT_CATALOG":   CURRENT_CATALOG,
@@ -84,6 +87,7 @@ var keywords = map[string]int{
 	"EXECUTE":           EXECUTE,
 	"EXISTS":            EXISTS,
 	"EXPLAIN":           EXPLAIN,
+	"EXPORT":            EXPORT,
 	"EXTRACT":           EXTRACT,
 	"EXTRACT_DURATION":  EXTRACT_DURATION,
 	"FALSE":             FALSE,
@@ -110,7 +114,9 @@ var keywords = map[string]int{
 	"IF":                IF,
 	"IFNULL":            IFNULL,
 	"ILIKE":             ILIKE,
+	"IMPORT":            IMPORT,
 	"IN":                IN,
+	"INCREMENT":         INCREMENT,
 	"INCREMENTAL":       INCREMENTAL,
 	"INDEX":             INDEX,
 	"INDEXES":           INDEXES,
@@ -126,9 +132,14 @@ var keywords = map[string]int{
 	"INTERSECT":         INTERSECT,
 	"INTERVAL":          INTERVAL,
 	"INTO":              INTO,
+	"INVERTED":          INVERTED,
 	"IS":                IS,
 	"ISOLATION":         ISOLATION,
+	"JOB":               JOB,
+	"JOBS":              JOBS,
 	"JOIN":              JOIN,
+	"JSON":              JSON,
+	"JSONB":             JSONB,
 	"KEY":               KEY,
 	"KEYS":              KEYS,
 	"LATERAL":           LATERAL,
@@ -145,7 +156,9 @@ var keywords = map[string]int{
 	"LOCALTIMESTAMP":    LOCALTIMESTAMP,
 	"LOW":               LOW,
 	"MATCH":             MATCH,
+	"MAXVALUE":          MAXVALUE,
 	"MINUTE":            MINUTE,
+	"MINVALUE":          MINVALUE,
 	"MONTH":             MONTH,
 	"NAME":              NAME,
 	"NAMES":             NAMES,
@@ -167,6 +180,7 @@ var keywords = map[string]int{
 	"OID":               OID,
 	"ON":                ON,
 	"ONLY":              ONLY,
+	"OPTION":            OPTION,
 	"OPTIONS":           OPTIONS,
 	"OR":                OR,
 	"ORDER":             ORDER,
@@ -180,6 +194,7 @@ var keywords = map[string]int{
 	"PARTIAL":           PARTIAL,
 	"PARTITION":         PARTITION,
 	"PASSWORD":          PASSWORD,
+	"PAUSE":             PAUSE,
 	"PLACING":           PLACING,
 	"POSITION":          POSITION,
 	"PRECEDING":         PRECEDING,
@@ -187,6 +202,8 @@ var keywords = map[string]int{
 	"PREPARE":           PREPARE,
 	"PRIMARY":           PRIMARY,
 	"PRIORITY":          PRIORITY,
+	"QUERIES":           QUERIES,
+	"QUERY":             QUERY,
 	"RANGE":             RANGE,
 	"READ":              READ,
 	"REAL":              REAL,
@@ -204,9 +221,12 @@ var keywords = map[string]int{
 	"RESET":             RESET,
 	"RESTORE":           RESTORE,
 	"RESTRICT":          RESTRICT,
+	"RESUME":            RESUME,
 	"RETURNING":         RETURNING,
 	"REVOKE":            REVOKE,
 	"RIGHT":             RIGHT,
+	"ROLE":              ROLE,
+	"ROLES":             ROLES,
 	"ROLLBACK":          ROLLBACK,
 	"ROLLUP":            ROLLUP,
 	"ROW":               ROW,
@@ -216,9 +236,11 @@ var keywords = map[string]int{
 	"SEARCH":            SEARCH,
 	"SECOND":            SECOND,
 	"SELECT":            SELECT,
+	"SEQUENCE":          SEQUENCE,
 	"SERIAL":            SERIAL,
 	"SERIALIZABLE":      SERIALIZABLE,
 	"SESSION":           SESSION,
+	"SESSIONS":          SESSIONS,
 	"SESSION_USER":      SESSION_USER,
 	"SET":               SET,
 	"SETTING":           SETTING,
@@ -233,8 +255,10 @@ var keywords = map[string]int{
 	"SPLIT":             SPLIT,
 	"SQL":               SQL,
 	"START":             START,
+	"STATISTICS":        STATISTICS,
 	"STATUS":            STATUS,
 	"STDIN":             STDIN,
+	"STORED":            STORED,
 	"STORING":           STORING,
 	"STRICT":            STRICT,
 	"STRING":            STRING,
@@ -269,6 +293,7 @@ var keywords = map[string]int{
 	"USER":              USER,
 	"USERS":             USERS,
 	"USING":             USING,
+	"UUID":              UUID,
 	"VALID":             VALID,
 	"VALIDATE":          VALIDATE,
 	"VALUE":             VALUE,
@@ -449,6 +449,7 @@ func (expr *ComparisonExpr) normalize(v *normalizeVisitor) TypedExpr {
 		SimilarTo, NotSimilarTo,
 		RegMatch, NotRegMatch,
 		RegIMatch, NotRegIMatch,
+		Contains, ContainedBy, JSONExists, Overlaps,
 		Any, Some, All:
 		if expr.TypedLeft() == DNull || expr.TypedRight() == DNull {
 			return DNull
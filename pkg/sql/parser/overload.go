@@ -156,14 +156,20 @@ func (HomogeneousType) String() string {
 	return "anyelement..."
 }
 
-// VariadicType is a typeList implementation which accepts any number of
-// arguments and matches when each argument is either NULL or of the type
-// typ.
+// VariadicType is a typeList implementation which accepts a fixed number of
+// arguments at the beginning and an arbitrary number of homogenous arguments
+// at the end. Each fixed argument must be either NULL or of the corresponding
+// type in FixedTypes, and each trailing argument must be either NULL or of
+// type VarType.
 type VariadicType struct {
-	Typ Type
+	FixedTypes []Type
+	VarType    Type
 }
 
 func (v VariadicType) match(types []Type) bool {
+	if !v.matchLen(len(types)) {
+		return false
+	}
 	for i := range types {
 		if !v.matchAt(types[i], i) {
 			return false
@@ -173,29 +179,42 @@ func (v VariadicType) match(types []Type) bool {
 }
 
 func (v VariadicType) matchAt(typ Type, i int) bool {
-	return typ == TypeNull || typ.Equivalent(v.Typ)
+	return typ == TypeNull || typ.Equivalent(v.getAt(i))
 }
 
 func (v VariadicType) matchLen(l int) bool {
-	return true
+	return l >= len(v.FixedTypes)
 }
 
 func (v VariadicType) getAt(i int) Type {
-	return v.Typ
+	if i < len(v.FixedTypes) {
+		return v.FixedTypes[i]
+	}
+	return v.VarType
 }
 
 // Length implements the typeList interface.
 func (v VariadicType) Length() int {
-	return 1
+	return len(v.FixedTypes) + 1
 }
 
 // Types implements the typeList interface.
 func (v VariadicType) Types() []Type {
-	return []Type{v.Typ}
+	result := make([]Type, len(v.FixedTypes)+1)
+	copy(result, v.FixedTypes)
+	result[len(result)-1] = v.VarType
+	return result
 }
 
 func (v VariadicType) String() string {
-	return fmt.Sprintf("%s...", v.Typ)
+	var s bytes.Buffer
+	for _, t := range v.FixedTypes {
+		s.WriteString(t.String())
+		s.WriteString(", ")
+	}
+	s.WriteString(v.VarType.String())
+	s.WriteString("...")
+	return s.String()
 }
 
 // unknownReturnType is returned from returnTypers when the arguments provided are
@@ -69,6 +69,12 @@ func TestParse(t *testing.T) {
 		{`CREATE DATABASE IF NOT EXISTS a LC_CTYPE = 'INVALID'`},
 		{`CREATE DATABASE IF NOT EXISTS a TEMPLATE = 'template0' ENCODING = 'UTF8' LC_COLLATE = 'C.UTF-8' LC_CTYPE = 'INVALID'`},
 
+		{`CREATE STATISTICS a ON col1 FROM t`},
+		{`CREATE STATISTICS a ON col1, col2 FROM d.t`},
+		{`EXPLAIN CREATE STATISTICS a ON col1 FROM t`},
+		{`ANALYZE t`},
+		{`ANALYZE d.t`},
+
 		{`CREATE INDEX a ON b (c)`},
 		{`CREATE INDEX a ON b.c (d)`},
 		{`CREATE INDEX ON a (b)`},
@@ -79,6 +85,11 @@ func TestParse(t *testing.T) {
 		{`CREATE UNIQUE INDEX a ON b (c) STORING (d)`},
 		{`CREATE UNIQUE INDEX a ON b (c) INTERLEAVE IN PARENT d (e, f)`},
 		{`CREATE UNIQUE INDEX a ON b.c (d)`},
+		{`CREATE INDEX a ON b (c) WHERE d > 1`},
+		{`CREATE INDEX a ON b (c) STORING (d) WHERE (d > 1) AND (e IS NOT NULL)`},
+		{`CREATE UNIQUE INDEX IF NOT EXISTS a ON b (c) WHERE d = 'foo'`},
+		{`CREATE INVERTED INDEX a ON b (c)`},
+		{`CREATE INVERTED INDEX IF NOT EXISTS a ON b (c) WHERE d > 1`},
 
 		{`CREATE TABLE a ()`},
 		{`CREATE TABLE a (b INT)`},
@@ -93,6 +104,8 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b SERIAL)`},
 		{`CREATE TABLE a (b SMALLSERIAL)`},
 		{`CREATE TABLE a (b BIGSERIAL)`},
+		{`CREATE TABLE a (b JSON)`},
+		{`CREATE TABLE a (b JSONB)`},
 		{`CREATE TABLE a (b INT NULL)`},
 		{`CREATE TABLE a (b INT CONSTRAINT maybe NULL)`},
 		{`CREATE TABLE a (b INT NOT NULL)`},
@@ -110,6 +123,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (a INT DEFAULT 1 CONSTRAINT positive CHECK (a > 0))`},
 		{`CREATE TABLE a (a INT CONSTRAINT one DEFAULT 1 CONSTRAINT positive CHECK (a > 0))`},
 		{`CREATE TABLE a (a INT CONSTRAINT one CHECK (a > 0) CONSTRAINT two CHECK (a < 10))`},
+		{`CREATE TABLE a (a INT, b INT AS (a + 1) STORED)`},
+		{`CREATE TABLE a (a STRING, b STRING NOT NULL AS (lower(a)) STORED)`},
+		{`CREATE TABLE a (a INT, b INT UNIQUE AS (a * 2) STORED FAMILY f)`},
 		// "0" lost quotes previously.
 		{`CREATE TABLE a (b INT, c TEXT, PRIMARY KEY (b, c, "0"))`},
 		{`CREATE TABLE a (b INT, c TEXT, FOREIGN KEY (b) REFERENCES other)`},
@@ -128,6 +144,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT, c INT REFERENCES foo (bar))`},
 		{`CREATE TABLE a (b INT, INDEX (b) STORING (c))`},
 		{`CREATE TABLE a (b INT, c TEXT, INDEX (b ASC, c DESC) STORING (c))`},
+		{`CREATE TABLE a (b INT, c TEXT, INDEX d (b) WHERE c = 'foo')`},
+		{`CREATE TABLE a (b INT[], c STRING(10)[], d DECIMAL(10,2)[], INVERTED INDEX e (b))`},
+		{`CREATE TABLE a (b INT, c TEXT, CONSTRAINT d UNIQUE (b) WHERE c IS NULL)`},
 		{`CREATE TABLE a (b INT, INDEX (b) INTERLEAVE IN PARENT c (d, e))`},
 		{`CREATE TABLE a (b INT, FAMILY (b))`},
 		{`CREATE TABLE a (b INT, c STRING, FAMILY foo (b), FAMILY (c))`},
@@ -152,6 +171,9 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE IF NOT EXISTS a AS SELECT * FROM b UNION VALUES ('one', 1) ORDER BY c LIMIT 5`},
 		{`CREATE TABLE a (b STRING COLLATE "DE")`},
 
+		{`CREATE ROLE foo`},
+		{`CREATE ROLE "test-role"`},
+
 		{`CREATE VIEW a AS SELECT * FROM b`},
 		{`CREATE VIEW a AS SELECT b.* FROM b LIMIT 5`},
 		{`CREATE VIEW a AS (SELECT c, d FROM b WHERE c > 0 ORDER BY c)`},
@@ -160,6 +182,12 @@ func TestParse(t *testing.T) {
 		{`CREATE VIEW a (x, y) AS VALUES (1, 'one'), (2, 'two')`},
 		{`CREATE VIEW a AS TABLE b`},
 
+		{`CREATE SEQUENCE a`},
+		{`CREATE SEQUENCE IF NOT EXISTS a`},
+		{`CREATE SEQUENCE a.b INCREMENT BY 5 START WITH 10`},
+		{`CREATE SEQUENCE a INCREMENT BY -1 MINVALUE -100 MAXVALUE -1`},
+		{`CREATE SEQUENCE a NO MINVALUE NO MAXVALUE`},
+
 		{`DELETE FROM a`},
 		{`DELETE FROM a.b`},
 		{`DELETE FROM a WHERE a = b`},
@@ -196,13 +224,19 @@ func TestParse(t *testing.T) {
 		{`DROP VIEW IF EXISTS a`},
 		{`DROP VIEW a RESTRICT`},
 		{`DROP VIEW IF EXISTS a, b RESTRICT`},
+		{`DROP SEQUENCE a`},
+		{`DROP SEQUENCE IF EXISTS a, b.c CASCADE`},
 		{`DROP VIEW a.b CASCADE`},
 		{`DROP VIEW a, b CASCADE`},
+		{`DROP ROLE a`},
+		{`DROP ROLE a, b`},
+		{`DROP ROLE IF EXISTS a`},
 
 		{`EXPLAIN SELECT 1`},
 		{`EXPLAIN EXPLAIN SELECT 1`},
 		{`EXPLAIN (DEBUG) SELECT 1`},
 		{`EXPLAIN (A, B, C) SELECT 1`},
+		{`EXPLAIN (ANALYZE) SELECT 1`},
 		{`SELECT * FROM [EXPLAIN SELECT 1]`},
 
 		{`HELP count`},
@@ -226,6 +260,7 @@ func TestParse(t *testing.T) {
 		{`SHOW CONSTRAINTS FROM a.b.c`},
 		{`SHOW TABLES FROM a; SHOW COLUMNS FROM b`},
 		{`SHOW USERS`},
+		{`SHOW ROLES`},
 		{`SHOW TESTING_RANGES FROM TABLE d.t`},
 		{`SHOW TESTING_RANGES FROM TABLE t`},
 		{`SHOW TESTING_RANGES FROM INDEX d.t@i`},
@@ -269,6 +304,10 @@ func TestParse(t *testing.T) {
 		{`GRANT SELECT, INSERT ON DATABASE bar TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO foo, bar, baz`},
 		{`GRANT SELECT, INSERT ON DATABASE db1, db2 TO "test-user"`},
+		{`GRANT foo TO root`},
+		{`GRANT foo, bar TO root, baz`},
+		{`GRANT foo TO root WITH ADMIN OPTION`},
+		{`GRANT "test-role" TO "test-user"`},
 
 		// Tables are the default, but can also be specified with
 		// REVOKE x ON TABLE y. However, the stringer does not output TABLE.
@@ -278,6 +317,9 @@ func TestParse(t *testing.T) {
 		{`REVOKE ALL ON DATABASE foo FROM root, test`},
 		{`REVOKE SELECT, INSERT ON DATABASE bar FROM foo, bar, baz`},
 		{`REVOKE SELECT, INSERT ON DATABASE db1, db2 FROM foo, bar, baz`},
+		{`REVOKE foo FROM root`},
+		{`REVOKE foo, bar FROM root, baz`},
+		{`REVOKE ADMIN OPTION FOR foo FROM root`},
 
 		{`INSERT INTO a VALUES (1)`},
 		{`INSERT INTO a.b VALUES (1)`},
@@ -454,6 +496,11 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t WHERE a !~ b`},
 		{`SELECT a FROM t WHERE a ~* c`},
 		{`SELECT a FROM t WHERE a !~* c`},
+		{`SELECT a FROM t WHERE a @> b`},
+		{`SELECT a FROM t WHERE a <@ b`},
+		{`SELECT a FROM t WHERE a && b`},
+		{`SELECT a FROM t WHERE a ? b`},
+		{`SELECT a -> b, a ->> b FROM t`},
 		{`SELECT a FROM t WHERE a BETWEEN b AND c`},
 		{`SELECT a FROM t WHERE a NOT BETWEEN b AND c`},
 		{`SELECT a FROM t WHERE a IS NULL`},
@@ -530,6 +577,12 @@ func TestParse(t *testing.T) {
 		{`SELECT a FROM t1 FULL JOIN t2 USING (a)`},
 		{`SELECT * FROM (t1 WITH ORDINALITY AS o1 CROSS JOIN t2 WITH ORDINALITY AS o2) WITH ORDINALITY AS o3`},
 
+		{`WITH a AS (SELECT 1) SELECT * FROM a`},
+		{`WITH a (x, y) AS (SELECT 1, 2), b AS (SELECT x FROM a) SELECT * FROM b ORDER BY x LIMIT 1`},
+		{`WITH RECURSIVE t (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM t WHERE n < 10) SELECT sum(n) FROM t`},
+		{`SELECT * FROM (WITH a AS (SELECT 1) SELECT * FROM a)`},
+		{`WITH a AS (INSERT INTO t VALUES (1) RETURNING k) SELECT * FROM a`},
+
 		{`SELECT a FROM t1 AS OF SYSTEM TIME '2016-01-01'`},
 		{`SELECT a FROM t1, t2 AS OF SYSTEM TIME '2016-01-01'`},
 
@@ -616,6 +669,7 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE IF EXISTS a ADD COLUMN b INT, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE IF EXISTS a ADD COLUMN IF NOT EXISTS b INT, ADD CONSTRAINT a_idx UNIQUE (a)`},
 		{`ALTER TABLE a ADD b INT FAMILY fam_a`},
+		{`ALTER TABLE a ADD b INT AS (a + 1) STORED`},
 		{`ALTER TABLE a ADD b INT CREATE FAMILY`},
 		{`ALTER TABLE a ADD b INT CREATE FAMILY fam_b`},
 		{`ALTER TABLE a ADD b INT CREATE IF NOT EXISTS FAMILY fam_b`},
@@ -639,6 +693,8 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE a ALTER COLUMN b DROP DEFAULT`},
 		{`ALTER TABLE a ALTER COLUMN b DROP NOT NULL`},
 		{`ALTER TABLE a ALTER b DROP NOT NULL`},
+		{`ALTER TABLE a ALTER COLUMN b TYPE DECIMAL(10,2)`},
+		{`ALTER TABLE a ALTER b TYPE STRING(20)`},
 
 		{`COPY t FROM STDIN`},
 		{`COPY t (a, b, c) FROM STDIN`},
@@ -678,6 +734,24 @@ func TestParse(t *testing.T) {
 		{`RESTORE DATABASE foo, baz FROM 'bar' AS OF SYSTEM TIME '1'`},
 		{`BACKUP foo TO 'bar' WITH OPTIONS ('key1', 'key2'='value')`},
 		{`RESTORE foo FROM 'bar' WITH OPTIONS ('key1', 'key2'='value')`},
+		{`IMPORT TABLE foo CREATE USING 'nodelocal:///some/file' CSV DATA ('path/to/some/file', $1) WITH OPTIONS ('temp'='path/to/temp')`},
+		{`IMPORT TABLE foo (id INT PRIMARY KEY, email STRING, age INT) CSV DATA ('path/to/some/file', $1) WITH OPTIONS ('temp'='path/to/temp')`},
+		{`IMPORT TABLE foo (id INT, email STRING, age INT) CSV DATA ('path/to/some/file', $1) WITH OPTIONS ('comma'=',')`},
+		{`EXPORT INTO CSV 'a' FROM TABLE a`},
+		{`EXPORT INTO CSV 'a' FROM SELECT * FROM a`},
+		{`EXPORT INTO CSV 's3://my/path' WITH OPTIONS ('delimiter'='|') FROM SELECT a, sum(b) FROM c WHERE d = 1 ORDER BY sum(b) DESC LIMIT 10`},
+
+		{`SHOW JOBS`},
+		{`PAUSE JOB 1`},
+		{`PAUSE JOB $1`},
+		{`RESUME JOB 1`},
+		{`RESUME JOB 1 WITH OPTIONS ('encryption_passphrase'='secret')`},
+		{`CANCEL JOB 1`},
+		{`CANCEL JOB (SELECT 1)`},
+		{`SHOW QUERIES`},
+		{`SHOW SESSIONS`},
+		{`CANCEL QUERY 'f6c55bd6f2e3d2f90000000000000001'`},
+		{`CANCEL QUERY $1`},
 		{`SET ROW (1, true, NULL)`},
 	}
 	for _, d := range testData {
@@ -702,6 +776,8 @@ func TestParse2(t *testing.T) {
 	}{
 		{`CREATE DATABASE a WITH ENCODING = 'foo'`,
 			`CREATE DATABASE a ENCODING = 'foo'`},
+		{`CREATE SEQUENCE a INCREMENT 2 START 3 MINVALUE +1`,
+			`CREATE SEQUENCE a INCREMENT BY 2 START WITH 3 MINVALUE 1`},
 		{`CREATE DATABASE a TEMPLATE = template0`,
 			`CREATE DATABASE a TEMPLATE = 'template0'`},
 		{`CREATE DATABASE a TEMPLATE = invalid`,
@@ -711,6 +787,8 @@ func TestParse2(t *testing.T) {
 		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) INTERLEAVE IN PARENT c (d))`,
 			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b) INTERLEAVE IN PARENT c (d))`},
 		{`CREATE INDEX ON a (b) COVERING (c)`, `CREATE INDEX ON a (b) STORING (c)`},
+		{`CREATE TABLE a (b INT, UNIQUE INDEX foo (b) WHERE b > 0)`,
+			`CREATE TABLE a (b INT, CONSTRAINT foo UNIQUE (b) WHERE b > 0)`},
 
 		{`SELECT TIMESTAMP WITHOUT TIME ZONE 'foo'`, `SELECT TIMESTAMP 'foo'`},
 		{`SELECT CAST('foo' AS TIMESTAMP WITHOUT TIME ZONE)`, `SELECT CAST('foo' AS TIMESTAMP)`},
@@ -741,6 +819,8 @@ func TestParse2(t *testing.T) {
 		{`SELECT a FROM t WHERE a = b / c`, `SELECT a FROM t WHERE a = (b / c)`},
 		{`SELECT a FROM t WHERE a = b % c`, `SELECT a FROM t WHERE a = (b % c)`},
 		{`SELECT a FROM t WHERE a = b || c`, `SELECT a FROM t WHERE a = (b || c)`},
+		{`SELECT a FROM t WHERE a->b @> c`, `SELECT a FROM t WHERE (a -> b) @> c`},
+		{`SELECT a FROM t WHERE a->>b = c`, `SELECT a FROM t WHERE (a ->> b) = c`},
 		{`SELECT a FROM t WHERE a = + b`, `SELECT a FROM t WHERE a = (+ b)`},
 		{`SELECT a FROM t WHERE a = - b`, `SELECT a FROM t WHERE a = (- b)`},
 		{`SELECT a FROM t WHERE a = ~ b`, `SELECT a FROM t WHERE a = (~ b)`},
@@ -910,6 +990,18 @@ func TestParse2(t *testing.T) {
 		{"ROLLBACK TO SAVEPOINT foo", "ROLLBACK TRANSACTION TO SAVEPOINT foo"},
 		{"ROLLBACK TRANSACTION TO foo", "ROLLBACK TRANSACTION TO SAVEPOINT foo"},
 		{"ROLLBACK TRANSACTION TO SAVEPOINT foo", "ROLLBACK TRANSACTION TO SAVEPOINT foo"},
+		{`ALTER TABLE a ALTER COLUMN b SET DATA TYPE INT`,
+			`ALTER TABLE a ALTER COLUMN b TYPE INT`},
+
+		{`EXPLAIN ANALYZE SELECT 1`,
+			`EXPLAIN (ANALYZE) SELECT 1`},
+		{`EXPLAIN ANALYSE SELECT 1`,
+			`EXPLAIN (ANALYZE) SELECT 1`},
+		{`EXPLAIN (ANALYSE) SELECT 1`,
+			`EXPLAIN (ANALYZE) SELECT 1`},
+		{`ANALYSE t`,
+			`ANALYZE t`},
+
 		{`DEALLOCATE PREPARE a`,
 			`DEALLOCATE a`},
 		{`DEALLOCATE PREPARE ALL`,
@@ -997,6 +1089,14 @@ SELECT 1 /* hello
 		{`SELECT '1`, `unterminated string
 SELECT '1
        ^
+`},
+		{`CREATE SEQUENCE a CYCLE`, `unimplemented at or near "cycle"
+CREATE SEQUENCE a CYCLE
+                  ^
+`},
+		{`CREATE SEQUENCE a MAXVALUE 9223372036854775808`, `numeric constant out of int64 range at or near "9223372036854775808"
+CREATE SEQUENCE a MAXVALUE 9223372036854775808
+                           ^
 `},
 		{`SELECT * FROM t WHERE k=`,
 			`syntax error at or near "EOF"
@@ -1025,6 +1125,14 @@ CREATE TABLE test (
   foo INT DEFAULT 1 DEFAULT 2
 )
 ^
+`},
+		{`CREATE TABLE test (
+  foo INT AS (1) STORED DEFAULT 2
+)`, `computed column "foo" cannot also have a DEFAULT expression at or near ")"
+CREATE TABLE test (
+  foo INT AS (1) STORED DEFAULT 2
+)
+^
 `},
 		{`CREATE TABLE test (
   foo INT REFERENCES t1 REFERENCES t2
@@ -1195,6 +1303,13 @@ SELECT CAST(1.2+2.3 AS notatype)
 			`syntax error at or near "notatype"
 SELECT ANNOTATE_TYPE(1.2+2.3, notatype)
                               ^
+`,
+		},
+		{
+			`GRANT SELECT, USAGE ON foo TO root`,
+			`not a valid privilege: "usage" at or near "on"
+GRANT SELECT, USAGE ON foo TO root
+                    ^
 `,
 		},
 		{
@@ -38,9 +38,11 @@ var typeBuiltinsHaveUnderscore = map[oid.Oid]struct{}{
 	TypeDate.Oid():        {},
 	TypeDecimal.Oid():     {},
 	TypeInterval.Oid():    {},
+	TypeJSON.Oid():        {},
 	TypeTimestamp.Oid():   {},
 	TypeTimestampTZ.Oid(): {},
 	TypeTuple.Oid():       {},
+	TypeUUID.Oid():        {},
 }
 
 // PGIOBuiltinPrefix returns the string prefix to a type's IO functions. This
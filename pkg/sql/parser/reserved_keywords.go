@@ -84,6 +84,8 @@ var reservedKeywords = map[string]struct{}{
 	"INTO":              {},
 	"IS":                {},
 	"JOIN":              {},
+	"JSON":              {},
+	"JSONB":             {},
 	"LATERAL":           {},
 	"LEADING":           {},
 	"LEAST":             {},
@@ -141,6 +143,7 @@ var reservedKeywords = map[string]struct{}{
 	"UNIQUE":            {},
 	"USER":              {},
 	"USING":             {},
+	"UUID":              {},
 	"VALUES":            {},
 	"VARCHAR":           {},
 	"VARIADIC":          {},
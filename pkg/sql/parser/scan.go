@@ -287,6 +287,10 @@ func (s *Scanner) scan(lval *sqlSymType) {
 			s.pos++
 			lval.id = LESS_EQUALS
 			return
+		case '@': // <@
+			s.pos++
+			lval.id = CONTAINED_BY
+			return
 		}
 		return
 
@@ -303,6 +307,30 @@ func (s *Scanner) scan(lval *sqlSymType) {
 		}
 		return
 
+	case '-':
+		switch s.peek() {
+		case '>': // ->
+			if s.peekN(1) == '>' {
+				// ->>
+				s.pos += 2
+				lval.id = FETCHTEXT
+				return
+			}
+			s.pos++
+			lval.id = FETCHVAL
+			return
+		}
+		return
+
+	case '@':
+		switch s.peek() {
+		case '>': // @>
+			s.pos++
+			lval.id = CONTAINS
+			return
+		}
+		return
+
 	case ':':
 		switch s.peek() {
 		case ':': // ::
@@ -318,6 +346,15 @@ func (s *Scanner) scan(lval *sqlSymType) {
 		}
 		return
 
+	case '&':
+		switch s.peek() {
+		case '&': // &&
+			s.pos++
+			lval.id = AND_AND
+			return
+		}
+		return
+
 	case '|':
 		switch s.peek() {
 		case '|': // ||
@@ -40,6 +40,7 @@ func (*ValuesClause) selectStatement() {}
 
 // Select represents a SelectStatement with an ORDER and/or LIMIT.
 type Select struct {
+	With    *With
 	Select  SelectStatement
 	OrderBy OrderBy
 	Limit   *Limit
@@ -47,11 +48,51 @@ type Select struct {
 
 // Format implements the NodeFormatter interface.
 func (node *Select) Format(buf *bytes.Buffer, f FmtFlags) {
+	if node.With != nil {
+		FormatNode(buf, f, node.With)
+		buf.WriteByte(' ')
+	}
 	FormatNode(buf, f, node.Select)
 	FormatNode(buf, f, node.OrderBy)
 	FormatNode(buf, f, node.Limit)
 }
 
+// With represents a WITH clause, which defines common table expressions that
+// can be referenced by name in the statement that follows.
+type With struct {
+	Recursive bool
+	CTEList   []*CTE
+}
+
+// Format implements the NodeFormatter interface.
+func (node *With) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("WITH ")
+	if node.Recursive {
+		buf.WriteString("RECURSIVE ")
+	}
+	for i, cte := range node.CTEList {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		FormatNode(buf, f, cte)
+	}
+}
+
+// CTE represents a common table expression: a named statement within a WITH
+// clause.
+type CTE struct {
+	Name AliasClause
+	Stmt Statement
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CTE) Format(buf *bytes.Buffer, f FmtFlags) {
+	FormatNode(buf, f, node.Name)
+	buf.WriteString(" AS (")
+	FormatNode(buf, f, node.Stmt)
+	buf.WriteByte(')')
+}
+
 // ParenSelect represents a parenthesized SELECT/UNION/VALUES statement.
 type ParenSelect struct {
 	Select *Select
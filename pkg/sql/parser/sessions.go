@@ -0,0 +1,46 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package parser
+
+import "bytes"
+
+// ShowSessions represents a SHOW SESSIONS statement.
+type ShowSessions struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowSessions) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW SESSIONS")
+}
+
+// ShowQueries represents a SHOW QUERIES statement.
+type ShowQueries struct {
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowQueries) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("SHOW QUERIES")
+}
+
+// CancelQuery represents a CANCEL QUERY statement.
+type CancelQuery struct {
+	ID Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CancelQuery) Format(buf *bytes.Buffer, f FmtFlags) {
+	buf.WriteString("CANCEL QUERY ")
+	FormatNode(buf, f, node.ID)
+}
@@ -269,9 +269,6 @@ func (u *sqlSymUnion) targetList() TargetList {
 func (u *sqlSymUnion) targetListPtr() *TargetList {
     return u.val.(*TargetList)
 }
-func (u *sqlSymUnion) privilegeType() privilege.Kind {
-    return u.val.(privilege.Kind)
-}
 func (u *sqlSymUnion) privilegeList() privilege.List {
     return u.val.(privilege.List)
 }
@@ -344,6 +341,27 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
     }
     return nil
 }
+func (u *sqlSymUnion) int64Val() int64 {
+    return u.val.(int64)
+}
+func (u *sqlSymUnion) seqOpt() SequenceOption {
+    return u.val.(SequenceOption)
+}
+func (u *sqlSymUnion) seqOpts() SequenceOptions {
+    if seqOpts, ok := u.val.(SequenceOptions); ok {
+        return seqOpts
+    }
+    return nil
+}
+func (u *sqlSymUnion) with() *With {
+    if with, ok := u.val.(*With); ok {
+        return with
+    }
+    return nil
+}
+func (u *sqlSymUnion) cte() *CTE {
+    return u.val.(*CTE)
+}
 
 %}
 
@@ -360,29 +378,38 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <Statement> stmt
 
 %type <Statement> alter_table_stmt
+%type <Statement> analyze_stmt
 %type <Statement> backup_stmt
+%type <Statement> cancel_stmt
 %type <Statement> copy_from_stmt
 %type <Statement> create_stmt
 %type <Statement> create_database_stmt
 %type <Statement> create_index_stmt
 %type <Statement> create_table_stmt
 %type <Statement> create_table_as_stmt
+%type <Statement> create_sequence_stmt
+%type <Statement> create_role_stmt
+%type <Statement> create_stats_stmt
 %type <Statement> create_user_stmt
 %type <Statement> create_view_stmt
 %type <Statement> delete_stmt
 %type <Statement> drop_stmt
 %type <Statement> explain_stmt
 %type <Statement> explainable_stmt
+%type <Statement> export_stmt
 %type <Statement> help_stmt
 %type <Statement> prepare_stmt
 %type <Statement> preparable_stmt
 %type <Statement> execute_stmt
 %type <Statement> deallocate_stmt
 %type <Statement> grant_stmt
+%type <Statement> import_stmt
 %type <Statement> insert_stmt
+%type <Statement> pause_stmt
 %type <Statement> release_stmt
 %type <Statement> rename_stmt
 %type <Statement> reset_stmt
+%type <Statement> resume_stmt
 %type <Statement> revoke_stmt
 %type <*Select> select_stmt
 %type <Statement> savepoint_stmt
@@ -399,6 +426,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <KVOption> kv_option
 %type <[]KVOption> kv_option_list opt_with_options
 %type <str> opt_equal_value
+%type <str> import_data_format
 
 %type <*Select> select_no_parens
 %type <SelectStatement> select_clause select_with_parens simple_select values_clause
@@ -542,6 +570,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <AsOfClause> opt_as_of_clause
 
 %type <str> explain_option_name
+%type <str> analyze_target
 %type <[]string> explain_option_list
 
 %type <ColumnType> typename simple_typename const_typename
@@ -582,9 +611,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 
 %type <Expr>  func_application func_expr_common_subexpr
 %type <Expr>  func_expr func_expr_windowless
-%type <empty> common_table_expr
-%type <empty> with_clause opt_with opt_with_clause
-%type <empty> cte_list
+%type <empty> opt_with opt_with_clause
 
 %type <empty> within_group_clause
 %type <Expr> filter_clause
@@ -599,8 +626,16 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %type <TargetList>    targets
 %type <*TargetList> on_privilege_target_clause
 %type <NameList>       grantee_list for_grantee_clause
-%type <privilege.List> privileges privilege_list
-%type <privilege.Kind> privilege
+%type <privilege.List> privileges
+%type <NameList>       privilege_list
+%type <str>            privilege
+%type <bool>           opt_with_admin_option
+
+%type <int64> signed_iconst64
+%type <SequenceOption> sequence_option_elem
+%type <SequenceOptions> sequence_option_list opt_sequence_option_list
+%type <*With> with_clause cte_list
+%type <*CTE> common_table_expr
 
 // Non-keyword token types.
 %token <str>   IDENT SCONST BCONST
@@ -609,6 +644,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %token <str>   TYPECAST TYPEANNOTATE DOT_DOT
 %token <str>   LESS_EQUALS GREATER_EQUALS NOT_EQUALS
 %token <str>   NOT_REGMATCH REGIMATCH NOT_REGIMATCH
+%token <str>   FETCHVAL FETCHTEXT CONTAINS CONTAINED_BY AND_AND
 %token <str>   ERROR
 
 // If you want to make any keyword changes, update the keyword table in
@@ -617,19 +653,19 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 // "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str>   ACTION ADD
+%token <str>   ACTION ADD ADMIN
 %token <str>   ALL ALTER ANALYSE ANALYZE AND ANY ANNOTATE_TYPE ARRAY AS ASC
 %token <str>   ASYMMETRIC AT
 
 %token <str>   BACKUP BEGIN BETWEEN BIGINT BIGSERIAL BIT
 %token <str>   BLOB BOOL BOOLEAN BOTH BY BYTEA BYTES
 
-%token <str>   CASCADE CASE CAST CHAR
+%token <str>   CANCEL CASCADE CASE CAST CHAR
 %token <str>   CHARACTER CHARACTERISTICS CHECK
 %token <str>   CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMIT
 %token <str>   COMMITTED CONCAT CONFLICT CONSTRAINT CONSTRAINTS
 %token <str>   COPY COVERING CREATE
-%token <str>   CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE
+%token <str>   CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE
 %token <str>   CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
 %token <str>   CURRENT_USER CYCLE
 
@@ -638,7 +674,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %token <str>   DISTINCT DO DOUBLE DROP
 
 %token <str>   ELSE ENCODING END ESCAPE EXCEPT
-%token <str>   EXISTS EXECUTE EXPLAIN EXTRACT EXTRACT_DURATION
+%token <str>   EXISTS EXECUTE EXPLAIN EXPORT EXTRACT EXTRACT_DURATION
 
 %token <str>   FALSE FAMILY FETCH FILTER FIRST FLOAT FLOORDIV FOLLOWING FOR
 %token <str>   FORCE_INDEX FOREIGN FROM FULL
@@ -647,12 +683,12 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 
 %token <str>   HAVING HELP HIGH HOUR
 
-%token <str>   INCREMENTAL IF IFNULL ILIKE IN INTERLEAVE
+%token <str>   IF IFNULL ILIKE IMPORT IN INCREMENT INCREMENTAL INTERLEAVE
 %token <str>   INDEX INDEXES INITIALLY
 %token <str>   INNER INSERT INT INT2VECTOR INT8 INT64 INTEGER
-%token <str>   INTERSECT INTERVAL INTO IS ISOLATION
+%token <str>   INTERSECT INTERVAL INTO INVERTED IS ISOLATION
 
-%token <str>   JOIN
+%token <str>   JOB JOBS JOIN JSON JSONB
 
 %token <str>   KEY KEYS
 
@@ -660,28 +696,31 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %token <str>   LEADING LEAST LEFT LEVEL LIKE LIMIT LOCAL
 %token <str>   LOCALTIME LOCALTIMESTAMP LOW LSHIFT
 
-%token <str>   MATCH MINUTE MONTH
+%token <str>   MATCH MAXVALUE MINUTE MINVALUE MONTH
 
 %token <str>   NAN NAME NAMES NATURAL NEXT NO NO_INDEX_JOIN NORMAL
 %token <str>   NOT NOTHING NULL NULLIF
 %token <str>   NULLS NUMERIC
 
-%token <str>   OF OFF OFFSET OID ON ONLY OPTIONS OR
+%token <str>   OF OFF OFFSET OID ON ONLY OPTION OPTIONS OR
 %token <str>   ORDER ORDINALITY OUT OUTER OVER OVERLAPS OVERLAY
 
-%token <str>   PARENT PARTIAL PARTITION PASSWORD PLACING POSITION
+%token <str>   PARENT PARTIAL PARTITION PASSWORD PAUSE PLACING POSITION
 %token <str>   PRECEDING PRECISION PREPARE PRIMARY PRIORITY
 
+%token <str>   QUERIES QUERY
+
 %token <str>   RANGE READ REAL RECURSIVE REF REFERENCES
 %token <str>   REGCLASS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE
 %token <str>   RENAME REPEATABLE
-%token <str>   RELEASE RESET RESTORE RESTRICT RETURNING REVOKE RIGHT ROLLBACK ROLLUP
+%token <str>   RELEASE RESET RESTORE RESTRICT RESUME RETURNING REVOKE RIGHT
+%token <str>   ROLE ROLES ROLLBACK ROLLUP
 %token <str>   ROW ROWS RSHIFT
 
 %token <str>   SAVEPOINT SCATTER SEARCH SECOND SELECT
-%token <str>   SERIAL SERIALIZABLE SESSION SESSION_USER SET SETTING SETTINGS SHOW
+%token <str>   SEQUENCE SERIAL SERIALIZABLE SESSION SESSIONS SESSION_USER SET SETTING SETTINGS SHOW
 %token <str>   SIMILAR SIMPLE SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
-%token <str>   START STATUS STDIN STRICT STRING STORING SUBSTRING
+%token <str>   START STATISTICS STATUS STDIN STRICT STRING STORED STORING SUBSTRING
 %token <str>   SYMMETRIC SYSTEM
 
 %token <str>   TABLE TABLES TEMPLATE TESTING_RANGES TESTING_RELOCATE TEXT THEN
@@ -689,7 +728,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %token <str>   TRUNCATE TYPE
 
 %token <str>   UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN
-%token <str>   UPDATE UPSERT USER USERS USING
+%token <str>   UPDATE UPSERT USER USERS USING UUID
 
 %token <str>   VALID VALIDATE VALUE VALUES VARCHAR VARIADIC VIEW VARYING
 
@@ -718,7 +757,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 %left      AND
 %right     NOT
 %nonassoc  IS                  // IS sets precedence for IS NULL, etc
-%nonassoc  '<' '>' '=' LESS_EQUALS GREATER_EQUALS NOT_EQUALS
+%nonassoc  '<' '>' '=' LESS_EQUALS GREATER_EQUALS NOT_EQUALS CONTAINS CONTAINED_BY '?' AND_AND
 %nonassoc  '~' BETWEEN IN LIKE ILIKE SIMILAR NOT_REGMATCH REGIMATCH NOT_REGIMATCH NOT_LA
 %nonassoc  ESCAPE              // ESCAPE must be just above LIKE/ILIKE/SIMILAR
 %nonassoc  OVERLAPS
@@ -748,7 +787,7 @@ func (u *sqlSymUnion) kvOptions() []KVOption {
 // funny behavior of UNBOUNDED on the SQL standard, though.
 %nonassoc  UNBOUNDED         // ideally should have same precedence as IDENT
 %nonassoc  IDENT NULL PARTITION RANGE ROWS PRECEDING FOLLOWING CUBE ROLLUP
-%left      CONCAT       // multi-character ops
+%left      CONCAT FETCHVAL FETCHTEXT  // multi-character ops
 %left      '|'
 %left      '#'
 %left      '&'
@@ -798,19 +837,25 @@ stmt_list:
 
 stmt:
   alter_table_stmt
+| analyze_stmt
 | backup_stmt
+| cancel_stmt
 | copy_from_stmt
 | create_stmt
 | delete_stmt
 | drop_stmt
 | explain_stmt
+| export_stmt
 | help_stmt
 | prepare_stmt
 | execute_stmt
 | deallocate_stmt
 | grant_stmt
+| import_stmt
 | insert_stmt
+| pause_stmt
 | rename_stmt
+| resume_stmt
 | revoke_stmt
 | savepoint_stmt
 | select_stmt
@@ -907,7 +952,14 @@ alter_table_cmd:
   }
   // ALTER TABLE <name> ALTER [COLUMN] <colname> [SET DATA] TYPE <typename>
   //     [ USING <expression> ]
-| ALTER opt_column name opt_set_data TYPE typename opt_collate_clause alter_using { return unimplemented(sqllex) }
+| ALTER opt_column name opt_set_data TYPE typename opt_collate_clause alter_using
+  {
+    $$.val = &AlterTableAlterColumnType{
+      columnKeyword: $2.bool(),
+      Column: Name($3),
+      ToType: $6.colType(),
+    }
+  }
   // ALTER TABLE <name> ADD CONSTRAINT ...
 | ADD table_constraint opt_validate_behavior
   {
@@ -1059,6 +1111,31 @@ opt_with_options:
   }
 | /* EMPTY */ {}
 
+// IMPORT TABLE t CREATE USING 'schema.sql' CSV DATA ('data.csv', ...)
+// IMPORT TABLE t (a INT PRIMARY KEY, ...) CSV DATA ('data.csv', ...)
+import_stmt:
+  IMPORT TABLE any_name CREATE USING string_or_placeholder import_data_format DATA '(' string_or_placeholder_list ')' opt_with_options
+  {
+    $$.val = &Import{Table: $3.normalizableTableName(), CreateFile: $6.expr(), FileFormat: $7, Files: $10.exprs(), Options: $12.kvOptions()}
+  }
+| IMPORT TABLE any_name '(' table_elem_list ')' import_data_format DATA '(' string_or_placeholder_list ')' opt_with_options
+  {
+    $$.val = &Import{Table: $3.normalizableTableName(), CreateDefs: $5.tblDefs(), FileFormat: $7, Files: $10.exprs(), Options: $12.kvOptions()}
+  }
+
+import_data_format:
+  CSV
+  {
+    $$ = "CSV"
+  }
+
+// EXPORT INTO CSV 'dir' [WITH OPTIONS (...)] FROM SELECT ...
+export_stmt:
+  EXPORT INTO import_data_format string_or_placeholder opt_with_options FROM select_stmt
+  {
+    $$.val = &Export{Query: $7.slct(), FileFormat: $3, File: $4.expr(), Options: $5.kvOptions()}
+  }
+
 copy_from_stmt:
   COPY qualified_name FROM STDIN
   {
@@ -1073,10 +1150,13 @@ copy_from_stmt:
     $$.val = &CopyFrom{Table: $2.normalizableTableName(), Columns: $4.unresolvedNames(), Stdin: true}
   }
 
-// CREATE [DATABASE|INDEX|TABLE|TABLE AS|VIEW]
+// CREATE [DATABASE|INDEX|ROLE|SEQUENCE|STATISTICS|TABLE|TABLE AS|USER|VIEW]
 create_stmt:
   create_database_stmt
 | create_index_stmt
+| create_role_stmt
+| create_sequence_stmt
+| create_stats_stmt
 | create_table_stmt
 | create_table_as_stmt
 | create_user_stmt
@@ -1131,6 +1211,22 @@ drop_stmt:
   {
     $$.val = &DropView{Names: $5.tableNameReferences(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP SEQUENCE table_name_list opt_drop_behavior
+  {
+    $$.val = &DropSequence{Names: $3.tableNameReferences(), IfExists: false, DropBehavior: $4.dropBehavior()}
+  }
+| DROP SEQUENCE IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &DropSequence{Names: $5.tableNameReferences(), IfExists: true, DropBehavior: $6.dropBehavior()}
+  }
+| DROP ROLE name_list
+  {
+    $$.val = &DropRole{Names: $3.nameList(), IfExists: false}
+  }
+| DROP ROLE IF EXISTS name_list
+  {
+    $$.val = &DropRole{Names: $5.nameList(), IfExists: true}
+  }
 
 table_name_list:
   any_name
@@ -1172,6 +1268,10 @@ explain_stmt:
   {
     $$.val = &Explain{Options: $3.strs(), Statement: $5.stmt()}
   }
+| EXPLAIN analyze_target explainable_stmt
+  {
+    $$.val = &Explain{Options: []string{"ANALYZE"}, Statement: $3.stmt()}
+  }
 
 explainable_stmt:
   select_stmt
@@ -1203,6 +1303,21 @@ explain_option_list:
 
 explain_option_name:
   non_reserved_word
+| analyze_target
+  {
+    $$ = "ANALYZE"
+  }
+
+analyze_target:
+  ANALYZE
+| ANALYSE
+
+// ANALYZE <tablename>
+analyze_stmt:
+  analyze_target qualified_name
+  {
+    $$.val = &Analyze{Table: $2.normalizableTableName()}
+  }
 
 // PREPARE <plan_name> [(args, ...)] AS <query>
 prepare_stmt:
@@ -1280,18 +1395,42 @@ deallocate_stmt:
   }
 
 // GRANT privileges ON targets TO grantee_list
+// GRANT role_list TO grantee_list [WITH ADMIN OPTION]
 grant_stmt:
   GRANT privileges ON targets TO grantee_list
   {
     $$.val = &Grant{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| GRANT privilege_list TO grantee_list opt_with_admin_option
+  {
+    $$.val = &GrantRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: $5.bool()}
+  }
 
 // REVOKE privileges ON targets FROM grantee_list
+// REVOKE [ADMIN OPTION FOR] role_list FROM grantee_list
 revoke_stmt:
   REVOKE privileges ON targets FROM grantee_list
   {
     $$.val = &Revoke{Privileges: $2.privilegeList(), Grantees: $6.nameList(), Targets: $4.targetList()}
   }
+| REVOKE privilege_list FROM grantee_list
+  {
+    $$.val = &RevokeRole{Roles: $2.nameList(), Members: $4.nameList(), AdminOption: false}
+  }
+| REVOKE ADMIN OPTION FOR privilege_list FROM grantee_list
+  {
+    $$.val = &RevokeRole{Roles: $5.nameList(), Members: $7.nameList(), AdminOption: true}
+  }
+
+opt_with_admin_option:
+  WITH ADMIN OPTION
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
 
 
 targets:
@@ -1314,48 +1453,35 @@ privileges:
   {
     $$.val = privilege.List{privilege.ALL}
   }
-  | privilege_list { }
+  | privilege_list
+  {
+    privList, err := privilege.ListFromStrings($1.nameList().ToStrings())
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = privList
+  }
 
+// A privilege_list is either a list of privileges or, in GRANT and REVOKE of
+// roles, a list of role names.
 privilege_list:
   privilege
   {
-    $$.val = privilege.List{$1.privilegeType()}
+    $$.val = NameList{Name($1)}
   }
   | privilege_list ',' privilege
   {
-    $$.val = append($1.privilegeList(), $3.privilegeType())
+    $$.val = append($1.nameList(), Name($3))
   }
 
-// This list must match the list of privileges in sql/privilege/privilege.go.
+// The privileges that are reserved keywords are listed explicitly; the others
+// are parsed as names. See privilege.ByName in sql/privilege/privilege.go.
 privilege:
-  CREATE
-  {
-    $$.val = privilege.CREATE
-  }
-| DROP
-  {
-    $$.val = privilege.DROP
-  }
+  name
+| CREATE
 | GRANT
-  {
-    $$.val = privilege.GRANT
-  }
 | SELECT
-  {
-    $$.val = privilege.SELECT
-  }
-| INSERT
-  {
-    $$.val = privilege.INSERT
-  }
-| DELETE
-  {
-    $$.val = privilege.DELETE
-  }
-| UPDATE
-  {
-    $$.val = privilege.UPDATE
-  }
 
 // TODO(marc): this should not be 'name', but should instead be a
 // type just for usernames.
@@ -1687,6 +1813,22 @@ show_stmt:
   {
     $$.val = &ShowUsers{}
   }
+| SHOW ROLES
+  {
+    $$.val = &ShowRoles{}
+  }
+| SHOW JOBS
+  {
+    $$.val = &ShowJobs{}
+  }
+| SHOW QUERIES
+  {
+    $$.val = &ShowQueries{}
+  }
+| SHOW SESSIONS
+  {
+    $$.val = &ShowSessions{}
+  }
 | SHOW TESTING_RANGES FROM TABLE qualified_name
   {
     /* SKIP DOC */
@@ -1698,6 +1840,32 @@ show_stmt:
     $$.val = &ShowRanges{Index: $5.tableWithIdx()}
   }
 
+// PAUSE JOB <id>
+pause_stmt:
+  PAUSE JOB a_expr
+  {
+    $$.val = &PauseJob{ID: $3.expr()}
+  }
+
+// RESUME JOB <id> [WITH OPTIONS (<option> [, ...])]
+resume_stmt:
+  RESUME JOB a_expr opt_with_options
+  {
+    $$.val = &ResumeJob{ID: $3.expr(), Options: $4.kvOptions()}
+  }
+
+// CANCEL JOB <id>
+// CANCEL QUERY <id>
+cancel_stmt:
+  CANCEL JOB a_expr
+  {
+    $$.val = &CancelJob{ID: $3.expr()}
+  }
+| CANCEL QUERY a_expr
+  {
+    $$.val = &CancelQuery{ID: $3.expr()}
+  }
+
 help_stmt:
   HELP unrestricted_name
   {
@@ -1934,6 +2102,10 @@ col_qualification_elem:
   {
     $$.val = &ColumnDefault{Expr: $2.expr()}
   }
+| AS '(' a_expr ')' STORED
+  {
+    $$.val = &ColumnComputedDef{Expr: $3.expr()}
+  }
 | REFERENCES qualified_name opt_name_parens key_match key_actions
  {
     $$.val = &ColumnFKConstraint{
@@ -1943,16 +2115,17 @@ col_qualification_elem:
  }
 
 index_def:
-  INDEX opt_name '(' index_params ')' opt_storing opt_interleave
+  INDEX opt_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &IndexTableDef{
       Name:    Name($2),
       Columns: $4.idxElems(),
       Storing: $6.nameList(),
       Interleave: $7.interleave(),
+      Predicate: $8.expr(),
     }
   }
-| UNIQUE INDEX opt_name '(' index_params ')' opt_storing opt_interleave
+| UNIQUE INDEX opt_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &UniqueConstraintTableDef{
       IndexTableDef: IndexTableDef {
@@ -1960,9 +2133,19 @@ index_def:
         Columns: $5.idxElems(),
         Storing: $7.nameList(),
         Interleave: $8.interleave(),
+        Predicate: $9.expr(),
       },
     }
   }
+| INVERTED INDEX opt_name '(' index_params ')' where_clause
+  {
+    $$.val = &IndexTableDef{
+      Name:     Name($3),
+      Columns:  $5.idxElems(),
+      Inverted: true,
+      Predicate: $7.expr(),
+    }
+  }
 
 family_def:
   FAMILY opt_name '(' name_list ')'
@@ -1994,13 +2177,14 @@ constraint_elem:
       Expr: $3.expr(),
     }
   }
-| UNIQUE '(' index_params ')' opt_storing opt_interleave
+| UNIQUE '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &UniqueConstraintTableDef{
       IndexTableDef: IndexTableDef{
         Columns: $3.idxElems(),
         Storing: $5.nameList(),
         Interleave: $6.interleave(),
+        Predicate: $7.expr(),
       },
     }
   }
@@ -2108,6 +2292,17 @@ truncate_stmt:
     $$.val = &Truncate{Tables: $3.tableNameReferences(), DropBehavior: $4.dropBehavior()}
   }
 
+// CREATE STATISTICS <statname> ON <colname> [, ...] FROM <tablename>
+create_stats_stmt:
+  CREATE STATISTICS name ON name_list FROM qualified_name
+  {
+    $$.val = &CreateStats{
+      Name: Name($3),
+      ColumnNames: $5.nameList(),
+      Table: $7.normalizableTableName(),
+    }
+  }
+
 // CREATE USER
 create_user_stmt:
   CREATE USER name opt_with opt_password
@@ -2115,6 +2310,13 @@ create_user_stmt:
     $$.val = &CreateUser{Name: Name($3), Password: $5.strPtr()}
   }
 
+// CREATE ROLE
+create_role_stmt:
+  CREATE ROLE name
+  {
+    $$.val = &CreateRole{Name: Name($3)}
+  }
+
 opt_password:
   PASSWORD SCONST
   {
@@ -2138,9 +2340,78 @@ create_view_stmt:
 
 // TODO(a-robinson): CREATE OR REPLACE VIEW support (#2971).
 
+// CREATE SEQUENCE
+create_sequence_stmt:
+  CREATE SEQUENCE any_name opt_sequence_option_list
+  {
+    $$.val = &CreateSequence{
+      Name: $3.normalizableTableName(),
+      Options: $4.seqOpts(),
+    }
+  }
+| CREATE SEQUENCE IF NOT EXISTS any_name opt_sequence_option_list
+  {
+    $$.val = &CreateSequence{
+      Name: $6.normalizableTableName(),
+      Options: $7.seqOpts(),
+      IfNotExists: true,
+    }
+  }
+
+opt_sequence_option_list:
+  sequence_option_list
+| /* EMPTY */ {
+    $$.val = SequenceOptions(nil)
+  }
+
+sequence_option_list:
+  sequence_option_elem
+  {
+    $$.val = SequenceOptions{$1.seqOpt()}
+  }
+| sequence_option_list sequence_option_elem
+  {
+    $$.val = append($1.seqOpts(), $2.seqOpt())
+  }
+
+sequence_option_elem:
+  INCREMENT opt_by signed_iconst64
+  {
+    x := $3.int64Val()
+    $$.val = SequenceOption{Name: SeqOptIncrement, IntVal: &x}
+  }
+| MINVALUE signed_iconst64
+  {
+    x := $2.int64Val()
+    $$.val = SequenceOption{Name: SeqOptMinValue, IntVal: &x}
+  }
+| NO MINVALUE
+  {
+    $$.val = SequenceOption{Name: SeqOptMinValue}
+  }
+| MAXVALUE signed_iconst64
+  {
+    x := $2.int64Val()
+    $$.val = SequenceOption{Name: SeqOptMaxValue, IntVal: &x}
+  }
+| NO MAXVALUE
+  {
+    $$.val = SequenceOption{Name: SeqOptMaxValue}
+  }
+| START opt_with signed_iconst64
+  {
+    x := $3.int64Val()
+    $$.val = SequenceOption{Name: SeqOptStart, IntVal: &x}
+  }
+| CYCLE { return unimplemented(sqllex) }
+
+opt_by:
+  BY {}
+| /* EMPTY */ {}
+
 // CREATE INDEX
 create_index_stmt:
-  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+  CREATE opt_unique INDEX opt_name ON qualified_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &CreateIndex{
       Name:    Name($4),
@@ -2149,9 +2420,10 @@ create_index_stmt:
       Columns: $8.idxElems(),
       Storing: $10.nameList(),
       Interleave: $11.interleave(),
+      Predicate: $12.expr(),
     }
   }
-| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave
+| CREATE opt_unique INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' opt_storing opt_interleave where_clause
   {
     $$.val = &CreateIndex{
       Name:        Name($7),
@@ -2161,6 +2433,28 @@ create_index_stmt:
       Columns:     $11.idxElems(),
       Storing:     $13.nameList(),
       Interleave: $14.interleave(),
+      Predicate: $15.expr(),
+    }
+  }
+| CREATE INVERTED INDEX opt_name ON qualified_name '(' index_params ')' where_clause
+  {
+    $$.val = &CreateIndex{
+      Name:     Name($4),
+      Table:    $6.normalizableTableName(),
+      Inverted: true,
+      Columns:  $8.idxElems(),
+      Predicate: $10.expr(),
+    }
+  }
+| CREATE INVERTED INDEX IF NOT EXISTS name ON qualified_name '(' index_params ')' where_clause
+  {
+    $$.val = &CreateIndex{
+      Name:        Name($7),
+      Table:       $9.normalizableTableName(),
+      Inverted:    true,
+      IfNotExists: true,
+      Columns:     $11.idxElems(),
+      Predicate: $13.expr(),
     }
   }
 
@@ -2647,15 +2941,15 @@ select_no_parens:
   }
 | with_clause select_clause
   {
-    $$.val = &Select{Select: $2.selectStmt()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt()}
   }
 | with_clause select_clause sort_clause
   {
-    $$.val = &Select{Select: $2.selectStmt(), OrderBy: $3.orderBy()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy()}
   }
 | with_clause select_clause opt_sort_clause select_limit
   {
-    $$.val = &Select{Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
+    $$.val = &Select{With: $1.with(), Select: $2.selectStmt(), OrderBy: $3.orderBy(), Limit: $4.limit()}
   }
 
 select_clause:
@@ -2758,16 +3052,41 @@ simple_select:
 //
 // Recognizing WITH_LA here allows a CTE to be named TIME or ORDINALITY.
 with_clause:
-WITH cte_list { return unimplemented(sqllex) }
-| WITH_LA cte_list { return unimplemented(sqllex) }
-| WITH RECURSIVE cte_list { return unimplemented(sqllex) }
+  WITH cte_list
+  {
+    $$.val = $2.with()
+  }
+| WITH_LA cte_list
+  {
+    $$.val = $2.with()
+  }
+| WITH RECURSIVE cte_list
+  {
+    with := $3.with()
+    with.Recursive = true
+    $$.val = with
+  }
 
 cte_list:
-  common_table_expr { return unimplemented(sqllex) }
-| cte_list ',' common_table_expr { return unimplemented(sqllex) }
+  common_table_expr
+  {
+    $$.val = &With{CTEList: []*CTE{$1.cte()}}
+  }
+| cte_list ',' common_table_expr
+  {
+    with := $1.with()
+    with.CTEList = append(with.CTEList, $3.cte())
+    $$.val = with
+  }
 
 common_table_expr:
-  name opt_name_list AS '(' preparable_stmt ')' { return unimplemented(sqllex) }
+  name opt_name_list AS '(' preparable_stmt ')'
+  {
+    $$.val = &CTE{
+      Name: AliasClause{Alias: Name($1), Cols: $2.nameList()},
+      Stmt: $5.stmt(),
+    }
+  }
 
 opt_with:
   WITH {}
@@ -3396,6 +3715,18 @@ simple_typename:
   {
     $$.val = intColTypeBigSerial
   }
+| UUID
+  {
+    $$.val = uuidColTypeUUID
+  }
+| JSON
+  {
+    $$.val = jsonColTypeJSON
+  }
+| JSONB
+  {
+    $$.val = jsonColTypeJSONB
+  }
 | OID
   {
     $$.val = oidColTypeOid
@@ -3854,6 +4185,14 @@ a_expr:
   {
     $$.val = &BinaryExpr{Operator: Concat, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr FETCHVAL a_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchVal, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr FETCHTEXT a_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchText, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr LSHIFT a_expr
   {
     $$.val = &BinaryExpr{Operator: LShift, Left: $1.expr(), Right: $3.expr()}
@@ -3874,6 +4213,22 @@ a_expr:
   {
     $$.val = &ComparisonExpr{Operator: NE, Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr CONTAINS a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: Contains, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr CONTAINED_BY a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: ContainedBy, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr AND_AND a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: Overlaps, Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr '?' a_expr
+  {
+    $$.val = &ComparisonExpr{Operator: JSONExists, Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr AND a_expr
   {
     $$.val = &AndExpr{Left: $1.expr(), Right: $3.expr()}
@@ -4115,6 +4470,14 @@ b_expr:
   {
     $$.val = &BinaryExpr{Operator: Concat, Left: $1.expr(), Right: $3.expr()}
   }
+| b_expr FETCHVAL b_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchVal, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr FETCHTEXT b_expr
+  {
+    $$.val = &BinaryExpr{Operator: JSONFetchText, Left: $1.expr(), Right: $3.expr()}
+  }
 | b_expr LSHIFT b_expr
   {
     $$.val = &BinaryExpr{Operator: LShift, Left: $1.expr(), Right: $3.expr()}
@@ -4135,6 +4498,22 @@ b_expr:
   {
     $$.val = &ComparisonExpr{Operator: NE, Left: $1.expr(), Right: $3.expr()}
   }
+| b_expr CONTAINS b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: Contains, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr CONTAINED_BY b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: ContainedBy, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr AND_AND b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: Overlaps, Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr '?' b_expr
+  {
+    $$.val = &ComparisonExpr{Operator: JSONExists, Left: $1.expr(), Right: $3.expr()}
+  }
 | b_expr IS DISTINCT FROM b_expr %prec IS
   {
     $$.val = &ComparisonExpr{Operator: IsDistinctFrom, Left: $1.expr(), Right: $5.expr()}
@@ -5018,7 +5397,10 @@ opt_name_list:
   {
     $$.val = $2.nameList()
   }
-| /* EMPTY */ {}
+| /* EMPTY */
+  {
+    $$.val = NameList(nil)
+  }
 
 // The production for a qualified func_name has to exactly match the production
 // for a qualified name, because we cannot tell which we are parsing until
@@ -5088,6 +5470,19 @@ signed_iconst:
     $$.val = &NumVal{Value: constant.UnaryOp(token.SUB, $2.numVal().Value, 0)}
   }
 
+// signed_iconst64 is a variant of signed_iconst which only accepts (signed)
+// integer literals that fit in an int64.
+signed_iconst64:
+  signed_iconst
+  {
+    val, err := $1.numVal().AsInt64()
+    if err != nil {
+      sqllex.Error(err.Error())
+      return 1
+    }
+    $$.val = val
+  }
+
 interval:
   const_interval SCONST opt_interval
   {
@@ -5176,12 +5571,14 @@ unrestricted_name:
 unreserved_keyword:
   ACTION
 | ADD
+| ADMIN
 | ALTER
 | AT
 | BACKUP
 | BEGIN
 | BLOB
 | BY
+| CANCEL
 | CASCADE
 | CLUSTER
 | COLUMNS
@@ -5191,6 +5588,7 @@ unreserved_keyword:
 | CONSTRAINTS
 | COPY
 | COVERING
+| CSV
 | CUBE
 | CURRENT
 | CYCLE
@@ -5205,6 +5603,7 @@ unreserved_keyword:
 | ENCODING
 | EXECUTE
 | EXPLAIN
+| EXPORT
 | FILTER
 | FIRST
 | FOLLOWING
@@ -5213,12 +5612,17 @@ unreserved_keyword:
 | HELP
 | HIGH
 | HOUR
+| IMPORT
+| INCREMENT
 | INCREMENTAL
 | INDEXES
 | INSERT
 | INT2VECTOR
 | INTERLEAVE
+| INVERTED
 | ISOLATION
+| JOB
+| JOBS
 | KEY
 | KEYS
 | LC_COLLATE
@@ -5227,7 +5631,9 @@ unreserved_keyword:
 | LOCAL
 | LOW
 | MATCH
+| MAXVALUE
 | MINUTE
+| MINVALUE
 | MONTH
 | NAMES
 | NAN
@@ -5239,6 +5645,7 @@ unreserved_keyword:
 | OF
 | OFF
 | OID
+| OPTION
 | OPTIONS
 | ORDINALITY
 | OVER
@@ -5246,9 +5653,12 @@ unreserved_keyword:
 | PARTIAL
 | PARTITION
 | PASSWORD
+| PAUSE
 | PRECEDING
 | PREPARE
 | PRIORITY
+| QUERIES
+| QUERY
 | RANGE
 | READ
 | RECURSIVE
@@ -5264,19 +5674,25 @@ unreserved_keyword:
 | RESET
 | RESTORE
 | RESTRICT
+| RESUME
 | REVOKE
+| ROLE
+| ROLES
 | ROLLBACK
 | ROLLUP
 | ROWS
 | SETTING
 | SETTINGS
+| STATISTICS
 | STATUS
 | SAVEPOINT
 | SCATTER
 | SEARCH
 | SECOND
+| SEQUENCE
 | SERIALIZABLE
 | SESSION
+| SESSIONS
 | SET
 | SHOW
 | SIMPLE
@@ -5284,6 +5700,7 @@ unreserved_keyword:
 | SQL
 | START
 | STDIN
+| STORED
 | STORING
 | STRICT
 | SPLIT
@@ -5350,6 +5767,8 @@ col_name_keyword:
 | INT64
 | INTEGER
 | INTERVAL
+| JSON
+| JSONB
 | LEAST
 | NAME
 | NULLIF
@@ -5370,6 +5789,7 @@ col_name_keyword:
 | TIMESTAMPTZ
 | TREAT
 | TRIM
+| UUID
 | VALUES
 | VARCHAR
 
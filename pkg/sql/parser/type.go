@@ -81,6 +81,10 @@ var (
 	TypeCollatedString Type = TCollatedString{}
 	// TypeBytes is the type of a DBytes. Can be compared with ==.
 	TypeBytes Type = tBytes{}
+	// TypeUUID is the type of a DUuid. Can be compared with ==.
+	TypeUUID Type = tUUID{}
+	// TypeJSON is the type of a DJSON. Can be compared with ==.
+	TypeJSON Type = tJSON{}
 	// TypeDate is the type of a DDate. Can be compared with ==.
 	TypeDate Type = tDate{}
 	// TypeTimestamp is the type of a DTimestamp. Can be compared with ==.
@@ -143,6 +147,8 @@ var (
 		TypeTimestamp,
 		TypeTimestampTZ,
 		TypeInterval,
+		TypeUUID,
+		TypeJSON,
 		TypeOid,
 	}
 )
@@ -170,6 +176,7 @@ var OidToType = map[oid.Oid]Type{
 	oid.T_int8:         TypeInt,
 	oid.T_int2vector:   TypeIntVector,
 	oid.T_interval:     TypeInterval,
+	oid.T_jsonb:        TypeJSON,
 	oid.T_name:         TypeName,
 	oid.T_numeric:      TypeDecimal,
 	oid.T_oid:          TypeOid,
@@ -186,6 +193,7 @@ var OidToType = map[oid.Oid]Type{
 	oid.T_text:         TypeString,
 	oid.T_timestamp:    TypeTimestamp,
 	oid.T_timestamptz:  TypeTimestampTZ,
+	oid.T_uuid:         TypeUUID,
 	oid.T_varchar:      typeVarChar,
 }
 
@@ -337,6 +345,26 @@ func (tBytes) Oid() oid.Oid                { return oid.T_bytea }
 func (tBytes) SQLName() string             { return "bytea" }
 func (tBytes) IsAmbiguous() bool           { return false }
 
+type tUUID struct{}
+
+func (tUUID) String() string              { return "uuid" }
+func (tUUID) Equivalent(other Type) bool  { return UnwrapType(other) == TypeUUID || other == TypeAny }
+func (tUUID) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeUUID }
+func (tUUID) Size() (uintptr, bool)       { return unsafe.Sizeof(DUuid{}), fixedSize }
+func (tUUID) Oid() oid.Oid                { return oid.T_uuid }
+func (tUUID) SQLName() string             { return "uuid" }
+func (tUUID) IsAmbiguous() bool           { return false }
+
+type tJSON struct{}
+
+func (tJSON) String() string              { return "jsonb" }
+func (tJSON) Equivalent(other Type) bool  { return UnwrapType(other) == TypeJSON || other == TypeAny }
+func (tJSON) FamilyEqual(other Type) bool { return UnwrapType(other) == TypeJSON }
+func (tJSON) Size() (uintptr, bool)       { return unsafe.Sizeof(DJSON{}), variableSize }
+func (tJSON) Oid() oid.Oid                { return oid.T_jsonb }
+func (tJSON) SQLName() string             { return "jsonb" }
+func (tJSON) IsAmbiguous() bool           { return false }
+
 type tDate struct{}
 
 func (tDate) String() string              { return "date" }
@@ -518,11 +546,20 @@ func (TArray) Size() (uintptr, bool) {
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
 var oidToArrayOid = map[oid.Oid]oid.Oid{
-	oid.T_int2: oid.T__int2,
-	oid.T_int4: oid.T__int4,
-	oid.T_int8: oid.T__int8,
-	oid.T_text: oid.T__text,
-	oid.T_name: oid.T__name,
+	oid.T_bool:        oid.T__bool,
+	oid.T_bytea:       oid.T__bytea,
+	oid.T_date:        oid.T__date,
+	oid.T_float8:      oid.T__float8,
+	oid.T_int2:        oid.T__int2,
+	oid.T_int4:        oid.T__int4,
+	oid.T_int8:        oid.T__int8,
+	oid.T_interval:    oid.T__interval,
+	oid.T_name:        oid.T__name,
+	oid.T_numeric:     oid.T__numeric,
+	oid.T_text:        oid.T__text,
+	oid.T_timestamp:   oid.T__timestamp,
+	oid.T_timestamptz: oid.T__timestamptz,
+	oid.T_uuid:        oid.T__uuid,
 }
 
 // Oid implements the Type interface.
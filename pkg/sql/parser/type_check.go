@@ -221,7 +221,7 @@ func (expr *CastExpr) TypeCheck(ctx *SemaContext, _ Type) (TypedExpr, error) {
 			// precision), the CastExpr becomes a no-op and can be elided.
 			switch expr.Type.(type) {
 			case *BoolColType, *DateColType, *TimestampColType, *TimestampTZColType,
-				*IntervalColType, *BytesColType:
+				*IntervalColType, *BytesColType, *UUIDColType, *JSONColType:
 				return expr.Expr.TypeCheck(ctx, returnType)
 			}
 		}
@@ -779,6 +779,14 @@ func (d *DCollatedString) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) {
 // identity function for Datum.
 func (d *DBytes) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DUuid) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DJSON) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DDate) TypeCheck(_ *SemaContext, _ Type) (TypedExpr, error) { return d, nil }
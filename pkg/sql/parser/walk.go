@@ -493,6 +493,12 @@ func (expr *DBool) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DBytes) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DUuid) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DJSON) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DDate) Walk(_ Visitor) Expr { return expr }
 
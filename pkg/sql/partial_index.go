@@ -0,0 +1,238 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// setPartialIndexPredicate makes idx a partial index of the table restricted
+// to the rows satisfying predicate, after verifying that predicate is valid.
+// It does nothing if predicate is nil.
+func setPartialIndexPredicate(
+	desc *sqlbase.TableDescriptor,
+	idx *sqlbase.IndexDescriptor,
+	predicate parser.Expr,
+	searchPath parser.SearchPath,
+) error {
+	if predicate == nil {
+		return nil
+	}
+	idx.Predicate = parser.Serialize(predicate)
+	return sqlbase.ValidatePartialIndexPredicate(desc, idx, searchPath)
+}
+
+// partialIndexUsable returns whether the scan can use the index, that is, if
+// the index is not a partial index or if the filter of the scan implies the
+// predicate of the partial index.
+func (p *planner) partialIndexUsable(
+	ctx context.Context, s *scanNode, idx *sqlbase.IndexDescriptor,
+) (bool, error) {
+	if !idx.IsPartial() {
+		return true, nil
+	}
+	if s.filter == nil {
+		return false, nil
+	}
+	expr, err := parser.ParseExpr(idx.Predicate)
+	if err != nil {
+		return false, err
+	}
+	// The predicate is typed against the columns of the scan so that it can be
+	// compared with the filter. We use a separate IndexedVarHelper so that the
+	// columns of the predicate are not marked as needed by the scan.
+	sources := multiSourceInfo{newSourceInfoForSingleTable(anonymousTable, s.resultColumns)}
+	ivarHelper := parser.MakeIndexedVarHelper(s, len(s.cols))
+	pred, err := p.analyzeExpr(ctx, expr, sources, ivarHelper, parser.TypeBool, false, "")
+	if err != nil {
+		// The predicate was valid when the index was created, so this can only
+		// happen if the scan does not expose all the columns of the table.
+		return false, nil
+	}
+	return filterImpliesPredicate(&p.evalCtx, s.filter, pred), nil
+}
+
+// filterImpliesPredicate returns whether every row that satisfies filter also
+// satisfies pred.
+//
+// The check is conservative: every conjunct of pred must either appear as is
+// among the conjuncts of filter, or be implied by a comparison of the same
+// column with a constant in filter (e.g. `a > 10` implies `a > 5` and `a IS NOT
+// NULL`).
+func filterImpliesPredicate(evalCtx *parser.EvalContext, filter, pred parser.TypedExpr) bool {
+	filterExprs := splitAndExpr(evalCtx, filter, nil)
+	for _, e := range splitAndExpr(evalCtx, pred, nil) {
+		if e == parser.DBoolTrue {
+			continue
+		}
+		implied := false
+		for _, f := range filterExprs {
+			if exprImplies(evalCtx, f, e) {
+				implied = true
+				break
+			}
+		}
+		if !implied {
+			return false
+		}
+	}
+	return true
+}
+
+// exprImplies returns whether every row that satisfies a also satisfies b.
+func exprImplies(evalCtx *parser.EvalContext, a, b parser.TypedExpr) bool {
+	if a.String() == b.String() {
+		return true
+	}
+	aVar, aOp, aVal, ok := constComparison(a)
+	if !ok {
+		return false
+	}
+	bVar, bOp, bVal, ok := constComparison(b)
+	if !ok || aVar.Idx != bVar.Idx || aVal == parser.DNull {
+		return false
+	}
+	if bOp == parser.IsNot && bVal == parser.DNull {
+		// Comparisons with non-NULL values are never true for NULL.
+		switch aOp {
+		case parser.EQ, parser.NE, parser.LT, parser.LE, parser.GT, parser.GE, parser.In:
+			return true
+		}
+		return false
+	}
+	if bVal == parser.DNull {
+		return false
+	}
+
+	switch aOp {
+	case parser.EQ:
+		return compareConsts(evalCtx, aVal, bOp, bVal)
+	case parser.In:
+		// Every value of the tuple must satisfy b.
+		tuple, ok := aVal.(*parser.DTuple)
+		if !ok || len(tuple.D) == 0 {
+			return false
+		}
+		for _, d := range tuple.D {
+			if d == parser.DNull || !compareConsts(evalCtx, d, bOp, bVal) {
+				return false
+			}
+		}
+		return true
+	case parser.GT, parser.GE:
+		// a is a lower bound; b must be a looser lower bound.
+		switch bOp {
+		case parser.GT, parser.NE:
+			if aOp == parser.GT {
+				return compareConsts(evalCtx, aVal, parser.GE, bVal)
+			}
+			return compareConsts(evalCtx, aVal, parser.GT, bVal)
+		case parser.GE:
+			return compareConsts(evalCtx, aVal, parser.GE, bVal)
+		}
+	case parser.LT, parser.LE:
+		// a is an upper bound; b must be a looser upper bound.
+		switch bOp {
+		case parser.LT, parser.NE:
+			if aOp == parser.LT {
+				return compareConsts(evalCtx, aVal, parser.LE, bVal)
+			}
+			return compareConsts(evalCtx, aVal, parser.LT, bVal)
+		case parser.LE:
+			return compareConsts(evalCtx, aVal, parser.LE, bVal)
+		}
+	}
+	return false
+}
+
+// constComparison decomposes an expression of the form `var op constant` or
+// `constant op var`, returning it as `var op constant`.
+func constComparison(
+	e parser.TypedExpr,
+) (*parser.IndexedVar, parser.ComparisonOperator, parser.Datum, bool) {
+	cmp, ok := e.(*parser.ComparisonExpr)
+	if !ok {
+		return nil, 0, nil, false
+	}
+	if v, ok := cmp.Left.(*parser.IndexedVar); ok {
+		if d, ok := cmp.Right.(parser.Datum); ok {
+			return v, cmp.Operator, d, true
+		}
+		return nil, 0, nil, false
+	}
+	v, ok := cmp.Right.(*parser.IndexedVar)
+	if !ok {
+		return nil, 0, nil, false
+	}
+	d, ok := cmp.Left.(parser.Datum)
+	if !ok {
+		return nil, 0, nil, false
+	}
+	switch cmp.Operator {
+	case parser.EQ, parser.NE:
+		return v, cmp.Operator, d, true
+	case parser.LT:
+		return v, parser.GT, d, true
+	case parser.LE:
+		return v, parser.GE, d, true
+	case parser.GT:
+		return v, parser.LT, d, true
+	case parser.GE:
+		return v, parser.LE, d, true
+	}
+	return nil, 0, nil, false
+}
+
+// compareConsts returns whether `left op right` is true for the non-NULL
+// constants left and right. It returns false if the constants cannot be
+// compared.
+func compareConsts(
+	evalCtx *parser.EvalContext, left parser.Datum, op parser.ComparisonOperator, right parser.Datum,
+) bool {
+	if op == parser.In {
+		tuple, ok := right.(*parser.DTuple)
+		if !ok {
+			return false
+		}
+		for _, d := range tuple.D {
+			if d != parser.DNull && compareConsts(evalCtx, left, parser.EQ, d) {
+				return true
+			}
+		}
+		return false
+	}
+	if !left.ResolvedType().Equivalent(right.ResolvedType()) {
+		return false
+	}
+	c := left.Compare(evalCtx, right)
+	switch op {
+	case parser.EQ:
+		return c == 0
+	case parser.NE:
+		return c != 0
+	case parser.LT:
+		return c < 0
+	case parser.LE:
+		return c <= 0
+	case parser.GT:
+		return c > 0
+	case parser.GE:
+		return c >= 0
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestFilterImpliesPredicate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		filter   string
+		pred     string
+		expected bool
+	}{
+		{`c`, `c`, true},
+		{`c AND a > 1`, `c`, true},
+		{`c OR d`, `c`, false},
+		{`a = 1`, `b = 1`, false},
+		{`a = 5`, `a > 1`, true},
+		{`a = 1`, `a > 1`, false},
+		{`a > 5`, `a > 1`, true},
+		{`a > 5`, `a >= 5`, true},
+		{`a >= 5`, `a > 5`, false},
+		{`a >= 6`, `a > 5`, true},
+		{`a < 5`, `a <= 5`, true},
+		{`a < 5`, `a < 3`, false},
+		{`a < 5`, `a != 5`, true},
+		{`a <= 5`, `a != 5`, false},
+		{`a IN (1, 2, 3)`, `a < 4`, true},
+		{`a IN (1, 2, 3)`, `a < 3`, false},
+		{`a = 2`, `a IN (1, 2)`, true},
+		{`a > 1`, `a IS NOT NULL`, true},
+		{`a > 1`, `b IS NOT NULL`, false},
+		{`a > 1 AND i = 'foo'`, `a > 0 AND i IS NOT NULL`, true},
+		{`a > 1`, `a > 0 AND b > 0`, false},
+		{`i = 'foo'`, `i = 'foo'`, true},
+		{`i = 'foo'`, `i != 'bar'`, true},
+	}
+	for _, d := range testData {
+		t.Run(d.filter+"=>"+d.pred, func(t *testing.T) {
+			evalCtx := &parser.EvalContext{}
+			sel := makeSelectNode(t)
+			filter := parseAndNormalizeExpr(t, evalCtx, d.filter, sel)
+			pred := parseAndNormalizeExpr(t, evalCtx, d.pred, sel)
+			if res := filterImpliesPredicate(evalCtx, filter, pred); res != d.expected {
+				t.Errorf("%s => %s: expected %t, but found %t", d.filter, d.pred, d.expected, res)
+			}
+		})
+	}
+}
@@ -56,6 +56,7 @@ var pgCatalog = virtualSchema{
 		pgCatalogAmTable,
 		pgCatalogAttrDefTable,
 		pgCatalogAttributeTable,
+		pgCatalogAuthMembersTable,
 		pgCatalogClassTable,
 		pgCatalogCollationTable,
 		pgCatalogConstraintTable,
@@ -220,11 +221,36 @@ CREATE TABLE pg_catalog.pg_attribute (
 }
 
 var (
-	relKindTable = parser.NewDString("r")
-	relKindIndex = parser.NewDString("i")
-	relKindView  = parser.NewDString("v")
+	relKindTable    = parser.NewDString("r")
+	relKindIndex    = parser.NewDString("i")
+	relKindView     = parser.NewDString("v")
+	relKindSequence = parser.NewDString("S")
 )
 
+// See: https://www.postgresql.org/docs/9.6/static/catalog-pg-auth-members.html.
+var pgCatalogAuthMembersTable = virtualSchemaTable{
+	schema: `
+CREATE TABLE pg_catalog.pg_auth_members (
+	roleid OID,
+	member OID,
+	grantor OID,
+	admin_option BOOL
+);
+`,
+	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
+		h := makeOidHasher()
+		return forEachRoleMembership(ctx, p,
+			func(role, member string, isAdmin bool) error {
+				return addRow(
+					h.UserOid(role),                         // roleid
+					h.UserOid(member),                       // member
+					parser.DNull,                            // grantor
+					parser.MakeDBool(parser.DBool(isAdmin)), // admin_option
+				)
+			})
+	},
+}
+
 // See: https://www.postgresql.org/docs/9.6/static/catalog-pg-class.html.
 var pgCatalogClassTable = virtualSchemaTable{
 	schema: `
@@ -265,6 +291,8 @@ CREATE TABLE pg_catalog.pg_class (
 			if table.IsView() {
 				// The only difference between tables and views is the relkind column.
 				relKind = relKindView
+			} else if table.IsSequence() {
+				relKind = relKindSequence
 			}
 			if err := addRow(
 				h.TableOid(db, table),       // oid
@@ -1130,10 +1158,10 @@ CREATE TABLE pg_catalog.pg_proc (
 
 				var argmodes parser.Datum
 				var variadicType parser.Datum
-				switch argTypes.(type) {
+				switch v := argTypes.(type) {
 				case parser.VariadicType:
 					argmodes = proArgModeVariadic
-					argType := argTypes.Types()[0]
+					argType := v.VarType
 					oid := argType.Oid()
 					variadicType = parser.NewDOid(parser.DInt(oid))
 				case parser.HomogeneousType:
@@ -1230,24 +1258,32 @@ CREATE TABLE pg_catalog.pg_roles (
 		// need to do the same. This shouldn't be an issue, because pg_roles doesn't
 		// include sensitive information such as password hashes.
 		h := makeOidHasher()
-		return forEachUser(ctx, p,
-			func(username string) error {
-				isRoot := parser.DBool(username == security.RootUser)
-				return addRow(
-					h.UserOid(username),           // oid
-					parser.NewDName(username),     // rolname
-					parser.MakeDBool(isRoot),      // rolsuper
-					parser.MakeDBool(false),       // rolinherit
-					parser.MakeDBool(isRoot),      // rolcreaterole
-					parser.MakeDBool(isRoot),      // rolcreatedb
-					parser.MakeDBool(false),       // rolcatupdate
-					parser.MakeDBool(true),        // rolcanlogin
-					negOneVal,                     // rolconnlimit
-					parser.NewDString("********"), // rolpassword
-					parser.DNull,                  // rolvaliduntil
-					parser.NewDString("{}"),       // rolconfig
-				)
-			})
+		addRole := func(name string, canLogin bool) error {
+			isRoot := parser.DBool(name == security.RootUser)
+			return addRow(
+				h.UserOid(name),                          // oid
+				parser.NewDName(name),                    // rolname
+				parser.MakeDBool(isRoot),                 // rolsuper
+				parser.MakeDBool(true),                   // rolinherit
+				parser.MakeDBool(isRoot),                 // rolcreaterole
+				parser.MakeDBool(isRoot),                 // rolcreatedb
+				parser.MakeDBool(false),                  // rolcatupdate
+				parser.MakeDBool(parser.DBool(canLogin)), // rolcanlogin
+				negOneVal,                                // rolconnlimit
+				parser.NewDString("********"),            // rolpassword
+				parser.DNull,                             // rolvaliduntil
+				parser.NewDString("{}"),                  // rolconfig
+			)
+		}
+		// Users and roles share the same namespace; only users can log in.
+		if err := forEachUser(ctx, p, func(username string) error {
+			return addRole(username, true /* canLogin */)
+		}); err != nil {
+			return err
+		}
+		return forEachRole(ctx, p, func(role string) error {
+			return addRole(role, false /* canLogin */)
+		})
 	},
 }
 
@@ -1326,7 +1362,7 @@ CREATE TABLE pg_catalog.pg_tables (
 `,
 	populate: func(ctx context.Context, p *planner, addRow func(...parser.Datum) error) error {
 		return forEachTableDesc(ctx, p, func(db *sqlbase.DatabaseDescriptor, table *sqlbase.TableDescriptor) error {
-			if table.IsView() {
+			if !table.IsTable() {
 				return nil
 			}
 			return addRow(
@@ -1541,6 +1577,8 @@ var datumToTypeCategory = map[reflect.Type]*parser.DString{
 	reflect.TypeOf(parser.TypeTimestampTZ): typCategoryDateTime,
 	reflect.TypeOf(parser.TypeTuple):       typCategoryPseudo,
 	reflect.TypeOf(parser.TypeTable):       typCategoryPseudo,
+	reflect.TypeOf(parser.TypeUUID):        typCategoryUserDefined,
+	reflect.TypeOf(parser.TypeJSON):        typCategoryUserDefined,
 	reflect.TypeOf(parser.TypeOid):         typCategoryNumeric,
 }
 
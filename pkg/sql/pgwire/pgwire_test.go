@@ -44,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 )
@@ -1601,3 +1602,63 @@ func TestPGWireAuth(t *testing.T) {
 		}
 	})
 }
+
+// redirectDialer is a lib/pq Dialer that dials the address requested by the
+// driver for the first connection, and redirectAddr for all later ones. lib/pq
+// sends cancel requests on new connections, so this lets a test send them to
+// another node than the one running the session.
+type redirectDialer struct {
+	redirectAddr string
+	dialed       bool
+}
+
+func (d *redirectDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialTimeout(network, address, 0)
+}
+
+func (d *redirectDialer) DialTimeout(
+	network, address string, timeout time.Duration,
+) (net.Conn, error) {
+	if d.dialed {
+		address = d.redirectAddr
+	}
+	d.dialed = true
+	return net.DialTimeout(network, address, timeout)
+}
+
+// TestPGWireCancelRequest verifies that canceling the context of a query run
+// through lib/pq, which sends a CancelRequest with the session's backend key,
+// cancels the query, including when the request lands on another node.
+func TestPGWireCancelRequest(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	tc := testcluster.StartTestCluster(t, 2, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(context.TODO())
+
+	pgURL, cleanupFn := sqlutils.PGUrl(
+		t, tc.Server(0).ServingAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+
+	for i, name := range []string{"local", "remote"} {
+		t.Run(name, func(t *testing.T) {
+			dialer := &redirectDialer{redirectAddr: tc.Server(i).ServingAddr()}
+			conn, err := pq.DialOpen(dialer, pgURL.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			rows, err := conn.(driver.QueryerContext).QueryContext(
+				ctx, `SELECT count(*) FROM generate_series(1, 1000000000000)`, nil,
+			)
+			if err == nil {
+				_ = rows.Close()
+			}
+			if !testutils.IsError(err, "query execution canceled") {
+				t.Fatalf("expected query to be canceled, got %v", err)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -60,8 +61,9 @@ var (
 )
 
 const (
-	version30  = 196608
-	versionSSL = 80877103
+	version30     = 196608
+	versionCancel = 80877102
+	versionSSL    = 80877103
 )
 
 const (
@@ -86,6 +88,11 @@ var (
 	sslUnsupported = []byte{'N'}
 )
 
+// CancelQueryByKeyFn cancels the queries of the session identified by a
+// backend key (see sql.Session.BackendKey()). The session may run on another
+// node than the one that received the cancel request.
+type CancelQueryByKeyFn func(ctx context.Context, nodeID roachpb.NodeID, secret int32) error
+
 // cancelChanMap keeps track of channels that are closed after the associated
 // cancellation function has been called and the cancellation has taken place.
 type cancelChanMap map[chan struct{}]context.CancelFunc
@@ -96,6 +103,8 @@ type Server struct {
 	cfg        *base.Config
 	executor   *sql.Executor
 
+	cancelQueryByKey CancelQueryByKeyFn
+
 	metrics ServerMetrics
 
 	mu struct {
@@ -154,12 +163,14 @@ func MakeServer(
 	internalMemMetrics *sql.MemoryMetrics,
 	parentMemoryMonitor *mon.MemoryMonitor,
 	histogramWindow time.Duration,
+	cancelQueryByKey CancelQueryByKeyFn,
 ) *Server {
 	server := &Server{
-		AmbientCtx: ambientCtx,
-		cfg:        cfg,
-		executor:   executor,
-		metrics:    makeServerMetrics(internalMemMetrics, histogramWindow),
+		AmbientCtx:       ambientCtx,
+		cfg:              cfg,
+		executor:         executor,
+		cancelQueryByKey: cancelQueryByKey,
+		metrics:          makeServerMetrics(internalMemMetrics, histogramWindow),
 	}
 	server.sqlMemoryPool = mon.MakeMonitor("sql",
 		server.metrics.SQLMemMetrics.CurBytesCount,
@@ -191,7 +202,7 @@ func Match(rd io.Reader) bool {
 	if err != nil {
 		return false
 	}
-	return version == version30 || version == versionSSL
+	return version == version30 || version == versionSSL || version == versionCancel
 }
 
 // IsDraining returns true if the server is not currently accepting
@@ -380,6 +391,13 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn) error {
 		errSSLRequired = true
 	}
 
+	if version == versionCancel {
+		// Like Postgres, we accept cancel requests on cleartext connections
+		// even on secure servers: clients such as libpq do not negotiate SSL
+		// for them, and the secret key authenticates the request.
+		return s.handleCancelRequest(ctx, &buf)
+	}
+
 	if version == version30 {
 		// We make a connection before anything. If there is an error
 		// parsing the connection arguments, the connection will only be
@@ -429,3 +447,22 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn) error {
 
 	return errors.Errorf("unknown protocol version %d", version)
 }
+
+// handleCancelRequest handles a CancelRequest message, which a client sends on
+// a new connection to cancel the queries running in one of its sessions. The
+// message carries the backend key sent to the client when the session was
+// set up. As in Postgres, no response is sent: the connection is just closed.
+func (s *Server) handleCancelRequest(ctx context.Context, buf *readBuffer) error {
+	nodeID, err := buf.getUint32()
+	if err != nil {
+		return err
+	}
+	secret, err := buf.getUint32()
+	if err != nil {
+		return err
+	}
+	if err := s.cancelQueryByKey(ctx, roachpb.NodeID(nodeID), int32(secret)); err != nil {
+		return errors.Wrapf(err, "canceling queries of session on node %d", nodeID)
+	}
+	return nil
+}
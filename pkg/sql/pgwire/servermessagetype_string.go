@@ -9,10 +9,11 @@ const (
 	_serverMessageType_name_1 = "serverMsgCommandCompleteserverMsgDataRowserverMsgErrorResponse"
 	_serverMessageType_name_2 = "serverMsgCopyInResponse"
 	_serverMessageType_name_3 = "serverMsgEmptyQuery"
-	_serverMessageType_name_4 = "serverMsgAuthserverMsgParameterStatusserverMsgRowDescription"
-	_serverMessageType_name_5 = "serverMsgReady"
-	_serverMessageType_name_6 = "serverMsgNoData"
-	_serverMessageType_name_7 = "serverMsgParameterDescription"
+	_serverMessageType_name_4 = "serverMsgBackendKeyData"
+	_serverMessageType_name_5 = "serverMsgAuthserverMsgParameterStatusserverMsgRowDescription"
+	_serverMessageType_name_6 = "serverMsgReady"
+	_serverMessageType_name_7 = "serverMsgNoData"
+	_serverMessageType_name_8 = "serverMsgParameterDescription"
 )
 
 var (
@@ -20,10 +21,11 @@ var (
 	_serverMessageType_index_1 = [...]uint8{0, 24, 40, 62}
 	_serverMessageType_index_2 = [...]uint8{0, 23}
 	_serverMessageType_index_3 = [...]uint8{0, 19}
-	_serverMessageType_index_4 = [...]uint8{0, 13, 37, 60}
-	_serverMessageType_index_5 = [...]uint8{0, 14}
-	_serverMessageType_index_6 = [...]uint8{0, 15}
-	_serverMessageType_index_7 = [...]uint8{0, 29}
+	_serverMessageType_index_4 = [...]uint8{0, 23}
+	_serverMessageType_index_5 = [...]uint8{0, 13, 37, 60}
+	_serverMessageType_index_6 = [...]uint8{0, 14}
+	_serverMessageType_index_7 = [...]uint8{0, 15}
+	_serverMessageType_index_8 = [...]uint8{0, 29}
 )
 
 func (i serverMessageType) String() string {
@@ -38,15 +40,17 @@ func (i serverMessageType) String() string {
 		return _serverMessageType_name_2
 	case i == 73:
 		return _serverMessageType_name_3
+	case i == 75:
+		return _serverMessageType_name_4
 	case 82 <= i && i <= 84:
 		i -= 82
-		return _serverMessageType_name_4[_serverMessageType_index_4[i]:_serverMessageType_index_4[i+1]]
+		return _serverMessageType_name_5[_serverMessageType_index_5[i]:_serverMessageType_index_5[i+1]]
 	case i == 90:
-		return _serverMessageType_name_5
-	case i == 110:
 		return _serverMessageType_name_6
-	case i == 116:
+	case i == 110:
 		return _serverMessageType_name_7
+	case i == 116:
+		return _serverMessageType_name_8
 	default:
 		return fmt.Sprintf("serverMessageType(%d)", i)
 	}
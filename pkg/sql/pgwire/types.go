@@ -89,6 +89,10 @@ func pgTypeForParserType(t parser.Type) pgType {
 
 const secondsInDay = 24 * 60 * 60
 
+// jsonbBinaryVersion is the version of the binary format of JSONB values,
+// which is the only one Postgres defines.
+const jsonbBinaryVersion = 1
+
 func (b *writeBuffer) writeTextDatum(d parser.Datum, sessionLoc *time.Location) {
 	if log.V(2) {
 		log.Infof(context.TODO(), "pgwire writing TEXT datum of type: %T, %#v", d, d)
@@ -122,6 +126,12 @@ func (b *writeBuffer) writeTextDatum(d parser.Datum, sessionLoc *time.Location)
 	case *parser.DDecimal:
 		b.writeLengthPrefixedDatum(v)
 
+	case *parser.DUuid:
+		b.writeLengthPrefixedString(v.UUID.String())
+
+	case *parser.DJSON:
+		b.writeLengthPrefixedString(v.JSON.String())
+
 	case *parser.DBytes:
 		// http://www.postgresql.org/docs/current/static/datatype-binary.html#AEN5667
 		// Code cribbed from github.com/lib/pq.
@@ -302,6 +312,18 @@ func (b *writeBuffer) writeBinaryDatum(d parser.Datum, sessionLoc *time.Location
 		b.putInt32(int32(len(*v)))
 		b.write([]byte(*v))
 
+	case *parser.DUuid:
+		b.putInt32(16)
+		b.write(v.GetBytes())
+
+	case *parser.DJSON:
+		// The binary format of JSONB is a version number followed by the
+		// textual representation of the document.
+		s := v.JSON.String()
+		b.putInt32(int32(len(s) + 1))
+		b.writeByte(jsonbBinaryVersion)
+		b.writeString(s)
+
 	case *parser.DString:
 		b.writeLengthPrefixedString(string(*v))
 
@@ -526,6 +548,18 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 				return nil, errors.Errorf("could not parse string %q as interval", b)
 			}
 			return d, nil
+		case oid.T_uuid:
+			u, err := parser.ParseDUuidFromString(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as uuid", b)
+			}
+			return u, nil
+		case oid.T_jsonb:
+			j, err := parser.ParseDJSON(string(b))
+			if err != nil {
+				return nil, errors.Errorf("could not parse string %q as jsonb", b)
+			}
+			return j, nil
 		case oid.T__int2, oid.T__int4, oid.T__int8:
 			var arr pq.Int64Array
 			if err := (&arr).Scan(b); err != nil {
@@ -700,6 +734,17 @@ func decodeOidDatum(id oid.Oid, code formatCode, b []byte) (parser.Datum, error)
 			}
 			i := int32(binary.BigEndian.Uint32(b))
 			return pgBinaryToDate(i), nil
+		case oid.T_uuid:
+			u, err := parser.ParseDUuidFromBytes(b)
+			if err != nil {
+				return nil, err
+			}
+			return u, nil
+		case oid.T_jsonb:
+			if len(b) < 1 || b[0] != jsonbBinaryVersion {
+				return nil, errors.Errorf("unsupported jsonb binary format version")
+			}
+			return parser.ParseDJSON(string(b[1:]))
 		case oid.T__int2, oid.T__int4, oid.T__int8, oid.T__text, oid.T__name:
 			return decodeBinaryArray(b, code)
 		}
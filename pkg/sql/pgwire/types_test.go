@@ -106,6 +106,62 @@ func TestIntArrayRoundTrip(t *testing.T) {
 	}
 }
 
+func TestUUIDRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	d, err := parser.ParseDUuidFromString("63616665-6630-3064-6465-616462656566")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, code := range []formatCode{formatText, formatBinary} {
+		buf := writeBuffer{bytecount: metric.NewCounter(metric.Metadata{})}
+		if code == formatText {
+			buf.writeTextDatum(d, time.UTC)
+		} else {
+			buf.writeBinaryDatum(d, time.UTC)
+		}
+
+		b := buf.wrapped.Bytes()
+
+		got, err := decodeOidDatum(oid.T_uuid, code, b[4:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Compare(&parser.EvalContext{}, d) != 0 {
+			t.Fatalf("%s: expected %s, got %s", code, d, got)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	d, err := parser.ParseDJSON(`{"a": [1, "b", null], "c": {"d": true}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, code := range []formatCode{formatText, formatBinary} {
+		buf := writeBuffer{bytecount: metric.NewCounter(metric.Metadata{})}
+		if code == formatText {
+			buf.writeTextDatum(d, time.UTC)
+		} else {
+			buf.writeBinaryDatum(d, time.UTC)
+		}
+
+		b := buf.wrapped.Bytes()
+
+		got, err := decodeOidDatum(oid.T_jsonb, code, b[4:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Compare(&parser.EvalContext{}, d) != 0 {
+			t.Fatalf("%s: expected %s, got %s", code, d, got)
+		}
+	}
+}
+
 func benchmarkWriteType(b *testing.B, d parser.Datum, format formatCode) {
 	buf := writeBuffer{bytecount: metric.NewCounter(metric.Metadata{Name: ""})}
 
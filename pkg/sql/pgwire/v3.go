@@ -64,6 +64,7 @@ const (
 	clientMsgTerminate   clientMessageType = 'X'
 
 	serverMsgAuth                 serverMessageType = 'R'
+	serverMsgBackendKeyData       serverMessageType = 'K'
 	serverMsgBindComplete         serverMessageType = '2'
 	serverMsgCommandComplete      serverMessageType = 'C'
 	serverMsgCloseComplete        serverMessageType = '3'
@@ -371,6 +372,17 @@ func (c *v3Conn) serve(ctx context.Context, draining func() bool, reserved mon.B
 	})
 	c.rd = bufio.NewReader(c.conn)
 
+	// Send the key with which the client can cancel the session's queries by
+	// sending a CancelRequest on another connection. See
+	// Server.handleCancelRequest().
+	nodeID, secret := c.session.BackendKey()
+	c.writeBuf.initMsg(serverMsgBackendKeyData)
+	c.writeBuf.putInt32(int32(nodeID))
+	c.writeBuf.putInt32(secret)
+	if err := c.writeBuf.finishMsg(c.wr); err != nil {
+		return err
+	}
+
 	for {
 		if !c.doingExtendedQueryMessage {
 			c.writeBuf.initMsg(serverMsgReady)
@@ -22,12 +22,15 @@ import (
 	"io/ioutil"
 	"net"
 	"testing"
+	"time"
 
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/mon"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -41,8 +44,11 @@ func makeTestV3Conn(c net.Conn) v3Conn {
 	exec := sql.NewExecutor(
 		sql.ExecutorConfig{
 			AmbientCtx:              log.AmbientContext{Tracer: tracing.NewTracer()},
+			NodeID:                  &base.NodeIDContainer{},
+			Clock:                   hlc.NewClock(hlc.UnixNano, time.Nanosecond),
 			HistogramWindowInterval: metric.TestSampleInterval,
 			TestingKnobs:            &sql.ExecutorTestingKnobs{},
+			SessionRegistry:         sql.MakeSessionRegistry(),
 		},
 		nil, /* stopper */
 	)
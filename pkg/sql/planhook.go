@@ -48,6 +48,7 @@ type PlanHookState interface {
 	TypeAsString(e parser.Expr, op string) (func() (string, error), error)
 	TypeAsStringArray(e parser.Exprs, op string) (func() ([]string, error), error)
 	User() string
+	EvalContext() parser.EvalContext
 	AuthorizationAccessor
 }
 
@@ -58,6 +59,24 @@ func AddPlanHook(f planHookFn) {
 	planHooks = append(planHooks, f)
 }
 
+// jobResumeHookFn is a function that can resume a paused job whose
+// implementation lives outside of the sql package, like a BACKUP or RESTORE.
+//
+// If the hook knows how to run the job tracked by the given JobLogger, it
+// returns a function that runs the job from its last checkpoint until it
+// finishes or is paused or canceled again, and records the outcome. Otherwise
+// it returns nil. The hook also validates the options of RESUME JOB.
+type jobResumeHookFn func(
+	context.Context, PlanHookState, *JobLogger, parser.KVOptions,
+) (func(context.Context) error, error)
+
+var jobResumeHooks []jobResumeHookFn
+
+// AddJobResumeHook adds a hook used by RESUME JOB to run paused jobs.
+func AddJobResumeHook(f jobResumeHookFn) {
+	jobResumeHooks = append(jobResumeHooks, f)
+}
+
 // hookFnNode is a planNode implemented in terms of a function. It runs the
 // provided function during Start and serves the results it returned.
 type hookFnNode struct {
@@ -70,6 +70,18 @@ type planner struct {
 	// See executor_statement_metrics.go for details.
 	phaseTimes phaseTimes
 
+	// ctes contains the common table expressions visible to the statement
+	// being planned, innermost last. See addCTEs().
+	ctes []cteSource
+
+	// queryMeta is the entry of the statement being executed in the session's
+	// active queries, if any.
+	queryMeta *queryMeta
+
+	// roleMembershipsCache caches the roles of which the session user is a
+	// member. See memberOf().
+	roleMembershipsCache map[string]bool
+
 	// Avoid allocations by embedding commonly used objects and visitors.
 	parser                parser.Parser
 	subqueryVisitor       subqueryVisitor
@@ -149,6 +161,11 @@ func (p *planner) User() string {
 	return p.session.User
 }
 
+// EvalContext implements the PlanHookState interface.
+func (p *planner) EvalContext() parser.EvalContext {
+	return p.evalCtx
+}
+
 // setTxn resets the current transaction in the planner and
 // initializes the timestamps used by SQL built-in functions from
 // the new txn object, if any.
@@ -62,3 +62,33 @@ func TestPrivilegeDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestListFromStrings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	testCases := []struct {
+		strs     []string
+		expected string
+		err      string
+	}{
+		{[]string{}, "", ""},
+		{[]string{"select"}, "SELECT", ""},
+		{[]string{"Insert", "DELETE", "grant"}, "INSERT, DELETE, GRANT", ""},
+		{[]string{"select", "usage"}, "", `not a valid privilege: "usage"`},
+	}
+
+	for _, tc := range testCases {
+		pl, err := privilege.ListFromStrings(tc.strs)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("%v: expected error %q, got %v", tc.strs, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.strs, err)
+		}
+		if pl.String() != tc.expected {
+			t.Fatalf("%v: expected %q, got %q", tc.strs, tc.expected, pl.String())
+		}
+	}
+}
@@ -421,6 +421,61 @@ func (p *planner) RenameColumn(ctx context.Context, n *parser.RenameColumn) (pla
 			tableDesc.Checks[i].Expr = after
 		}
 	}
+	// Rename the column in the expressions of the computed columns.
+	renameInComputedExpr := func(col *sqlbase.ColumnDescriptor) error {
+		if !col.IsComputed() {
+			return nil
+		}
+		expr, err := parser.ParseExpr(*col.ComputedExpr)
+		if err != nil {
+			return err
+		}
+		if expr, err = parser.SimpleVisit(expr, preFn); err != nil {
+			return err
+		}
+		s := expr.String()
+		col.ComputedExpr = &s
+		return nil
+	}
+	for i := range tableDesc.Columns {
+		if err := renameInComputedExpr(&tableDesc.Columns[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range tableDesc.Mutations {
+		if col := m.GetColumn(); col != nil {
+			if err := renameInComputedExpr(col); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Rename the column in the predicates of the partial indexes.
+	renameInPredicate := func(idx *sqlbase.IndexDescriptor) error {
+		if !idx.IsPartial() {
+			return nil
+		}
+		expr, err := parser.ParseExpr(idx.Predicate)
+		if err != nil {
+			return err
+		}
+		if expr, err = parser.SimpleVisit(expr, preFn); err != nil {
+			return err
+		}
+		idx.Predicate = expr.String()
+		return nil
+	}
+	for i := range tableDesc.Indexes {
+		if err := renameInPredicate(&tableDesc.Indexes[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range tableDesc.Mutations {
+		if idx := m.GetIndex(); idx != nil {
+			if err := renameInPredicate(idx); err != nil {
+				return nil, err
+			}
+		}
+	}
 	// Rename the column in the indexes.
 	tableDesc.RenameColumnNormalized(column.ID, normNewColName)
 	column.Name = normNewColName
@@ -173,8 +173,23 @@ func (p *planner) Select(
 	limit := n.Limit
 	orderBy := n.OrderBy
 
+	if n.With != nil {
+		restoreCTEs, err := p.addCTEs(ctx, n.With)
+		if err != nil {
+			return nil, err
+		}
+		defer restoreCTEs()
+	}
+
 	for s, ok := wrapped.(*parser.ParenSelect); ok; s, ok = wrapped.(*parser.ParenSelect) {
 		wrapped = s.Select.Select
+		if s.Select.With != nil {
+			restoreCTEs, err := p.addCTEs(ctx, s.Select.With)
+			if err != nil {
+				return nil, err
+			}
+			defer restoreCTEs()
+		}
 		if s.Select.OrderBy != nil {
 			if orderBy != nil {
 				return nil, fmt.Errorf("multiple ORDER BY clauses not allowed")
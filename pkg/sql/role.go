@@ -0,0 +1,367 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// Roles are principals stored in system.roles. Privileges can be granted to
+// them like to users, and their members (users or other roles, recorded in
+// system.role_members) inherit these privileges. Roles share their namespace
+// with users but cannot log in.
+
+// roleNode is a planNode that runs CREATE ROLE, DROP ROLE, GRANT <role> or
+// REVOKE <role>. The statement is checked when it is planned; startFn makes
+// the changes.
+type roleNode struct {
+	p       *planner
+	startFn func(ctx context.Context) error
+}
+
+// CreateRole creates a role.
+// Privileges: INSERT on system.roles.
+func (p *planner) CreateRole(ctx context.Context, n *parser.CreateRole) (planNode, error) {
+	if err := p.checkSystemTablePrivilege(ctx, "roles", privilege.INSERT); err != nil {
+		return nil, err
+	}
+	name, err := NormalizeAndValidateUsername(string(n.Name))
+	if err != nil {
+		return nil, err
+	}
+	return &roleNode{p: p, startFn: func(ctx context.Context) error {
+		if exists, err := p.userExists(ctx, name); err != nil {
+			return err
+		} else if exists {
+			return errors.Errorf("a user named %s already exists", name)
+		}
+		internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+		if _, err := internalExecutor.ExecuteStatementInTransaction(
+			ctx, "create-role", p.txn, `INSERT INTO system.roles VALUES ($1)`, name,
+		); err != nil {
+			if sqlbase.IsUniquenessConstraintViolationError(err) {
+				err = errors.Errorf("role %s already exists", name)
+			}
+			return err
+		}
+		return nil
+	}}, nil
+}
+
+// DropRole drops roles. A role cannot be dropped while privileges are granted
+// to it; its memberships are removed.
+// Privileges: DELETE on system.roles.
+func (p *planner) DropRole(ctx context.Context, n *parser.DropRole) (planNode, error) {
+	if err := p.checkSystemTablePrivilege(ctx, "roles", privilege.DELETE); err != nil {
+		return nil, err
+	}
+	names, err := normalizeRoleNames(n.Names)
+	if err != nil {
+		return nil, err
+	}
+	return &roleNode{p: p, startFn: func(ctx context.Context) error {
+		descs, err := getAllDescriptors(ctx, p.txn)
+		if err != nil {
+			return err
+		}
+		internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+		for _, name := range names {
+			for _, desc := range descs {
+				if desc.GetPrivileges().AnyPrivilege(name) {
+					return errors.Errorf("cannot drop role %s: it has privileges on %s %s",
+						name, desc.TypeName(), desc.GetName())
+				}
+			}
+			rowsAffected, err := internalExecutor.ExecuteStatementInTransaction(
+				ctx, "drop-role", p.txn, `DELETE FROM system.roles WHERE name = $1`, name,
+			)
+			if err != nil {
+				return err
+			}
+			if rowsAffected == 0 {
+				if n.IfExists {
+					continue
+				}
+				return errors.Errorf("role %s does not exist", name)
+			}
+			if _, err := internalExecutor.ExecuteStatementInTransaction(
+				ctx, "drop-role-members", p.txn,
+				`DELETE FROM system.role_members WHERE role = $1 OR member = $1`, name,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}, nil
+}
+
+// GrantRole adds members to roles. Memberships cannot form cycles.
+// Privileges: INSERT on system.role_members, or the admin option on the roles.
+func (p *planner) GrantRole(ctx context.Context, n *parser.GrantRole) (planNode, error) {
+	roles, err := normalizeRoleNames(n.Roles)
+	if err != nil {
+		return nil, err
+	}
+	members, err := normalizeRoleNames(n.Members)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkRoleAdmin(ctx, privilege.INSERT, roles); err != nil {
+		return nil, err
+	}
+	return &roleNode{p: p, startFn: func(ctx context.Context) error {
+		if err := p.checkRolesExist(ctx, roles); err != nil {
+			return err
+		}
+		for _, member := range members {
+			if member == security.RootUser {
+				return errors.Errorf("%s cannot be a member of a role", member)
+			}
+			userExists, err := p.userExists(ctx, member)
+			if err != nil {
+				return err
+			}
+			roleExists, err := p.roleExists(ctx, member)
+			if err != nil {
+				return err
+			}
+			if !userExists && !roleExists {
+				return errors.Errorf("user or role %s does not exist", member)
+			}
+		}
+
+		stmt := `INSERT INTO system.role_members VALUES ($1, $2, false)
+			ON CONFLICT (role, member) DO NOTHING`
+		if n.AdminOption {
+			stmt = `UPSERT INTO system.role_members VALUES ($1, $2, true)`
+		}
+		internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+		for _, role := range roles {
+			parents, err := p.roleMemberships(ctx, role)
+			if err != nil {
+				return err
+			}
+			for _, member := range members {
+				if _, ok := parents[member]; ok || member == role {
+					return errors.Errorf("making %s a member of %s would create a cycle", member, role)
+				}
+				if _, err := internalExecutor.ExecuteStatementInTransaction(
+					ctx, "grant-role", p.txn, stmt, role, member,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}}, nil
+}
+
+// RevokeRole removes members from roles, or only their admin option.
+// Privileges: DELETE on system.role_members, or the admin option on the roles.
+func (p *planner) RevokeRole(ctx context.Context, n *parser.RevokeRole) (planNode, error) {
+	roles, err := normalizeRoleNames(n.Roles)
+	if err != nil {
+		return nil, err
+	}
+	members, err := normalizeRoleNames(n.Members)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkRoleAdmin(ctx, privilege.DELETE, roles); err != nil {
+		return nil, err
+	}
+	return &roleNode{p: p, startFn: func(ctx context.Context) error {
+		if err := p.checkRolesExist(ctx, roles); err != nil {
+			return err
+		}
+		stmt := `DELETE FROM system.role_members WHERE role = $1 AND member = $2`
+		if n.AdminOption {
+			stmt = `UPDATE system.role_members SET "isAdmin" = false WHERE role = $1 AND member = $2`
+		}
+		internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+		for _, role := range roles {
+			for _, member := range members {
+				if _, err := internalExecutor.ExecuteStatementInTransaction(
+					ctx, "revoke-role", p.txn, stmt, role, member,
+				); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}}, nil
+}
+
+// ShowRoles returns all the roles.
+// Privileges: SELECT on system.roles.
+func (p *planner) ShowRoles(ctx context.Context, n *parser.ShowRoles) (planNode, error) {
+	stmt, err := parser.ParseOne(`SELECT name FROM system.roles ORDER BY 1`)
+	if err != nil {
+		return nil, err
+	}
+	return p.newPlan(ctx, stmt, nil)
+}
+
+func normalizeRoleNames(names parser.NameList) ([]string, error) {
+	ret := make([]string, len(names))
+	for i, name := range names {
+		normalized, err := NormalizeAndValidateUsername(string(name))
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = normalized
+	}
+	return ret, nil
+}
+
+// checkSystemTablePrivilege verifies that the session user has a privilege on
+// a table of the system database.
+func (p *planner) checkSystemTablePrivilege(
+	ctx context.Context, table string, priv privilege.Kind,
+) error {
+	tn := &parser.TableName{DatabaseName: "system", TableName: parser.Name(table)}
+	tDesc, err := getTableDesc(ctx, p.txn, p.getVirtualTabler(), tn)
+	if err != nil {
+		return err
+	}
+	if tDesc == nil {
+		return sqlbase.NewUndefinedTableError(tn.String())
+	}
+	return p.CheckPrivilege(tDesc, priv)
+}
+
+// checkRoleAdmin verifies that the session user can change the members of
+// roles: it needs either privilege priv on system.role_members or the admin
+// option on all the roles.
+func (p *planner) checkRoleAdmin(ctx context.Context, priv privilege.Kind, roles []string) error {
+	if err := p.checkSystemTablePrivilege(ctx, "role_members", priv); err == nil {
+		return nil
+	}
+	memberOf, err := p.memberOf(ctx)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if !memberOf[role] {
+			return errors.Errorf("user %s must have the admin option on role %s", p.session.User, role)
+		}
+	}
+	return nil
+}
+
+func (p *planner) checkRolesExist(ctx context.Context, roles []string) error {
+	for _, role := range roles {
+		exists, err := p.roleExists(ctx, role)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.Errorf("role %s does not exist", role)
+		}
+	}
+	return nil
+}
+
+func (p *planner) userExists(ctx context.Context, name string) (bool, error) {
+	return p.rowExists(ctx, "user-exists", `SELECT 1 FROM system.users WHERE username = $1`, name)
+}
+
+func (p *planner) roleExists(ctx context.Context, name string) (bool, error) {
+	return p.rowExists(ctx, "role-exists", `SELECT 1 FROM system.roles WHERE name = $1`, name)
+}
+
+func (p *planner) rowExists(
+	ctx context.Context, opName string, stmt string, qargs ...interface{},
+) (bool, error) {
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	rows, err := internalExecutor.QueryRowsInTransaction(ctx, opName, p.txn, stmt, qargs...)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
+
+// memberOf returns the roles of which the session user is a member, as
+// computed by roleMemberships. The result is cached in the planner. root is
+// not a member of any role.
+func (p *planner) memberOf(ctx context.Context) (map[string]bool, error) {
+	user := p.session.User
+	if user == security.RootUser || user == security.NodeUser || p.txn == nil {
+		return nil, nil
+	}
+	if p.roleMembershipsCache == nil {
+		memberships, err := p.roleMemberships(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		p.roleMembershipsCache = memberships
+	}
+	return p.roleMembershipsCache, nil
+}
+
+// roleMemberships returns the roles of which member is a member, directly or
+// through other roles. Each role maps to whether member holds the admin
+// option on it, which is the case when member or one of its roles was
+// granted the role with the admin option.
+func (p *planner) roleMemberships(ctx context.Context, member string) (map[string]bool, error) {
+	internalExecutor := InternalExecutor{LeaseManager: p.LeaseMgr()}
+	memberships := make(map[string]bool)
+	toVisit := []string{member}
+	for len(toVisit) > 0 {
+		m := toVisit[0]
+		toVisit = toVisit[1:]
+		rows, err := internalExecutor.QueryRowsInTransaction(
+			ctx, "role-memberships", p.txn,
+			`SELECT role, "isAdmin" FROM system.role_members WHERE member = $1`, m,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			role := string(parser.MustBeDString(row[0]))
+			isAdmin := bool(*row[1].(*parser.DBool))
+			if _, ok := memberships[role]; !ok {
+				toVisit = append(toVisit, role)
+			}
+			memberships[role] = memberships[role] || isAdmin
+		}
+	}
+	return memberships, nil
+}
+
+func (n *roleNode) Start(ctx context.Context) error {
+	// The memberships of the session user may change.
+	n.p.roleMembershipsCache = nil
+	return n.startFn(ctx)
+}
+
+func (*roleNode) Next(context.Context) (bool, error) { return false, nil }
+func (*roleNode) Close(context.Context)              {}
+func (*roleNode) Columns() sqlbase.ResultColumns     { return make(sqlbase.ResultColumns, 0) }
+func (*roleNode) Ordering() orderingInfo             { return orderingInfo{} }
+func (*roleNode) Values() parser.Datums              { return parser.Datums{} }
+func (*roleNode) DebugValues() debugValues           { return debugValues{} }
+func (*roleNode) MarkDebug(mode explainMode)         {}
+
+func (*roleNode) Spans(context.Context) (_, _ roachpb.Spans, _ error) {
+	panic("unimplemented")
+}
@@ -92,6 +92,11 @@ type scanNode struct {
 
 	disableBatchLimits bool
 
+	// estimatedRowCount is the number of rows the scan is estimated to
+	// produce, derived from the statistics of the table by index selection.
+	// It is -1 if the table has no statistics.
+	estimatedRowCount int64
+
 	scanVisibility scanVisibility
 	// This struct must be allocated on the heap and its location stay
 	// stable after construction because it implements
@@ -102,7 +107,7 @@ type scanNode struct {
 }
 
 func (p *planner) Scan() *scanNode {
-	return &scanNode{p: p}
+	return &scanNode{p: p, estimatedRowCount: -1}
 }
 
 func (n *scanNode) Columns() sqlbase.ResultColumns {
@@ -405,6 +410,11 @@ func (n *scanNode) computeOrdering(
 	index *sqlbase.IndexDescriptor, exactPrefix int, reverse bool,
 ) orderingInfo {
 	var ordering orderingInfo
+	if index.Type == sqlbase.IndexDescriptor_INVERTED {
+		// A row can be found under several elements of an inverted index, so
+		// its scans provide no ordering.
+		return ordering
+	}
 
 	columnIDs, dirs := index.FullColumnIDs()
 
@@ -473,8 +473,16 @@ func (sc *SchemaChanger) waitToUpdateLeases(ctx context.Context, tableID sqlbase
 // It ensures that all nodes are on the current (pre-update) version of the
 // schema.
 // Returns the updated of the descriptor.
-func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error) {
-	return sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+//
+// Completing the mutations changing the type of a column swaps in the shadow
+// column and indexes, and queues the drop of the replaced column and indexes
+// as a new mutation, whose ID is returned.
+func (sc *SchemaChanger) done(
+	ctx context.Context,
+) (*sqlbase.Descriptor, sqlbase.MutationID, error) {
+	followupMutationID := sqlbase.InvalidMutationID
+	desc, err := sc.leaseMgr.Publish(ctx, sc.tableID, func(desc *sqlbase.TableDescriptor) error {
+		followupMutationID = sqlbase.InvalidMutationID
 		i := 0
 		for _, mutation := range desc.Mutations {
 			if mutation.MutationID != sc.mutationID {
@@ -492,6 +500,10 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error)
 		}
 		// Trim the executed mutations from the descriptor.
 		desc.Mutations = desc.Mutations[i:]
+		if n := len(desc.Mutations); n > 0 && desc.Mutations[n-1].MutationID == desc.NextMutationID {
+			followupMutationID = desc.NextMutationID
+			desc.NextMutationID++
+		}
 		return nil
 	}, func(txn *client.Txn) error {
 		// Log "Finish Schema Change" event. Only the table ID and mutation ID
@@ -508,6 +520,7 @@ func (sc *SchemaChanger) done(ctx context.Context) (*sqlbase.Descriptor, error)
 			}{uint32(sc.mutationID)},
 		)
 	})
+	return desc, followupMutationID, err
 }
 
 // notFirstInLine returns true whenever the schema change has been queued
@@ -547,8 +560,14 @@ func (sc *SchemaChanger) runStateMachineAndBackfill(
 	}
 
 	// Mark the mutations as completed.
-	_, err := sc.done(ctx)
-	return err
+	_, followupMutationID, err := sc.done(ctx)
+	if err != nil || followupMutationID == sqlbase.InvalidMutationID {
+		return err
+	}
+
+	// Drop the column and indexes replaced by a column type change right away.
+	sc.mutationID = followupMutationID
+	return sc.runStateMachineAndBackfill(ctx, lease, evalCtx)
 }
 
 // reverseMutations reverses the direction of all the mutations with the
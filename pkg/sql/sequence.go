@@ -0,0 +1,383 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"math"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// sequenceState holds the session-scoped state used by the currval() and
+// lastval() builtins.
+type sequenceState struct {
+	mu syncutil.Mutex
+	// latestValues stores the value most recently obtained by nextval() for
+	// each sequence, by descriptor ID.
+	latestValues map[sqlbase.ID]int64
+	// lastSequenceIncremented is the ID of the sequence nextval() was most
+	// recently called on, or 0 if it was never called in this session.
+	lastSequenceIncremented sqlbase.ID
+}
+
+// setLatestValue records val as the current value of the given sequence in
+// this session.
+func (ss *sequenceState) setLatestValue(seqID sqlbase.ID, val int64) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.latestValues == nil {
+		ss.latestValues = make(map[sqlbase.ID]int64)
+	}
+	ss.latestValues[seqID] = val
+}
+
+// recordNextval records the result of a call to nextval().
+func (ss *sequenceState) recordNextval(seqID sqlbase.ID, val int64) {
+	ss.setLatestValue(seqID, val)
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.lastSequenceIncremented = seqID
+}
+
+func (ss *sequenceState) getLatestValue(seqID sqlbase.ID) (int64, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	val, ok := ss.latestValues[seqID]
+	return val, ok
+}
+
+func (ss *sequenceState) getLastValue() (int64, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.lastSequenceIncremented == 0 {
+		return 0, false
+	}
+	val, ok := ss.latestValues[ss.lastSequenceIncremented]
+	return val, ok
+}
+
+// getSequenceDesc returns the descriptor of the sequence with the given name,
+// or an error if the name does not designate a sequence.
+func (p *planner) getSequenceDesc(
+	ctx context.Context, seqName *parser.TableName,
+) (*sqlbase.TableDescriptor, error) {
+	descFunc := p.session.leases.getTableLease
+	if p.avoidCachedDescriptors {
+		descFunc = mustGetTableOrViewDesc
+	}
+	desc, err := descFunc(ctx, p.txn, p.getVirtualTabler(), seqName)
+	if err != nil {
+		return nil, err
+	}
+	if !desc.IsSequence() {
+		return nil, sqlbase.NewWrongObjectTypeError(seqName.String(), "sequence")
+	}
+	return desc, nil
+}
+
+// IncrementSequence implements the parser.EvalPlanner interface.
+func (p *planner) IncrementSequence(ctx context.Context, seqName *parser.TableName) (int64, error) {
+	desc, err := p.getSequenceDesc(ctx, seqName)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.CheckPrivilege(desc, privilege.UPDATE); err != nil {
+		return 0, err
+	}
+
+	// The increment is not part of the current transaction: as in PostgreSQL,
+	// a value handed out by nextval() is never handed out again, even if the
+	// transaction that obtained it aborts.
+	seqOpts := desc.SequenceOpts
+	val, overflow, err := incrementSequenceValue(ctx, p.ExecCfg().DB, desc)
+	if err != nil {
+		return 0, err
+	}
+	if val > seqOpts.MaxValue || (overflow && seqOpts.Increment > 0) {
+		return 0, pgerror.NewErrorf(pgerror.CodeSequenceGeneratorLimitExceededError,
+			"reached maximum value of sequence %q (%d)", desc.Name, seqOpts.MaxValue)
+	}
+	if val < seqOpts.MinValue || (overflow && seqOpts.Increment < 0) {
+		return 0, pgerror.NewErrorf(pgerror.CodeSequenceGeneratorLimitExceededError,
+			"reached minimum value of sequence %q (%d)", desc.Name, seqOpts.MinValue)
+	}
+
+	p.session.sequenceState.recordNextval(desc.ID, val)
+	return val, nil
+}
+
+// The value of a sequence is stored under keys.MakeSequenceKey: it is the value
+// most recently returned by nextval() or, before the next value is handed out
+// for the first time, the value preceding it. When the latter doesn't fit in an
+// int64, as with MINVALUE -9223372036854775808 START -9223372036854775808, the
+// next value is stored under keys.MakeSequenceUncalledKey instead and the value
+// key holds the int64 bound in the direction of the increment, so that
+// incrementing it fails.
+
+// incrementSequenceValue increments the value of the sequence and returns the
+// new value, or overflow set to true if the sequence went past the bounds of
+// an int64.
+func incrementSequenceValue(
+	ctx context.Context, db *client.DB, desc *sqlbase.TableDescriptor,
+) (val int64, overflow bool, _ error) {
+	seqKey := keys.MakeSequenceKey(uint32(desc.ID))
+	uncalledKey := keys.MakeSequenceUncalledKey(uint32(desc.ID))
+	increment := desc.SequenceOpts.Increment
+	for retried := false; ; retried = true {
+		res, incErr := db.Inc(ctx, seqKey, increment)
+		if incErr == nil {
+			return res.ValueInt(), false, nil
+		}
+
+		// The increment fails if it overflows. Find out whether the next value
+		// is the one stored under the uncalled key, in which case it is handed
+		// out now.
+		var uncalled bool
+		if err := db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+			uncalled, overflow = false, false
+			kv, err := txn.Get(ctx, uncalledKey)
+			if err != nil {
+				return err
+			}
+			if kv.Value != nil {
+				uncalled, val = true, kv.ValueInt()
+				b := txn.NewBatch()
+				b.Del(uncalledKey)
+				b.Put(seqKey, val)
+				return txn.CommitInBatch(ctx, b)
+			}
+			if kv, err = txn.Get(ctx, seqKey); err != nil {
+				return err
+			}
+			overflow = sequenceIncrementOverflows(kv.ValueInt(), increment)
+			return nil
+		}); err != nil {
+			return 0, false, err
+		}
+		if uncalled {
+			return val, false, nil
+		}
+		if overflow {
+			return 0, true, nil
+		}
+		// The value changed since the increment failed, which happens when
+		// the uncalled value was handed out concurrently. Any other error is
+		// returned once retrying doesn't help.
+		if retried {
+			return 0, false, incErr
+		}
+	}
+}
+
+// sequenceIncrementOverflows returns whether adding increment to val overflows
+// an int64.
+func sequenceIncrementOverflows(val, increment int64) bool {
+	return (increment > 0 && val > math.MaxInt64-increment) ||
+		(increment < 0 && val < math.MinInt64-increment)
+}
+
+// setSequenceValue stores the value of the sequence such that the next call
+// to nextval() returns the value following val or, if !isCalled, val itself.
+func setSequenceValue(b *client.Batch, desc *sqlbase.TableDescriptor, val int64, isCalled bool) {
+	seqKey := keys.MakeSequenceKey(uint32(desc.ID))
+	uncalledKey := keys.MakeSequenceUncalledKey(uint32(desc.ID))
+	increment := desc.SequenceOpts.Increment
+	if !isCalled {
+		valBefore, ok := sequenceValueBefore(val, increment)
+		if !ok {
+			bound := int64(math.MaxInt64)
+			if increment < 0 {
+				bound = math.MinInt64
+			}
+			b.Put(seqKey, bound)
+			b.Put(uncalledKey, val)
+			return
+		}
+		val = valBefore
+	}
+	b.Put(seqKey, val)
+	b.Del(uncalledKey)
+}
+
+// GetLatestValueInSessionForSequence implements the parser.EvalPlanner
+// interface.
+func (p *planner) GetLatestValueInSessionForSequence(
+	ctx context.Context, seqName *parser.TableName,
+) (int64, error) {
+	desc, err := p.getSequenceDesc(ctx, seqName)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.CheckPrivilege(desc, privilege.SELECT); err != nil {
+		return 0, err
+	}
+
+	val, ok := p.session.sequenceState.getLatestValue(desc.ID)
+	if !ok {
+		return 0, pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"currval of sequence %q is not yet defined in this session", desc.Name)
+	}
+	return val, nil
+}
+
+// GetLastSequenceValue implements the parser.EvalPlanner interface.
+func (p *planner) GetLastSequenceValue(ctx context.Context) (int64, error) {
+	val, ok := p.session.sequenceState.getLastValue()
+	if !ok {
+		return 0, pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+			"lastval is not yet defined in this session")
+	}
+	return val, nil
+}
+
+// SetSequenceValue implements the parser.EvalPlanner interface.
+func (p *planner) SetSequenceValue(
+	ctx context.Context, seqName *parser.TableName, newVal int64, isCalled bool,
+) error {
+	desc, err := p.getSequenceDesc(ctx, seqName)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPrivilege(desc, privilege.UPDATE); err != nil {
+		return err
+	}
+
+	seqOpts := desc.SequenceOpts
+	if newVal > seqOpts.MaxValue || newVal < seqOpts.MinValue {
+		return pgerror.NewErrorf(pgerror.CodeNumericValueOutOfRangeError,
+			"value %d is out of bounds for sequence %q (%d..%d)",
+			newVal, desc.Name, seqOpts.MinValue, seqOpts.MaxValue)
+	}
+
+	// As with nextval(), the new value is written outside of the current
+	// transaction.
+	if err := p.ExecCfg().DB.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		b := txn.NewBatch()
+		setSequenceValue(b, desc, newVal, isCalled)
+		return txn.CommitInBatch(ctx, b)
+	}); err != nil {
+		return err
+	}
+	if isCalled {
+		p.session.sequenceState.setLatestValue(desc.ID, newVal)
+	}
+	return nil
+}
+
+// makeSequenceTableDesc creates the descriptor of a new sequence.
+func makeSequenceTableDesc(
+	sequenceName string,
+	options parser.SequenceOptions,
+	parentID sqlbase.ID,
+	id sqlbase.ID,
+	privileges *sqlbase.PrivilegeDescriptor,
+) (sqlbase.TableDescriptor, error) {
+	desc := sqlbase.TableDescriptor{
+		ID:            id,
+		Name:          sequenceName,
+		ParentID:      parentID,
+		FormatVersion: sqlbase.FamilyFormatVersion,
+		Version:       1,
+		Privileges:    privileges,
+		SequenceOpts:  &sqlbase.TableDescriptor_SequenceOpts{},
+	}
+	return desc, assignSequenceOptions(desc.SequenceOpts, options)
+}
+
+// assignSequenceOptions fills in opts from the options given to CREATE
+// SEQUENCE, applying the same defaults and checks as PostgreSQL.
+func assignSequenceOptions(
+	opts *sqlbase.TableDescriptor_SequenceOpts, optsNode parser.SequenceOptions,
+) error {
+	var minValue, maxValue, start *int64
+	opts.Increment = 1
+	seen := make(map[string]bool, len(optsNode))
+	for _, option := range optsNode {
+		if seen[option.Name] {
+			return pgerror.NewError(pgerror.CodeSyntaxError, "conflicting or redundant options")
+		}
+		seen[option.Name] = true
+
+		switch option.Name {
+		case parser.SeqOptIncrement:
+			opts.Increment = *option.IntVal
+		case parser.SeqOptMinValue:
+			minValue = option.IntVal
+		case parser.SeqOptMaxValue:
+			maxValue = option.IntVal
+		case parser.SeqOptStart:
+			start = option.IntVal
+		}
+	}
+
+	if opts.Increment == 0 {
+		return pgerror.NewError(pgerror.CodeInvalidParameterValueError, "INCREMENT must not be zero")
+	}
+
+	// Ascending sequences default to the range [1, MaxInt64], descending
+	// sequences to [MinInt64, -1].
+	if opts.Increment > 0 {
+		opts.MinValue, opts.MaxValue = 1, math.MaxInt64
+	} else {
+		opts.MinValue, opts.MaxValue = math.MinInt64, -1
+	}
+	if minValue != nil {
+		opts.MinValue = *minValue
+	}
+	if maxValue != nil {
+		opts.MaxValue = *maxValue
+	}
+	if opts.MinValue >= opts.MaxValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"MINVALUE (%d) must be less than MAXVALUE (%d)", opts.MinValue, opts.MaxValue)
+	}
+
+	// The start value defaults to the bound the sequence moves away from.
+	if opts.Increment > 0 {
+		opts.Start = opts.MinValue
+	} else {
+		opts.Start = opts.MaxValue
+	}
+	if start != nil {
+		opts.Start = *start
+	}
+	if opts.Start < opts.MinValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"START value (%d) cannot be less than MINVALUE (%d)", opts.Start, opts.MinValue)
+	}
+	if opts.Start > opts.MaxValue {
+		return pgerror.NewErrorf(pgerror.CodeInvalidParameterValueError,
+			"START value (%d) cannot be greater than MAXVALUE (%d)", opts.Start, opts.MaxValue)
+	}
+	return nil
+}
+
+// sequenceValueBefore returns the value preceding val in a sequence with the
+// given increment, and false if computing it overflows.
+func sequenceValueBefore(val, increment int64) (int64, bool) {
+	if (increment > 0 && val < math.MinInt64+increment) ||
+		(increment < 0 && val > math.MaxInt64+increment) {
+		return 0, false
+	}
+	return val - increment, true
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 )
@@ -142,6 +143,14 @@ var DistSQLClusterExecMode = settings.RegisterEnumSetting(
 	},
 )
 
+// StatementClusterTimeout controls the cluster default for the maximum
+// duration of a statement. See the statement_timeout session variable.
+var StatementClusterTimeout = settings.RegisterNonNegativeDurationSetting(
+	"sql.defaults.statement_timeout",
+	"default maximum duration of a statement, after which it is canceled (set to 0 to disable)",
+	0,
+)
+
 // Session contains the state of a SQL client connection.
 // Create instances using NewSession().
 type Session struct {
@@ -151,7 +160,8 @@ type Session struct {
 
 	// ApplicationName is the name of the application running the
 	// current session. This can be used for logging and per-application
-	// statistics. Change via resetApplicationName().
+	// statistics. Change via resetApplicationName(), which holds mu so that
+	// the SessionRegistry can read it concurrently.
 	ApplicationName string
 	// Database indicates the "current" database for the purpose of
 	// resolving names. See searchAndQualifyDatabase() for details.
@@ -168,6 +178,9 @@ type Session struct {
 	// before the database. Currently, this is used only for SELECTs.
 	// Names in the search path must have been normalized already.
 	SearchPath parser.SearchPath
+	// StatementTimeout is the maximum duration of a statement, after which it
+	// is canceled. Zero means no timeout.
+	StatementTimeout time.Duration
 	// User is the name of the user logged into the session.
 	User string
 
@@ -197,6 +210,15 @@ type Session struct {
 	// Run-time state.
 	//
 
+	// id uniquely identifies the session across the cluster.
+	id string
+	// clientAddr is the address of the client connected to the session, if
+	// any.
+	clientAddr string
+	// cancelSecret is the secret part of the key with which clients can cancel
+	// the session's queries from another connection. It is assigned by the
+	// SessionRegistry. See BackendKey().
+	cancelSecret int32
 	// execCfg is the configuration of the Executor that is executing this
 	// session.
 	execCfg *ExecutorConfig
@@ -239,6 +261,25 @@ type Session struct {
 	// If set, contains the in progress COPY FROM columns.
 	copyFrom *copyNode
 
+	// sequenceState tracks the values obtained from sequences in this
+	// session, for use by currval() and lastval().
+	sequenceState sequenceState
+
+	// mu contains the state that is read by other goroutines through the
+	// SessionRegistry.
+	mu struct {
+		syncutil.Mutex
+
+		// ActiveQueries contains the queries running in the session, keyed by
+		// query ID. There can be more than one when statements are
+		// parallelized.
+		ActiveQueries map[string]*queryMeta
+
+		// LastActiveQuery is the query that most recently started running in
+		// the session.
+		LastActiveQuery parser.Statement
+	}
+
 	//
 	// Testing state.
 	//
@@ -295,9 +336,11 @@ func NewSession(
 		distSQLMode = DistSQLExecModeFromInt(e.cfg.TestingKnobs.OverrideDistSQLMode.Get())
 	}
 	s := &Session{
+		id:               e.generateID(),
 		Database:         args.Database,
 		DistSQLMode:      distSQLMode,
 		SearchPath:       sqlbase.DefaultSearchPath,
+		StatementTimeout: StatementClusterTimeout.Get(),
 		Location:         time.UTC,
 		User:             args.User,
 		virtualSchemas:   e.virtualSchemas,
@@ -316,9 +359,13 @@ func NewSession(
 		},
 	}
 	s.phaseTimes[sessionInit] = timeutil.Now()
+	s.mu.ActiveQueries = make(map[string]*queryMeta)
 	s.resetApplicationName(args.ApplicationName)
 	s.PreparedStatements = makePreparedStatements(s)
 	s.PreparedPortals = makePreparedPortals(s)
+	if remote != nil {
+		s.clientAddr = remote.String()
+	}
 
 	if traceSessionEventLogEnabled.Get() {
 		remoteStr := "<admin>"
@@ -328,6 +375,7 @@ func NewSession(
 		s.eventLog = trace.NewEventLog(fmt.Sprintf("sql [%s]", args.User), remoteStr)
 	}
 	s.context, s.cancel = context.WithCancel(ctx)
+	e.cfg.SessionRegistry.register(s)
 
 	return s
 }
@@ -345,6 +393,7 @@ func (s *Session) Finish(e *Executor) {
 		panic("session.Finish: session monitors were never initialized. Missing call " +
 			"to session.StartMonitor?")
 	}
+	e.cfg.SessionRegistry.deregister(s)
 
 	// Make sure that no statements remain in the ParallelizeQueue. If no statements
 	// are in the queue, this will be a no-op. If there are statements in the
@@ -565,6 +614,9 @@ type txnState struct {
 
 	// Ctx is the context for everything running in this SQL txn.
 	Ctx context.Context
+	// cancel cancels Ctx. It is called by finishSQLTxn, or earlier when one of
+	// the txn's queries is canceled.
+	cancel context.CancelFunc
 
 	// If set, the user declared the intention to retry the txn in case of retriable
 	// errors. The txn will enter a RestartWait state in case of such errors.
@@ -648,7 +700,7 @@ func (ts *txnState) resetForNewSQLTxn(e *Executor, s *Session) {
 	}
 
 	ts.sp = opentracing.SpanFromContext(ctx)
-	ts.Ctx = ctx
+	ts.Ctx, ts.cancel = context.WithCancel(ctx)
 
 	ts.mon.Start(ctx, &s.mon, mon.BoundAccount{})
 
@@ -695,6 +747,7 @@ func (ts *txnState) finishSQLTxn(sessionCtx context.Context) {
 	sampledFor7881 := (ts.sp.BaggageItem(keyFor7881Sample) != "")
 	ts.sp.Finish()
 	ts.sp = nil
+	ts.cancel()
 	if ts.trace != nil {
 		durThreshold := traceTxnThreshold.Get()
 		if sampledFor7881 || (durThreshold > 0 && timeutil.Since(ts.sqlTimestamp) >= durThreshold) {
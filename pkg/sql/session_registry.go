@@ -0,0 +1,320 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// SessionRegistry stores the sessions running on a node, so that they and the
+// queries they are running can be listed (see crdb_internal.node_sessions and
+// crdb_internal.node_queries) and canceled (see CANCEL QUERY).
+type SessionRegistry struct {
+	syncutil.Mutex
+	// store maps the cancel secret of each session to the session.
+	store map[int32]*Session
+}
+
+// MakeSessionRegistry creates a new SessionRegistry with an empty set of
+// sessions.
+func MakeSessionRegistry() *SessionRegistry {
+	return &SessionRegistry{store: make(map[int32]*Session)}
+}
+
+// register adds the session to the registry and assigns it a cancel secret
+// that is unique among the sessions of this node.
+func (r *SessionRegistry) register(s *Session) {
+	r.Lock()
+	defer r.Unlock()
+	for {
+		s.cancelSecret = randomCancelSecret()
+		if _, ok := r.store[s.cancelSecret]; !ok {
+			break
+		}
+	}
+	r.store[s.cancelSecret] = s
+}
+
+func (r *SessionRegistry) deregister(s *Session) {
+	r.Lock()
+	delete(r.store, s.cancelSecret)
+	r.Unlock()
+}
+
+// sessionInfos returns a snapshot of the registered sessions that the given
+// user is allowed to see, ordered by session start time. The root user can see
+// all sessions; other users only see their own.
+func (r *SessionRegistry) sessionInfos(user string) []sessionInfo {
+	r.Lock()
+	infos := make([]sessionInfo, 0, len(r.store))
+	for _, s := range r.store {
+		if user == security.RootUser || user == s.User {
+			infos = append(infos, s.info())
+		}
+	}
+	r.Unlock()
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].start.Equal(infos[j].start) {
+			return infos[i].start.Before(infos[j].start)
+		}
+		return infos[i].id < infos[j].id
+	})
+	return infos
+}
+
+// cancelQuery cancels the query with the given ID on behalf of the given user.
+// The root user can cancel any query; other users can only cancel their own.
+// It returns false if no such query is running on this node.
+func (r *SessionRegistry) cancelQuery(queryID string, user string) bool {
+	r.Lock()
+	defer r.Unlock()
+	for _, s := range r.store {
+		if user != security.RootUser && user != s.User {
+			continue
+		}
+		if s.cancelQuery(queryID) {
+			return true
+		}
+	}
+	return false
+}
+
+// CancelQueryBySecret cancels the queries running in the session with the
+// given cancel secret. It returns false if no session on this node has that
+// secret. See Session.BackendKey().
+func (r *SessionRegistry) CancelQueryBySecret(secret int32) bool {
+	r.Lock()
+	defer r.Unlock()
+	s, ok := r.store[secret]
+	if !ok {
+		return false
+	}
+	s.cancelActiveQueries()
+	return true
+}
+
+// randomCancelSecret returns a cryptographically random secret, so that
+// clients cannot guess the cancel keys of other sessions.
+func randomCancelSecret() int32 {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return int32(binary.BigEndian.Uint32(b[:]))
+}
+
+// generateID returns an ID that is unique across the cluster: it combines the
+// current HLC timestamp of this node, which never repeats, with the node's ID.
+func (e *Executor) generateID() string {
+	ts := e.cfg.Clock.Now()
+	return fmt.Sprintf("%016x%08x%08x", ts.WallTime, uint32(ts.Logical), uint32(e.cfg.NodeID.Get()))
+}
+
+// queryPhase is the phase of execution a query is in.
+type queryPhase int
+
+const (
+	// preparing is the phase during which the query is planned.
+	preparing queryPhase = iota
+	// executing is the phase during which the plan runs.
+	executing
+)
+
+func (p queryPhase) String() string {
+	switch p {
+	case preparing:
+		return "preparing"
+	case executing:
+		return "executing"
+	default:
+		return fmt.Sprintf("queryPhase(%d)", int(p))
+	}
+}
+
+// queryMeta stores the metadata of a query running in a session. The fields
+// that change while the query runs are protected by the session's mu.
+type queryMeta struct {
+	// id uniquely identifies the query across the cluster.
+	id string
+	// start is the time at which the query started running.
+	start time.Time
+	// stmt is the statement being run.
+	stmt parser.Statement
+
+	// phase is the current phase of execution of the query.
+	phase queryPhase
+	// isDistributed is set if the query is run through DistSQL.
+	isDistributed bool
+
+	// ctxCancel cancels the context of the SQL transaction in which the query
+	// runs. A canceled query thus aborts its transaction.
+	ctxCancel context.CancelFunc
+
+	// timeoutTimer cancels the query when the session's statement timeout
+	// expires. It is nil if the session has no statement timeout.
+	timeoutTimer *time.Timer
+	// timedOut is set if the query was canceled by its timeout.
+	timedOut bool
+}
+
+// sessionInfo is a snapshot of the state of a session and of its active
+// queries.
+type sessionInfo struct {
+	id              string
+	user            string
+	clientAddr      string
+	applicationName string
+	start           time.Time
+	activeQueries   []queryInfo
+	lastActiveQuery string
+}
+
+// queryInfo is a snapshot of the state of a query.
+type queryInfo struct {
+	id            string
+	start         time.Time
+	sql           string
+	phase         queryPhase
+	isDistributed bool
+}
+
+// info returns a snapshot of the state of the session. It is safe to call
+// concurrently with the session's execution.
+func (s *Session) info() sessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := sessionInfo{
+		id:              s.id,
+		user:            s.User,
+		clientAddr:      s.clientAddr,
+		applicationName: s.ApplicationName,
+		start:           s.phaseTimes[sessionInit],
+		activeQueries:   make([]queryInfo, 0, len(s.mu.ActiveQueries)),
+	}
+	for _, qm := range s.mu.ActiveQueries {
+		info.activeQueries = append(info.activeQueries, queryInfo{
+			id:            qm.id,
+			start:         qm.start,
+			sql:           qm.stmt.String(),
+			phase:         qm.phase,
+			isDistributed: qm.isDistributed,
+		})
+	}
+	sort.Slice(info.activeQueries, func(i, j int) bool {
+		return info.activeQueries[i].id < info.activeQueries[j].id
+	})
+	if s.mu.LastActiveQuery != nil {
+		info.lastActiveQuery = s.mu.LastActiveQuery.String()
+	}
+	return info
+}
+
+// addActiveQuery registers a query that starts running in the session's
+// current SQL transaction. If the session has a statement timeout, the query
+// is canceled once the timeout expires.
+func (s *Session) addActiveQuery(id string, stmt parser.Statement) *queryMeta {
+	qm := &queryMeta{
+		id:        id,
+		start:     timeutil.Now(),
+		stmt:      stmt,
+		phase:     preparing,
+		ctxCancel: s.TxnState.cancel,
+	}
+	s.mu.Lock()
+	s.mu.ActiveQueries[id] = qm
+	s.mu.LastActiveQuery = stmt
+	s.mu.Unlock()
+	if s.StatementTimeout > 0 {
+		qm.timeoutTimer = time.AfterFunc(s.StatementTimeout, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			// The query may have finished while the timer fired.
+			if _, ok := s.mu.ActiveQueries[qm.id]; ok {
+				qm.timedOut = true
+				qm.ctxCancel()
+			}
+		})
+	}
+	return qm
+}
+
+// removeActiveQuery deregisters a query once it is done running.
+func (s *Session) removeActiveQuery(qm *queryMeta) {
+	s.mu.Lock()
+	delete(s.mu.ActiveQueries, qm.id)
+	s.mu.Unlock()
+	if qm.timeoutTimer != nil {
+		qm.timeoutTimer.Stop()
+	}
+}
+
+// queryTimedOut returns whether the query was canceled by the statement
+// timeout.
+func (s *Session) queryTimedOut(qm *queryMeta) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return qm.timedOut
+}
+
+// setQueryExecutionMode records that the query has been planned and is now
+// executing, possibly through DistSQL.
+func (s *Session) setQueryExecutionMode(qm *queryMeta, isDistributed bool) {
+	s.mu.Lock()
+	qm.phase = executing
+	qm.isDistributed = isDistributed
+	s.mu.Unlock()
+}
+
+// BackendKey returns the key with which clients can cancel the session's
+// queries from another connection, for example with the pgwire CancelRequest
+// message. The key is made of the ID of the node running the session and a
+// secret; a cancel request can thus be routed to this node from any node.
+func (s *Session) BackendKey() (roachpb.NodeID, int32) {
+	return s.execCfg.NodeID.Get(), s.cancelSecret
+}
+
+// cancelActiveQueries cancels all the queries running in the session.
+func (s *Session) cancelActiveQueries() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, qm := range s.mu.ActiveQueries {
+		qm.ctxCancel()
+	}
+}
+
+// cancelQuery cancels the query with the given ID if it is running in the
+// session.
+func (s *Session) cancelQuery(queryID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	qm, ok := s.mu.ActiveQueries[queryID]
+	if !ok {
+		return false
+	}
+	qm.ctxCancel()
+	return true
+}
@@ -18,6 +18,7 @@ package sql
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -249,6 +250,45 @@ func (p *planner) getStringVal(name string, values []parser.TypedExpr) (string,
 	return string(s), nil
 }
 
+// getTimeoutVal evaluates the value of a timeout variable. Like in Postgres,
+// integers and strings without a unit are interpreted as milliseconds, and
+// other strings as intervals, for example '10s' or '1 minute'.
+func (p *planner) getTimeoutVal(name string, values []parser.TypedExpr) (time.Duration, error) {
+	if len(values) != 1 {
+		return 0, fmt.Errorf("set %s: requires a single value", name)
+	}
+	val, err := values[0].Eval(&p.evalCtx)
+	if err != nil {
+		return 0, err
+	}
+	var timeout time.Duration
+	switch v := val.(type) {
+	case *parser.DInt:
+		timeout = time.Duration(*v) * time.Millisecond
+	case *parser.DString:
+		if ms, err := strconv.ParseInt(string(*v), 10, 64); err == nil {
+			timeout = time.Duration(ms) * time.Millisecond
+			break
+		}
+		interval, err := parser.ParseDInterval(string(*v))
+		if err != nil {
+			return 0, fmt.Errorf("set %s: %v", name, err)
+		}
+		nanos, _, _, err := interval.Duration.Encode()
+		if err != nil {
+			return 0, fmt.Errorf("set %s: %v", name, err)
+		}
+		timeout = time.Duration(nanos)
+	default:
+		return 0, fmt.Errorf("set %s: requires an integer or string value: %s is a %s",
+			name, values[0], val.ResolvedType())
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("set %s: cannot be negative: %s", name, values[0])
+	}
+	return timeout, nil
+}
+
 func (p *planner) SetDefaultIsolation(n *parser.SetDefaultIsolation) (planNode, error) {
 	// Note: We also support SET DEFAULT_TRANSACTION_ISOLATION TO ' .... ' above.
 	// Ensure both versions stay in sync.
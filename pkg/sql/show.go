@@ -421,6 +421,9 @@ func (p *planner) showCreateTable(
 		if col.DefaultExpr != nil {
 			fmt.Fprintf(&buf, " DEFAULT %s", *col.DefaultExpr)
 		}
+		if col.IsComputed() {
+			fmt.Fprintf(&buf, " AS (%s) STORED", *col.ComputedExpr)
+		}
 		if desc.IsPhysicalTable() && desc.PrimaryIndex.ColumnIDs[0] == col.ID {
 			// Only set primary if the primary key is on a visible column (not rowid).
 			primary = fmt.Sprintf(",\n\tCONSTRAINT %s PRIMARY KEY (%s)",
@@ -439,6 +442,10 @@ func (p *planner) showCreateTable(
 		if err != nil {
 			return "", err
 		}
+		var predicate string
+		if idx.IsPartial() {
+			predicate = fmt.Sprintf(" WHERE %s", idx.Predicate)
+		}
 		if fk := idx.ForeignKey; fk.IsSet() {
 			fkTable, err := p.session.leases.getTableLeaseByID(ctx, p.txn, fk.Table)
 			if err != nil {
@@ -455,12 +462,14 @@ func (p *planner) showCreateTable(
 				quoteNames(fkIdx.ColumnNames...),
 			)
 		} else {
-			fmt.Fprintf(&buf, ",\n\t%sINDEX %s (%s)%s%s",
+			fmt.Fprintf(&buf, ",\n\t%s%sINDEX %s (%s)%s%s%s",
 				isUnique[idx.Unique],
+				isInverted[idx.Type == sqlbase.IndexDescriptor_INVERTED],
 				quoteNames(idx.Name),
 				makeIndexColNames(idx),
 				storing,
 				interleave,
+				predicate,
 			)
 		}
 	}
@@ -508,6 +517,7 @@ func makeIndexColNames(d sqlbase.IndexDescriptor) string {
 }
 
 var isUnique = map[bool]string{true: "UNIQUE "}
+var isInverted = map[bool]string{true: "INVERTED "}
 
 // quoteName quotes and adds commas between names.
 func quoteNames(names ...string) string {
@@ -928,6 +938,41 @@ func (p *planner) ShowUsers(ctx context.Context, n *parser.ShowUsers) (planNode,
 	return p.newPlan(ctx, stmt, nil)
 }
 
+// ShowJobs returns all the jobs.
+// Privileges: SELECT on system.jobs.
+func (p *planner) ShowJobs(ctx context.Context, n *parser.ShowJobs) (planNode, error) {
+	stmt, err := parser.ParseOne(`SELECT id, type, description, username, status, created, started,
+		finished, modified, fraction_completed, error FROM crdb_internal.jobs ORDER BY created, id`)
+	if err != nil {
+		return nil, err
+	}
+	return p.newPlan(ctx, stmt, nil)
+}
+
+// ShowQueries returns the queries running on this node.
+// Privileges: None; non-root users only see their own queries.
+func (p *planner) ShowQueries(ctx context.Context, n *parser.ShowQueries) (planNode, error) {
+	stmt, err := parser.ParseOne(`SELECT query_id, node_id, username, start, query,
+		client_address, application_name, distributed, phase
+		FROM crdb_internal.node_queries ORDER BY start, query_id`)
+	if err != nil {
+		return nil, err
+	}
+	return p.newPlan(ctx, stmt, nil)
+}
+
+// ShowSessions returns the sessions running on this node.
+// Privileges: None; non-root users only see their own sessions.
+func (p *planner) ShowSessions(ctx context.Context, n *parser.ShowSessions) (planNode, error) {
+	stmt, err := parser.ParseOne(`SELECT session_id, node_id, username, client_address,
+		application_name, active_queries, last_active_query, session_start,
+		oldest_query_start FROM crdb_internal.node_sessions ORDER BY session_start, session_id`)
+	if err != nil {
+		return nil, err
+	}
+	return p.newPlan(ctx, stmt, nil)
+}
+
 // Help returns usage information for the builtin functions
 // Privileges: None
 func (p *planner) Help(ctx context.Context, n *parser.Help) (planNode, error) {
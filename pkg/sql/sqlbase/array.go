@@ -0,0 +1,73 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// encodeArray appends the encoding of the elements of the array to appendTo:
+// the number of elements followed by the value encoding of each element,
+// NULLs included.
+func encodeArray(appendTo []byte, d *parser.DArray) ([]byte, error) {
+	b := encoding.EncodeNonsortingUvarint(appendTo, uint64(d.Len()))
+	for _, elem := range d.Array {
+		var err error
+		b, err = EncodeTableValue(b, ColumnID(encoding.NoColumnID), elem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// decodeArray decodes the elements of an array of the given element type
+// encoded by encodeArray.
+func decodeArray(a *DatumAlloc, elemType parser.Type, b []byte) (parser.Datum, error) {
+	b, _, n, err := encoding.DecodeNonsortingUvarint(b)
+	if err != nil {
+		return nil, err
+	}
+	result := parser.NewDArray(elemType)
+	result.Array = make(parser.Datums, 0, n)
+	for i := uint64(0); i < n; i++ {
+		var elem parser.Datum
+		elem, b, err = DecodeTableValue(a, elemType, b)
+		if err != nil {
+			return nil, err
+		}
+		if err := result.Append(elem); err != nil {
+			return nil, err
+		}
+	}
+	if len(b) > 0 {
+		return nil, errors.Errorf("%d trailing bytes in encoded array", len(b))
+	}
+	return result, nil
+}
+
+// checkArrayElementType verifies that the elements of the array have the type
+// of the elements of the array column.
+func checkArrayElementType(col ColumnDescriptor, d *parser.DArray) error {
+	elemType := col.Type.ElementType()
+	if d.ParamTyp == parser.TypeNull || d.ParamTyp.Equivalent(elemType.ToDatumType()) {
+		return nil
+	}
+	return errors.Errorf("value type %s doesn't match type %s of column %q",
+		d.ResolvedType(), col.Type.SQLString(), col.Name)
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// ColumnConverter converts the values of a column whose type is changed to
+// values of its shadow column (see DescriptorMutation.ShadowedColumnID).
+//
+// Values are cast to the new type without its width, precision or scale and
+// then checked against them, so that values that don't fit the new type are
+// rejected instead of being silently truncated.
+type ColumnConverter struct {
+	from, to ColumnDescriptor
+	expr     parser.TypedExpr
+	evalCtx  parser.EvalContext
+	curVal   parser.Datum
+}
+
+var _ parser.IndexedVarContainer = &ColumnConverter{}
+
+// MakeColumnConverter returns a converter from the values of column from to
+// values of column to. It returns an error if there is no cast between the
+// types of the columns.
+func MakeColumnConverter(from, to ColumnDescriptor) (*ColumnConverter, error) {
+	c := &ColumnConverter{from: from, to: to}
+	toType := to.Type.ToDatumType()
+	target, err := parser.DatumTypeToColumnType(toType)
+	if err != nil {
+		return nil, err
+	}
+	h := parser.MakeIndexedVarHelper(c, 1)
+	cast := &parser.CastExpr{Expr: h.IndexedVar(0), Type: target}
+	if c.expr, err = cast.TypeCheck(&parser.SemaContext{}, toType); err != nil {
+		return nil, pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+			"column %q cannot be converted from %s to %s",
+			from.Name, from.Type.SQLString(), to.Type.SQLString())
+	}
+	return c, nil
+}
+
+// Convert returns the value of the shadow column for the given value of the
+// converted column.
+func (c *ColumnConverter) Convert(val parser.Datum) (parser.Datum, error) {
+	if val == parser.DNull {
+		return parser.DNull, nil
+	}
+	c.curVal = val
+	res, err := c.expr.Eval(&c.evalCtx)
+	if err == nil {
+		err = CheckValueWidth(c.to, res)
+	}
+	if err != nil {
+		return nil, NewColumnConversionError(c.from.Name, val, c.to.Type, err)
+	}
+	return res, nil
+}
+
+// IndexedVarEval implements the parser.IndexedVarContainer interface.
+func (c *ColumnConverter) IndexedVarEval(idx int, ctx *parser.EvalContext) (parser.Datum, error) {
+	return c.curVal, nil
+}
+
+// IndexedVarResolvedType implements the parser.IndexedVarContainer interface.
+func (c *ColumnConverter) IndexedVarResolvedType(idx int) parser.Type {
+	return c.from.Type.ToDatumType()
+}
+
+// IndexedVarFormat implements the parser.IndexedVarContainer interface.
+func (c *ColumnConverter) IndexedVarFormat(buf *bytes.Buffer, f parser.FmtFlags, idx int) {
+	parser.FormatNode(buf, f, parser.Name(c.from.Name))
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestColumnConverter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	intCol := ColumnDescriptor{Name: "a", Type: ColumnType{Kind: ColumnType_INT}}
+	decimalCol := ColumnDescriptor{
+		Name: "b", Type: ColumnType{Kind: ColumnType_DECIMAL, Precision: 4, Width: 2},
+	}
+	stringCol := ColumnDescriptor{Name: "c", Type: ColumnType{Kind: ColumnType_STRING}}
+	shortStringCol := ColumnDescriptor{
+		Name: "d", Type: ColumnType{Kind: ColumnType_STRING, Width: 3},
+	}
+	bytesCol := ColumnDescriptor{Name: "e", Type: ColumnType{Kind: ColumnType_BYTES}}
+
+	testData := []struct {
+		from, to ColumnDescriptor
+		val      parser.Datum
+		expected string
+		err      string
+	}{
+		{intCol, decimalCol, parser.NewDInt(12), "12.00", ""},
+		{intCol, decimalCol, parser.DNull, "NULL", ""},
+		{intCol, decimalCol, parser.NewDInt(123), "", "cannot be converted to DECIMAL\\(4,2\\)"},
+		{intCol, stringCol, parser.NewDInt(123), "'123'", ""},
+		{stringCol, intCol, parser.NewDString("42"), "42", ""},
+		{stringCol, intCol, parser.NewDString("foo"), "", `value 'foo' of column "c"`},
+		{stringCol, shortStringCol, parser.NewDString("abc"), "'abc'", ""},
+		{stringCol, shortStringCol, parser.NewDString("abcd"), "", "value too long"},
+	}
+	for _, d := range testData {
+		c, err := MakeColumnConverter(d.from, d.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := c.Convert(d.val)
+		if !testutils.IsError(err, d.err) {
+			t.Errorf("%s -> %s: expected error %q, got %v",
+				d.val, d.to.Type.SQLString(), d.err, err)
+			continue
+		}
+		if err == nil && res.String() != d.expected {
+			t.Errorf("%s -> %s: expected %s, got %s", d.val, d.to.Type.SQLString(), d.expected, res)
+		}
+	}
+
+	if _, err := MakeColumnConverter(intCol, bytesCol); !testutils.IsError(
+		err, `column "a" cannot be converted from INT to BYTES`,
+	) {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
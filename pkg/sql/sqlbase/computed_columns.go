@@ -0,0 +1,295 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+)
+
+// IsComputed returns whether the column is a computed column.
+func (desc *ColumnDescriptor) IsComputed() bool {
+	return desc.ComputedExpr != nil
+}
+
+// ComputedColumnEvaluator computes the value of a computed column from the
+// values of the other columns of a row.
+//
+// The computed expression can only reference the public columns of the table
+// that are not computed themselves, and can only use pure functions, so that
+// the value of the column only depends on the row it belongs to.
+type ComputedColumnEvaluator struct {
+	col ColumnDescriptor
+	rowExpr
+}
+
+// MakeComputedColumnEvaluator returns an evaluator for the computed column
+// col of the table.
+func MakeComputedColumnEvaluator(
+	tableDesc *TableDescriptor, col ColumnDescriptor,
+) (*ComputedColumnEvaluator, error) {
+	return makeComputedColumnEvaluator(tableDesc, col, nil)
+}
+
+// ValidateComputedColumn verifies that the expression of the computed column
+// col is valid for the table. The search path is used for name resolution of
+// the functions used by the expression.
+func ValidateComputedColumn(
+	tableDesc *TableDescriptor, col ColumnDescriptor, searchPath parser.SearchPath,
+) error {
+	_, err := makeComputedColumnEvaluator(tableDesc, col, searchPath)
+	return err
+}
+
+func makeComputedColumnEvaluator(
+	tableDesc *TableDescriptor, col ColumnDescriptor, searchPath parser.SearchPath,
+) (*ComputedColumnEvaluator, error) {
+	if !col.IsComputed() {
+		return nil, errors.Errorf("column %q is not a computed column", col.Name)
+	}
+	c := &ComputedColumnEvaluator{col: col}
+	subject := fmt.Sprintf("computed column %q", col.Name)
+	checkRef := func(ref *ColumnDescriptor) error {
+		if ref.IsComputed() {
+			return errors.Errorf("%s cannot reference computed column %q", subject, ref.Name)
+		}
+		return nil
+	}
+	if err := c.rowExpr.init(
+		tableDesc, *col.ComputedExpr, col.Type.ToDatumType(), "computed column", subject, checkRef,
+		searchPath,
+	); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Column returns the computed column.
+func (c *ComputedColumnEvaluator) Column() ColumnDescriptor {
+	return c.col
+}
+
+// ReferencedColumnIDs returns the IDs of the columns that the value of the
+// computed column depends on.
+func (c *ComputedColumnEvaluator) ReferencedColumnIDs() []ColumnID {
+	return c.refs
+}
+
+// Eval returns the value of the computed column for the given row, whose
+// layout is given by colIDtoRowIndex. Columns missing from the row are NULL.
+// It returns an error if the value cannot be written to the column.
+func (c *ComputedColumnEvaluator) Eval(
+	row parser.Datums, colIDtoRowIndex map[ColumnID]int,
+) (parser.Datum, error) {
+	val, err := c.eval(row, colIDtoRowIndex)
+	if err != nil {
+		return nil, err
+	}
+	if val == parser.DNull && !c.col.Nullable {
+		return nil, NewNonNullViolationError(c.col.Name)
+	}
+	if err := CheckValueWidth(c.col, val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// rowExpr is an expression over the public columns of a table, evaluated on
+// the rows of the table. It is used for the expressions of computed columns
+// and the predicates of partial indexes.
+type rowExpr struct {
+	// cols are the public columns of the table. The IndexedVars of expr are
+	// indexes into cols.
+	cols    []ColumnDescriptor
+	expr    parser.TypedExpr
+	evalCtx parser.EvalContext
+	// refs are the IDs of the columns referenced by expr.
+	refs []ColumnID
+
+	// The row being evaluated.
+	row             parser.Datums
+	colIDtoRowIndex map[ColumnID]int
+}
+
+var _ parser.IndexedVarContainer = &rowExpr{}
+
+// init parses and type checks the expression exprStr, which must be of type
+// typ. Errors describe the expression as a context expression, and name its
+// owner as subject. checkRef, if not nil, is called on every column referenced
+// by the expression.
+func (r *rowExpr) init(
+	tableDesc *TableDescriptor,
+	exprStr string,
+	typ parser.Type,
+	context, subject string,
+	checkRef func(*ColumnDescriptor) error,
+	searchPath parser.SearchPath,
+) error {
+	r.cols = tableDesc.Columns
+	expr, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		return err
+	}
+	h := parser.MakeIndexedVarHelper(r, len(r.cols))
+	refs := make(map[ColumnID]struct{})
+	preFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		switch t := expr.(type) {
+		case *parser.Subquery:
+			return errors.Errorf("%s cannot use subqueries", subject), false, nil
+		case parser.VarName:
+			v, err := t.NormalizeVarName()
+			if err != nil {
+				return err, false, nil
+			}
+			item, ok := v.(*parser.ColumnItem)
+			if !ok || item.TableName.TableName != "" || len(item.Selector) > 0 {
+				return errors.Errorf("%s cannot reference %s", subject, t), false, nil
+			}
+			for i := range r.cols {
+				ref := &r.cols[i]
+				if parser.ReNormalizeName(ref.Name) != item.ColumnName.Normalize() {
+					continue
+				}
+				if checkRef != nil {
+					if err := checkRef(ref); err != nil {
+						return err, false, nil
+					}
+				}
+				refs[ref.ID] = struct{}{}
+				return nil, false, h.IndexedVar(i)
+			}
+			return errors.Errorf("column %q does not exist", item.ColumnName.Normalize()), false, nil
+		}
+		return nil, true, expr
+	}
+	if expr, err = parser.SimpleVisit(expr, preFn); err != nil {
+		return err
+	}
+
+	var p parser.Parser
+	if err := p.AssertNoAggregationOrWindowing(
+		expr, context+" expressions", searchPath,
+	); err != nil {
+		return err
+	}
+	ctx := parser.SemaContext{SearchPath: searchPath}
+	if r.expr, err = parser.TypeCheck(expr, &ctx, typ); err != nil {
+		return err
+	}
+	if actual := r.expr.ResolvedType(); !typ.Equivalent(actual) && r.expr != parser.DNull {
+		return incompatibleExprTypeError(context, typ, actual)
+	}
+	impureFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		if f, ok := expr.(*parser.FuncExpr); ok && f.IsImpure() {
+			return errors.Errorf("%s cannot use impure function %s",
+				subject, f.Func.String()), false, nil
+		}
+		return nil, true, expr
+	}
+	if _, err := parser.SimpleVisit(r.expr, impureFn); err != nil {
+		return err
+	}
+
+	for id := range refs {
+		r.refs = append(r.refs, id)
+	}
+	return nil
+}
+
+// eval evaluates the expression on the given row, whose layout is given by
+// colIDtoRowIndex. Columns missing from the row are NULL.
+func (r *rowExpr) eval(row parser.Datums, colIDtoRowIndex map[ColumnID]int) (parser.Datum, error) {
+	r.row, r.colIDtoRowIndex = row, colIDtoRowIndex
+	val, err := r.expr.Eval(&r.evalCtx)
+	r.row, r.colIDtoRowIndex = nil, nil
+	return val, err
+}
+
+// IndexedVarEval implements the parser.IndexedVarContainer interface.
+func (r *rowExpr) IndexedVarEval(idx int, ctx *parser.EvalContext) (parser.Datum, error) {
+	if i, ok := r.colIDtoRowIndex[r.cols[idx].ID]; ok {
+		return r.row[i], nil
+	}
+	return parser.DNull, nil
+}
+
+// IndexedVarResolvedType implements the parser.IndexedVarContainer interface.
+func (r *rowExpr) IndexedVarResolvedType(idx int) parser.Type {
+	return r.cols[idx].Type.ToDatumType()
+}
+
+// IndexedVarFormat implements the parser.IndexedVarContainer interface.
+func (r *rowExpr) IndexedVarFormat(buf *bytes.Buffer, f parser.FmtFlags, idx int) {
+	parser.FormatNode(buf, f, parser.Name(r.cols[idx].Name))
+}
+
+// FindComputedColumnReferencing returns a computed column of the table, public
+// or in a mutation, whose expression references the named column, or nil if
+// there is none.
+func (desc *TableDescriptor) FindComputedColumnReferencing(
+	name string,
+) (*ColumnDescriptor, error) {
+	cols := make([]*ColumnDescriptor, 0, len(desc.Columns)+len(desc.Mutations))
+	for i := range desc.Columns {
+		cols = append(cols, &desc.Columns[i])
+	}
+	for _, m := range desc.Mutations {
+		if col := m.GetColumn(); col != nil {
+			cols = append(cols, col)
+		}
+	}
+	for _, col := range cols {
+		if !col.IsComputed() {
+			continue
+		}
+		if found, err := exprReferencesColumn(*col.ComputedExpr, name); err != nil {
+			return nil, err
+		} else if found {
+			return col, nil
+		}
+	}
+	return nil, nil
+}
+
+// exprReferencesColumn returns whether the serialized expression exprStr
+// references the named column.
+func exprReferencesColumn(exprStr string, name string) (bool, error) {
+	expr, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		return false, err
+	}
+	normName := parser.ReNormalizeName(name)
+	found := false
+	preFn := func(expr parser.Expr) (err error, recurse bool, newExpr parser.Expr) {
+		vBase, ok := expr.(parser.VarName)
+		if !ok {
+			return nil, true, expr
+		}
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return err, false, nil
+		}
+		if item, ok := v.(*parser.ColumnItem); ok && item.ColumnName.Normalize() == normName {
+			found = true
+		}
+		return nil, false, v
+	}
+	_, err = parser.SimpleVisit(expr, preFn)
+	return found, err
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestComputedColumnEvaluator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	intType := ColumnType{Kind: ColumnType_INT}
+	computed := func(name, expr string, nullable bool) ColumnDescriptor {
+		return ColumnDescriptor{
+			Name: name, ID: 10, Type: intType, Nullable: nullable, ComputedExpr: &expr,
+		}
+	}
+	desc := TableDescriptor{
+		Columns: []ColumnDescriptor{
+			{Name: "a", ID: 1, Type: intType},
+			{Name: "b", ID: 2, Type: intType, Nullable: true},
+			computed("c", "a * 2", true),
+		},
+	}
+
+	testData := []struct {
+		col      ColumnDescriptor
+		row      parser.Datums
+		expected string
+		err      string
+	}{
+		{computed("d", "a + b", true), parser.Datums{parser.NewDInt(1), parser.NewDInt(2)}, "3", ""},
+		{computed("d", "a + b", true), parser.Datums{parser.NewDInt(1), parser.DNull}, "NULL", ""},
+		{computed("d", "a + b", false), parser.Datums{parser.NewDInt(1), parser.DNull}, "",
+			`null value in column "d" violates not-null constraint`},
+		{computed("d", "a // b", true), parser.Datums{parser.NewDInt(1), parser.NewDInt(0)}, "",
+			"division by zero"},
+	}
+	colIDtoRowIndex := map[ColumnID]int{1: 0, 2: 1}
+	for _, d := range testData {
+		eval, err := MakeComputedColumnEvaluator(&desc, d.col)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := eval.Eval(d.row, colIDtoRowIndex)
+		if !testutils.IsError(err, d.err) {
+			t.Errorf("%s: expected error %q, got %v", *d.col.ComputedExpr, d.err, err)
+			continue
+		}
+		if err == nil && res.String() != d.expected {
+			t.Errorf("%s: expected %s, got %s", *d.col.ComputedExpr, d.expected, res)
+		}
+	}
+
+	for expr, expectedErr := range map[string]string{
+		"c + 1":          `computed column "d" cannot reference computed column "c"`,
+		"e + 1":          `column "e" does not exist`,
+		"unique_rowid()": `computed column "d" cannot use impure function unique_rowid`,
+		"true":           "incompatible type for computed column expression: bool vs int",
+	} {
+		if err := ValidateComputedColumn(&desc, computed("d", expr, true), nil); !testutils.IsError(
+			err, expectedErr,
+		) {
+			t.Errorf("%s: expected error %q, got %v", expr, expectedErr, err)
+		}
+	}
+}
@@ -191,8 +191,9 @@ func TestEncDatumCompare(t *testing.T) {
 		if kind == ColumnType_NULL {
 			continue
 		}
-		// TODO(cuongdo): we don't support persistence for arrays yet.
-		if kind == ColumnType_INT_ARRAY ||
+		// Arrays and vectors have no key encoding.
+		if kind == ColumnType_ARRAY ||
+			kind == ColumnType_INT_ARRAY ||
 			kind == ColumnType_INT2VECTOR {
 			continue
 		}
@@ -43,6 +43,8 @@ const (
 	txnCommittedMsg = "current transaction is committed, commands ignored " +
 		"until end of transaction block"
 	txnRetryMsgPrefix = "restart transaction"
+	queryCanceledMsg  = "query execution canceled"
+	queryTimeoutMsg   = "query execution canceled due to statement timeout"
 )
 
 // NewRetryError creates an error signifying that the transaction can be retried.
@@ -70,6 +72,17 @@ func NewTransactionCommittedError() error {
 	return pgerror.NewError(pgerror.CodeInvalidTransactionStateError, txnCommittedMsg)
 }
 
+// NewQueryCanceledError creates an error reporting that a query was canceled.
+func NewQueryCanceledError() error {
+	return pgerror.NewError(pgerror.CodeQueryCanceledError, queryCanceledMsg)
+}
+
+// NewQueryTimeoutError creates an error reporting that a query was canceled
+// because it ran for longer than the statement timeout.
+func NewQueryTimeoutError() error {
+	return pgerror.NewError(pgerror.CodeQueryCanceledError, queryTimeoutMsg)
+}
+
 // NewNonNullViolationError creates an error for a violation of a non-NULL constraint.
 func NewNonNullViolationError(columnName string) error {
 	return pgerror.NewErrorf(pgerror.CodeNotNullViolationError, "null value in column %q violates not-null constraint", columnName)
@@ -102,12 +115,27 @@ func NewInvalidSchemaDefinitionError(err error) error {
 	return pgerror.NewError(pgerror.CodeInvalidSchemaDefinitionError, err.Error())
 }
 
+// NewColumnConversionError creates an error for a value of a column that
+// cannot be converted to the new type of the column.
+func NewColumnConversionError(columnName string, val parser.Datum, typ ColumnType, cause error) error {
+	return pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+		"value %s of column %q cannot be converted to %s: %v", val, columnName, typ.SQLString(), cause)
+}
+
+// CannotWriteToComputedColError creates an error for a statement that writes
+// to a computed column.
+func CannotWriteToComputedColError(col ColumnDescriptor) error {
+	return pgerror.NewErrorf(pgerror.CodeObjectNotInPrerequisiteStateError,
+		"cannot write directly to computed column %q", col.Name)
+}
+
 // IsPermanentSchemaChangeError returns true if the error results in
 // a permanent failure of a schema change.
 func IsPermanentSchemaChangeError(err error) bool {
 	return errHasCode(err, pgerror.CodeNotNullViolationError) ||
 		errHasCode(err, pgerror.CodeUniqueViolationError) ||
-		errHasCode(err, pgerror.CodeInvalidSchemaDefinitionError)
+		errHasCode(err, pgerror.CodeInvalidSchemaDefinitionError) ||
+		errHasCode(err, pgerror.CodeDatatypeMismatchError)
 }
 
 // NewUndefinedDatabaseError creates an error that represents a missing database.
@@ -136,6 +164,12 @@ func NewUndefinedViewError(name string) error {
 	return pgerror.NewErrorf(pgerror.CodeUndefinedTableError, "view %q does not exist", name)
 }
 
+// NewUndefinedSequenceError creates an error that represents a missing
+// sequence.
+func NewUndefinedSequenceError(name string) error {
+	return pgerror.NewErrorf(pgerror.CodeUndefinedTableError, "sequence %q does not exist", name)
+}
+
 // IsUndefinedTableError returns true if the error is for an undefined table.
 func IsUndefinedTableError(err error) bool {
 	return errHasCode(err, pgerror.CodeUndefinedTableError)
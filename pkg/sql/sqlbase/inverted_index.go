@@ -0,0 +1,106 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EncodeInvertedIndexKeys encodes the entries of an inverted index for a row.
+// colMap maps ColumnIDs to indices in `values`.
+//
+// There is one entry per distinct non-NULL element of the indexed array. The
+// key of an entry is the key encoding of the element followed by the primary
+// key of the row, so the entries of a value are grouped together regardless of
+// the rows they belong to. The entries are returned sorted by key.
+func EncodeInvertedIndexKeys(
+	tableDesc *TableDescriptor,
+	index *IndexDescriptor,
+	colMap map[ColumnID]int,
+	values []parser.Datum,
+) ([]IndexEntry, error) {
+	if len(index.ColumnIDs) != 1 {
+		return nil, errors.Errorf("inverted index %q must contain exactly 1 column", index.Name)
+	}
+	val := parser.DNull
+	if i, ok := colMap[index.ColumnIDs[0]]; ok {
+		val = values[i]
+	}
+	if val == parser.DNull {
+		return nil, nil
+	}
+	array, ok := parser.AsDArray(val)
+	if !ok {
+		return nil, errors.Errorf("inverted index %q cannot index a value of type %s",
+			index.Name, val.ResolvedType())
+	}
+
+	// The primary key columns are encoded ascendingly, like the extra columns
+	// of other non-unique indexes.
+	extraKey, _, err := EncodeColumns(index.ExtraColumnIDs, nil, colMap, values, nil)
+	if err != nil {
+		return nil, err
+	}
+	keyPrefix := MakeIndexKeyPrefix(tableDesc, index.ID)
+
+	entries := make([]IndexEntry, 0, len(array.Array))
+	for _, elem := range array.Array {
+		if elem == parser.DNull {
+			continue
+		}
+		key, err := EncodeTableKey(append([]byte(nil), keyPrefix...), elem, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		key = append(key, extraKey...)
+		entry := IndexEntry{Key: keys.MakeRowSentinelKey(key)}
+		// The zero value for an index-key is a 0-length bytes value.
+		entry.Value.SetBytes([]byte{})
+		entries = append(entries, entry)
+	}
+
+	// Duplicate elements have a single entry.
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].Key, entries[j].Key) < 0
+	})
+	deduped := entries[:0]
+	for _, entry := range entries {
+		if n := len(deduped); n > 0 && bytes.Equal(entry.Key, deduped[n-1].Key) {
+			continue
+		}
+		deduped = append(deduped, entry)
+	}
+	return deduped, nil
+}
+
+// InvertedIndexSpan returns the span of the entries of an inverted index for
+// the given element.
+func InvertedIndexSpan(
+	tableDesc *TableDescriptor, index *IndexDescriptor, elem parser.Datum,
+) (roachpb.Span, error) {
+	key, err := EncodeTableKey(MakeIndexKeyPrefix(tableDesc, index.ID), elem, encoding.Ascending)
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	return roachpb.Span{Key: key, EndKey: roachpb.Key(key).PrefixEnd()}, nil
+}
@@ -99,6 +99,8 @@ type kvFetcher struct {
 	kvs          []client.KeyValue
 	kvIndex      int
 	totalFetched int64
+	// bytesRead is the total size of the keys and values fetched so far.
+	bytesRead int64
 
 	// returnRangeInfo, is set, causes the kvFetcher to populate rangeInfos.
 	// See also rowFetcher.returnRangeInfo.
@@ -260,6 +262,12 @@ func (f *kvFetcher) fetch(ctx context.Context) error {
 					PrettySpans(f.spans, 0))
 			}
 			f.kvs = append(f.kvs, result.Rows...)
+			for _, kv := range result.Rows {
+				f.bytesRead += int64(len(kv.Key))
+				if kv.Value != nil {
+					f.bytesRead += int64(len(kv.Value.RawBytes))
+				}
+			}
 		}
 		if result.ResumeSpan.Key != nil {
 			// Verify we don't receive results for any remaining spans.
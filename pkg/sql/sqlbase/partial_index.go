@@ -0,0 +1,141 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sqlbase
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+)
+
+// IsPartial returns whether the index is a partial index, which only has
+// entries for the rows that satisfy its predicate.
+func (desc *IndexDescriptor) IsPartial() bool {
+	return desc.Predicate != ""
+}
+
+// PartialIndexPredicate evaluates the predicate of a partial index on the rows
+// of its table.
+//
+// Like the expression of a computed column, the predicate can only reference
+// the public columns of the table and can only use pure functions, so that
+// whether a row has an entry in the index only depends on the row itself.
+type PartialIndexPredicate struct {
+	rowExpr
+}
+
+// MakePartialIndexPredicate returns the predicate of the partial index of the
+// table.
+func MakePartialIndexPredicate(
+	tableDesc *TableDescriptor, index *IndexDescriptor,
+) (*PartialIndexPredicate, error) {
+	return makePartialIndexPredicate(tableDesc, index, nil)
+}
+
+// ValidatePartialIndexPredicate verifies that the predicate of the partial
+// index is a valid boolean expression over the columns of the table. The
+// search path is used for name resolution of the functions used by the
+// predicate.
+func ValidatePartialIndexPredicate(
+	tableDesc *TableDescriptor, index *IndexDescriptor, searchPath parser.SearchPath,
+) error {
+	_, err := makePartialIndexPredicate(tableDesc, index, searchPath)
+	return err
+}
+
+func makePartialIndexPredicate(
+	tableDesc *TableDescriptor, index *IndexDescriptor, searchPath parser.SearchPath,
+) (*PartialIndexPredicate, error) {
+	if !index.IsPartial() {
+		return nil, errors.Errorf("index %q is not a partial index", index.Name)
+	}
+	p := &PartialIndexPredicate{}
+	if err := p.rowExpr.init(
+		tableDesc, index.Predicate, parser.TypeBool, "partial index predicate",
+		fmt.Sprintf("partial index %q", index.Name), nil /* checkRef */, searchPath,
+	); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ReferencedColumnIDs returns the IDs of the columns referenced by the
+// predicate.
+func (p *PartialIndexPredicate) ReferencedColumnIDs() []ColumnID {
+	return p.refs
+}
+
+// Eval returns whether the given row, whose layout is given by
+// colIDtoRowIndex, satisfies the predicate. Columns missing from the row are
+// NULL.
+func (p *PartialIndexPredicate) Eval(
+	row parser.Datums, colIDtoRowIndex map[ColumnID]int,
+) (bool, error) {
+	val, err := p.eval(row, colIDtoRowIndex)
+	if err != nil {
+		return false, err
+	}
+	return val == parser.DBoolTrue, nil
+}
+
+// makePartialIndexPredicates returns the predicates of the given indexes of
+// the table, in a slice parallel to indexes. The predicates of the indexes
+// that are not partial are nil, and the slice is nil if none of them is.
+func makePartialIndexPredicates(
+	tableDesc *TableDescriptor, indexes []IndexDescriptor,
+) ([]*PartialIndexPredicate, error) {
+	var preds []*PartialIndexPredicate
+	for i := range indexes {
+		if !indexes[i].IsPartial() {
+			continue
+		}
+		if preds == nil {
+			preds = make([]*PartialIndexPredicate, len(indexes))
+		}
+		var err error
+		if preds[i], err = MakePartialIndexPredicate(tableDesc, &indexes[i]); err != nil {
+			return nil, err
+		}
+	}
+	return preds, nil
+}
+
+// FindPartialIndexReferencing returns a partial index of the table, public or
+// in a mutation, whose predicate references the named column, or nil if there
+// is none.
+func (desc *TableDescriptor) FindPartialIndexReferencing(name string) (*IndexDescriptor, error) {
+	indexes := make([]*IndexDescriptor, 0, len(desc.Indexes)+len(desc.Mutations))
+	for i := range desc.Indexes {
+		indexes = append(indexes, &desc.Indexes[i])
+	}
+	for _, m := range desc.Mutations {
+		if index := m.GetIndex(); index != nil {
+			indexes = append(indexes, index)
+		}
+	}
+	for _, index := range indexes {
+		if !index.IsPartial() {
+			continue
+		}
+		if found, err := exprReferencesColumn(index.Predicate, name); err != nil {
+			return nil, err
+		} else if found {
+			return index, nil
+		}
+	}
+	return nil, nil
+}
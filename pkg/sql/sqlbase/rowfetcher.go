@@ -82,7 +82,10 @@ type RowFetcher struct {
 
 	// -- Fields updated during a scan --
 
-	kvFetcher      kvFetcher
+	kvFetcher kvFetcher
+	// prevBytesRead is the number of bytes read by the kvFetchers of the
+	// previous scans.
+	prevBytesRead  int64
 	keyVals        []EncDatum  // the index key values for the current row
 	extraVals      EncDatumRow // the extra column values for unique indexes
 	indexKey       []byte      // the index key of the current row
@@ -138,9 +141,14 @@ func (rf *RowFetcher) Init(
 		}
 	}
 
+	inverted := index.Type == IndexDescriptor_INVERTED
 	if isSecondaryIndex {
 		for i, needed := range valNeededForCol {
-			if needed && !index.ContainsColumnID(rf.cols[i].ID) {
+			if !needed {
+				continue
+			}
+			if !index.ContainsColumnID(rf.cols[i].ID) ||
+				(inverted && rf.cols[i].ID == index.ColumnIDs[0]) {
 				return errors.Errorf("requested column %s not in index", rf.cols[i].Name)
 			}
 		}
@@ -152,6 +160,11 @@ func (rf *RowFetcher) Init(
 	if err != nil {
 		return err
 	}
+	if inverted {
+		// The keys of inverted indexes contain an element of the array instead
+		// of the array itself.
+		rf.keyVals[0].Type = rf.keyVals[0].Type.ElementType()
+	}
 
 	if isSecondaryIndex && index.Unique {
 		// Unique secondary indexes have a value that is the primary index
@@ -190,6 +203,7 @@ func (rf *RowFetcher) StartScan(
 		firstBatchLimit++
 	}
 
+	rf.prevBytesRead += rf.kvFetcher.bytesRead
 	var err error
 	rf.kvFetcher, err = makeKVFetcher(txn, spans, rf.reverse, limitBatches, firstBatchLimit, rf.returnRangeInfo)
 	if err != nil {
@@ -286,8 +300,12 @@ func (rf *RowFetcher) ProcessKV(
 			rf.row[i].UnsetDatum()
 		}
 
-		// Fill in the column values that are part of the index key.
+		// Fill in the column values that are part of the index key. The
+		// array column of an inverted index cannot be recovered from its key.
 		for i, v := range rf.keyVals {
+			if i == 0 && rf.index.Type == IndexDescriptor_INVERTED {
+				continue
+			}
 			rf.row[rf.indexColIdx[i]] = v
 		}
 	}
@@ -608,6 +626,12 @@ func (rf *RowFetcher) Key() roachpb.Key {
 	return rf.kv.Key
 }
 
+// BytesRead returns the number of bytes of keys and values that all the scans
+// of the RowFetcher read from the KV layer.
+func (rf *RowFetcher) BytesRead() int64 {
+	return rf.prevBytesRead + rf.kvFetcher.bytesRead
+}
+
 // GetRangeInfo returns information about the ranges where the rows came from.
 // The RangeInfo's are deduped and not ordered.
 func (rf *RowFetcher) GetRangeInfo() []roachpb.RangeInfo {
@@ -45,6 +45,11 @@ type rowHelper struct {
 	TableDesc    *TableDescriptor
 	Indexes      []IndexDescriptor
 	indexEntries []IndexEntry
+	// predicates are the predicates of the partial indexes, parallel to
+	// Indexes. They are nil for the other indexes.
+	predicates []*PartialIndexPredicate
+	// hasInverted is set if some of the Indexes are inverted indexes.
+	hasInverted bool
 
 	// Computed and cached.
 	primaryIndexKeyPrefix []byte
@@ -52,12 +57,33 @@ type rowHelper struct {
 	sortedColumnFamilies  map[FamilyID][]ColumnID
 }
 
+// makeRowHelper returns a rowHelper that writes the given secondary indexes of
+// the table.
+func makeRowHelper(tableDesc *TableDescriptor, indexes []IndexDescriptor) (rowHelper, error) {
+	predicates, err := makePartialIndexPredicates(tableDesc, indexes)
+	if err != nil {
+		return rowHelper{}, err
+	}
+	rh := rowHelper{TableDesc: tableDesc, Indexes: indexes, predicates: predicates}
+	for i := range indexes {
+		if indexes[i].Type == IndexDescriptor_INVERTED {
+			rh.hasInverted = true
+		}
+	}
+	return rh, nil
+}
+
 // encodeIndexes encodes the primary and secondary index keys. The
 // secondaryIndexEntries are only valid until the next call to encodeIndexes or
 // encodeSecondaryIndexes.
 func (rh *rowHelper) encodeIndexes(
 	colIDtoRowIndex map[ColumnID]int, values []parser.Datum,
-) (primaryIndexKey []byte, secondaryIndexEntries []IndexEntry, err error) {
+) (
+	primaryIndexKey []byte,
+	secondaryIndexEntries []IndexEntry,
+	invertedIndexEntries [][]IndexEntry,
+	err error,
+) {
 	if rh.primaryIndexKeyPrefix == nil {
 		rh.primaryIndexKeyPrefix = MakeIndexKeyPrefix(rh.TableDesc,
 			rh.TableDesc.PrimaryIndex.ID)
@@ -65,30 +91,61 @@ func (rh *rowHelper) encodeIndexes(
 	primaryIndexKey, _, err = EncodeIndexKey(
 		rh.TableDesc, &rh.TableDesc.PrimaryIndex, colIDtoRowIndex, values, rh.primaryIndexKeyPrefix)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	secondaryIndexEntries, err = rh.encodeSecondaryIndexes(colIDtoRowIndex, values)
+	secondaryIndexEntries, invertedIndexEntries, err = rh.encodeSecondaryIndexes(colIDtoRowIndex, values)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	return primaryIndexKey, secondaryIndexEntries, nil
+	return primaryIndexKey, secondaryIndexEntries, invertedIndexEntries, nil
 }
 
-// encodeSecondaryIndexes encodes the secondary index keys. The
-// secondaryIndexEntries are only valid until the next call to encodeIndexes or
-// encodeSecondaryIndexes.
+// encodeSecondaryIndexes encodes the secondary index keys. The entries of the
+// partial indexes whose predicate the row does not satisfy have a nil Key, as
+// do the entries of the inverted indexes. The entries of the inverted indexes
+// are instead returned in invertedIndexEntries, which is parallel to Indexes
+// and nil if there are no inverted indexes. The secondaryIndexEntries are only
+// valid until the next call to encodeIndexes or encodeSecondaryIndexes.
 func (rh *rowHelper) encodeSecondaryIndexes(
 	colIDtoRowIndex map[ColumnID]int, values []parser.Datum,
-) (secondaryIndexEntries []IndexEntry, err error) {
+) (secondaryIndexEntries []IndexEntry, invertedIndexEntries [][]IndexEntry, err error) {
 	if len(rh.indexEntries) != len(rh.Indexes) {
 		rh.indexEntries = make([]IndexEntry, len(rh.Indexes))
 	}
 	err = EncodeSecondaryIndexes(
 		rh.TableDesc, rh.Indexes, colIDtoRowIndex, values, rh.indexEntries)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return rh.indexEntries, nil
+	if rh.hasInverted {
+		invertedIndexEntries = make([][]IndexEntry, len(rh.Indexes))
+		for i := range rh.Indexes {
+			if rh.Indexes[i].Type != IndexDescriptor_INVERTED {
+				continue
+			}
+			invertedIndexEntries[i], err = EncodeInvertedIndexKeys(
+				rh.TableDesc, &rh.Indexes[i], colIDtoRowIndex, values)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	for i, pred := range rh.predicates {
+		if pred == nil {
+			continue
+		}
+		ok, err := pred.Eval(values, colIDtoRowIndex)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			rh.indexEntries[i] = IndexEntry{}
+			if invertedIndexEntries != nil {
+				invertedIndexEntries[i] = nil
+			}
+		}
+	}
+	return rh.indexEntries, invertedIndexEntries, nil
 }
 
 // skipColumnInPK returns true if the value at column colID does not need
@@ -140,6 +197,58 @@ func (rh *rowHelper) sortedColumnFamily(famID FamilyID) ([]ColumnID, bool) {
 	return colIDs, ok
 }
 
+// shadowColumn is the shadow column of a column whose type is being changed,
+// which row writers write along with the column it replaces.
+type shadowColumn struct {
+	col ColumnDescriptor
+	// srcIdx and dstIdx are the positions of the values of the replaced column
+	// and of the shadow column in the row being written.
+	srcIdx, dstIdx int
+	conv           *ColumnConverter
+}
+
+func makeShadowColumn(
+	tableDesc *TableDescriptor, m DescriptorMutation, srcIdx, dstIdx int,
+) (shadowColumn, error) {
+	src, err := tableDesc.FindColumnByID(m.ShadowedColumnID)
+	if err != nil {
+		return shadowColumn{}, err
+	}
+	col := *m.GetColumn()
+	conv, err := MakeColumnConverter(*src, col)
+	if err != nil {
+		return shadowColumn{}, err
+	}
+	return shadowColumn{col: col, srcIdx: srcIdx, dstIdx: dstIdx, conv: conv}, nil
+}
+
+// computedColumn is a computed column, which row writers write with the value
+// computed from the other values of the row being written.
+type computedColumn struct {
+	eval *ComputedColumnEvaluator
+	// dstIdx is the position of the value of the column in the row being
+	// written.
+	dstIdx int
+}
+
+// writableComputedColumns returns the computed columns of the table that row
+// writers write: the public ones and the ones in mutation state WRITE_ONLY.
+func writableComputedColumns(tableDesc *TableDescriptor) []ColumnDescriptor {
+	var cols []ColumnDescriptor
+	for _, col := range tableDesc.Columns {
+		if col.IsComputed() {
+			cols = append(cols, col)
+		}
+	}
+	for _, m := range tableDesc.Mutations {
+		if col := m.GetColumn(); col != nil && col.IsComputed() &&
+			m.State == DescriptorMutation_WRITE_ONLY {
+			cols = append(cols, *col)
+		}
+	}
+	return cols
+}
+
 // RowInserter abstracts the key/value operations for inserting table rows.
 type RowInserter struct {
 	Helper                rowHelper
@@ -147,6 +256,13 @@ type RowInserter struct {
 	InsertColIDtoRowIndex map[ColumnID]int
 	Fks                   fkInsertHelper
 
+	// shadows are the shadow columns whose values are converted from the
+	// values of the columns they replace.
+	shadows []shadowColumn
+	// computed are the computed columns whose values are computed from the
+	// values of the other columns.
+	computed []computedColumn
+
 	// For allocation avoidance.
 	marshalled []roachpb.Value
 	key        roachpb.Key
@@ -174,8 +290,12 @@ func MakeRowInserter(
 		}
 	}
 
+	helper, err := makeRowHelper(tableDesc, indexes)
+	if err != nil {
+		return RowInserter{}, err
+	}
 	ri := RowInserter{
-		Helper:                rowHelper{TableDesc: tableDesc, Indexes: indexes},
+		Helper:                helper,
 		InsertCols:            insertCols,
 		InsertColIDtoRowIndex: ColIDtoRowIndexFromCols(insertCols),
 		marshalled:            make([]roachpb.Value, len(insertCols)),
@@ -187,6 +307,40 @@ func MakeRowInserter(
 		}
 	}
 
+	// The shadow columns of columns whose type is being changed are written
+	// with the converted values of the columns they replace.
+	for _, m := range tableDesc.Mutations {
+		col := m.GetColumn()
+		if col == nil || m.ShadowedColumnID == 0 || m.State != DescriptorMutation_WRITE_ONLY {
+			continue
+		}
+		srcIdx, ok := ri.InsertColIDtoRowIndex[m.ShadowedColumnID]
+		if !ok {
+			continue
+		}
+		dstIdx, ok := ri.InsertColIDtoRowIndex[col.ID]
+		if !ok {
+			continue
+		}
+		shadow, err := makeShadowColumn(tableDesc, m, srcIdx, dstIdx)
+		if err != nil {
+			return RowInserter{}, err
+		}
+		ri.shadows = append(ri.shadows, shadow)
+	}
+
+	for _, col := range writableComputedColumns(tableDesc) {
+		dstIdx, ok := ri.InsertColIDtoRowIndex[col.ID]
+		if !ok {
+			continue
+		}
+		eval, err := MakeComputedColumnEvaluator(tableDesc, col)
+		if err != nil {
+			return RowInserter{}, err
+		}
+		ri.computed = append(ri.computed, computedColumn{eval: eval, dstIdx: dstIdx})
+	}
+
 	if checkFKs {
 		var err error
 		if ri.Fks, err = makeFKInsertHelper(txn, *tableDesc, fkTables, ri.InsertColIDtoRowIndex); err != nil {
@@ -221,6 +375,19 @@ type puter interface {
 	Put(key, value interface{})
 }
 
+// ComputeColumns sets the values of the computed columns in the given values
+// for InsertCols. InsertRow computes them itself; this is exposed for callers
+// that need the values of the computed columns before inserting the row.
+func (ri *RowInserter) ComputeColumns(values []parser.Datum) error {
+	for _, c := range ri.computed {
+		var err error
+		if values[c.dstIdx], err = c.eval.Eval(values, ri.InsertColIDtoRowIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // InsertRow adds to the batch the kv operations necessary to insert a table row
 // with the given values.
 func (ri *RowInserter) InsertRow(
@@ -235,6 +402,16 @@ func (ri *RowInserter) InsertRow(
 		putFn = insertPutFn
 	}
 
+	for _, shadow := range ri.shadows {
+		var err error
+		if values[shadow.dstIdx], err = shadow.conv.Convert(values[shadow.srcIdx]); err != nil {
+			return err
+		}
+	}
+	if err := ri.ComputeColumns(values); err != nil {
+		return err
+	}
+
 	// Encode the values to the expected column type. This needs to
 	// happen before index encoding because certain datum types (i.e. tuple)
 	// cannot be used as index values.
@@ -250,7 +427,8 @@ func (ri *RowInserter) InsertRow(
 		return err
 	}
 
-	primaryIndexKey, secondaryIndexEntries, err := ri.Helper.encodeIndexes(ri.InsertColIDtoRowIndex, values)
+	primaryIndexKey, secondaryIndexEntries, invertedIndexEntries, err :=
+		ri.Helper.encodeIndexes(ri.InsertColIDtoRowIndex, values)
 	if err != nil {
 		return err
 	}
@@ -333,8 +511,17 @@ func (ri *RowInserter) InsertRow(
 
 	for i := range secondaryIndexEntries {
 		e := &secondaryIndexEntries[i]
+		if e.Key == nil {
+			// The row is not in this partial index.
+			continue
+		}
 		putFn(ctx, b, &e.Key, &e.Value)
 	}
+	for _, entries := range invertedIndexEntries {
+		for i := range entries {
+			putFn(ctx, b, &entries[i].Key, &entries[i].Value)
+		}
+	}
 
 	return nil
 }
@@ -349,6 +536,15 @@ type RowUpdater struct {
 	deleteOnlyIndex       map[int]struct{}
 	primaryKeyColChange   bool
 
+	// shadows are the shadow columns that are updated along with the columns
+	// they replace. Their updated values are stored in shadowValues.
+	shadows      []shadowColumn
+	shadowValues []parser.Datum
+	// computed are the computed columns that depend on updated columns. They
+	// are treated as updated columns whose values follow the values of the
+	// shadow columns.
+	computed []computedColumn
+
 	rd RowDeleter
 	ri RowInserter
 
@@ -405,27 +601,98 @@ func MakeRowUpdater(
 		}
 	}
 
+	// The shadow columns of updated columns whose type is being changed are
+	// updated with the converted values of the columns they replace. They are
+	// treated as updated columns whose values follow the updateValues.
+	var shadows []shadowColumn
+	if updateType != RowUpdaterOnlyColumns {
+		for _, m := range tableDesc.Mutations {
+			col := m.GetColumn()
+			if col == nil || m.ShadowedColumnID == 0 || m.State != DescriptorMutation_WRITE_ONLY {
+				continue
+			}
+			srcIdx, ok := updateColIDtoRowIndex[m.ShadowedColumnID]
+			if !ok {
+				continue
+			}
+			shadow, err := makeShadowColumn(tableDesc, m, srcIdx, len(updateCols)+len(shadows))
+			if err != nil {
+				return RowUpdater{}, err
+			}
+			updateColIDtoRowIndex[col.ID] = shadow.dstIdx
+			shadows = append(shadows, shadow)
+		}
+	}
+
+	// The computed columns that depend on updated columns are recomputed.
+	var computed []computedColumn
+	if updateType != RowUpdaterOnlyColumns {
+		for _, col := range writableComputedColumns(tableDesc) {
+			if _, ok := updateColIDtoRowIndex[col.ID]; ok {
+				continue
+			}
+			eval, err := MakeComputedColumnEvaluator(tableDesc, col)
+			if err != nil {
+				return RowUpdater{}, err
+			}
+			dependsOnUpdate := false
+			for _, id := range eval.ReferencedColumnIDs() {
+				if _, ok := updateColIDtoRowIndex[id]; ok {
+					dependsOnUpdate = true
+					break
+				}
+			}
+			if !dependsOnUpdate {
+				continue
+			}
+			c := computedColumn{eval: eval, dstIdx: len(updateCols) + len(shadows) + len(computed)}
+			updateColIDtoRowIndex[col.ID] = c.dstIdx
+			computed = append(computed, c)
+			if _, ok := primaryIndexCols[col.ID]; ok {
+				primaryKeyColChange = true
+			}
+		}
+	}
+
 	// Secondary indexes needing updating.
-	needsUpdate := func(index IndexDescriptor) bool {
+	needsUpdate := func(index IndexDescriptor) (bool, error) {
 		if updateType == RowUpdaterOnlyColumns {
 			// Only update columns.
-			return false
+			return false, nil
 		}
 		// If the primary key changed, we need to update all of them.
 		if primaryKeyColChange {
-			return true
+			return true, nil
 		}
-		return index.RunOverAllColumns(func(id ColumnID) error {
+		if index.RunOverAllColumns(func(id ColumnID) error {
 			if _, ok := updateColIDtoRowIndex[id]; ok {
 				return returnTruePseudoError
 			}
 			return nil
-		}) != nil
+		}) != nil {
+			return true, nil
+		}
+		// A row can enter or leave a partial index when the columns referenced
+		// by its predicate are updated.
+		if index.IsPartial() {
+			pred, err := MakePartialIndexPredicate(tableDesc, &index)
+			if err != nil {
+				return false, err
+			}
+			for _, id := range pred.ReferencedColumnIDs() {
+				if _, ok := updateColIDtoRowIndex[id]; ok {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
 	}
 
 	indexes := make([]IndexDescriptor, 0, len(tableDesc.Indexes)+len(tableDesc.Mutations))
 	for _, index := range tableDesc.Indexes {
-		if needsUpdate(index) {
+		if update, err := needsUpdate(index); err != nil {
+			return RowUpdater{}, err
+		} else if update {
 			indexes = append(indexes, index)
 		}
 	}
@@ -433,7 +700,11 @@ func MakeRowUpdater(
 	var deleteOnlyIndex map[int]struct{}
 	for _, m := range tableDesc.Mutations {
 		if index := m.GetIndex(); index != nil {
-			if needsUpdate(*index) {
+			update, err := needsUpdate(*index)
+			if err != nil {
+				return RowUpdater{}, err
+			}
+			if update {
 				indexes = append(indexes, *index)
 
 				switch m.State {
@@ -450,19 +721,25 @@ func MakeRowUpdater(
 		}
 	}
 
+	helper, err := makeRowHelper(tableDesc, indexes)
+	if err != nil {
+		return RowUpdater{}, err
+	}
 	ru := RowUpdater{
-		Helper:                rowHelper{TableDesc: tableDesc, Indexes: indexes},
+		Helper:                helper,
 		UpdateCols:            updateCols,
 		updateColIDtoRowIndex: updateColIDtoRowIndex,
 		deleteOnlyIndex:       deleteOnlyIndex,
 		primaryKeyColChange:   primaryKeyColChange,
-		marshalled:            make([]roachpb.Value, len(updateCols)),
+		shadows:               shadows,
+		shadowValues:          make([]parser.Datum, len(shadows)),
+		computed:              computed,
+		marshalled:            make([]roachpb.Value, len(updateCols)+len(shadows)+len(computed)),
 		newValues:             make([]parser.Datum, len(tableDesc.Columns)+len(tableDesc.Mutations)),
 	}
 
 	if primaryKeyColChange {
 		// These fields are only used when the primary key is changing.
-		var err error
 		// When changing the primary key, we delete the old values and reinsert
 		// them, so request them all.
 		if ru.rd, err = MakeRowDeleter(txn, tableDesc, fkTables, tableDesc.Columns, SkipFKs); err != nil {
@@ -493,6 +770,18 @@ func MakeRowUpdater(
 				return RowUpdater{}, err
 			}
 		}
+		// The computed columns are computed from the new values of the
+		// columns they reference.
+		for _, c := range computed {
+			for _, colID := range c.eval.ReferencedColumnIDs() {
+				if err := maybeAddCol(colID); err != nil {
+					return RowUpdater{}, err
+				}
+			}
+			if err := maybeAddCol(c.eval.Column().ID); err != nil {
+				return RowUpdater{}, err
+			}
+		}
 		for _, fam := range tableDesc.Families {
 			familyBeingUpdated := false
 			for _, colID := range fam.ColumnIDs {
@@ -514,9 +803,18 @@ func MakeRowUpdater(
 				return RowUpdater{}, err
 			}
 		}
+		for _, pred := range ru.Helper.predicates {
+			if pred == nil {
+				continue
+			}
+			for _, colID := range pred.ReferencedColumnIDs() {
+				if err := maybeAddCol(colID); err != nil {
+					return RowUpdater{}, err
+				}
+			}
+		}
 	}
 
-	var err error
 	if ru.Fks, err = makeFKUpdateHelper(txn, *tableDesc, fkTables, ru.FetchColIDtoRowIndex); err != nil {
 		return RowUpdater{}, err
 	}
@@ -540,7 +838,8 @@ func (ru *RowUpdater) UpdateRow(
 		return nil, errors.Errorf("got %d values but expected %d", len(updateValues), len(ru.UpdateCols))
 	}
 
-	primaryIndexKey, secondaryIndexEntries, err := ru.Helper.encodeIndexes(ru.FetchColIDtoRowIndex, oldValues)
+	primaryIndexKey, secondaryIndexEntries, invertedIndexEntries, err :=
+		ru.Helper.encodeIndexes(ru.FetchColIDtoRowIndex, oldValues)
 	if err != nil {
 		return nil, err
 	}
@@ -559,25 +858,49 @@ func (ru *RowUpdater) UpdateRow(
 			return nil, err
 		}
 	}
+	for i, shadow := range ru.shadows {
+		if ru.shadowValues[i], err = shadow.conv.Convert(updateValues[shadow.srcIdx]); err != nil {
+			return nil, err
+		}
+		if ru.marshalled[shadow.dstIdx], err = MarshalColumnValue(shadow.col, ru.shadowValues[i]); err != nil {
+			return nil, err
+		}
+	}
 
 	// Update the row values.
 	copy(ru.newValues, oldValues)
 	for i, updateCol := range ru.UpdateCols {
 		ru.newValues[ru.FetchColIDtoRowIndex[updateCol.ID]] = updateValues[i]
 	}
+	for i, shadow := range ru.shadows {
+		if idx, ok := ru.FetchColIDtoRowIndex[shadow.col.ID]; ok {
+			ru.newValues[idx] = ru.shadowValues[i]
+		}
+	}
+	for _, c := range ru.computed {
+		val, err := c.eval.Eval(ru.newValues, ru.FetchColIDtoRowIndex)
+		if err != nil {
+			return nil, err
+		}
+		if ru.marshalled[c.dstIdx], err = MarshalColumnValue(c.eval.Column(), val); err != nil {
+			return nil, err
+		}
+		ru.newValues[ru.FetchColIDtoRowIndex[c.eval.Column().ID]] = val
+	}
 
 	rowPrimaryKeyChanged := false
 	var newSecondaryIndexEntries []IndexEntry
+	var newInvertedIndexEntries [][]IndexEntry
 	if ru.primaryKeyColChange {
 		var newPrimaryIndexKey []byte
-		newPrimaryIndexKey, newSecondaryIndexEntries, err =
+		newPrimaryIndexKey, newSecondaryIndexEntries, newInvertedIndexEntries, err =
 			ru.Helper.encodeIndexes(ru.FetchColIDtoRowIndex, ru.newValues)
 		if err != nil {
 			return nil, err
 		}
 		rowPrimaryKeyChanged = !bytes.Equal(primaryIndexKey, newPrimaryIndexKey)
 	} else {
-		newSecondaryIndexEntries, err =
+		newSecondaryIndexEntries, newInvertedIndexEntries, err =
 			ru.Helper.encodeSecondaryIndexes(ru.FetchColIDtoRowIndex, ru.newValues)
 		if err != nil {
 			return nil, err
@@ -711,15 +1034,22 @@ func (ru *RowUpdater) UpdateRow(
 				return nil, err
 			}
 
-			if log.V(2) {
-				log.Infof(ctx, "Del %s", secondaryIndexEntry.Key)
+			// The old row may not have been in the partial index.
+			if secondaryIndexEntry.Key != nil {
+				if log.V(2) {
+					log.Infof(ctx, "Del %s", secondaryIndexEntry.Key)
+				}
+				b.Del(secondaryIndexEntry.Key)
 			}
-			b.Del(secondaryIndexEntry.Key)
 		} else if !bytes.Equal(newSecondaryIndexEntry.Value.RawBytes, secondaryIndexEntry.Value.RawBytes) {
 			expValue = &secondaryIndexEntry.Value
 		} else {
 			continue
 		}
+		if newSecondaryIndexEntry.Key == nil {
+			// The new row is not in the partial index.
+			continue
+		}
 		// Do not update Indexes in the DELETE_ONLY state.
 		if _, ok := ru.deleteOnlyIndex[i]; !ok {
 			if log.V(2) {
@@ -729,9 +1059,53 @@ func (ru *RowUpdater) UpdateRow(
 		}
 	}
 
+	// Update inverted indexes.
+	for i, newEntries := range newInvertedIndexEntries {
+		_, deleteOnly := ru.deleteOnlyIndex[i]
+		updateInvertedIndexEntries(ctx, b, invertedIndexEntries[i], newEntries, deleteOnly)
+	}
+
 	return ru.newValues, nil
 }
 
+// updateInvertedIndexEntries adds to the batch the kv operations necessary to
+// replace the sorted entries of a row in an inverted index with the new sorted
+// entries. The entries common to both are left untouched. New entries are not
+// written to DELETE_ONLY indexes.
+func updateInvertedIndexEntries(
+	ctx context.Context, b *client.Batch, oldEntries, newEntries []IndexEntry, deleteOnly bool,
+) {
+	for len(oldEntries) > 0 || len(newEntries) > 0 {
+		var c int
+		switch {
+		case len(oldEntries) == 0:
+			c = 1
+		case len(newEntries) == 0:
+			c = -1
+		default:
+			c = bytes.Compare(oldEntries[0].Key, newEntries[0].Key)
+		}
+		switch {
+		case c < 0:
+			if log.V(2) {
+				log.Infof(ctx, "Del %s", oldEntries[0].Key)
+			}
+			b.Del(oldEntries[0].Key)
+			oldEntries = oldEntries[1:]
+		case c > 0:
+			if !deleteOnly {
+				if log.V(2) {
+					log.Infof(ctx, "CPut %s -> %v", newEntries[0].Key, newEntries[0].Value.PrettyPrint())
+				}
+				b.CPut(newEntries[0].Key, &newEntries[0].Value, nil)
+			}
+			newEntries = newEntries[1:]
+		default:
+			oldEntries, newEntries = oldEntries[1:], newEntries[1:]
+		}
+	}
+}
+
 // IsColumnOnlyUpdate returns true if this RowUpdater is only updating column
 // data (in contrast to updating the primary key or other indexes).
 func (ru *RowUpdater) IsColumnOnlyUpdate() bool {
@@ -806,14 +1180,29 @@ func MakeRowDeleter(
 			}
 		}
 	}
+	helper, err := makeRowHelper(tableDesc, indexes)
+	if err != nil {
+		return RowDeleter{}, err
+	}
+	// The columns referenced by the predicates of partial indexes determine
+	// which indexes have entries for the row.
+	for _, pred := range helper.predicates {
+		if pred == nil {
+			continue
+		}
+		for _, colID := range pred.ReferencedColumnIDs() {
+			if err := maybeAddCol(colID); err != nil {
+				return RowDeleter{}, err
+			}
+		}
+	}
 
 	rd := RowDeleter{
-		Helper:               rowHelper{TableDesc: tableDesc, Indexes: indexes},
+		Helper:               helper,
 		FetchCols:            fetchCols,
 		FetchColIDtoRowIndex: fetchColIDtoRowIndex,
 	}
 	if checkFKs {
-		var err error
 		if rd.Fks, err = makeFKDeleteHelper(txn, *tableDesc, fkTables, fetchColIDtoRowIndex); err != nil {
 			return RowDeleter{}, err
 		}
@@ -829,17 +1218,30 @@ func (rd *RowDeleter) DeleteRow(ctx context.Context, b *client.Batch, values []p
 		return err
 	}
 
-	primaryIndexKey, secondaryIndexEntries, err := rd.Helper.encodeIndexes(rd.FetchColIDtoRowIndex, values)
+	primaryIndexKey, secondaryIndexEntries, invertedIndexEntries, err :=
+		rd.Helper.encodeIndexes(rd.FetchColIDtoRowIndex, values)
 	if err != nil {
 		return err
 	}
 
 	for _, secondaryIndexEntry := range secondaryIndexEntries {
+		if secondaryIndexEntry.Key == nil {
+			// The row is not in this partial index.
+			continue
+		}
 		if log.V(2) {
 			log.Infof(ctx, "Del %s", secondaryIndexEntry.Key)
 		}
 		b.Del(secondaryIndexEntry.Key)
 	}
+	for _, entries := range invertedIndexEntries {
+		for _, entry := range entries {
+			if log.V(2) {
+				log.Infof(ctx, "Del %s", entry.Key)
+			}
+			b.Del(entry.Key)
+		}
+	}
 
 	// Delete the row.
 	rd.startKey = roachpb.Key(primaryIndexKey)
@@ -861,6 +1263,19 @@ func (rd *RowDeleter) DeleteIndexRow(
 	if err := rd.Fks.checkAll(ctx, values); err != nil {
 		return err
 	}
+	if idx.Type == IndexDescriptor_INVERTED {
+		entries, err := EncodeInvertedIndexKeys(rd.Helper.TableDesc, idx, rd.FetchColIDtoRowIndex, values)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if log.V(2) {
+				log.Infof(ctx, "Del %s", entry.Key)
+			}
+			b.Del(entry.Key)
+		}
+		return nil
+	}
 	secondaryIndexEntry, err := EncodeSecondaryIndex(
 		rd.Helper.TableDesc, idx, rd.FetchColIDtoRowIndex, values)
 	if err != nil {
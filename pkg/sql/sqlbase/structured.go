@@ -288,6 +288,9 @@ func (desc *TableDescriptor) TypeName() string {
 	if desc.IsView() {
 		return "view"
 	}
+	if desc.IsSequence() {
+		return "sequence"
+	}
 	return "table"
 }
 
@@ -305,7 +308,7 @@ func (desc *TableDescriptor) IsEmpty() bool {
 // IsTable returns true if the TableDescriptor actually describes a
 // Table resource, as opposed to a different resource (like a View).
 func (desc *TableDescriptor) IsTable() bool {
-	return !desc.IsView()
+	return !desc.IsView() && !desc.IsSequence()
 }
 
 // IsView returns true if the TableDescriptor actually describes a
@@ -314,6 +317,12 @@ func (desc *TableDescriptor) IsView() bool {
 	return desc.ViewQuery != ""
 }
 
+// IsSequence returns true if the TableDescriptor actually describes a
+// Sequence resource rather than a Table.
+func (desc *TableDescriptor) IsSequence() bool {
+	return desc.SequenceOpts != nil
+}
+
 // IsVirtualTable returns true if the TableDescriptor describes a
 // virtual Table (like the information_schema tables) and thus doesn't
 // need to be physically stored.
@@ -563,6 +572,24 @@ func HasCompositeKeyEncoding(kind ColumnType_Kind) bool {
 	return false
 }
 
+// ColumnTypeIsIndexable returns whether the type t is valid as an indexed
+// column.
+func ColumnTypeIsIndexable(t ColumnType) bool {
+	switch t.Kind {
+	case ColumnType_JSON, ColumnType_ARRAY, ColumnType_INT_ARRAY:
+		return false
+	}
+	return true
+}
+
+// ColumnTypeIsInvertedIndexable returns whether the type t is valid as the
+// column of an inverted index, that is, whether it is an ARRAY whose elements
+// can be indexed.
+func ColumnTypeIsInvertedIndexable(t ColumnType) bool {
+	return t.Kind == ColumnType_ARRAY && t.ArrayContents != nil &&
+		ColumnTypeIsIndexable(t.ElementType())
+}
+
 // HasOldStoredColumns returns whether the index has stored columns in the old
 // format (data encoded the same way as if they were in an implicit column).
 func (desc *IndexDescriptor) HasOldStoredColumns() bool {
@@ -973,6 +1000,11 @@ func (desc *TableDescriptor) ValidateTable() error {
 			desc.Name, desc.GetFormatVersion(), FamilyFormatVersion, InterleavedFormatVersion)
 	}
 
+	if desc.IsSequence() {
+		// Sequences have no columns or indexes to validate.
+		return desc.Privileges.Validate(desc.GetID())
+	}
+
 	if len(desc.Columns) == 0 {
 		return ErrMissingColumns
 	}
@@ -1160,6 +1192,20 @@ func (desc *TableDescriptor) validateTableIndexes(
 		if len(index.ColumnIDs) == 0 {
 			return fmt.Errorf("index \"%s\" must contain at least 1 column", index.Name)
 		}
+		if index.Type == IndexDescriptor_INVERTED {
+			if len(index.ColumnIDs) != 1 {
+				return fmt.Errorf("inverted index \"%s\" must contain exactly 1 column", index.Name)
+			}
+			if index.Unique {
+				return fmt.Errorf("inverted index \"%s\" cannot be unique", index.Name)
+			}
+			if index.ColumnDirections[0] != IndexDescriptor_ASC {
+				return fmt.Errorf("inverted index \"%s\" cannot have a descending column", index.Name)
+			}
+			if len(index.StoreColumnIDs) > 0 || len(index.StoreColumnNames) > 0 {
+				return fmt.Errorf("inverted index \"%s\" cannot store columns", index.Name)
+			}
+		}
 
 		for i, name := range index.ColumnNames {
 			colID, ok := columnNames[parser.ReNormalizeName(name)]
@@ -1170,6 +1216,17 @@ func (desc *TableDescriptor) validateTableIndexes(
 				return fmt.Errorf("index \"%s\" column \"%s\" should have ID %d, but found ID %d",
 					index.Name, name, colID, index.ColumnIDs[i])
 			}
+			if col, err := desc.FindColumnByID(colID); err == nil {
+				if index.Type == IndexDescriptor_INVERTED {
+					if !ColumnTypeIsInvertedIndexable(col.Type) {
+						return fmt.Errorf("column %s is of type %s and thus is not indexable with an inverted index",
+							col.Name, col.Type.SQLString())
+					}
+				} else if !ColumnTypeIsIndexable(col.Type) {
+					return fmt.Errorf("column %s is of type %s and thus is not indexable",
+						col.Name, col.Type.SQLString())
+				}
+			}
 		}
 	}
 
@@ -1202,6 +1259,12 @@ func upperBoundColumnValueEncodedSize(col ColumnDescriptor) (int, bool) {
 		typ = encoding.Float
 	case ColumnType_INTERVAL:
 		typ = encoding.Duration
+	case ColumnType_UUID:
+		typ = encoding.UUID
+	case ColumnType_JSON:
+		typ = encoding.JSON
+	case ColumnType_ARRAY:
+		typ = encoding.Array
 	case ColumnType_STRING, ColumnType_BYTES, ColumnType_COLLATEDSTRING, ColumnType_NAME:
 		// STRINGs are counted as runes, so this isn't totally correct, but this
 		// seems better than always assuming the maximum rune width.
@@ -1523,9 +1586,17 @@ func (desc *TableDescriptor) MakeMutationComplete(m DescriptorMutation) {
 	case DescriptorMutation_ADD:
 		switch t := m.Descriptor_.(type) {
 		case *DescriptorMutation_Column:
+			if m.ShadowedColumnID != 0 {
+				desc.swapShadowColumn(*t.Column, m.ShadowedColumnID)
+				break
+			}
 			desc.AddColumn(*t.Column)
 
 		case *DescriptorMutation_Index:
+			if m.ShadowedIndexID != 0 {
+				desc.swapShadowIndex(*t.Index, m.ShadowedIndexID)
+				break
+			}
 			if err := desc.AddIndex(*t.Index, false); err != nil {
 				panic(err)
 			}
@@ -1559,6 +1630,105 @@ func (desc *TableDescriptor) AddIndexMutation(
 	desc.addMutation(m)
 }
 
+// AddShadowColumnMutation adds a mutation adding the shadow column of a column
+// whose type is changed. Once the mutation completes, the shadow column
+// replaces the column with ID shadowedID.
+func (desc *TableDescriptor) AddShadowColumnMutation(c ColumnDescriptor, shadowedID ColumnID) {
+	m := DescriptorMutation{
+		Descriptor_:      &DescriptorMutation_Column{Column: &c},
+		Direction:        DescriptorMutation_ADD,
+		ShadowedColumnID: shadowedID,
+	}
+	m.ResumeSpans = append(m.ResumeSpans, desc.PrimaryIndexSpan())
+	desc.addMutation(m)
+}
+
+// AddShadowIndexMutation adds a mutation adding the shadow index of an index
+// referencing a column whose type is changed. Once the mutation completes, the
+// shadow index replaces the index with ID shadowedID.
+func (desc *TableDescriptor) AddShadowIndexMutation(idx IndexDescriptor, shadowedID IndexID) {
+	m := DescriptorMutation{
+		Descriptor_:     &DescriptorMutation_Index{Index: &idx},
+		Direction:       DescriptorMutation_ADD,
+		ShadowedIndexID: shadowedID,
+	}
+	m.ResumeSpans = append(m.ResumeSpans, desc.PrimaryIndexSpan())
+	desc.addMutation(m)
+}
+
+// swapShadowColumn replaces the column with ID shadowedID with its shadow
+// column. The shadow column takes the name, position and constraints of the
+// replaced column, which gets the name of the shadow column and is dropped by
+// a new mutation with ID NextMutationID. If the replaced column was dropped in
+// the meantime, the shadow column is dropped instead.
+func (desc *TableDescriptor) swapShadowColumn(shadow ColumnDescriptor, shadowedID ColumnID) {
+	for i := range desc.Columns {
+		if desc.Columns[i].ID != shadowedID {
+			continue
+		}
+		old := desc.Columns[i]
+		desc.renameColumnReferences(old.Name, shadow.Name)
+		old.Name, shadow.Name = shadow.Name, old.Name
+		shadow.Nullable = old.Nullable
+		shadow.DefaultExpr = old.DefaultExpr
+		shadow.Hidden = old.Hidden
+		desc.Columns[i] = shadow
+		desc.AddColumnMutation(old, DescriptorMutation_DROP)
+		return
+	}
+	desc.AddColumnMutation(shadow, DescriptorMutation_DROP)
+}
+
+// swapShadowIndex replaces the index with ID shadowedID with its shadow index.
+// The shadow index takes the name and position of the replaced index, which
+// gets the name of the shadow index and is dropped by a new mutation with ID
+// NextMutationID. If the replaced index was dropped in the meantime, the
+// shadow index is dropped instead.
+func (desc *TableDescriptor) swapShadowIndex(shadow IndexDescriptor, shadowedID IndexID) {
+	for i := range desc.Indexes {
+		if desc.Indexes[i].ID != shadowedID {
+			continue
+		}
+		old := desc.Indexes[i]
+		old.Name, shadow.Name = shadow.Name, old.Name
+		desc.Indexes[i] = shadow
+		desc.AddIndexMutation(old, DescriptorMutation_DROP)
+		return
+	}
+	desc.AddIndexMutation(shadow, DescriptorMutation_DROP)
+}
+
+// renameColumnReferences swaps the column names a and b in the indexes and
+// column families of the table.
+func (desc *TableDescriptor) renameColumnReferences(a, b string) {
+	swap := func(names []string) {
+		for i, name := range names {
+			switch name {
+			case a:
+				names[i] = b
+			case b:
+				names[i] = a
+			}
+		}
+	}
+	swapIndex := func(idx *IndexDescriptor) {
+		swap(idx.ColumnNames)
+		swap(idx.StoreColumnNames)
+	}
+	swapIndex(&desc.PrimaryIndex)
+	for i := range desc.Indexes {
+		swapIndex(&desc.Indexes[i])
+	}
+	for _, m := range desc.Mutations {
+		if idx := m.GetIndex(); idx != nil {
+			swapIndex(idx)
+		}
+	}
+	for i := range desc.Families {
+		swap(desc.Families[i].ColumnNames)
+	}
+}
+
 func (desc *TableDescriptor) addMutation(m DescriptorMutation) {
 	switch m.Direction {
 	case DescriptorMutation_ADD:
@@ -1667,12 +1837,27 @@ func (c *ColumnType) SQLString() string {
 			return fmt.Sprintf("%s(%d) COLLATE %s", ColumnType_STRING.String(), c.Width, *c.Locale)
 		}
 		return fmt.Sprintf("%s COLLATE %s", ColumnType_STRING.String(), *c.Locale)
+	case ColumnType_ARRAY:
+		elemType := c.ElementType()
+		return elemType.SQLString() + "[]"
 	case ColumnType_INT_ARRAY:
 		return "INT[]"
 	}
 	return c.Kind.String()
 }
 
+// ElementType returns the type of the elements of an ARRAY type.
+func (c *ColumnType) ElementType() ColumnType {
+	if c.ArrayContents == nil {
+		panic(fmt.Sprintf("element kind is required for %s", c.Kind))
+	}
+	elemType := *c
+	elemType.Kind = *c.ArrayContents
+	elemType.ArrayContents = nil
+	elemType.ArrayDimensions = nil
+	return elemType
+}
+
 // MaxCharacterLength returns the declared maximum length of characters if the
 // ColumnType is a character or bit string data type. Returns false if the data
 // type is not a character or bit string, or if the string's length is not bounded.
@@ -1762,17 +1947,25 @@ func DatumTypeToColumnType(ptyp parser.Type) ColumnType {
 		ctyp.Kind = ColumnType_INTERVAL
 	case parser.TypeOid:
 		ctyp.Kind = ColumnType_OID
+	case parser.TypeUUID:
+		ctyp.Kind = ColumnType_UUID
+	case parser.TypeJSON:
+		ctyp.Kind = ColumnType_JSON
 	case parser.TypeNull:
 		ctyp.Kind = ColumnType_NULL
-	case parser.TypeIntArray:
-		ctyp.Kind = ColumnType_INT_ARRAY
 	case parser.TypeIntVector:
 		ctyp.Kind = ColumnType_INT2VECTOR
 	default:
-		if t, ok := ptyp.(parser.TCollatedString); ok {
+		switch t := ptyp.(type) {
+		case parser.TCollatedString:
 			ctyp.Kind = ColumnType_COLLATEDSTRING
 			ctyp.Locale = &t.Locale
-		} else {
+		case parser.TArray:
+			ctyp = DatumTypeToColumnType(t.Typ)
+			elemKind := ctyp.Kind
+			ctyp.Kind = ColumnType_ARRAY
+			ctyp.ArrayContents = &elemKind
+		default:
 			panic(fmt.Sprintf("unsupported result type: %s", ptyp))
 		}
 	}
@@ -1812,8 +2005,15 @@ func (c *ColumnType) ToDatumType() parser.Type {
 		return parser.TypeName
 	case ColumnType_OID:
 		return parser.TypeOid
+	case ColumnType_UUID:
+		return parser.TypeUUID
+	case ColumnType_JSON:
+		return parser.TypeJSON
 	case ColumnType_NULL:
 		return parser.TypeNull
+	case ColumnType_ARRAY:
+		elemType := c.ElementType()
+		return parser.TArray{Typ: elemType.ToDatumType()}
 	case ColumnType_INT_ARRAY:
 		return parser.TypeIntArray
 	case ColumnType_INT2VECTOR:
@@ -853,6 +853,8 @@ func TestColumnTypeSQLString(t *testing.T) {
 		{ColumnType{Kind: ColumnType_STRING}, "STRING"},
 		{ColumnType{Kind: ColumnType_STRING, Width: 10}, "STRING(10)"},
 		{ColumnType{Kind: ColumnType_BYTES}, "BYTES"},
+		{ColumnType{Kind: ColumnType_UUID}, "UUID"},
+		{ColumnType{Kind: ColumnType_JSON}, "JSON"},
 	}
 	for i, d := range testData {
 		sql := d.colType.SQLString()
@@ -881,6 +883,8 @@ func TestColumnValueEncodedSize(t *testing.T) {
 		{ColumnType{Kind: ColumnType_STRING}, -1},
 		{ColumnType{Kind: ColumnType_STRING, Width: 100}, 110},
 		{ColumnType{Kind: ColumnType_BYTES}, -1},
+		{ColumnType{Kind: ColumnType_UUID}, 17},
+		{ColumnType{Kind: ColumnType_JSON}, -1},
 	}
 	for i, test := range tests {
 		testIsBounded := test.size != -1
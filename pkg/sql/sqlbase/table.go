@@ -32,6 +32,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 func exprContainsVarsError(context string, Expr parser.Expr) error {
@@ -79,16 +81,6 @@ func MakeColumnDefDescs(
 		Nullable: d.Nullable.Nullability != parser.NotNull && !d.PrimaryKey,
 	}
 
-	// Prevent unsupported array types from falling through.
-	// TODO(jordan): This is ugly and a workaround for incompatibility between
-	// CastTargetToDatumType and structured.go's DatumTypeToColumnType. See #15813
-	switch t := d.Type.(type) {
-	case *parser.ArrayColType:
-		if _, ok := t.ParamType.(*parser.IntColType); !ok {
-			return nil, nil, errors.Errorf("arrays of type %s are unsupported", t.ParamType)
-		}
-	}
-
 	// Set Type.Kind and Type.Locale.
 	colDatumType := parser.CastTargetToDatumType(d.Type)
 	col.Type = DatumTypeToColumnType(colDatumType)
@@ -102,6 +94,9 @@ func MakeColumnDefDescs(
 			if d.HasDefaultExpr() {
 				return nil, nil, fmt.Errorf("SERIAL column %q cannot have a default value", col.Name)
 			}
+			if d.IsComputed() {
+				return nil, nil, fmt.Errorf("SERIAL column %q cannot be a computed column", col.Name)
+			}
 			s := "unique_rowid()"
 			col.DefaultExpr = &s
 		}
@@ -131,11 +126,24 @@ func MakeColumnDefDescs(
 		col.Type.Width = int32(t.N)
 	case *parser.NameColType:
 	case *parser.BytesColType:
+	case *parser.UUIDColType:
+	case *parser.JSONColType:
 	case *parser.CollatedStringColType:
 		col.Type.Width = int32(t.N)
 	case *parser.ArrayColType:
-		if _, ok := t.ParamType.(*parser.IntColType); !ok {
-			return nil, nil, errors.Errorf("arrays of type %s are unsupported", t.ParamType)
+		// The width and precision of the elements are those of the array.
+		switch p := t.ParamType.(type) {
+		case *parser.IntColType:
+			col.Type.Width = int32(p.N)
+		case *parser.FloatColType:
+			col.Type.Precision = int32(p.Prec)
+		case *parser.DecimalColType:
+			col.Type.Width = int32(p.Scale)
+			col.Type.Precision = int32(p.Prec)
+		case *parser.StringColType:
+			col.Type.Width = int32(p.N)
+		case *parser.CollatedStringColType:
+			col.Type.Width = int32(p.N)
 		}
 		for i, e := range t.BoundsExprs {
 			ctx := parser.SemaContext{SearchPath: searchPath}
@@ -203,6 +211,13 @@ func MakeColumnDefDescs(
 		col.DefaultExpr = &s
 	}
 
+	if d.IsComputed() {
+		// The expression references other columns of the table, so it can
+		// only be verified once the table is known; see ValidateComputedColumn.
+		s := parser.Serialize(d.Computed.Expr)
+		col.ComputedExpr = &s
+	}
+
 	var idx *IndexDescriptor
 	if d.PrimaryKey || d.Unique {
 		idx = &IndexDescriptor{
@@ -528,6 +543,11 @@ func EncodeTableKey(b []byte, val parser.Datum, dir encoding.Direction) ([]byte,
 			return encoding.EncodeDurationAscending(b, t.Duration)
 		}
 		return encoding.EncodeDurationDescending(b, t.Duration)
+	case *parser.DUuid:
+		if dir == encoding.Ascending {
+			return encoding.EncodeBytesAscending(b, t.GetBytes()), nil
+		}
+		return encoding.EncodeBytesDescending(b, t.GetBytes()), nil
 	case *parser.DTuple:
 		for _, datum := range t.D {
 			var err error
@@ -590,6 +610,16 @@ func EncodeTableValue(appendTo []byte, colID ColumnID, val parser.Datum) ([]byte
 		return encoding.EncodeTimeValue(appendTo, uint32(colID), t.Time), nil
 	case *parser.DInterval:
 		return encoding.EncodeDurationValue(appendTo, uint32(colID), t.Duration), nil
+	case *parser.DUuid:
+		return encoding.EncodeUUIDValue(appendTo, uint32(colID), t.UUID), nil
+	case *parser.DJSON:
+		return encoding.EncodeJSONValue(appendTo, uint32(colID), json.EncodeJSON(nil, t.JSON)), nil
+	case *parser.DArray:
+		data, err := encodeArray(nil, t)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeArrayValue(appendTo, uint32(colID), data), nil
 	case *parser.DCollatedString:
 		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Contents)), nil
 	case *parser.DOid:
@@ -890,6 +920,7 @@ type DatumAlloc struct {
 	dtimestampAlloc   []parser.DTimestamp
 	dtimestampTzAlloc []parser.DTimestampTZ
 	dintervalAlloc    []parser.DInterval
+	duuidAlloc        []parser.DUuid
 	doidAlloc         []parser.DOid
 	env               parser.CollationEnvironment
 }
@@ -1007,6 +1038,18 @@ func (a *DatumAlloc) NewDInterval(v parser.DInterval) *parser.DInterval {
 	return r
 }
 
+// NewDUuid allocates a DUuid.
+func (a *DatumAlloc) NewDUuid(v parser.DUuid) *parser.DUuid {
+	buf := &a.duuidAlloc
+	if len(*buf) == 0 {
+		*buf = make([]parser.DUuid, datumAllocSize)
+	}
+	r := &(*buf)[0]
+	*r = v
+	*buf = (*buf)[1:]
+	return r
+}
+
 // NewDOid allocates a DOid.
 func (a *DatumAlloc) NewDOid(v parser.DOid) parser.Datum {
 	buf := &a.doidAlloc
@@ -1124,6 +1167,19 @@ func DecodeTableKey(
 			rkey, d, err = encoding.DecodeDurationDescending(key)
 		}
 		return a.NewDInterval(parser.DInterval{Duration: d}), rkey, err
+	case parser.TypeUUID:
+		var r []byte
+		if dir == encoding.Ascending {
+			rkey, r, err = encoding.DecodeBytesAscending(key, nil)
+		} else {
+			rkey, r, err = encoding.DecodeBytesDescending(key, nil)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		var u uuid.UUID
+		u, err = uuid.FromBytes(r)
+		return a.NewDUuid(parser.DUuid{UUID: u}), rkey, err
 	case parser.TypeOid:
 		var i int64
 		if dir == encoding.Ascending {
@@ -1202,6 +1258,19 @@ func DecodeTableValue(a *DatumAlloc, valType parser.Type, b []byte) (parser.Datu
 		var d duration.Duration
 		b, d, err = encoding.DecodeDurationValue(b)
 		return a.NewDInterval(parser.DInterval{Duration: d}), b, err
+	case parser.TypeUUID:
+		var data uuid.UUID
+		b, data, err = encoding.DecodeUUIDValue(b)
+		return a.NewDUuid(parser.DUuid{UUID: data}), b, err
+	case parser.TypeJSON:
+		var data []byte
+		b, data, err = encoding.DecodeJSONValue(b)
+		if err != nil {
+			return nil, b, err
+		}
+		var j json.JSON
+		j, err = json.DecodeJSON(data)
+		return parser.NewDJSON(j), b, err
 	case parser.TypeOid:
 		var i int64
 		b, i, err = encoding.DecodeIntValue(b)
@@ -1212,6 +1281,16 @@ func DecodeTableValue(a *DatumAlloc, valType parser.Type, b []byte) (parser.Datu
 			b, data, err = encoding.DecodeBytesValue(b)
 			return parser.NewDCollatedString(string(data), typ.Locale, &a.env), b, err
 		}
+		if typ, ok := valType.(parser.TArray); ok {
+			var data []byte
+			b, data, err = encoding.DecodeArrayValue(b)
+			if err != nil {
+				return nil, b, err
+			}
+			var d parser.Datum
+			d, err = decodeArray(a, typ.Typ, data)
+			return d, b, err
+		}
 		return nil, nil, errors.Errorf("TODO(pmattis): decoded index value: %s", valType)
 	}
 }
@@ -1320,7 +1399,9 @@ func EncodeSecondaryIndex(
 // EncodeSecondaryIndexes encodes key/values for the secondary indexes. colMap
 // maps ColumnIDs to indices in `values`. secondaryIndexEntries is the return
 // value (passed as a parameter so the caller can reuse between rows) and is
-// expected to be the same length as indexes.
+// expected to be the same length as indexes. The entries of inverted indexes,
+// which have several entries per row, are left empty; they are encoded by
+// EncodeInvertedIndexKeys.
 func EncodeSecondaryIndexes(
 	tableDesc *TableDescriptor,
 	indexes []IndexDescriptor,
@@ -1329,6 +1410,10 @@ func EncodeSecondaryIndexes(
 	secondaryIndexEntries []IndexEntry,
 ) error {
 	for i := range indexes {
+		if indexes[i].Type == IndexDescriptor_INVERTED {
+			secondaryIndexEntries[i] = IndexEntry{}
+			continue
+		}
 		var err error
 		secondaryIndexEntries[i], err = EncodeSecondaryIndex(tableDesc, &indexes[i], colMap, values)
 		if err != nil {
@@ -1423,6 +1508,28 @@ func MarshalColumnValue(col ColumnDescriptor, val parser.Datum) (roachpb.Value,
 			err := r.SetDuration(v.Duration)
 			return r, err
 		}
+	case ColumnType_UUID:
+		if v, ok := val.(*parser.DUuid); ok {
+			r.SetBytes(v.GetBytes())
+			return r, nil
+		}
+	case ColumnType_JSON:
+		if v, ok := val.(*parser.DJSON); ok {
+			r.SetBytes(json.EncodeJSON(nil, v.JSON))
+			return r, nil
+		}
+	case ColumnType_ARRAY:
+		if v, ok := parser.AsDArray(val); ok {
+			if err := checkArrayElementType(col, v); err != nil {
+				return r, err
+			}
+			b, err := encodeArray(nil, v)
+			if err != nil {
+				return r, err
+			}
+			r.SetBytes(b)
+			return r, nil
+		}
 	case ColumnType_COLLATEDSTRING:
 		if col.Type.Locale == nil {
 			panic("locale is required for COLLATEDSTRING")
@@ -1519,6 +1626,33 @@ func UnmarshalColumnValue(
 			return nil, err
 		}
 		return a.NewDInterval(parser.DInterval{Duration: d}), nil
+	case ColumnType_UUID:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		u, err := uuid.FromBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		return a.NewDUuid(parser.DUuid{UUID: u}), nil
+	case ColumnType_JSON:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		j, err := json.DecodeJSON(v)
+		if err != nil {
+			return nil, err
+		}
+		return parser.NewDJSON(j), nil
+	case ColumnType_ARRAY:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		elemType := typ.ElementType()
+		return decodeArray(a, elemType.ToDatumType(), v)
 	case ColumnType_COLLATEDSTRING:
 		v, err := value.GetBytes()
 		if err != nil {
@@ -1582,6 +1716,16 @@ func CheckValueWidth(col ColumnDescriptor, val parser.Datum) error {
 				return errors.Wrapf(err, "type %s (column %q)", col.Type.SQLString(), col.Name)
 			}
 		}
+	case ColumnType_ARRAY:
+		if v, ok := parser.AsDArray(val); ok {
+			elemCol := col
+			elemCol.Type = col.Type.ElementType()
+			for _, elem := range v.Array {
+				if err := CheckValueWidth(elemCol, elem); err != nil {
+					return err
+				}
+			}
+		}
 	}
 	return nil
 }
@@ -31,6 +31,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 // This file contains utility functions for tests (in other packages).
@@ -133,12 +135,25 @@ func RandDatum(rng *rand.Rand, typ ColumnType, null bool) parser.Datum {
 			p[i] = byte(1 + rng.Intn(127))
 		}
 		return parser.NewDName(string(p))
+	case ColumnType_UUID:
+		return parser.NewDUuid(parser.DUuid{UUID: *uuid.NewPopulatedUUID(rng)})
+	case ColumnType_JSON:
+		return parser.NewDJSON(json.Random(20, rng))
 	case ColumnType_OID:
 		return parser.NewDOid(parser.DInt(rng.Int63()))
 	case ColumnType_NULL:
 		return parser.DNull
+	case ColumnType_ARRAY:
+		elemType := typ.ElementType()
+		arr := parser.NewDArray(elemType.ToDatumType())
+		for i := rng.Intn(10); i > 0; i-- {
+			if err := arr.Append(RandDatum(rng, elemType, null)); err != nil {
+				panic(err)
+			}
+		}
+		return arr
 	case ColumnType_INT_ARRAY, ColumnType_INT2VECTOR:
-		// TODO(cuongdo): we don't support for persistence of arrays or vectors yet
+		// Only ARRAY is used for new descriptors, and vectors are not persisted.
 		return parser.DNull
 	default:
 		panic(fmt.Sprintf("invalid type %s", typ.String()))
@@ -152,6 +167,11 @@ var (
 
 func init() {
 	for k := range ColumnType_Kind_name {
+		// The random types are used by tests which key-encode the generated
+		// datums, so skip the types that cannot be indexed.
+		if !ColumnTypeIsIndexable(ColumnType{Kind: ColumnType_Kind(k)}) {
+			continue
+		}
 		columnKinds = append(columnKinds, ColumnType_Kind(k))
 	}
 }
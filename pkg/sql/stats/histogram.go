@@ -0,0 +1,122 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EquiDepthHistogram builds a histogram in which each bucket contains about
+// the same number of samples. A bucket can contain more samples than the
+// others when its upper bound is a frequent value, since all the samples
+// equal to the upper bound of a bucket belong to it.
+//
+// samples must be sorted and must not contain NULLs. numRows is the number of
+// (non-NULL) values of which samples is a sample; the counts of the buckets
+// are scaled accordingly.
+func EquiDepthHistogram(
+	evalCtx *parser.EvalContext, samples parser.Datums, numRows int64, maxBuckets int,
+) (HistogramData, error) {
+	numSamples := len(samples)
+	if numSamples == 0 {
+		return HistogramData{}, nil
+	}
+	if maxBuckets < 1 {
+		return HistogramData{}, errors.Errorf("histogram requires at least one bucket")
+	}
+	if numRows < int64(numSamples) {
+		return HistogramData{}, errors.Errorf("more samples than rows")
+	}
+	h := HistogramData{
+		ColumnType: sqlbase.DatumTypeToColumnType(samples[0].ResolvedType()),
+	}
+	for i := 0; i < numSamples; {
+		numBuckets := maxBuckets - len(h.Buckets)
+		if numBuckets < 1 {
+			numBuckets = 1
+		}
+		// The bucket extends to the sample at index j, rounded up so that the
+		// last bucket is not smaller than the others.
+		j := i + (numSamples-i+numBuckets-1)/numBuckets - 1
+		upper := samples[j]
+		// All the samples equal to the upper bound belong to the bucket.
+		for j+1 < numSamples && upper.Compare(evalCtx, samples[j+1]) == 0 {
+			j++
+		}
+		numEq := 1
+		for k := j - 1; k >= i && upper.Compare(evalCtx, samples[k]) == 0; k-- {
+			numEq++
+		}
+		numRange := j - i + 1 - numEq
+
+		encoded, err := sqlbase.EncodeTableKey(nil, upper, encoding.Ascending)
+		if err != nil {
+			return HistogramData{}, err
+		}
+		h.Buckets = append(h.Buckets, HistogramData_Bucket{
+			NumEq:      int64(numEq) * numRows / int64(numSamples),
+			NumRange:   int64(numRange) * numRows / int64(numSamples),
+			UpperBound: encoded,
+		})
+		i = j + 1
+	}
+	return h, nil
+}
+
+// histogramBucket is a bucket of a HistogramData whose upper bound has been
+// decoded.
+type histogramBucket struct {
+	numEq      float64
+	numRange   float64
+	upperBound parser.Datum
+}
+
+// decodeHistogram decodes the upper bounds of the buckets of a histogram.
+func decodeHistogram(h *HistogramData) ([]histogramBucket, error) {
+	var a sqlbase.DatumAlloc
+	typ := h.ColumnType.ToDatumType()
+	buckets := make([]histogramBucket, len(h.Buckets))
+	for i, b := range h.Buckets {
+		datum, _, err := sqlbase.DecodeTableKey(&a, typ, b.UpperBound, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		buckets[i] = histogramBucket{
+			numEq:      float64(b.NumEq),
+			numRange:   float64(b.NumRange),
+			upperBound: datum,
+		}
+	}
+	return buckets, nil
+}
+
+// HistogramSupported returns whether histograms can be built on a column of
+// type t. The upper bounds of the buckets are stored in their key encoding,
+// which does not exist or cannot be decoded for some types.
+func HistogramSupported(t sqlbase.ColumnType) bool {
+	switch t.Kind {
+	case sqlbase.ColumnType_BOOL, sqlbase.ColumnType_INT, sqlbase.ColumnType_FLOAT,
+		sqlbase.ColumnType_DECIMAL, sqlbase.ColumnType_DATE, sqlbase.ColumnType_TIMESTAMP,
+		sqlbase.ColumnType_INTERVAL, sqlbase.ColumnType_STRING, sqlbase.ColumnType_BYTES,
+		sqlbase.ColumnType_TIMESTAMPTZ, sqlbase.ColumnType_NAME, sqlbase.ColumnType_OID,
+		sqlbase.ColumnType_UUID:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+type expBucket struct {
+	upper           int
+	numEq, numRange int64
+}
+
+func intDatums(vals ...int) parser.Datums {
+	res := make(parser.Datums, len(vals))
+	for i, v := range vals {
+		res[i] = parser.NewDInt(parser.DInt(v))
+	}
+	return res
+}
+
+func TestEquiDepthHistogram(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	uniform := make([]int, 100)
+	for i := range uniform {
+		uniform[i] = i + 1
+	}
+
+	testCases := []struct {
+		samples    []int
+		numRows    int64
+		maxBuckets int
+		buckets    []expBucket
+	}{
+		{
+			samples:    []int{1, 2, 4, 5, 5, 9},
+			numRows:    6,
+			maxBuckets: 10,
+			buckets: []expBucket{
+				{1, 1, 0}, {2, 1, 0}, {4, 1, 0}, {5, 2, 0}, {9, 1, 0},
+			},
+		},
+		{
+			samples:    uniform,
+			numRows:    100,
+			maxBuckets: 4,
+			buckets: []expBucket{
+				{25, 1, 24}, {50, 1, 24}, {75, 1, 24}, {100, 1, 24},
+			},
+		},
+		{
+			// The counts are scaled by the number of rows.
+			samples:    []int{1, 1, 1, 1, 1, 1, 2, 3, 4, 5},
+			numRows:    100,
+			maxBuckets: 3,
+			buckets: []expBucket{
+				{1, 60, 0}, {3, 10, 10}, {5, 10, 10},
+			},
+		},
+		{
+			samples:    []int{1, 2, 3},
+			numRows:    3,
+			maxBuckets: 1,
+			buckets: []expBucket{
+				{3, 1, 2},
+			},
+		},
+	}
+
+	evalCtx := parser.MakeTestingEvalContext()
+	for i, tc := range testCases {
+		h, err := EquiDepthHistogram(&evalCtx, intDatums(tc.samples...), tc.numRows, tc.maxBuckets)
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		buckets, err := decodeHistogram(&h)
+		if err != nil {
+			t.Fatalf("%d: %s", i, err)
+		}
+		if len(buckets) != len(tc.buckets) {
+			t.Fatalf("%d: expected %d buckets, got %d", i, len(tc.buckets), len(buckets))
+		}
+		for j, b := range buckets {
+			exp := tc.buckets[j]
+			if upper := int(*b.upperBound.(*parser.DInt)); upper != exp.upper ||
+				int64(b.numEq) != exp.numEq || int64(b.numRange) != exp.numRange {
+				t.Errorf("%d: bucket %d: expected %+v, got {%d %.0f %.0f}",
+					i, j, exp, upper, b.numEq, b.numRange)
+			}
+		}
+	}
+
+	if _, err := EquiDepthHistogram(&evalCtx, intDatums(1, 2), 1, 10); !testutils.IsError(
+		err, "more samples than rows",
+	) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"container/heap"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// SampledRow is a row that was sampled.
+type SampledRow struct {
+	Row  sqlbase.EncDatumRow
+	Rank uint64
+}
+
+// SampleReservoir implements reservoir sampling using random sort. Each row
+// is assigned a rank (which should be a uniformly generated random value),
+// and the rows with the smallest ranks are retained.
+//
+// Sampling by rank makes it easy to combine the samples of several streams:
+// the union of their samples can simply be sampled again.
+//
+// The reservoir is a max-heap of the retained rows, so that a new row only
+// needs to be compared with the retained row of highest rank.
+type SampleReservoir struct {
+	size    int
+	samples []SampledRow
+	ra      sqlbase.EncDatumRowAlloc
+}
+
+var _ heap.Interface = &SampleReservoir{}
+
+// Init initializes a SampleReservoir that retains at most size rows.
+func (sr *SampleReservoir) Init(size int) {
+	sr.size = size
+	sr.samples = make([]SampledRow, 0, size)
+}
+
+// Len is part of heap.Interface.
+func (sr *SampleReservoir) Len() int {
+	return len(sr.samples)
+}
+
+// Less is part of heap.Interface.
+func (sr *SampleReservoir) Less(i, j int) bool {
+	// We want a max heap, so we return the opposite.
+	return sr.samples[i].Rank > sr.samples[j].Rank
+}
+
+// Swap is part of heap.Interface.
+func (sr *SampleReservoir) Swap(i, j int) {
+	sr.samples[i], sr.samples[j] = sr.samples[j], sr.samples[i]
+}
+
+// Push is part of heap.Interface, but we're not using it.
+func (sr *SampleReservoir) Push(x interface{}) { panic("unimplemented") }
+
+// Pop is part of heap.Interface, but we're not using it.
+func (sr *SampleReservoir) Pop() interface{} { panic("unimplemented") }
+
+// SampleRow looks at a row and either drops it or adds it to the reservoir.
+// The row is copied if it is retained.
+func (sr *SampleReservoir) SampleRow(row sqlbase.EncDatumRow, rank uint64) {
+	if len(sr.samples) < sr.size {
+		sr.samples = append(sr.samples, SampledRow{Row: sr.ra.CopyRow(row), Rank: rank})
+		if len(sr.samples) == sr.size {
+			heap.Init(sr)
+		}
+		return
+	}
+	// Replace the max rank if ours is smaller.
+	if sr.size > 0 && rank < sr.samples[0].Rank {
+		sr.samples[0] = SampledRow{Row: sr.ra.CopyRow(row), Rank: rank}
+		heap.Fix(sr, 0)
+	}
+}
+
+// Get returns the sampled rows, in no particular order.
+func (sr *SampleReservoir) Get() []SampledRow {
+	return sr.samples
+}
@@ -0,0 +1,70 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestSampleReservoir(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, size := range []int{1, 5, 100} {
+		for _, numRows := range []int{0, 1, 5, 500} {
+			var sr SampleReservoir
+			sr.Init(size)
+			// The ranks are a permutation of the row indexes, so the retained
+			// rows are the ones with the smallest ranks.
+			for i := 0; i < numRows; i++ {
+				rank := uint64((i * 7919) % numRows)
+				row := sqlbase.EncDatumRow{
+					sqlbase.DatumToEncDatum(
+						sqlbase.ColumnType{Kind: sqlbase.ColumnType_INT},
+						parser.NewDInt(parser.DInt(rank)),
+					),
+				}
+				sr.SampleRow(row, rank)
+			}
+
+			expected := size
+			if numRows < size {
+				expected = numRows
+			}
+			samples := sr.Get()
+			if len(samples) != expected {
+				t.Fatalf("size %d, %d rows: expected %d samples, got %d",
+					size, numRows, expected, len(samples))
+			}
+			ranks := make([]int, len(samples))
+			for i, s := range samples {
+				if v := uint64(*s.Row[0].Datum.(*parser.DInt)); v != s.Rank {
+					t.Fatalf("row %d was sampled with rank %d", v, s.Rank)
+				}
+				ranks[i] = int(s.Rank)
+			}
+			sort.Ints(ranks)
+			for i, r := range ranks {
+				if r != i {
+					t.Fatalf("size %d, %d rows: unexpected ranks %v", size, numRows, ranks)
+				}
+			}
+		}
+	}
+}
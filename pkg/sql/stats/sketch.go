@@ -0,0 +1,151 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"container/heap"
+	"hash/fnv"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// DistinctSketch estimates the number of distinct values in a set.
+//
+// It retains the k smallest hashes of the values (a "k minimum values"
+// sketch). The hashes are uniformly distributed, so if the largest retained
+// hash is the fraction f of the range of the hashes, the set has about
+// (k-1)/f distinct values. The estimate is exact when the set has fewer than
+// k distinct values.
+//
+// Sketches built on different sets can be merged into a sketch of the union
+// of the sets, which is how the distinct counts of a table are computed from
+// the sketches built on each of its ranges.
+type DistinctSketch struct {
+	k int
+	// hashes is a max-heap of the smallest hashes added so far.
+	hashes uint64Heap
+	// seen contains the elements of hashes.
+	seen map[uint64]struct{}
+}
+
+// NewDistinctSketch creates a DistinctSketch that retains k hashes.
+func NewDistinctSketch(k int) *DistinctSketch {
+	return &DistinctSketch{
+		k:    k,
+		seen: make(map[uint64]struct{}),
+	}
+}
+
+// Add adds an encoded value to the set. Values are equal if their encodings
+// are equal.
+func (s *DistinctSketch) Add(value []byte) {
+	h := fnv.New64a()
+	_, _ = h.Write(value)
+	s.addHash(mix64(h.Sum64()))
+}
+
+func (s *DistinctSketch) addHash(hash uint64) {
+	if _, ok := s.seen[hash]; ok {
+		return
+	}
+	if len(s.hashes) < s.k {
+		heap.Push(&s.hashes, hash)
+		s.seen[hash] = struct{}{}
+		return
+	}
+	if hash >= s.hashes[0] {
+		return
+	}
+	delete(s.seen, s.hashes[0])
+	s.hashes[0] = hash
+	heap.Fix(&s.hashes, 0)
+	s.seen[hash] = struct{}{}
+}
+
+// Merge adds the values of another sketch to the sketch.
+func (s *DistinctSketch) Merge(other *DistinctSketch) {
+	for _, hash := range other.hashes {
+		s.addHash(hash)
+	}
+}
+
+// Estimate returns the estimated number of distinct values in the set.
+func (s *DistinctSketch) Estimate() uint64 {
+	if len(s.hashes) < s.k || s.k < 2 {
+		return uint64(len(s.hashes))
+	}
+	fraction := float64(s.hashes[0]) / math.MaxUint64
+	return uint64(float64(s.k-1) / fraction)
+}
+
+// MarshalBinary encodes the sketch.
+func (s *DistinctSketch) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 8*(len(s.hashes)+1))
+	b = encoding.EncodeUvarintAscending(b, uint64(s.k))
+	for _, hash := range s.hashes {
+		b = encoding.EncodeUint64Ascending(b, hash)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes a sketch encoded by MarshalBinary.
+func (s *DistinctSketch) UnmarshalBinary(b []byte) error {
+	b, k, err := encoding.DecodeUvarintAscending(b)
+	if err != nil {
+		return errors.Wrap(err, "decoding sketch")
+	}
+	if len(b)%8 != 0 || uint64(len(b)/8) > k {
+		return errors.Errorf("invalid sketch encoding")
+	}
+	*s = *NewDistinctSketch(int(k))
+	for len(b) > 0 {
+		var hash uint64
+		b, hash, err = encoding.DecodeUint64Ascending(b)
+		if err != nil {
+			return errors.Wrap(err, "decoding sketch")
+		}
+		s.addHash(hash)
+	}
+	return nil
+}
+
+// mix64 is the finalizer of MurmurHash3. It spreads the bits of FNV hashes,
+// which are not uniform enough for the estimate.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// uint64Heap is a max-heap of uint64s.
+type uint64Heap []uint64
+
+func (h uint64Heap) Len() int            { return len(h) }
+func (h uint64Heap) Less(i, j int) bool  { return h[i] > h[j] }
+func (h uint64Heap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *uint64Heap) Push(x interface{}) { *h = append(*h, x.(uint64)) }
+func (h *uint64Heap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func sketchValue(i int) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(i))
+	return b[:]
+}
+
+func TestDistinctSketch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		numValues   int
+		numDistinct int
+	}{
+		{0, 0},
+		{10, 1},
+		{100, 100},
+		{1000, 10},
+		{10000, 5000},
+		{100000, 100000},
+	}
+	for _, tc := range testCases {
+		s := NewDistinctSketch(1024)
+		for i := 0; i < tc.numValues; i++ {
+			s.Add(sketchValue(i % tc.numDistinct))
+		}
+		est := float64(s.Estimate())
+		// The estimate is exact below the size of the sketch; above, its
+		// standard error is about 3%.
+		lo, hi := 0.85*float64(tc.numDistinct), 1.15*float64(tc.numDistinct)
+		if tc.numDistinct < 1024 {
+			lo, hi = float64(tc.numDistinct), float64(tc.numDistinct)
+		}
+		if est < lo || est > hi {
+			t.Errorf("%d values, %d distinct: estimate %.0f not in [%.0f, %.0f]",
+				tc.numValues, tc.numDistinct, est, lo, hi)
+		}
+	}
+}
+
+func TestDistinctSketchMerge(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	// Three overlapping sets of 20000 values: [0, 20000), [10000, 30000) and
+	// [20000, 40000).
+	merged := NewDistinctSketch(1024)
+	for part := 0; part < 3; part++ {
+		s := NewDistinctSketch(1024)
+		for i := part * 10000; i < part*10000+20000; i++ {
+			s.Add(sketchValue(i))
+		}
+		// Round-trip the sketch through its encoding, as the samplers do.
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded DistinctSketch
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Estimate() != s.Estimate() {
+			t.Fatalf("decoded estimate %d, expected %d", decoded.Estimate(), s.Estimate())
+		}
+		merged.Merge(&decoded)
+	}
+	if est := merged.Estimate(); est < 34000 || est > 46000 {
+		t.Errorf("merged estimate %d, expected about 40000", est)
+	}
+}
+
+func TestDistinctSketchUnmarshalInvalid(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var s DistinctSketch
+	for _, data := range [][]byte{nil, {0x89, 1, 2, 3}} {
+		if err := s.UnmarshalBinary(data); err == nil {
+			t.Errorf("expected error decoding %v", data)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// A TableStatisticsCache contains the statistics of recently used tables.
+// The statistics are read from system.table_statistics the first time a
+// table is used, and are kept until the cache entry is evicted or
+// invalidated by the collection of new statistics on the table. The node
+// collecting new statistics notifies the caches on all nodes through gossip
+// (see GossipTableStatAdded).
+type TableStatisticsCache struct {
+	mu    syncutil.Mutex
+	cache *cache.UnorderedCache
+
+	db       *client.DB
+	executor sqlutil.InternalExecutor
+}
+
+// NewTableStatisticsCache creates a TableStatisticsCache that holds the
+// statistics of at most cacheSize tables. If g is not nil, the cache
+// invalidates its entries when new statistics are gossiped.
+func NewTableStatisticsCache(
+	cacheSize int, g *gossip.Gossip, db *client.DB, executor sqlutil.InternalExecutor,
+) *TableStatisticsCache {
+	sc := &TableStatisticsCache{
+		cache: cache.NewUnorderedCache(cache.Config{
+			Policy: cache.CacheLRU,
+			ShouldEvict: func(s int, key, value interface{}) bool {
+				return s > cacheSize
+			},
+		}),
+		db:       db,
+		executor: executor,
+	}
+	if g != nil {
+		g.RegisterCallback(
+			gossip.MakePrefixPattern(gossip.KeyTableStatAddedPrefix),
+			sc.tableStatAddedGossipUpdate,
+		)
+	}
+	return sc
+}
+
+// GossipTableStatAdded notifies the statistics caches on all nodes that new
+// statistics were collected for the given table.
+func GossipTableStatAdded(g *gossip.Gossip, tableID sqlbase.ID) error {
+	return g.AddInfo(
+		gossip.MakeTableStatAddedKey(uint32(tableID)),
+		nil, /* value */
+		0,   /* ttl */
+	)
+}
+
+// tableStatAddedGossipUpdate is the gossip callback that invalidates the
+// statistics of a table when new statistics are collected on any node.
+func (sc *TableStatisticsCache) tableStatAddedGossipUpdate(key string, _ roachpb.Value) {
+	tableID, err := gossip.TableIDFromTableStatAddedKey(key)
+	if err != nil {
+		log.Errorf(context.Background(), "tableStatAddedGossipUpdate(%s) error: %v", key, err)
+		return
+	}
+	sc.InvalidateTableStats(sqlbase.ID(tableID))
+}
+
+// GetTableStats returns the statistics of a table, most recent first. The
+// statistics of system tables are never collected, so none are returned for
+// them.
+func (sc *TableStatisticsCache) GetTableStats(
+	ctx context.Context, tableID sqlbase.ID,
+) ([]*TableStatistic, error) {
+	if tableID <= keys.MaxReservedDescID {
+		return nil, nil
+	}
+	sc.mu.Lock()
+	if v, ok := sc.cache.Get(tableID); ok {
+		sc.mu.Unlock()
+		return v.([]*TableStatistic), nil
+	}
+	sc.mu.Unlock()
+
+	// The statistics are read outside of the lock; concurrent lookups of the
+	// same table may read them several times, which is harmless.
+	stats, err := sc.getTableStatsFromDB(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.cache.Add(tableID, stats)
+	return stats, nil
+}
+
+// InvalidateTableStats invalidates the cached statistics of a table.
+func (sc *TableStatisticsCache) InvalidateTableStats(tableID sqlbase.ID) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.cache.Del(tableID)
+}
+
+const (
+	statisticIDIndex = iota
+	nameIndex
+	columnIDsIndex
+	createdAtIndex
+	rowCountIndex
+	distinctCountIndex
+	nullCountIndex
+	histogramIndex
+	statsLen
+)
+
+// getTableStatsFromDB reads the statistics of a table from
+// system.table_statistics.
+func (sc *TableStatisticsCache) getTableStatsFromDB(
+	ctx context.Context, tableID sqlbase.ID,
+) ([]*TableStatistic, error) {
+	const getTableStatisticsStmt = `
+SELECT "statisticID", name, "columnIDs", "createdAt", "rowCount", "distinctCount", "nullCount", histogram
+FROM system.table_statistics
+WHERE "tableID" = $1
+ORDER BY "createdAt" DESC
+`
+	var rows []parser.Datums
+	if err := sc.db.Txn(ctx, func(ctx context.Context, txn *client.Txn) error {
+		var err error
+		rows, err = sc.executor.QueryRowsInTransaction(
+			ctx, "get-table-statistics", txn, getTableStatisticsStmt, tableID,
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	stats := make([]*TableStatistic, 0, len(rows))
+	for _, row := range rows {
+		stat, err := parseStats(tableID, row)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// parseStats converts a row of system.table_statistics into a TableStatistic.
+func parseStats(tableID sqlbase.ID, row parser.Datums) (*TableStatistic, error) {
+	if len(row) != statsLen {
+		return nil, errors.Errorf("%d values returned from table statistics lookup, expected %d",
+			len(row), statsLen)
+	}
+	stat := &TableStatistic{
+		TableID:       tableID,
+		StatisticID:   int64(*row[statisticIDIndex].(*parser.DInt)),
+		CreatedAt:     row[createdAtIndex].(*parser.DTimestamp).Time,
+		RowCount:      int64(*row[rowCountIndex].(*parser.DInt)),
+		DistinctCount: int64(*row[distinctCountIndex].(*parser.DInt)),
+		NullCount:     int64(*row[nullCountIndex].(*parser.DInt)),
+	}
+	if row[nameIndex] != parser.DNull {
+		stat.Name = string(*row[nameIndex].(*parser.DString))
+	}
+	columnIDs := row[columnIDsIndex].(*parser.DArray).Array
+	stat.ColumnIDs = make([]sqlbase.ColumnID, len(columnIDs))
+	for i, d := range columnIDs {
+		stat.ColumnIDs[i] = sqlbase.ColumnID(*d.(*parser.DInt))
+	}
+	if row[histogramIndex] != parser.DNull {
+		var h HistogramData
+		if err := h.Unmarshal([]byte(*row[histogramIndex].(*parser.DBytes))); err != nil {
+			return nil, err
+		}
+		if err := stat.SetHistogram(&h); err != nil {
+			return nil, err
+		}
+	}
+	return stat, nil
+}
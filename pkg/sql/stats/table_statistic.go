@@ -0,0 +1,175 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// DefaultRangeSelectivity is the estimated fraction of the rows that satisfy
+// a range constraint on a column without a histogram.
+const DefaultRangeSelectivity = 1.0 / 3
+
+// A TableStatistic is a statistic on a column of a table, as stored in
+// system.table_statistics.
+type TableStatistic struct {
+	TableID     sqlbase.ID
+	StatisticID int64
+	// Name is the name given to the statistic by CREATE STATISTICS. It is
+	// empty for the statistics collected by ANALYZE.
+	Name      string
+	ColumnIDs []sqlbase.ColumnID
+	CreatedAt time.Time
+
+	// RowCount is the number of rows of the table.
+	RowCount int64
+	// DistinctCount is the estimated number of distinct non-NULL values of
+	// the column.
+	DistinctCount int64
+	// NullCount is the number of rows in which the column is NULL.
+	NullCount int64
+
+	// histogram is the decoded histogram of the column, if any.
+	histogram []histogramBucket
+	// histogramType is the type of the upper bounds of the histogram.
+	histogramType parser.Type
+}
+
+// SetHistogram sets the histogram of the statistic.
+func (s *TableStatistic) SetHistogram(h *HistogramData) error {
+	buckets, err := decodeHistogram(h)
+	if err != nil {
+		return err
+	}
+	s.histogram = buckets
+	s.histogramType = h.ColumnType.ToDatumType()
+	return nil
+}
+
+// Bound is a bound of a range of values. A nil Datum denotes an unbounded
+// range.
+type Bound struct {
+	Datum     parser.Datum
+	Inclusive bool
+}
+
+// NullSelectivity returns the estimated fraction of the rows in which the
+// column is NULL.
+func (s *TableStatistic) NullSelectivity() float64 {
+	return s.fraction(float64(s.NullCount))
+}
+
+// EqSelectivity returns the estimated fraction of the rows in which the
+// column is equal to d.
+func (s *TableStatistic) EqSelectivity(evalCtx *parser.EvalContext, d parser.Datum) float64 {
+	if d == parser.DNull {
+		return 0
+	}
+	// The average number of rows per distinct value.
+	avg := float64(s.RowCount - s.NullCount)
+	if s.DistinctCount > 1 {
+		avg /= float64(s.DistinctCount)
+	}
+	if !s.useHistogram(d) {
+		return s.fraction(avg)
+	}
+	for _, b := range s.histogram {
+		switch c := d.Compare(evalCtx, b.upperBound); {
+		case c == 0:
+			return s.fraction(b.numEq)
+		case c < 0:
+			if avg > b.numRange {
+				avg = b.numRange
+			}
+			return s.fraction(avg)
+		}
+	}
+	// The value is greater than all the sampled values.
+	return s.fraction(0)
+}
+
+// RangeSelectivity returns the estimated fraction of the rows in which the
+// column is between the bounds.
+func (s *TableStatistic) RangeSelectivity(evalCtx *parser.EvalContext, lo, hi Bound) float64 {
+	if lo.Datum == nil && hi.Datum == nil {
+		return s.fraction(float64(s.RowCount - s.NullCount))
+	}
+	if (lo.Datum != nil && !s.useHistogram(lo.Datum)) ||
+		(hi.Datum != nil && !s.useHistogram(hi.Datum)) || len(s.histogram) == 0 {
+		return DefaultRangeSelectivity
+	}
+
+	var rows float64
+	var prev parser.Datum
+	for _, b := range s.histogram {
+		// cmpLo and cmpHi compare the upper bound of the bucket with the
+		// bounds of the range.
+		cmpLo, cmpHi := 1, -1
+		if lo.Datum != nil {
+			cmpLo = b.upperBound.Compare(evalCtx, lo.Datum)
+		}
+		if hi.Datum != nil {
+			cmpHi = b.upperBound.Compare(evalCtx, hi.Datum)
+		}
+		if (cmpLo > 0 || (cmpLo == 0 && lo.Inclusive)) &&
+			(cmpHi < 0 || (cmpHi == 0 && hi.Inclusive)) {
+			rows += b.numEq
+		}
+
+		// The values counted by numRange are strictly between prev (or the
+		// smallest value, if this is the first bucket) and the upper bound.
+		// They are all in the range if the range covers the whole bucket, and
+		// none of them are if the range is disjoint from the bucket; otherwise,
+		// the range is assumed to contain half of them.
+		switch {
+		case cmpLo <= 0:
+			// The range starts after the bucket.
+		case hi.Datum != nil && prev != nil && hi.Datum.Compare(evalCtx, prev) <= 0:
+			// The range ends before the bucket.
+		case cmpHi <= 0 &&
+			(lo.Datum == nil || (prev != nil && lo.Datum.Compare(evalCtx, prev) <= 0)):
+			rows += b.numRange
+		default:
+			rows += b.numRange / 2
+		}
+		prev = b.upperBound
+	}
+	return s.fraction(rows)
+}
+
+// useHistogram returns whether the histogram can be used to estimate the
+// selectivity of a constraint comparing the column to d.
+func (s *TableStatistic) useHistogram(d parser.Datum) bool {
+	return s.histogram != nil && d.ResolvedType().Equivalent(s.histogramType)
+}
+
+// fraction returns the fraction of the rows of the table that rows
+// represents. Estimates are never lower than one row, since the statistics
+// may be stale or may have missed rare values.
+func (s *TableStatistic) fraction(rows float64) float64 {
+	if s.RowCount <= 0 {
+		return 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	if f := rows / float64(s.RowCount); f < 1 {
+		return f
+	}
+	return 1
+}
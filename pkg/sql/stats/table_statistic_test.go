@@ -0,0 +1,76 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stats
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func TestTableStatisticSelectivity(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	evalCtx := parser.MakeTestingEvalContext()
+	// 100 rows: 60 of them have the value 1, 10 each have the values 2 to 5,
+	// and 0 rows have NULL.
+	h, err := EquiDepthHistogram(
+		&evalCtx, intDatums(1, 1, 1, 1, 1, 1, 2, 3, 4, 5), 100 /* numRows */, 3, /* maxBuckets */
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withHist := &TableStatistic{RowCount: 100, DistinctCount: 5}
+	if err := withHist.SetHistogram(&h); err != nil {
+		t.Fatal(err)
+	}
+	// The same table, with 20 more NULL rows and no histogram.
+	noHist := &TableStatistic{RowCount: 120, DistinctCount: 5, NullCount: 20}
+
+	d := func(v int) parser.Datum { return parser.NewDInt(parser.DInt(v)) }
+	incl := func(v int) Bound { return Bound{Datum: d(v), Inclusive: true} }
+	excl := func(v int) Bound { return Bound{Datum: d(v)} }
+	unbounded := Bound{}
+
+	testCases := []struct {
+		name     string
+		estimate float64
+		expected float64
+	}{
+		// The frequent value is the upper bound of a bucket.
+		{"eq frequent", withHist.EqSelectivity(&evalCtx, d(1)), 0.6},
+		// The other values are limited by the size of their bucket.
+		{"eq rare", withHist.EqSelectivity(&evalCtx, d(2)), 0.1},
+		// The values outside the histogram are estimated to match one row.
+		{"eq absent", withHist.EqSelectivity(&evalCtx, d(7)), 0.01},
+		{"eq null", withHist.EqSelectivity(&evalCtx, parser.DNull), 0},
+		{"eq without histogram", noHist.EqSelectivity(&evalCtx, d(1)), 20.0 / 120},
+		{"range", withHist.RangeSelectivity(&evalCtx, incl(2), incl(5)), 0.35},
+		{"range exclusive", withHist.RangeSelectivity(&evalCtx, excl(1), excl(5)), 0.3},
+		{"range below", withHist.RangeSelectivity(&evalCtx, unbounded, incl(1)), 0.6},
+		{"range above", withHist.RangeSelectivity(&evalCtx, excl(5), unbounded), 0.01},
+		{"not null", withHist.RangeSelectivity(&evalCtx, unbounded, unbounded), 1},
+		{"not null without histogram", noHist.RangeSelectivity(&evalCtx, unbounded, unbounded), 100.0 / 120},
+		{"range without histogram", noHist.RangeSelectivity(&evalCtx, incl(2), unbounded), DefaultRangeSelectivity},
+		{"null", noHist.NullSelectivity(), 20.0 / 120},
+	}
+	for _, tc := range testCases {
+		if math.Abs(tc.estimate-tc.expected) > 1e-9 {
+			t.Errorf("%s: expected %g, got %g", tc.name, tc.expected, tc.estimate)
+		}
+	}
+}
@@ -307,6 +307,10 @@ func (tu *tableUpserter) init(txn *client.Txn) error {
 }
 
 func (tu *tableUpserter) row(ctx context.Context, row parser.Datums) (parser.Datums, error) {
+	// The values of the computed columns are needed to detect conflicts.
+	if err := tu.ri.ComputeColumns(row); err != nil {
+		return nil, err
+	}
 	if tu.fastPathBatch != nil {
 		primaryKey, _, err := sqlbase.EncodeIndexKey(
 			tu.tableDesc, &tu.tableDesc.PrimaryIndex, tu.ri.InsertColIDtoRowIndex, row, tu.indexKeyPrefix)
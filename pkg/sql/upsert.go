@@ -184,6 +184,10 @@ func upsertExprsAndIndex(
 		}
 		updateExprs := make(parser.UpdateExprs, 0, len(insertCols))
 		for _, c := range insertCols {
+			if c.IsComputed() {
+				// The row updater recomputes the computed columns.
+				continue
+			}
 			if _, ok := indexColSet[c.ID]; !ok {
 				names := parser.UnresolvedNames{
 					parser.UnresolvedName{parser.Name(c.Name)},
@@ -199,7 +203,8 @@ func upsertExprsAndIndex(
 	}
 
 	indexMatch := func(index sqlbase.IndexDescriptor) bool {
-		if !index.Unique {
+		// Partial indexes only enforce uniqueness among the rows they contain.
+		if !index.Unique || index.IsPartial() {
 			return false
 		}
 		if len(index.ColumnNames) != len(onConflict.Columns) {
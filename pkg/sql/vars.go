@@ -179,6 +179,21 @@ var varGen = map[string]sessionVar{
 			return nil
 		},
 	},
+	`statement_timeout`: {
+		Set: func(_ context.Context, p *planner, values []parser.TypedExpr) error {
+			timeout, err := p.getTimeoutVal(`statement_timeout`, values)
+			if err != nil {
+				return err
+			}
+			p.session.StatementTimeout = timeout
+			return nil
+		},
+		Get: func(p *planner) string { return p.session.StatementTimeout.String() },
+		Reset: func(p *planner) error {
+			p.session.StatementTimeout = StatementClusterTimeout.Get()
+			return nil
+		},
+	},
 	`standard_conforming_strings`: {
 		Set: func(_ context.Context, p *planner, values []parser.TypedExpr) error {
 			// If true, escape backslash literals in strings. We do this by default,
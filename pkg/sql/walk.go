@@ -84,6 +84,10 @@ func (v *planVisitor) visit(plan planNode) {
 	if v.err != nil {
 		return
 	}
+	if n, ok := plan.(*instrumentedNode); ok {
+		// EXPLAIN ANALYZE's instrumentation is not part of the plan.
+		plan = n.planNode
+	}
 
 	name := nodeName(plan)
 	recurse := true
@@ -203,6 +207,9 @@ func (v *planVisitor) visit(plan planNode) {
 				buf.WriteByte(')')
 				v.observer.attr(name, "equality", buf.String())
 			}
+			if n.buildLeft {
+				v.observer.attr(name, "build", "left")
+			}
 		}
 		subplans := v.expr(name, "pred", -1, n.pred.onCond, nil)
 		v.subqueries(name, subplans)
@@ -276,6 +283,12 @@ func (v *planVisitor) visit(plan planNode) {
 	case *splitNode:
 		v.visit(n.rows)
 
+	case *cancelQueryNode:
+		v.subqueries(name, v.expr(name, "query", -1, n.queryID, nil))
+
+	case *controlJobNode:
+		v.subqueries(name, v.expr(name, "job", -1, n.jobID, nil))
+
 	case *relocateNode:
 		v.visit(n.rows)
 
@@ -373,9 +386,15 @@ func (v *planVisitor) visit(plan planNode) {
 	case *explainDistSQLNode:
 		v.visit(n.plan)
 
+	case *explainAnalyzeNode:
+		v.visit(n.plan)
+
 	case *ordinalityNode:
 		v.visit(n.source)
 
+	case *recursiveCTENode:
+		v.visit(n.initial)
+
 	case *explainTraceNode:
 		v.visit(n.plan)
 
@@ -492,9 +511,13 @@ func nodeName(plan planNode) string {
 // be changed without changing the output of "EXPLAIN".
 var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterTableNode{}):     "alter table",
+	reflect.TypeOf(&cancelQueryNode{}):    "cancel query",
 	reflect.TypeOf(&copyNode{}):           "copy",
 	reflect.TypeOf(&createDatabaseNode{}): "create database",
 	reflect.TypeOf(&createIndexNode{}):    "create index",
+	reflect.TypeOf(&controlJobNode{}):     "control job",
+	reflect.TypeOf(&createSequenceNode{}): "create sequence",
+	reflect.TypeOf(&createStatsNode{}):    "create statistics",
 	reflect.TypeOf(&createTableNode{}):    "create table",
 	reflect.TypeOf(&createUserNode{}):     "create user",
 	reflect.TypeOf(&createViewNode{}):     "create view",
@@ -503,9 +526,11 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&distinctNode{}):       "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):   "drop database",
 	reflect.TypeOf(&dropIndexNode{}):      "drop index",
+	reflect.TypeOf(&dropSequenceNode{}):   "drop sequence",
 	reflect.TypeOf(&dropTableNode{}):      "drop table",
 	reflect.TypeOf(&dropViewNode{}):       "drop view",
 	reflect.TypeOf(&emptyNode{}):          "empty",
+	reflect.TypeOf(&explainAnalyzeNode{}): "explain analyze",
 	reflect.TypeOf(&explainDebugNode{}):   "explain debug",
 	reflect.TypeOf(&explainDistSQLNode{}): "explain dist_sql",
 	reflect.TypeOf(&explainPlanNode{}):    "explain plan",
@@ -518,8 +543,10 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&joinNode{}):           "join",
 	reflect.TypeOf(&limitNode{}):          "limit",
 	reflect.TypeOf(&ordinalityNode{}):     "ordinality",
+	reflect.TypeOf(&recursiveCTENode{}):   "recursive cte",
 	reflect.TypeOf(&relocateNode{}):       "relocate",
 	reflect.TypeOf(&renderNode{}):         "render",
+	reflect.TypeOf(&roleNode{}):           "role",
 	reflect.TypeOf(&scanNode{}):           "scan",
 	reflect.TypeOf(&scatterNode{}):        "scatter",
 	reflect.TypeOf(&showRangesNode{}):     "showRanges",
@@ -0,0 +1,491 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlbase"
+)
+
+// cteSource is a common table expression (CTE) defined by a WITH clause. It
+// can be referenced by name as a data source while planning the statement the
+// WITH clause is attached to.
+type cteSource struct {
+	name string
+	// plan builds a new data source for each reference to the CTE. Like a
+	// view, a CTE is planned anew every time it is referenced.
+	plan func(ctx context.Context) (planDataSource, error)
+}
+
+// getCTEDataSource returns the data source for the innermost visible CTE
+// with the given name, if there is one.
+func (p *planner) getCTEDataSource(
+	ctx context.Context, tn *parser.TableName,
+) (planDataSource, bool, error) {
+	if tn.DatabaseName != "" {
+		// A CTE name is never qualified.
+		return planDataSource{}, false, nil
+	}
+	name := tn.TableName.Normalize()
+	for i := len(p.ctes) - 1; i >= 0; i-- {
+		if p.ctes[i].name == name {
+			ds, err := p.ctes[i].plan(ctx)
+			return ds, true, err
+		}
+	}
+	return planDataSource{}, false, nil
+}
+
+// addCTEs makes the CTEs defined by a WITH clause visible to the planning of
+// the statement the clause is attached to. The returned function must be
+// called once that statement is planned, to restore the previously visible
+// CTEs.
+func (p *planner) addCTEs(ctx context.Context, with *parser.With) (func(), error) {
+	saved := p.ctes
+	restore := func() { p.ctes = saved }
+
+	seen := make(map[string]struct{}, len(with.CTEList))
+	for _, cte := range with.CTEList {
+		name := cte.Name.Alias.Normalize()
+		if _, ok := seen[name]; ok {
+			restore()
+			return nil, pgerror.NewErrorf(pgerror.CodeDuplicateAliasError,
+				"WITH query name %q specified more than once", name)
+		}
+		seen[name] = struct{}{}
+
+		sel, ok := cte.Stmt.(*parser.Select)
+		if !ok {
+			restore()
+			return nil, pgerror.NewErrorf(pgerror.CodeFeatureNotSupportedError,
+				"%s is not supported in WITH", cte.Stmt.StatementTag())
+		}
+
+		// Each CTE can only see the ones defined before it. The capacity of
+		// scope is capped so that appending to it never overwrites entries
+		// visible to another CTE.
+		scope := p.ctes[:len(p.ctes):len(p.ctes)]
+		src := cteSource{name: name}
+		if with.Recursive {
+			var err error
+			src.plan, err = p.makeRecursiveCTE(ctx, name, cte.Name.Cols, sel, scope)
+			if err != nil {
+				restore()
+				return nil, err
+			}
+		} else {
+			src.plan = p.makeCTE(name, cte.Name.Cols, sel, scope)
+		}
+		p.ctes = append(scope, src)
+	}
+	return restore, nil
+}
+
+// withCTEScope runs fn with the given set of CTEs visible.
+func (p *planner) withCTEScope(scope []cteSource, fn func() error) error {
+	saved := p.ctes
+	p.ctes = scope
+	defer func() { p.ctes = saved }()
+	return fn()
+}
+
+// makeCTE returns the function planning a reference to a non-recursive CTE.
+func (p *planner) makeCTE(
+	name string, colNames parser.NameList, sel *parser.Select, scope []cteSource,
+) func(ctx context.Context) (planDataSource, error) {
+	return func(ctx context.Context) (planDataSource, error) {
+		var plan planNode
+		if err := p.withCTEScope(scope, func() (err error) {
+			plan, err = p.newPlan(ctx, sel, nil)
+			return err
+		}); err != nil {
+			return planDataSource{}, err
+		}
+		columns, err := cteColumns(name, plan.Columns(), colNames)
+		if err != nil {
+			plan.Close(ctx)
+			return planDataSource{}, err
+		}
+		return planDataSource{
+			info: newSourceInfoForSingleTable(cteTableName(name), columns),
+			plan: plan,
+		}, nil
+	}
+}
+
+// makeRecursiveCTE returns the function planning a reference to a CTE
+// defined in a WITH RECURSIVE clause.
+//
+// A recursive CTE has the form "non-recursive-term UNION [ALL]
+// recursive-term", where only the recursive term refers to the CTE itself.
+// A CTE defined in a WITH RECURSIVE clause that does not refer to itself is
+// planned like any other CTE.
+func (p *planner) makeRecursiveCTE(
+	ctx context.Context,
+	name string,
+	colNames parser.NameList,
+	sel *parser.Select,
+	scope []cteSource,
+) (func(ctx context.Context) (planDataSource, error), error) {
+	union, ok := sel.Select.(*parser.UnionClause)
+	if !ok || union.Type != parser.UnionOp || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil {
+		// This CTE can't be recursive; referring to itself is an error.
+		return p.makeCTE(name, colNames, sel, append(scope, cteSource{
+			name: name,
+			plan: func(context.Context) (planDataSource, error) {
+				return planDataSource{}, pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+					"recursive query %q does not have the form non-recursive-term UNION [ALL] recursive-term",
+					name)
+			},
+		})), nil
+	}
+
+	// The non-recursive term can't refer to the CTE either.
+	nonRecursiveScope := append(scope, cteSource{
+		name: name,
+		plan: func(context.Context) (planDataSource, error) {
+			return planDataSource{}, pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+				"recursive reference to query %q must not appear within its non-recursive term", name)
+		},
+	})
+	planInitial := func(ctx context.Context) (planNode, sqlbase.ResultColumns, error) {
+		var plan planNode
+		if err := p.withCTEScope(nonRecursiveScope, func() (err error) {
+			plan, err = p.newPlan(ctx, union.Left, nil)
+			return err
+		}); err != nil {
+			return nil, nil, err
+		}
+		columns, err := cteColumns(name, plan.Columns(), colNames)
+		if err != nil {
+			plan.Close(ctx)
+			return nil, nil, err
+		}
+		return plan, columns, nil
+	}
+
+	// Plan both terms once, to validate them and find out whether the
+	// recursive term refers to the CTE at all.
+	initial, columns, err := planInitial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	initial.Close(ctx)
+
+	numRefs := 0
+	var recursive planNode
+	if err := p.withCTEScope(append(scope, cteSource{
+		name: name,
+		plan: func(context.Context) (planDataSource, error) {
+			numRefs++
+			return p.makeWorkingTable(name, columns, nil), nil
+		},
+	}), func() (err error) {
+		recursive, err = p.newPlan(ctx, union.Right, nil)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	recursiveColumns := recursive.Columns()
+	recursive.Close(ctx)
+
+	switch {
+	case numRefs == 0:
+		return p.makeCTE(name, colNames, sel, scope), nil
+	case numRefs > 1:
+		return nil, pgerror.NewErrorf(pgerror.CodeInvalidRecursionError,
+			"recursive reference to query %q must not appear more than once", name)
+	}
+	if err := checkRecursiveCTEColumns(name, columns, recursiveColumns); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) (planDataSource, error) {
+		initial, columns, err := planInitial(ctx)
+		if err != nil {
+			return planDataSource{}, err
+		}
+		n := &recursiveCTENode{
+			p:       p,
+			name:    name,
+			columns: columns,
+			all:     union.All,
+			initial: initial,
+			seenAcc: p.session.TxnState.OpenAccount(),
+		}
+		n.genIteration = func(ctx context.Context, rows *sqlbase.RowContainer) (planNode, error) {
+			consumed := false
+			var plan planNode
+			if err := p.withCTEScope(append(scope, cteSource{
+				name: name,
+				plan: func(context.Context) (planDataSource, error) {
+					consumed = true
+					return p.makeWorkingTable(name, columns, rows), nil
+				},
+			}), func() (err error) {
+				plan, err = p.newPlan(ctx, union.Right, nil)
+				return err
+			}); err != nil {
+				if !consumed {
+					rows.Close(ctx)
+				}
+				return nil, err
+			}
+			if !consumed {
+				rows.Close(ctx)
+			}
+			if err := checkRecursiveCTEColumns(name, columns, plan.Columns()); err != nil {
+				plan.Close(ctx)
+				return nil, err
+			}
+			plan, err := p.optimizePlan(ctx, plan, allColumns(plan))
+			if err != nil {
+				plan.Close(ctx)
+				return nil, err
+			}
+			if err := p.startPlan(ctx, plan); err != nil {
+				plan.Close(ctx)
+				return nil, err
+			}
+			return plan, nil
+		}
+		return planDataSource{
+			info: newSourceInfoForSingleTable(cteTableName(name), columns),
+			plan: n,
+		}, nil
+	}, nil
+}
+
+// makeWorkingTable returns a data source serving the given rows under the
+// name of a recursive CTE. The data source takes ownership of the rows.
+func (p *planner) makeWorkingTable(
+	name string, columns sqlbase.ResultColumns, rows *sqlbase.RowContainer,
+) planDataSource {
+	// The valuesNode gets its own copy of the columns, as they are modified
+	// when the needed columns are propagated.
+	columns = append(sqlbase.ResultColumns(nil), columns...)
+	return planDataSource{
+		info: newSourceInfoForSingleTable(cteTableName(name), columns),
+		plan: &valuesNode{p: p, columns: columns, rows: rows},
+	}
+}
+
+// cteTableName returns the name under which the columns of a CTE are
+// visible.
+func cteTableName(name string) parser.TableName {
+	return parser.TableName{TableName: parser.Name(name), DBNameOriginallyOmitted: true}
+}
+
+// cteColumns applies the column names listed in the definition of a CTE, if
+// any, to the columns produced by its query.
+func cteColumns(
+	name string, cols sqlbase.ResultColumns, colNames parser.NameList,
+) (sqlbase.ResultColumns, error) {
+	if len(colNames) == 0 {
+		return cols, nil
+	}
+	// Make a copy of the slice since we are about to modify the contents.
+	cols = append(sqlbase.ResultColumns(nil), cols...)
+	for colIdx, nameIdx := 0, 0; nameIdx < len(colNames); colIdx++ {
+		if colIdx >= len(cols) {
+			return nil, pgerror.NewErrorf(pgerror.CodeInvalidColumnReferenceError,
+				"WITH query %q has %d columns available but %d columns specified",
+				name, nameIdx, len(colNames))
+		}
+		if cols[colIdx].Hidden {
+			continue
+		}
+		cols[colIdx].Name = string(colNames[nameIdx])
+		nameIdx++
+	}
+	return cols, nil
+}
+
+// checkRecursiveCTEColumns verifies that the recursive term of a recursive CTE
+// produces rows that can be stored alongside those of its non-recursive term.
+func checkRecursiveCTEColumns(name string, cols, recursiveCols sqlbase.ResultColumns) error {
+	if len(cols) != len(recursiveCols) {
+		return fmt.Errorf("each %v query must have the same number of columns: %d vs %d",
+			parser.UnionOp, len(cols), len(recursiveCols))
+	}
+	for i := range cols {
+		l, r := cols[i].Typ, recursiveCols[i].Typ
+		if !l.Equivalent(r) && r != parser.TypeNull {
+			return pgerror.NewErrorf(pgerror.CodeDatatypeMismatchError,
+				"recursive query %q column %d has type %s in non-recursive term but type %s overall",
+				name, i+1, l, r)
+		}
+	}
+	return nil
+}
+
+// recursiveCTENode implements a recursive CTE. It first produces the rows of
+// the non-recursive term, then repeatedly runs the recursive term, which reads
+// the rows produced by the previous iteration (the "working table") under the
+// name of the CTE. It stops when an iteration produces no new rows.
+//
+// Only the rows of a single iteration are kept in memory, unless the CTE uses
+// UNION rather than UNION ALL, in which case the encoding of every row
+// produced so far is kept to discard duplicates.
+type recursiveCTENode struct {
+	p       *planner
+	name    string
+	columns sqlbase.ResultColumns
+	// all is set for UNION ALL, which does not discard duplicate rows.
+	all bool
+
+	// initial is the plan of the non-recursive term.
+	initial planNode
+	// genIteration plans and starts the recursive term, reading from the given
+	// working table. It takes ownership of the rows.
+	genIteration func(ctx context.Context, rows *sqlbase.RowContainer) (planNode, error)
+
+	// current is the plan producing rows: initial, then each iteration of the
+	// recursive term in turn. It is nil once the recursion is done.
+	current planNode
+	// nextRows collects the rows produced by current, which make up the
+	// working table of the next iteration.
+	nextRows *sqlbase.RowContainer
+
+	// seen contains the encoding of all the rows produced so far, if
+	// duplicates must be discarded.
+	seen    map[string]struct{}
+	seenAcc WrappableMemoryAccount
+	scratch []byte
+
+	row     parser.Datums
+	explain explainMode
+	rowIdx  int
+}
+
+func (n *recursiveCTENode) newRowContainer() *sqlbase.RowContainer {
+	return sqlbase.NewRowContainer(
+		n.p.session.TxnState.makeBoundAccount(), sqlbase.ColTypeInfoFromResCols(n.columns), 0,
+	)
+}
+
+func (n *recursiveCTENode) Start(ctx context.Context) error {
+	if !n.all {
+		n.seen = make(map[string]struct{})
+	}
+	n.nextRows = n.newRowContainer()
+	n.current = n.initial
+	return n.initial.Start(ctx)
+}
+
+func (n *recursiveCTENode) Next(ctx context.Context) (bool, error) {
+	for n.current != nil {
+		next, err := n.current.Next(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !next {
+			// The current term is exhausted; start the next iteration.
+			if err := n.nextIteration(ctx); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		row := n.current.Values()
+		if !n.all {
+			n.scratch, err = sqlbase.EncodeDatums(n.scratch[:0], row)
+			if err != nil {
+				return false, err
+			}
+			if _, ok := n.seen[string(n.scratch)]; ok {
+				continue
+			}
+			if err := n.seenAcc.Wtxn(n.p.session).Grow(ctx, int64(len(n.scratch))); err != nil {
+				return false, err
+			}
+			n.seen[string(n.scratch)] = struct{}{}
+		}
+		if _, err := n.nextRows.AddRow(ctx, row); err != nil {
+			return false, err
+		}
+		n.row = row
+		n.rowIdx++
+		return true, nil
+	}
+	return false, nil
+}
+
+// nextIteration closes the current term and, unless the last one produced no
+// rows, starts the next iteration of the recursive term.
+func (n *recursiveCTENode) nextIteration(ctx context.Context) error {
+	if n.current != n.initial {
+		n.current.Close(ctx)
+	}
+	n.current = nil
+	if n.nextRows.Len() == 0 {
+		return nil
+	}
+	rows := n.nextRows
+	n.nextRows = n.newRowContainer()
+	plan, err := n.genIteration(ctx, rows)
+	if err != nil {
+		return err
+	}
+	n.current = plan
+	return nil
+}
+
+func (n *recursiveCTENode) Columns() sqlbase.ResultColumns { return n.columns }
+func (n *recursiveCTENode) Values() parser.Datums          { return n.row }
+func (*recursiveCTENode) Ordering() orderingInfo           { return orderingInfo{} }
+
+func (n *recursiveCTENode) Spans(ctx context.Context) (_, _ roachpb.Spans, _ error) {
+	return n.initial.Spans(ctx)
+}
+
+func (n *recursiveCTENode) MarkDebug(mode explainMode) {
+	if mode != explainDebug {
+		panic(fmt.Sprintf("unknown debug mode %d", mode))
+	}
+	n.explain = mode
+}
+
+func (n *recursiveCTENode) DebugValues() debugValues {
+	if n.explain != explainDebug {
+		panic(fmt.Sprintf("node not in debug mode (mode %d)", n.explain))
+	}
+	return debugValues{
+		rowIdx: n.rowIdx - 1,
+		key:    fmt.Sprintf("%d", n.rowIdx-1),
+		value:  n.row.String(),
+		output: debugValueRow,
+	}
+}
+
+func (n *recursiveCTENode) Close(ctx context.Context) {
+	if n.current != nil && n.current != n.initial {
+		n.current.Close(ctx)
+	}
+	n.current = nil
+	n.initial.Close(ctx)
+	if n.nextRows != nil {
+		n.nextRows.Close(ctx)
+		n.nextRows = nil
+	}
+	n.seen = nil
+	n.seenAcc.Wtxn(n.p.session).Close(ctx)
+}
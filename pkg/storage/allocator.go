@@ -48,6 +48,12 @@ const (
 	// that store.
 	baseRebalanceThreshold = 0.05
 
+	// minQPSDifferenceForRebalance is the minimum difference between a store's
+	// queries-per-second and the mean for the store to be considered overfull
+	// or underfull in terms of load. It prevents lightly loaded clusters from
+	// moving replicas and leases around because of noise.
+	minQPSDifferenceForRebalance = 100
+
 	// priorities for various repair operations.
 	addMissingReplicaPriority  float64 = 10000
 	removeDeadReplicaPriority  float64 = 1000
@@ -83,6 +89,16 @@ var (
 		"set greater than 1.0 to rebalance leases toward load more aggressively, "+
 			"or between 0 and 1.0 to be more conservative about rebalancing leases",
 		1.0)
+
+	// QPSRebalanceThreshold is the fraction above or below the mean
+	// queries-per-second of the stores that makes a store overfull or
+	// underfull in terms of load, which the allocator corrects by moving
+	// replicas and leases.
+	QPSRebalanceThreshold = settings.RegisterNonNegativeFloatSetting(
+		"kv.allocator.qps_rebalance_threshold",
+		"minimum fraction away from the mean a store's QPS (queries per second) can be "+
+			"before it is considered overfull or underfull",
+		0.25)
 )
 
 // AllocatorAction enumerates the various replication adjustments that may be
@@ -295,9 +311,13 @@ func (a *Allocator) AllocateTarget(
 // set. It first attempts to randomly select a target from the set of stores
 // that have greater than the average number of replicas. Failing that, it
 // falls back to selecting a random target from any of the existing
-// replicas.
+// replicas. leaseStoreID is the store holding the lease of the range, or 0 if
+// it is unknown.
 func (a Allocator) RemoveTarget(
-	ctx context.Context, constraints config.Constraints, existing []roachpb.ReplicaDescriptor,
+	ctx context.Context,
+	constraints config.Constraints,
+	existing []roachpb.ReplicaDescriptor,
+	leaseStoreID roachpb.StoreID,
 ) (roachpb.ReplicaDescriptor, error) {
 	if len(existing) == 0 {
 		return roachpb.ReplicaDescriptor{}, errors.Errorf("must supply at least one replica to allocator.RemoveTarget()")
@@ -314,6 +334,7 @@ func (a Allocator) RemoveTarget(
 		sl,
 		constraints,
 		a.storePool.getLocalities(existing),
+		leaseStoreID,
 		a.storePool.deterministic,
 	)
 	if log.V(3) {
@@ -340,8 +361,8 @@ func (a Allocator) RemoveTarget(
 // cluster.
 //
 // The supplied parameters are the required attributes for the range, a list of
-// the existing replicas of the range, and the range ID of the replica being
-// allocated.
+// the existing replicas of the range, the store holding the lease of the range
+// (or 0 if it is unknown) and the range ID of the replica being allocated.
 //
 // The existing replicas modulo any store with dead replicas are candidates for
 // rebalancing. Note that rebalancing is accomplished by first adding a new
@@ -356,6 +377,7 @@ func (a Allocator) RebalanceTarget(
 	ctx context.Context,
 	constraints config.Constraints,
 	existing []roachpb.ReplicaDescriptor,
+	leaseStoreID roachpb.StoreID,
 	rangeID roachpb.RangeID,
 ) (*roachpb.StoreDescriptor, error) {
 	sl, _, _ := a.storePool.getStoreList(rangeID)
@@ -366,6 +388,7 @@ func (a Allocator) RebalanceTarget(
 		constraints,
 		existing,
 		a.storePool.getLocalities(existing),
+		leaseStoreID,
 		a.storePool.deterministic,
 	)
 
@@ -406,9 +429,12 @@ func (a Allocator) RebalanceTarget(
 // TransferLeaseTarget returns a suitable replica to transfer the range lease
 // to from the provided list. It excludes the current lease holder replica
 // unless asked to do otherwise by the checkTransferLeaseSource parameter.
+// When lease preferences are specified, the lease is only transferred to
+// replicas matching the first preference that any replica matches.
 func (a *Allocator) TransferLeaseTarget(
 	ctx context.Context,
 	constraints config.Constraints,
+	leasePreferences []config.LeasePreference,
 	existing []roachpb.ReplicaDescriptor,
 	leaseStoreID roachpb.StoreID,
 	rangeID roachpb.RangeID,
@@ -416,6 +442,22 @@ func (a *Allocator) TransferLeaseTarget(
 	checkTransferLeaseSource bool,
 	checkCandidateFullness bool,
 ) roachpb.ReplicaDescriptor {
+	preferred := a.preferredLeaseholders(leasePreferences, existing)
+	switch {
+	case len(preferred) == 0:
+	case !replicasContainStore(preferred, leaseStoreID):
+		// The current lease holder doesn't match the preferences, so the lease
+		// should move to one of the preferred replicas regardless of the balance
+		// between stores.
+		existing = preferred
+		checkTransferLeaseSource = false
+		checkCandidateFullness = false
+	case len(preferred) > 1 || checkTransferLeaseSource:
+		// Only move the lease between preferred replicas, unless it has to be
+		// moved away from the only one.
+		existing = preferred
+	}
+
 	sl, _, _ := a.storePool.getStoreList(rangeID)
 	sl = sl.filter(constraints)
 
@@ -447,6 +489,12 @@ func (a *Allocator) TransferLeaseTarget(
 		return roachpb.ReplicaDescriptor{}
 	}
 
+	// Move load away from the source store first if it is overfull in terms of
+	// queries-per-second.
+	if repl := a.qpsLeaseTransferTarget(ctx, sl, source, existing, stats); repl != (roachpb.ReplicaDescriptor{}) {
+		return repl
+	}
+
 	// Try to pick a replica to transfer the lease to while also determining
 	// whether we actually should be transferring the lease. The transfer
 	// decision is only needed if we've been asked to check the source.
@@ -494,15 +542,31 @@ func (a *Allocator) TransferLeaseTarget(
 
 // ShouldTransferLease returns true if the specified store is overfull in terms
 // of leases with respect to the other stores matching the specified
-// attributes.
+// attributes, or if it doesn't match the lease preferences while another
+// replica does.
 func (a *Allocator) ShouldTransferLease(
 	ctx context.Context,
 	constraints config.Constraints,
+	leasePreferences []config.LeasePreference,
 	existing []roachpb.ReplicaDescriptor,
 	leaseStoreID roachpb.StoreID,
 	rangeID roachpb.RangeID,
 	stats *replicaStats,
 ) bool {
+	if preferred := a.preferredLeaseholders(leasePreferences, existing); len(preferred) > 0 {
+		if !replicasContainStore(preferred, leaseStoreID) {
+			if log.V(3) {
+				log.Infof(ctx, "ShouldTransferLease decision (lease-holder=%d): true, not preferred", leaseStoreID)
+			}
+			return true
+		}
+		if len(preferred) == 1 {
+			// The lease holder is the only preferred replica.
+			return false
+		}
+		existing = preferred
+	}
+
 	source, ok := a.storePool.getStoreDescriptor(leaseStoreID)
 	if !ok {
 		return false
@@ -513,6 +577,13 @@ func (a *Allocator) ShouldTransferLease(
 		log.Infof(ctx, "ShouldTransferLease (lease-holder=%d):\n%s", leaseStoreID, sl)
 	}
 
+	if repl := a.qpsLeaseTransferTarget(ctx, sl, source, existing, stats); repl != (roachpb.ReplicaDescriptor{}) {
+		if log.V(3) {
+			log.Infof(ctx, "ShouldTransferLease decision (lease-holder=%d): true, qps-overfull", leaseStoreID)
+		}
+		return true
+	}
+
 	transferDec, _ := a.shouldTransferLeaseUsingStats(ctx, sl, source, existing, stats)
 	var result bool
 	switch transferDec {
@@ -559,6 +630,7 @@ func (a Allocator) shouldTransferLeaseUsingStats(
 	if len(requestCounts) == 0 {
 		return decideWithoutStats, roachpb.ReplicaDescriptor{}
 	}
+	rangeQPS, _ := stats.avgQPS()
 
 	replicaWeights := make(map[roachpb.NodeID]float64)
 	replicaLocalities := a.storePool.getLocalities(existing)
@@ -595,6 +667,12 @@ func (a Allocator) shouldTransferLeaseUsingStats(
 		if !ok {
 			continue
 		}
+		// Moving the lease toward the requests must not make the store
+		// overfull in terms of load, which would cause the lease to be
+		// transferred away again.
+		if leaseTransferMakesQPSOverfull(sl, storeDesc, rangeQPS) {
+			continue
+		}
 		addr, err := a.storePool.gossip.GetNodeIDAddress(repl.NodeID)
 		if err != nil {
 			log.Errorf(ctx, "missing address for node %d: %s", repl.NodeID, err)
@@ -693,6 +771,63 @@ func loadBasedLeaseRebalanceScore(
 	return totalScore
 }
 
+// qpsLeaseTransferTarget returns a replica to transfer the lease to in order
+// to move load away from the source store when it receives significantly more
+// requests than the mean, or an empty descriptor. The store receiving the
+// fewest requests is picked among those that would not become overfull
+// themselves, which would cause the lease to be transferred back.
+func (a Allocator) qpsLeaseTransferTarget(
+	ctx context.Context,
+	sl StoreList,
+	source roachpb.StoreDescriptor,
+	existing []roachpb.ReplicaDescriptor,
+	stats *replicaStats,
+) roachpb.ReplicaDescriptor {
+	if stats == nil || !qpsOverfull(sl, source) {
+		return roachpb.ReplicaDescriptor{}
+	}
+	// Transferring the lease of a range that receives no requests doesn't
+	// move any load.
+	rangeQPS, _ := stats.avgQPS()
+	if rangeQPS <= 0 {
+		return roachpb.ReplicaDescriptor{}
+	}
+
+	var bestRepl roachpb.ReplicaDescriptor
+	bestQPS := math.MaxFloat64
+	for _, repl := range existing {
+		if repl.StoreID == source.StoreID {
+			continue
+		}
+		storeDesc, ok := a.storePool.getStoreDescriptor(repl.StoreID)
+		if !ok {
+			continue
+		}
+		qps := storeDesc.Capacity.QueriesPerSecond
+		if qps >= sl.candidateQPS.mean || qps >= bestQPS ||
+			leaseTransferMakesQPSOverfull(sl, storeDesc, rangeQPS) {
+			continue
+		}
+		bestRepl, bestQPS = repl, qps
+	}
+	if log.V(1) && bestRepl != (roachpb.ReplicaDescriptor{}) {
+		log.Infof(ctx, "transferring lease away from qps-overfull s%d (qps=%.2f, mean=%.2f, range-qps=%.2f) to s%d (qps=%.2f)",
+			source.StoreID, source.Capacity.QueriesPerSecond, sl.candidateQPS.mean, rangeQPS,
+			bestRepl.StoreID, bestQPS)
+	}
+	return bestRepl
+}
+
+// leaseTransferMakesQPSOverfull returns whether transferring the lease of a
+// range receiving rangeQPS requests per second to the store would make it
+// overfull in terms of queries-per-second.
+func leaseTransferMakesQPSOverfull(
+	sl StoreList, store roachpb.StoreDescriptor, rangeQPS float64,
+) bool {
+	store.Capacity.QueriesPerSecond += rangeQPS
+	return qpsOverfull(sl, store)
+}
+
 func (a Allocator) shouldTransferLeaseWithoutStats(
 	ctx context.Context,
 	sl StoreList,
@@ -730,6 +865,41 @@ func (a Allocator) shouldTransferLeaseWithoutStats(
 	return false
 }
 
+// preferredLeaseholders returns the replicas on stores matching the first of
+// the lease preferences matched by any of the existing replicas, or nil if
+// none of them match any preference.
+func (a Allocator) preferredLeaseholders(
+	leasePreferences []config.LeasePreference, existing []roachpb.ReplicaDescriptor,
+) []roachpb.ReplicaDescriptor {
+	for _, preference := range leasePreferences {
+		constraints := config.Constraints{Constraints: preference.Constraints}
+		var preferred []roachpb.ReplicaDescriptor
+		for _, repl := range existing {
+			storeDesc, ok := a.storePool.getStoreDescriptor(repl.StoreID)
+			if !ok {
+				continue
+			}
+			if ok, _ := constraintCheck(storeDesc, constraints); ok {
+				preferred = append(preferred, repl)
+			}
+		}
+		if len(preferred) > 0 {
+			return preferred
+		}
+	}
+	return nil
+}
+
+// replicasContainStore returns whether one of the replicas is on the store.
+func replicasContainStore(replicas []roachpb.ReplicaDescriptor, storeID roachpb.StoreID) bool {
+	for _, repl := range replicas {
+		if repl.StoreID == storeID {
+			return true
+		}
+	}
+	return false
+}
+
 // computeQuorum computes the quorum value for the given number of nodes.
 func computeQuorum(nodes int) int {
 	return (nodes / 2) + 1
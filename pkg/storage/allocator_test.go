@@ -632,6 +632,7 @@ func TestAllocatorRebalance(t *testing.T) {
 			ctx,
 			config.Constraints{},
 			[]roachpb.ReplicaDescriptor{{StoreID: 3}},
+			0, /* leaseStoreID */
 			firstRange,
 		)
 		if err != nil {
@@ -655,7 +656,7 @@ func TestAllocatorRebalance(t *testing.T) {
 			t.Fatalf("%d: unable to get store %d descriptor", i, store.StoreID)
 		}
 		sl, _, _ := a.storePool.getStoreList(firstRange)
-		result := shouldRebalance(ctx, desc, sl)
+		result := shouldRebalance(ctx, desc, sl, false /* leaseholder */)
 		if expResult := (i >= 2); expResult != result {
 			t.Errorf("%d: expected rebalance %t; got %t", i, expResult, result)
 		}
@@ -727,7 +728,7 @@ func TestAllocatorRebalanceDeadNodes(t *testing.T) {
 	for _, c := range testCases {
 		t.Run("", func(t *testing.T) {
 			result, err := a.RebalanceTarget(
-				ctx, config.Constraints{}, c.existing, firstRange)
+				ctx, config.Constraints{}, c.existing, 0 /* leaseStoreID */, firstRange)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -858,7 +859,8 @@ func TestAllocatorRebalanceThrashing(t *testing.T) {
 				if !ok {
 					t.Fatalf("[store %d]: unable to get store %d descriptor", j, store.StoreID)
 				}
-				if a, e := shouldRebalance(context.Background(), desc, sl), tc.cluster[j].shouldRebalanceFrom; a != e {
+				should := shouldRebalance(context.Background(), desc, sl, false /* leaseholder */)
+				if a, e := should, tc.cluster[j].shouldRebalanceFrom; a != e {
 					t.Errorf("[store %d]: shouldRebalance %t != expected %t", store.StoreID, a, e)
 				}
 			}
@@ -908,6 +910,7 @@ func TestAllocatorRebalanceByCount(t *testing.T) {
 			ctx,
 			config.Constraints{},
 			[]roachpb.ReplicaDescriptor{{StoreID: stores[0].StoreID}},
+			0, /* leaseStoreID */
 			firstRange,
 		)
 		if err != nil {
@@ -925,7 +928,7 @@ func TestAllocatorRebalanceByCount(t *testing.T) {
 			t.Fatalf("%d: unable to get store %d descriptor", i, store.StoreID)
 		}
 		sl, _, _ := a.storePool.getStoreList(firstRange)
-		result := shouldRebalance(ctx, desc, sl)
+		result := shouldRebalance(ctx, desc, sl, false /* leaseholder */)
 		if expResult := (i < 3); expResult != result {
 			t.Errorf("%d: expected rebalance %t; got %t", i, expResult, result)
 		}
@@ -980,6 +983,7 @@ func TestAllocatorTransferLeaseTarget(t *testing.T) {
 			target := a.TransferLeaseTarget(
 				context.Background(),
 				config.Constraints{},
+				nil, /* leasePreferences */
 				c.existing,
 				c.leaseholder,
 				0,
@@ -1033,6 +1037,7 @@ func TestAllocatorTransferLeaseTargetMultiStore(t *testing.T) {
 			target := a.TransferLeaseTarget(
 				context.Background(),
 				config.Constraints{},
+				nil, /* leasePreferences */
 				existing,
 				c.leaseholder,
 				0,
@@ -1095,6 +1100,7 @@ func TestAllocatorShouldTransferLease(t *testing.T) {
 			result := a.ShouldTransferLease(
 				context.Background(),
 				config.Constraints{},
+				nil, /* leasePreferences */
 				c.existing,
 				c.leaseholder,
 				0,
@@ -1107,6 +1113,209 @@ func TestAllocatorShouldTransferLease(t *testing.T) {
 	}
 }
 
+// TestAllocatorLeasePreferences verifies that lease transfers honor the lease
+// preferences of the zone config.
+func TestAllocatorLeasePreferences(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper, g, _, a, _ := createTestAllocator( /* deterministic */ true)
+	defer stopper.Stop(context.Background())
+
+	// 4 stores with the same lease count, the first two in region "a" and the
+	// last two in region "b".
+	var stores []*roachpb.StoreDescriptor
+	for i, region := range []string{"a", "a", "b", "b"} {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i + 1),
+			Node: roachpb.NodeDescriptor{
+				NodeID: roachpb.NodeID(i + 1),
+				Locality: roachpb.Locality{
+					Tiers: []roachpb.Tier{{Key: "region", Value: region}},
+				},
+			},
+			Capacity: roachpb.StoreCapacity{LeaseCount: 10},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+
+	replicas := func(storeIDs ...roachpb.StoreID) []roachpb.ReplicaDescriptor {
+		var r []roachpb.ReplicaDescriptor
+		for _, storeID := range storeIDs {
+			r = append(r, roachpb.ReplicaDescriptor{
+				NodeID:  roachpb.NodeID(storeID),
+				StoreID: storeID,
+			})
+		}
+		return r
+	}
+	preferences := func(shorts ...string) []config.LeasePreference {
+		var prefs []config.LeasePreference
+		for _, short := range shorts {
+			var c config.Constraint
+			if err := c.FromString(short); err != nil {
+				t.Fatal(err)
+			}
+			prefs = append(prefs, config.LeasePreference{Constraints: []config.Constraint{c}})
+		}
+		return prefs
+	}
+
+	testCases := []struct {
+		preferences []config.LeasePreference
+		leaseholder roachpb.StoreID
+		existing    []roachpb.ReplicaDescriptor
+		// expectedShould is the result of ShouldTransferLease, expectedCheck the
+		// target of TransferLeaseTarget when checking the lease holder and
+		// expectedNoCheck the target when the lease has to be moved away.
+		expectedShould  bool
+		expectedCheck   roachpb.StoreID
+		expectedNoCheck roachpb.StoreID
+	}{
+		// No preferences.
+		{nil, 1, replicas(1, 3), false, 0, 3},
+		// The lease holder is not preferred.
+		{preferences("+region=b"), 1, replicas(1, 2, 3), true, 3, 3},
+		{preferences("-region=a"), 1, replicas(1, 2, 3), true, 3, 3},
+		// The lease holder is preferred, along with another replica.
+		{preferences("+region=b"), 3, replicas(1, 3, 4), false, 0, 4},
+		// The lease holder is the only preferred replica, so the lease only
+		// leaves it if it has to.
+		{preferences("+region=b"), 3, replicas(1, 3), false, 0, 1},
+		// Preferences that no replica matches are skipped.
+		{preferences("+region=c", "+region=a"), 3, replicas(1, 3), true, 1, 1},
+		{preferences("+region=c"), 1, replicas(1, 3), false, 0, 3},
+	}
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			should := a.ShouldTransferLease(
+				context.Background(),
+				config.Constraints{},
+				c.preferences,
+				c.existing,
+				c.leaseholder,
+				0,
+				nil, /* replicaStats */
+			)
+			if c.expectedShould != should {
+				t.Errorf("expected should transfer %v, but found %v", c.expectedShould, should)
+			}
+			for _, check := range []bool{true, false} {
+				target := a.TransferLeaseTarget(
+					context.Background(),
+					config.Constraints{},
+					c.preferences,
+					c.existing,
+					c.leaseholder,
+					0,
+					nil, /* replicaStats */
+					check,
+					false, /* checkCandidateFullness */
+				)
+				expected := c.expectedNoCheck
+				if check {
+					expected = c.expectedCheck
+				}
+				if expected != target.StoreID {
+					t.Errorf("check=%t: expected %d, but found %d", check, expected, target.StoreID)
+				}
+			}
+		})
+	}
+}
+
+// TestAllocatorTransferLeaseTargetQPS verifies that leases are moved from
+// stores receiving more requests than the mean to stores receiving fewer.
+func TestAllocatorTransferLeaseTargetQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	stopper, g, _, a, _ := createTestAllocator( /* deterministic */ true)
+	defer stopper.Stop(context.Background())
+
+	// 4 stores with the same lease count where store 1 receives significantly
+	// more requests than the mean of 387.5 QPS, and stores 2 and 4
+	// significantly fewer.
+	var stores []*roachpb.StoreDescriptor
+	for i, qps := range []float64{1000, 100, 300, 150} {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID:  roachpb.StoreID(i + 1),
+			Node:     roachpb.NodeDescriptor{NodeID: roachpb.NodeID(i + 1)},
+			Capacity: roachpb.StoreCapacity{LeaseCount: 20, QueriesPerSecond: qps},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+
+	// rangeStats returns stats for a range receiving about qps requests per
+	// second.
+	rangeStats := func(qps int) *replicaStats {
+		manual := hlc.NewManualClock(123)
+		rs := newReplicaStats(hlc.NewClock(manual.UnixNano, time.Nanosecond), func(roachpb.NodeID) string {
+			return ""
+		})
+		for i := 0; i < 10*qps; i++ {
+			rs.record(1)
+		}
+		manual.Increment(int64(10 * time.Second))
+		return rs
+	}
+
+	existing := func(storeIDs ...roachpb.StoreID) []roachpb.ReplicaDescriptor {
+		var replicas []roachpb.ReplicaDescriptor
+		for _, storeID := range storeIDs {
+			replicas = append(replicas, roachpb.ReplicaDescriptor{NodeID: roachpb.NodeID(storeID), StoreID: storeID})
+		}
+		return replicas
+	}
+
+	testCases := []struct {
+		existing    []roachpb.ReplicaDescriptor
+		leaseholder roachpb.StoreID
+		stats       *replicaStats
+		expected    roachpb.StoreID
+	}{
+		// The lease moves to the store receiving the fewest requests.
+		{existing(1, 2, 3), 1, rangeStats(10), 2},
+		{existing(1, 3, 4), 1, rangeStats(10), 4},
+		// Any store below the QPS mean can receive the lease.
+		{existing(1, 3), 1, rangeStats(10), 3},
+		// Store 3 isn't overfull.
+		{existing(1, 2, 3), 3, rangeStats(10), 0},
+		// Moving a range without requests doesn't move load.
+		{existing(1, 2, 3), 1, rangeStats(0), 0},
+		// Moving a range this hot would make the target overfull.
+		{existing(1, 2, 3), 1, rangeStats(400), 0},
+	}
+	for i, c := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			target := a.TransferLeaseTarget(
+				context.Background(),
+				config.Constraints{},
+				nil, /* leasePreferences */
+				c.existing,
+				c.leaseholder,
+				0,
+				c.stats,
+				true, /* checkTransferLeaseSource */
+				true, /* checkCandidateFullness */
+			)
+			if c.expected != target.StoreID {
+				t.Fatalf("expected %d, but found %d", c.expected, target.StoreID)
+			}
+			should := a.ShouldTransferLease(
+				context.Background(),
+				config.Constraints{},
+				nil, /* leasePreferences */
+				c.existing,
+				c.leaseholder,
+				0,
+				c.stats,
+			)
+			if expected := c.expected != 0; expected != should {
+				t.Fatalf("expected ShouldTransferLease to return %t, but found %t", expected, should)
+			}
+		})
+	}
+}
+
 // Test out the load-based lease transfer algorithm against a variety of
 // request distributions and inter-node latencies.
 func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
@@ -1261,6 +1470,7 @@ func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
 			target := a.TransferLeaseTarget(
 				context.Background(),
 				config.Constraints{},
+				nil, /* leasePreferences */
 				existing,
 				c.leaseholder,
 				0,
@@ -1275,6 +1485,91 @@ func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
 	}
 }
 
+// TestAllocatorTransferLeaseTargetLoadBasedQPS verifies that leases aren't
+// moved toward the requests of a range onto stores that would become overfull
+// in terms of queries-per-second.
+func TestAllocatorTransferLeaseTargetLoadBasedQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	stopper, g, _, storePool, _ := createTestStorePool(
+		TestTimeUntilStoreDeadOff, true /* deterministic */, nodeStatusLive)
+	defer stopper.Stop(context.Background())
+
+	makeStores := func(qps ...float64) []*roachpb.StoreDescriptor {
+		var stores []*roachpb.StoreDescriptor
+		for i := 1; i <= len(qps); i++ {
+			stores = append(stores, &roachpb.StoreDescriptor{
+				StoreID: roachpb.StoreID(i),
+				Node: roachpb.NodeDescriptor{
+					NodeID:  roachpb.NodeID(i),
+					Address: util.MakeUnresolvedAddr("tcp", strconv.Itoa(i)),
+					Locality: roachpb.Locality{
+						Tiers: []roachpb.Tier{
+							{Key: "l", Value: strconv.Itoa(i)},
+						},
+					},
+				},
+				Capacity: roachpb.StoreCapacity{LeaseCount: 10, QueriesPerSecond: qps[i-1]},
+			})
+		}
+		return stores
+	}
+	stores := makeStores(0, 0, 0)
+	for _, store := range stores {
+		if err := g.SetNodeDescriptor(&store.Node); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+
+	manual := hlc.NewManualClock(123)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+	// All the requests come from the locality of store 3.
+	stats := newReplicaStats(clock, func(nodeID roachpb.NodeID) string {
+		return "l=" + strconv.Itoa(int(nodeID))
+	})
+	for i := 0; i < 100; i++ {
+		stats.record(3)
+	}
+	manual.Increment(int64(MinLeaseTransferStatsDuration))
+
+	a := MakeAllocator(storePool, func(string) (time.Duration, bool) {
+		return 50 * time.Millisecond, true
+	})
+	existing := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1},
+		{NodeID: 2, StoreID: 2},
+		{NodeID: 3, StoreID: 3},
+	}
+
+	testCases := []struct {
+		stores   []*roachpb.StoreDescriptor
+		expected roachpb.StoreID
+	}{
+		// The lease follows the requests.
+		{stores, 3},
+		// Unless store 3 is overfull in terms of queries-per-second.
+		{makeStores(200, 200, 400), 0},
+	}
+	for i, c := range testCases {
+		sg.GossipStores(c.stores, t)
+		target := a.TransferLeaseTarget(
+			context.Background(),
+			config.Constraints{},
+			nil, /* leasePreferences */
+			existing,
+			1, /* leaseStoreID */
+			0,
+			stats,
+			true, /* checkTransferLeaseSource */
+			true, /* checkCandidateFullness */
+		)
+		if c.expected != target.StoreID {
+			t.Errorf("%d: expected %d, got %d", i, c.expected, target.StoreID)
+		}
+	}
+}
+
 func TestLoadBasedLeaseRebalanceScore(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	remoteStore := roachpb.StoreDescriptor{
@@ -1450,7 +1745,7 @@ func TestAllocatorRemoveTarget(t *testing.T) {
 
 	// Repeat this test 10 times, it should always be either store 2 or 3.
 	for i := 0; i < 10; i++ {
-		targetRepl, err := a.RemoveTarget(ctx, config.Constraints{}, replicas)
+		targetRepl, err := a.RemoveTarget(ctx, config.Constraints{}, replicas, 0 /* leaseStoreID */)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -2150,6 +2445,7 @@ func TestAllocatorRebalanceAway(t *testing.T) {
 				ctx,
 				constraints,
 				existingReplicas,
+				0, /* leaseStoreID */
 				firstRange,
 			)
 			if err != nil {
@@ -2265,6 +2561,7 @@ func Example_rebalancing() {
 				context.Background(),
 				config.Constraints{},
 				[]roachpb.ReplicaDescriptor{{NodeID: ts.Node.NodeID, StoreID: ts.StoreID}},
+				0, /* leaseStoreID */
 				firstRange,
 			)
 			if err != nil {
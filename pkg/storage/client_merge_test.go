@@ -24,17 +24,23 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/internal/client"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/engine"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 )
@@ -435,6 +441,155 @@ func TestStoreRangeMergeStats(t *testing.T) {
 	}
 }
 
+// TestMergeQueue verifies that the merge queue merges adjacent ranges whose
+// combined size is below the minimum range size of their zone.
+func TestMergeQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer settings.TestingSetBool(&storage.MergeQueueEnabled, true)()
+	defer settings.TestingSetBool(&storage.SplitByLoadEnabled, false)()
+	storeCfg := storage.TestStoreConfig(nil)
+	storeCfg.TestingKnobs.DisableSplitQueue = true
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	store := createTestStoreWithConfig(t, stopper, storeCfg)
+	config.TestingSetupZoneConfigHook(stopper)
+
+	const minBytes = 1 << 10
+	descID := uint32(keys.MaxReservedDescID + 1)
+	config.TestingSetZoneConfig(descID, config.ZoneConfig{RangeMinBytes: minBytes, RangeMaxBytes: 1 << 20})
+
+	// Trigger gossip callback.
+	if err := store.Gossip().AddInfoProto(gossip.KeySystemConfig, &config.SystemConfig{}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	// rowKey returns the key of the row of the table with the given primary
+	// key, and familyKey the key of its only column family.
+	rowKey := func(i int) roachpb.Key {
+		indexPrefix := encoding.EncodeUvarintAscending(keys.MakeTablePrefix(descID), 1)
+		return encoding.EncodeUvarintAscending(indexPrefix, uint64(i))
+	}
+	familyKey := func(i int) roachpb.Key {
+		return keys.MakeFamilyKey(rowKey(i), 0)
+	}
+	for _, i := range []int{1, 2, 3} {
+		if err := store.DB().AdminSplit(ctx, familyKey(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The last range is too big to be merged into the one before it.
+	if err := store.DB().Put(ctx, familyKey(4), make([]byte, 2*minBytes)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := roachpb.RSpan{Key: roachpb.RKey(rowKey(1)), EndKey: roachpb.RKey(rowKey(3))}
+	testutils.SucceedsSoon(t, func() error {
+		store.ForceMergeScanAndProcess()
+		if actual := store.LookupReplica(expected.Key, nil).Desc().RSpan(); !actual.Equal(expected) {
+			return errors.Errorf("expected a range spanning %s, got %s", expected, actual)
+		}
+		return nil
+	})
+	if repl := store.LookupReplica(roachpb.RKey(rowKey(3)), nil); !repl.Desc().EndKey.Equal(roachpb.RKeyMax) {
+		t.Fatalf("expected %s not to be merged", repl)
+	}
+}
+
+// TestMergeQueueLoadBasedSplit verifies that the merge queue doesn't merge
+// back the ranges created by a load-based split while their combined load is
+// above the load-based splitting threshold.
+func TestMergeQueueLoadBasedSplit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer settings.TestingSetBool(&storage.MergeQueueEnabled, true)()
+	// The load of the range is above the threshold, but the load of each half
+	// of the range after the split is below it.
+	const qps = 8
+	defer settings.TestingSetInt(&storage.SplitByLoadQPSThreshold, 6)()
+	manual := hlc.NewManualClock(123)
+	storeCfg := storage.TestStoreConfig(hlc.NewClock(manual.UnixNano, time.Nanosecond))
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	store := createTestStoreWithConfig(t, stopper, storeCfg)
+	config.TestingSetupZoneConfigHook(stopper)
+
+	const minBytes = 1 << 10
+	descID := uint32(keys.MaxReservedDescID + 1)
+	config.TestingSetZoneConfig(descID, config.ZoneConfig{RangeMinBytes: minBytes, RangeMaxBytes: 1 << 20})
+
+	// Trigger gossip callback.
+	if err := store.Gossip().AddInfoProto(gossip.KeySystemConfig, &config.SystemConfig{}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	// rowKey returns the key of the row of the table with the given primary
+	// key, and familyKey the key of its only column family.
+	rowKey := func(i int) roachpb.Key {
+		indexPrefix := encoding.EncodeUvarintAscending(keys.MakeTablePrefix(descID), 1)
+		return encoding.EncodeUvarintAscending(indexPrefix, uint64(i))
+	}
+	familyKey := func(i int) roachpb.Key {
+		return keys.MakeFamilyKey(rowKey(i), 0)
+	}
+	for _, i := range []int{0, 1000} {
+		if err := store.DB().AdminSplit(ctx, familyKey(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The last range is too big to be merged into the one before it.
+	if err := store.DB().Put(ctx, familyKey(1000), make([]byte, 2*minBytes)); err != nil {
+		t.Fatal(err)
+	}
+
+	// loadSecond sends a second of load, spread uniformly over the rows in
+	// [0, 1000).
+	rng, _ := randutil.NewPseudoRand()
+	loadSecond := func() {
+		for i := 0; i < qps; i++ {
+			if _, err := store.DB().Get(ctx, familyKey(rng.Intn(1000))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		manual.Increment(time.Second.Nanoseconds())
+	}
+	endKey := func() roachpb.RKey {
+		return store.LookupReplica(roachpb.RKey(rowKey(0)), nil).Desc().EndKey
+	}
+
+	for sec := 0; endKey().Equal(roachpb.RKey(rowKey(1000))); sec++ {
+		if sec > 120 {
+			t.Fatal("expected the range to be split based on its load")
+		}
+		loadSecond()
+		store.ForceSplitScanAndProcess()
+	}
+	splitKey := endKey()
+
+	// Both halves are small enough to be merged, but they are kept apart, first
+	// because their load was only measured since the split, then because their
+	// combined load is above the threshold.
+	for sec := 0; sec <= 6*60; sec++ {
+		loadSecond()
+		if sec%30 == 0 {
+			store.ForceMergeScanAndProcess()
+			if actual := endKey(); !actual.Equal(splitKey) {
+				t.Fatalf("after %ds: expected the range to end at %s, got %s", sec, splitKey, actual)
+			}
+		}
+	}
+
+	// Without load-based splitting, the ranges are merged.
+	defer settings.TestingSetBool(&storage.SplitByLoadEnabled, false)()
+	testutils.SucceedsSoon(t, func() error {
+		store.ForceMergeScanAndProcess()
+		if actual := endKey(); !actual.Equal(roachpb.RKey(rowKey(1000))) {
+			return errors.Errorf("expected the range to end at %s, got %s", rowKey(1000), actual)
+		}
+		return nil
+	})
+}
+
 func BenchmarkStoreRangeMerge(b *testing.B) {
 	defer tracing.Disable()()
 	storeCfg := storage.TestStoreConfig(nil)
@@ -0,0 +1,290 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+const (
+	// loadSplitCheckInterval is how often a replica checks whether its QPS is
+	// above the load-based splitting threshold.
+	loadSplitCheckInterval = time.Second
+	// loadSplitMinDuration is how long a replica must stay above the QPS
+	// threshold before a split key is suggested for it.
+	loadSplitMinDuration = 10 * time.Second
+
+	// splitKeySampleSize is the number of request start keys sampled as split
+	// key candidates.
+	splitKeySampleSize = 20
+	// splitKeyMinCounter is the number of requests that must have been compared
+	// to a candidate split key for it to be considered.
+	splitKeyMinCounter = 100
+	// splitKeyContainedThreshold is the maximum fraction of the requests
+	// compared to a candidate split key that may span it.
+	splitKeyContainedThreshold = 0.5
+)
+
+// SplitByLoadEnabled controls whether ranges are split based on their load.
+var SplitByLoadEnabled = settings.RegisterBoolSetting(
+	"kv.range_split.by_load_enabled",
+	"allow automatic splits of ranges based on where their load is concentrated",
+	true)
+
+// SplitByLoadQPSThreshold is the number of queries per second above which a
+// range is split based on its load.
+var SplitByLoadQPSThreshold = settings.RegisterIntSetting(
+	"kv.range_split.load_qps_threshold",
+	"the QPS over which a range becomes a candidate for load-based splitting",
+	250)
+
+// splitKeySample is a candidate split key along with the number of requests
+// seen since it was sampled that fell to its left, to its right, or spanned
+// it.
+type splitKeySample struct {
+	key                    roachpb.RKey
+	left, right, contained int
+}
+
+// splitFinder looks for a key that splits the load of a range in two halves.
+// It keeps a reservoir sample of the start keys of the requests to the range
+// as candidate split keys, and counts how each following request falls
+// relative to each candidate.
+type splitFinder struct {
+	startTime time.Time
+	count     int
+	samples   [splitKeySampleSize]splitKeySample
+}
+
+func newSplitFinder(startTime time.Time) *splitFinder {
+	return &splitFinder{startTime: startTime}
+}
+
+// ready returns whether the finder has collected data for long enough for its
+// split key to be used.
+func (f *splitFinder) ready(now time.Time) bool {
+	return now.Sub(f.startTime) >= loadSplitMinDuration
+}
+
+// record records a request for the given span. intn is used to pick the
+// sample replaced by the start key of the span, as in rand.Intn.
+func (f *splitFinder) record(span roachpb.RSpan, intn func(n int) int) {
+	n := f.count
+	f.count++
+
+	for i := 0; i < len(f.samples) && i < n; i++ {
+		s := &f.samples[i]
+		if !s.key.Less(span.EndKey) {
+			s.left++
+		} else if !span.Key.Less(s.key) {
+			s.right++
+		} else {
+			s.contained++
+		}
+	}
+	// Reservoir sampling: once all samples are taken, the key replaces one of
+	// them with probability len(f.samples)/f.count.
+	idx := n
+	if idx >= len(f.samples) {
+		idx = intn(f.count)
+	}
+	if idx < len(f.samples) {
+		f.samples[idx] = splitKeySample{key: span.Key}
+	}
+}
+
+// key returns the candidate split key that best balances the requests between
+// both sides of the split, or nil if there is none. Candidates spanned by too
+// many requests or which send all requests to one side are ignored.
+func (f *splitFinder) key() roachpb.RKey {
+	var bestKey roachpb.RKey
+	bestBalance := math.Inf(1)
+	for i := 0; i < len(f.samples) && i < f.count; i++ {
+		s := f.samples[i]
+		total := s.left + s.right + s.contained
+		if total < splitKeyMinCounter || s.left == 0 || s.right == 0 {
+			continue
+		}
+		if float64(s.contained)/float64(total) > splitKeyContainedThreshold {
+			continue
+		}
+		if balance := math.Abs(float64(s.left-s.right)) / float64(s.left+s.right); balance < bestBalance {
+			bestKey, bestBalance = s.key, balance
+		}
+	}
+	return bestKey
+}
+
+// loadSplitter tracks whether a replica has stayed above the QPS threshold of
+// load-based splitting and, while it has, looks for a key to split it at.
+type loadSplitter struct {
+	// lastCheckNanos is the time of the last check of the QPS of the replica,
+	// in nanoseconds since the epoch. It is accessed atomically so that
+	// requests to replicas below the threshold don't take the mutex.
+	lastCheckNanos int64
+	// sampling is 1 while mu.finder is not nil. It is accessed atomically and
+	// only written with the mutex held.
+	sampling int32
+
+	mu struct {
+		syncutil.Mutex
+		// finder is nil while the replica is below the QPS threshold.
+		finder *splitFinder
+	}
+}
+
+// record records a request at the given time. qpsFn returns the QPS of the
+// replica and spanFn the span of the request; they are only called when
+// needed. record returns true, at most once per loadSplitCheckInterval, when
+// a split key is available.
+func (ls *loadSplitter) record(
+	now time.Time, qpsFn func() float64, spanFn func() (roachpb.RSpan, bool),
+) bool {
+	var check bool
+	lastCheckNanos := atomic.LoadInt64(&ls.lastCheckNanos)
+	if now.UnixNano()-lastCheckNanos >= int64(loadSplitCheckInterval) {
+		// Only one of the concurrent requests performs the check.
+		check = atomic.CompareAndSwapInt64(&ls.lastCheckNanos, lastCheckNanos, now.UnixNano())
+	}
+	if !check && atomic.LoadInt32(&ls.sampling) == 0 {
+		return false
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	var shouldSplit bool
+	if check {
+		if SplitByLoadEnabled.Get() && qpsFn() >= float64(SplitByLoadQPSThreshold.Get()) {
+			if ls.mu.finder == nil {
+				ls.mu.finder = newSplitFinder(now)
+				atomic.StoreInt32(&ls.sampling, 1)
+			} else if ls.mu.finder.ready(now) {
+				shouldSplit = ls.mu.finder.key() != nil
+			}
+		} else {
+			ls.mu.finder = nil
+			atomic.StoreInt32(&ls.sampling, 0)
+		}
+	}
+	if ls.mu.finder != nil {
+		if span, ok := spanFn(); ok {
+			ls.mu.finder.record(span, rand.Intn)
+		}
+	}
+	return shouldSplit
+}
+
+// splitKey returns the key the replica should be split at to balance its
+// load, or nil if there is none.
+func (ls *loadSplitter) splitKey(now time.Time) roachpb.RKey {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.mu.finder == nil || !ls.mu.finder.ready(now) {
+		return nil
+	}
+	return ls.mu.finder.key()
+}
+
+// reset discards the data collected by the loadSplitter.
+func (ls *loadSplitter) reset() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	atomic.StoreInt64(&ls.lastCheckNanos, 0)
+	atomic.StoreInt32(&ls.sampling, 0)
+	ls.mu.finder = nil
+}
+
+// loadSplitSpan returns the span of the batch for load-based splitting. Ranges
+// are only split between the rows of SQL tables, so the start key of the span,
+// which becomes a candidate split key, is moved back to the start of its row:
+// the key of a point request is the key of a column family, which is truncated
+// to its row, while a scan already starts at the boundary of a row or index.
+func loadSplitSpan(ba *roachpb.BatchRequest) (roachpb.RSpan, bool) {
+	span, err := keys.Range(*ba)
+	if err != nil {
+		return roachpb.RSpan{}, false
+	}
+	var start roachpb.Key
+	var isRange bool
+	for _, union := range ba.Requests {
+		req := union.GetInner()
+		if key := req.Header().Key; start == nil || key.Compare(start) < 0 {
+			start, isRange = key, roachpb.IsRange(req)
+		}
+	}
+	if !isRange {
+		if start, err = keys.EnsureSafeSplitKey(start); err != nil {
+			return roachpb.RSpan{}, false
+		}
+	}
+	// Range-local keys are not split keys.
+	if key, err := keys.Addr(start); err != nil || !key.Equal(start) {
+		return roachpb.RSpan{}, false
+	}
+	span.Key = roachpb.RKey(start)
+	return span, true
+}
+
+// recordLoadForSplit records a batch for load-based splitting, and queues the
+// replica for a split once a key balancing its load was found. It must only
+// be called for batches served by the leaseholder.
+func (r *Replica) recordLoadForSplit(ba *roachpb.BatchRequest) {
+	if r.stats == nil {
+		return
+	}
+	qpsFn := func() float64 {
+		qps, _ := r.stats.avgQPS()
+		return qps
+	}
+	spanFn := func() (roachpb.RSpan, bool) {
+		return loadSplitSpan(ba)
+	}
+	if r.loadSplitter.record(r.store.Clock().PhysicalTime(), qpsFn, spanFn) {
+		r.store.splitQueue.MaybeAdd(r, r.store.Clock().Now())
+	}
+}
+
+// loadSplitKey returns the key the replica should be split at to balance its
+// load, or nil if there is none. The key is passed to AdminSplit, which
+// truncates the key of a column family to its row (see EnsureSafeSplitKey):
+// as the sampled keys are at the start of rows already, a key in a table is
+// returned as the key of the first column family of its row.
+func (r *Replica) loadSplitKey(now time.Time) roachpb.Key {
+	splitKey := r.loadSplitter.splitKey(now)
+	if splitKey == nil {
+		return nil
+	}
+	// The requests may have been sampled before the bounds of the range
+	// changed.
+	desc := r.Desc()
+	if !desc.StartKey.Less(splitKey) || !splitKey.Less(desc.EndKey) {
+		return nil
+	}
+	key := splitKey.AsRawKey()
+	if encoding.PeekType(key) == encoding.Int {
+		key = keys.MakeFamilyKey(append(roachpb.Key(nil), key...), 0)
+	}
+	return key
+}
@@ -0,0 +1,199 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+func testSplitKey(i int) roachpb.RKey {
+	return roachpb.RKey(fmt.Sprintf("k%04d", i))
+}
+
+func TestSplitFinder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng := rand.New(rand.NewSource(1))
+	pointSpan := func(i int) roachpb.RSpan {
+		return roachpb.RSpan{Key: testSplitKey(i), EndKey: testSplitKey(i).Next()}
+	}
+
+	testCases := []struct {
+		name string
+		span func() roachpb.RSpan
+		// The split key is expected in [minKey, maxKey), or to be nil if both
+		// are zero.
+		minKey, maxKey int
+	}{
+		{"uniform", func() roachpb.RSpan {
+			return pointSpan(rng.Intn(1000))
+		}, 400, 600},
+		{"skewed", func() roachpb.RSpan {
+			// Three quarters of the requests are for keys below 100.
+			if rng.Intn(4) > 0 {
+				return pointSpan(rng.Intn(100))
+			}
+			return pointSpan(100 + rng.Intn(900))
+		}, 40, 100},
+		{"single key", func() roachpb.RSpan {
+			return pointSpan(7)
+		}, 0, 0},
+		{"full scans", func() roachpb.RSpan {
+			return roachpb.RSpan{Key: testSplitKey(rng.Intn(10)), EndKey: testSplitKey(1000)}
+		}, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newSplitFinder(time.Time{})
+			for i := 0; i < 10000; i++ {
+				f.record(tc.span(), rng.Intn)
+			}
+			key := f.key()
+			if tc.minKey == 0 && tc.maxKey == 0 {
+				if key != nil {
+					t.Fatalf("expected no split key, got %s", key)
+				}
+				return
+			}
+			if key.Less(testSplitKey(tc.minKey)) || !key.Less(testSplitKey(tc.maxKey)) {
+				t.Fatalf("expected a split key in [%s, %s), got %s",
+					testSplitKey(tc.minKey), testSplitKey(tc.maxKey), key)
+			}
+		})
+	}
+}
+
+func TestLoadSplitter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer settings.TestingSetInt(&SplitByLoadQPSThreshold, 100)()
+
+	var ls loadSplitter
+	rng := rand.New(rand.NewSource(1))
+	qps := 10.0
+	qpsFn := func() float64 { return qps }
+	spanFn := func() (roachpb.RSpan, bool) {
+		key := testSplitKey(rng.Intn(1000))
+		return roachpb.RSpan{Key: key, EndKey: key.Next()}, true
+	}
+
+	// Records 1000 requests over a second, starting at the given time, and
+	// returns whether the loadSplitter requested a split.
+	start := time.Unix(0, 0)
+	recordSecond := func(sec int) bool {
+		var shouldSplit bool
+		for i := 0; i < 1000; i++ {
+			now := start.Add(time.Duration(sec)*time.Second + time.Duration(i)*time.Millisecond)
+			if ls.record(now, qpsFn, spanFn) {
+				shouldSplit = true
+			}
+		}
+		return shouldSplit
+	}
+
+	// Below the threshold, no requests are sampled.
+	for sec := 0; sec < 20; sec++ {
+		if recordSecond(sec) {
+			t.Fatalf("%d: unexpected split below the QPS threshold", sec)
+		}
+	}
+	if key := ls.splitKey(start.Add(20 * time.Second)); key != nil {
+		t.Fatalf("unexpected split key %s below the QPS threshold", key)
+	}
+
+	// Above the threshold, a split key is suggested after loadSplitMinDuration.
+	qps = 1000
+	minSecs := int(loadSplitMinDuration / time.Second)
+	for sec := 20; sec < 20+minSecs; sec++ {
+		if recordSecond(sec) {
+			t.Fatalf("%d: unexpected split before %s", sec, loadSplitMinDuration)
+		}
+	}
+	if !recordSecond(20 + minSecs) {
+		t.Fatalf("expected a split after %s above the QPS threshold", loadSplitMinDuration)
+	}
+	if key := ls.splitKey(start.Add(time.Duration(21+minSecs) * time.Second)); key == nil {
+		t.Fatal("expected a split key")
+	}
+
+	// Dropping below the threshold discards the collected data.
+	qps = 10
+	recordSecond(21 + minSecs)
+	if key := ls.splitKey(start.Add(time.Duration(22+minSecs) * time.Second)); key != nil {
+		t.Fatalf("unexpected split key %s after dropping below the QPS threshold", key)
+	}
+
+	// So does disabling load-based splitting.
+	qps = 1000
+	for sec := 22 + minSecs; sec < 23+2*minSecs; sec++ {
+		recordSecond(sec)
+	}
+	if key := ls.splitKey(start.Add(time.Duration(23+2*minSecs) * time.Second)); key == nil {
+		t.Fatal("expected a split key")
+	}
+	func() {
+		defer settings.TestingSetBool(&SplitByLoadEnabled, false)()
+		if recordSecond(23 + 2*minSecs) {
+			t.Fatal("unexpected split while load-based splitting is disabled")
+		}
+	}()
+	if key := ls.splitKey(start.Add(time.Duration(24+2*minSecs) * time.Second)); key != nil {
+		t.Fatalf("unexpected split key %s after disabling load-based splitting", key)
+	}
+}
+
+func TestLoadSplitSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rowKey := func(i int) roachpb.Key {
+		indexPrefix := encoding.EncodeUvarintAscending(keys.MakeTablePrefix(keys.MaxReservedDescID+1), 1)
+		return encoding.EncodeUvarintAscending(indexPrefix, uint64(i))
+	}
+	row := rowKey(7)
+	family := keys.MakeFamilyKey(rowKey(7), 0)
+	end := keys.MakeFamilyKey(rowKey(8), 0)
+
+	testCases := []struct {
+		req      roachpb.Request
+		expected roachpb.Key
+	}{
+		// The key of a column family samples the row it belongs to.
+		{&roachpb.GetRequest{Span: roachpb.Span{Key: family}}, row},
+		// The start key of a scan is a row boundary already.
+		{&roachpb.ScanRequest{Span: roachpb.Span{Key: family, EndKey: end}}, family},
+		{&roachpb.GetRequest{Span: roachpb.Span{Key: roachpb.Key("a")}}, roachpb.Key("a")},
+		// Range-local keys are not sampled.
+		{&roachpb.GetRequest{Span: roachpb.Span{Key: keys.RangeDescriptorKey(roachpb.RKey("a"))}}, nil},
+	}
+	for i, c := range testCases {
+		var ba roachpb.BatchRequest
+		ba.Add(c.req)
+		span, ok := loadSplitSpan(&ba)
+		if ok != (c.expected != nil) {
+			t.Errorf("%d: expected ok=%t, got %t", i, c.expected != nil, ok)
+		} else if ok && !span.Key.Equal(roachpb.RKey(c.expected)) {
+			t.Errorf("%d: expected span starting at %s, got %s", i, c.expected, span)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/internal/client"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+const (
+	// mergeQueueTimerDuration is the duration between merges of queued ranges.
+	mergeQueueTimerDuration = 0 // zero duration to process merges greedily.
+
+	// mergeQueueMinQPSDuration is how long the QPS of two ranges must have been
+	// measured for before they are merged while load-based splitting is
+	// enabled. The QPS of both sides of a split is measured from the split.
+	mergeQueueMinQPSDuration = 5 * time.Minute
+
+	// mergeQueueScanBatchSize is the number of keys scanned at a time to
+	// estimate the size of a right-hand range that has no replica on this
+	// store.
+	mergeQueueScanBatchSize = 1000
+)
+
+// MergeQueueEnabled controls whether the merge queue merges small adjacent
+// ranges.
+var MergeQueueEnabled = settings.RegisterBoolSetting(
+	"kv.range_merge.queue_enabled",
+	"set to enable automatic merging of adjacent ranges smaller than the zone's minimum range size",
+	false)
+
+var (
+	metaMergeQueueMergeCount = metric.Metadata{
+		Name: "queue.merge.merges",
+		Help: "Number of range merges attempted by the merge queue"}
+	metaMergeQueueRelocateCount = metric.Metadata{
+		Name: "queue.merge.relocaterange",
+		Help: "Number of right-hand ranges relocated by the merge queue to collocate them with their left-hand neighbors"}
+	metaMergeQueueTransferLeaseCount = metric.Metadata{
+		Name: "queue.merge.transferlease",
+		Help: "Number of range lease transfers attempted by the merge queue"}
+)
+
+// MergeQueueMetrics is the set of metrics for the merge queue.
+type MergeQueueMetrics struct {
+	MergeCount         *metric.Counter
+	RelocateCount      *metric.Counter
+	TransferLeaseCount *metric.Counter
+}
+
+func makeMergeQueueMetrics() MergeQueueMetrics {
+	return MergeQueueMetrics{
+		MergeCount:         metric.NewCounter(metaMergeQueueMergeCount),
+		RelocateCount:      metric.NewCounter(metaMergeQueueRelocateCount),
+		TransferLeaseCount: metric.NewCounter(metaMergeQueueTransferLeaseCount),
+	}
+}
+
+// mergeQueue manages a queue of ranges slated to be merged with the range
+// that follows them because both are small. A range is processed by the
+// store holding its lease. The right-hand range is first relocated to the
+// stores of the left-hand one and its lease is moved to this store, since
+// AdminMerge requires collocated replicas and leases.
+type mergeQueue struct {
+	*baseQueue
+	metrics MergeQueueMetrics
+	db      *client.DB
+}
+
+// newMergeQueue returns a new instance of mergeQueue.
+func newMergeQueue(store *Store, db *client.DB, gossip *gossip.Gossip) *mergeQueue {
+	mq := &mergeQueue{
+		metrics: makeMergeQueueMetrics(),
+		db:      db,
+	}
+	store.metrics.registry.AddMetricStruct(&mq.metrics)
+	mq.baseQueue = newBaseQueue(
+		"merge", mq, store, gossip,
+		queueConfig{
+			maxSize:              defaultQueueMaxSize,
+			needsLease:           true,
+			acceptsUnsplitRanges: false,
+			successes:            store.metrics.MergeQueueSuccesses,
+			failures:             store.metrics.MergeQueueFailures,
+			pending:              store.metrics.MergeQueuePending,
+			processingNanos:      store.metrics.MergeQueueProcessingNanos,
+		},
+	)
+	return mq
+}
+
+// shouldQueue determines whether a range should be queued for merging. This
+// is true if the range is smaller than the minimum size of its zone and it is
+// not followed by a zone config boundary. Only table data is merged. Smaller
+// ranges have a higher priority.
+func (mq *mergeQueue) shouldQueue(
+	ctx context.Context, now hlc.Timestamp, repl *Replica, sysCfg config.SystemConfig,
+) (shouldQ bool, priority float64) {
+	if !MergeQueueEnabled.Get() {
+		return false, 0
+	}
+	desc := repl.Desc()
+	if desc.StartKey.Less(roachpb.RKey(keys.TableDataMin)) || desc.EndKey.Equal(roachpb.RKeyMax) {
+		return false, 0
+	}
+	// The range cannot be merged with its right-hand neighbor if the merged
+	// range would have to be split again at the end key.
+	if sysCfg.NeedsSplit(desc.StartKey, desc.EndKey.Next()) {
+		return false, 0
+	}
+	zone, err := sysCfg.GetZoneConfigForKey(desc.StartKey)
+	if err != nil {
+		log.ErrEventf(ctx, "could not look up zone config: %s", err)
+		return false, 0
+	}
+	size := repl.GetMVCCStats().Total()
+	if size >= zone.RangeMinBytes {
+		return false, 0
+	}
+	return true, 1 - float64(size)/float64(zone.RangeMinBytes)
+}
+
+// process merges the range with its right-hand neighbor if their combined size
+// is below the minimum size of their zone, first relocating the right-hand
+// range to the stores of this one if needed. While load-based splitting is
+// enabled, ranges are not merged if that could undo a load-based split.
+func (mq *mergeQueue) process(ctx context.Context, lhsRepl *Replica, sysCfg config.SystemConfig) error {
+	if !MergeQueueEnabled.Get() {
+		return nil
+	}
+	lhsDesc := lhsRepl.Desc()
+	if lhsDesc.EndKey.Equal(roachpb.RKeyMax) {
+		return nil
+	}
+
+	var rhsDesc roachpb.RangeDescriptor
+	if err := mq.db.GetProto(ctx, keys.RangeDescriptorKey(lhsDesc.EndKey), &rhsDesc); err != nil {
+		return err
+	}
+	if !rhsDesc.IsInitialized() {
+		return errors.Errorf("could not find the range descriptor of the range following %s", lhsRepl)
+	}
+	if sysCfg.NeedsSplit(lhsDesc.StartKey, rhsDesc.EndKey) {
+		log.VEventf(ctx, 2, "not merging across a zone config boundary at %s", rhsDesc.StartKey)
+		return nil
+	}
+
+	zone, err := sysCfg.GetZoneConfigForKey(lhsDesc.StartKey)
+	if err != nil {
+		return err
+	}
+	lhsSize := lhsRepl.GetMVCCStats().Total()
+	rhsSize, err := mq.rangeSize(ctx, &rhsDesc, zone.RangeMinBytes-lhsSize)
+	if err != nil {
+		return errors.Wrapf(err, "computing the size of r%d", rhsDesc.RangeID)
+	}
+	if lhsSize+rhsSize >= zone.RangeMinBytes {
+		log.VEventf(ctx, 2, "not merging: combined size %d is not below the minimum of %d",
+			lhsSize+rhsSize, zone.RangeMinBytes)
+		return nil
+	}
+
+	if !replicaSetsEqual(lhsDesc.Replicas, rhsDesc.Replicas) {
+		// Move the replicas of the right-hand range to the stores of this one,
+		// with the lease on this store.
+		targets := []roachpb.ReplicationTarget{{
+			NodeID:  mq.store.Ident.NodeID,
+			StoreID: mq.store.StoreID(),
+		}}
+		for _, r := range lhsDesc.Replicas {
+			if r.StoreID != mq.store.StoreID() {
+				targets = append(targets, roachpb.ReplicationTarget{NodeID: r.NodeID, StoreID: r.StoreID})
+			}
+		}
+		log.Infof(ctx, "relocating r%d to %v to merge it into this range", rhsDesc.RangeID, targets)
+		mq.metrics.RelocateCount.Inc(1)
+		if err := RelocateRange(ctx, mq.db, rhsDesc, targets); err != nil {
+			return errors.Wrapf(err, "relocating r%d", rhsDesc.RangeID)
+		}
+	} else if rhsRepl, err := mq.store.GetReplica(rhsDesc.RangeID); err != nil {
+		return err
+	} else if lease, _ := rhsRepl.getLease(); !lease.OwnedBy(mq.store.StoreID()) {
+		// The timestamp cache is per store, so the reads served by the
+		// right-hand range are only accounted for after the merge if its lease
+		// is held by this store.
+		mq.metrics.TransferLeaseCount.Inc(1)
+		if err := mq.db.AdminTransferLease(ctx, rhsDesc.StartKey.AsRawKey(), mq.store.StoreID()); err != nil {
+			return errors.Wrapf(err, "transferring the lease of r%d", rhsDesc.RangeID)
+		}
+	}
+
+	if SplitByLoadEnabled.Get() {
+		// The QPS of the right-hand range is only known once its lease is held
+		// by this store, so a range that was just relocated or whose lease was
+		// just transferred is merged on a later pass.
+		rhsRepl, err := mq.store.GetReplica(rhsDesc.RangeID)
+		if err != nil {
+			return err
+		}
+		if loadPreventsMerge(ctx, lhsRepl, rhsRepl) {
+			return nil
+		}
+	}
+
+	log.Infof(ctx, "merging r%d (size=%d) into this range (size=%d)", rhsDesc.RangeID, rhsSize, lhsSize)
+	mq.metrics.MergeCount.Inc(1)
+	if _, pErr := lhsRepl.AdminMerge(ctx, roachpb.AdminMergeRequest{
+		Span: roachpb.Span{Key: lhsDesc.StartKey.AsRawKey()},
+	}); pErr != nil {
+		return pErr.GoError()
+	}
+	return nil
+}
+
+// loadPreventsMerge returns whether merging the ranges of lhsRepl and rhsRepl
+// could undo a load-based split: either the merged range would be above the
+// QPS threshold of load-based splitting, or the QPS of one of the ranges, which
+// is measured from its last split or lease transfer, was not measured for long
+// enough to tell.
+func loadPreventsMerge(ctx context.Context, lhsRepl, rhsRepl *Replica) bool {
+	var qps float64
+	for _, repl := range []*Replica{lhsRepl, rhsRepl} {
+		if repl.stats == nil {
+			return false
+		}
+		replQPS, dur := repl.stats.avgQPS()
+		if dur < mergeQueueMinQPSDuration {
+			log.VEventf(ctx, 2, "not merging: the QPS of %s was only measured over %s", repl, dur)
+			return true
+		}
+		qps += replQPS
+	}
+	if threshold := float64(SplitByLoadQPSThreshold.Get()); qps >= threshold {
+		log.VEventf(ctx, 2, "not merging: combined QPS %.2f is not below the load-based splitting threshold of %.0f",
+			qps, threshold)
+		return true
+	}
+	return false
+}
+
+// rangeSize returns the size of the given range, using the stats of its
+// replica on this store if there is one. Otherwise, the range is scanned,
+// which only accounts for the live data, until its size reaches limit.
+func (mq *mergeQueue) rangeSize(
+	ctx context.Context, desc *roachpb.RangeDescriptor, limit int64,
+) (int64, error) {
+	if repl, err := mq.store.GetReplica(desc.RangeID); err == nil && repl.IsInitialized() {
+		return repl.GetMVCCStats().Total(), nil
+	}
+	var size int64
+	start, end := desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey()
+	for size < limit {
+		rows, err := mq.db.Scan(ctx, start, end, mergeQueueScanBatchSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, row := range rows {
+			size += int64(len(row.Key))
+			if row.Value != nil {
+				size += int64(len(row.Value.RawBytes))
+			}
+		}
+		if len(rows) < mergeQueueScanBatchSize {
+			break
+		}
+		start = rows[len(rows)-1].Key.Next()
+	}
+	return size, nil
+}
+
+// timer returns interval between processing successive queued merges.
+func (*mergeQueue) timer(_ time.Duration) time.Duration {
+	return mergeQueueTimerDuration
+}
+
+// purgatoryChan returns nil.
+func (*mergeQueue) purgatoryChan() <-chan struct{} {
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/cockroachdb/cockroach/pkg/config"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage/engine/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+)
+
+// TestMergeQueueShouldQueue verifies that shouldQueue only queues ranges of
+// table data smaller than their zone's minimum size that are not followed by a
+// zone config boundary.
+func TestMergeQueueShouldQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer settings.TestingSetBool(&MergeQueueEnabled, true)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	config.TestingSetZoneConfig(2000, config.ZoneConfig{RangeMinBytes: 1 << 20, RangeMaxBytes: 64 << 20})
+	config.TestingSetZoneConfig(2002, config.ZoneConfig{RangeMinBytes: 1 << 20, RangeMaxBytes: 64 << 20})
+
+	tableKey := func(id uint32, suffix string) roachpb.RKey {
+		return roachpb.RKey(append(keys.MakeTablePrefix(id), suffix...))
+	}
+
+	testCases := []struct {
+		start, end roachpb.RKey
+		bytes      int64
+		shouldQ    bool
+		priority   float64
+	}{
+		// Not table data.
+		{roachpb.RKeyMin, roachpb.RKey(keys.MetaMax), 0, false, 0},
+		// Empty range inside a table.
+		{tableKey(2000, "a"), tableKey(2000, "b"), 0, true, 1},
+		// Half the minimum size.
+		{tableKey(2000, "a"), tableKey(2000, "b"), 1 << 19, true, 0.5},
+		// At the minimum size.
+		{tableKey(2000, "a"), tableKey(2000, "b"), 1 << 20, false, 0},
+		// Followed by a table boundary.
+		{tableKey(2000, "a"), roachpb.RKey(keys.MakeRowSentinelKey(keys.MakeTablePrefix(2001))), 0, false, 0},
+		// Last range.
+		{tableKey(2002, "a"), roachpb.RKeyMax, 0, false, 0},
+	}
+
+	mergeQ := newMergeQueue(tc.store, nil, tc.gossip)
+
+	cfg, ok := tc.gossip.GetSystemConfig()
+	if !ok {
+		t.Fatal("config not set")
+	}
+
+	check := func(i int, expectedShouldQ bool, expectedPriority float64, repl *Replica) {
+		shouldQ, priority := mergeQ.shouldQueue(context.TODO(), hlc.Timestamp{}, repl, cfg)
+		if shouldQ != expectedShouldQ {
+			t.Errorf("%d: should queue expected %t; got %t", i, expectedShouldQ, shouldQ)
+		}
+		if math.Abs(priority-expectedPriority) > 0.00001 {
+			t.Errorf("%d: priority expected %f; got %f", i, expectedPriority, priority)
+		}
+	}
+
+	for i, test := range testCases {
+		// Create a replica for testing that is not hooked up to the store. This
+		// ensures that the store won't be mucking with our replica concurrently
+		// during testing (e.g. via the system config gossip update).
+		copy := *tc.repl.Desc()
+		copy.StartKey = test.start
+		copy.EndKey = test.end
+		repl, err := NewReplica(&copy, tc.store, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		repl.mu.Lock()
+		repl.mu.state.Stats = enginepb.MVCCStats{KeyBytes: test.bytes}
+		repl.mu.Unlock()
+
+		check(i, test.shouldQ, test.priority, repl)
+		if test.shouldQ {
+			// Nothing is queued when the queue is disabled.
+			func() {
+				defer settings.TestingSetBool(&MergeQueueEnabled, false)()
+				check(i, false, 0, repl)
+			}()
+		}
+	}
+}
+
+// TestMaybeMergeTimestampCaches verifies that merging a range whose lease is
+// held by another store forwards the read timestamps of its span in the
+// timestamp cache of the store holding the lease of the subsuming range.
+func TestMaybeMergeTimestampCaches(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	tc := testContext{}
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.TODO())
+	tc.Start(t, stopper)
+
+	now := tc.Clock().Now()
+	makeRepl := func(start, end roachpb.RKey, storeID roachpb.StoreID) *Replica {
+		copy := *tc.repl.Desc()
+		copy.StartKey = start
+		copy.EndKey = end
+		repl, err := NewReplica(&copy, tc.store, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repl.mu.Lock()
+		repl.mu.state.Lease = &roachpb.Lease{
+			Start:      now,
+			Expiration: now.Add(time.Hour.Nanoseconds(), 0),
+			Replica: roachpb.ReplicaDescriptor{
+				NodeID: roachpb.NodeID(storeID), StoreID: storeID, ReplicaID: roachpb.ReplicaID(storeID),
+			},
+		}
+		repl.mu.Unlock()
+		return repl
+	}
+	subsuming := makeRepl(roachpb.RKey("a"), roachpb.RKey("c"), tc.store.StoreID())
+	subsumed := makeRepl(roachpb.RKey("c"), roachpb.RKey("e"), tc.store.StoreID()+1)
+
+	if err := tc.store.maybeMergeTimestampCaches(context.TODO(), subsuming, subsumed); err != nil {
+		t.Fatal(err)
+	}
+
+	minReadTS := now.Add(tc.store.Clock().MaxOffset().Nanoseconds(), 0)
+	for _, test := range []struct {
+		key       string
+		forwarded bool
+	}{
+		{"a", false},
+		{"c", true},
+		{"d", true},
+	} {
+		tc.store.tsCacheMu.Lock()
+		rTS, _, _ := tc.store.tsCacheMu.cache.GetMaxRead(roachpb.Key(test.key), nil)
+		tc.store.tsCacheMu.Unlock()
+		if forwarded := !rTS.Less(minReadTS); forwarded != test.forwarded {
+			t.Errorf("%s: expected forwarded %t, got read timestamp %s (min %s)",
+				test.key, test.forwarded, rTS, minReadTS)
+		}
+	}
+}
+
+////
+// NOTE: tests which actually verify processing of the merge queue are
+// in client_merge_test.go, which is in a different test package in
+// order to allow for distributed transactions with a proper client.
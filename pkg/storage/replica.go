@@ -213,6 +213,8 @@ type Replica struct {
 	pushTxnQueue *pushTxnQueue // Queues push txn attempts by txn ID
 
 	stats *replicaStats
+	// loadSplitter finds keys to split the replica at when its load is high.
+	loadSplitter loadSplitter
 
 	// creatingReplica is set when a replica is created as uninitialized
 	// via a raft message.
@@ -2007,6 +2009,7 @@ func (r *Replica) executeReadOnlyBatch(
 		if _, pErr = r.redirectOnOrAcquireLease(ctx); pErr != nil {
 			return nil, pErr
 		}
+		r.recordLoadForSplit(&ba)
 	}
 
 	spans, err := collectSpans(*r.Desc(), &ba)
@@ -2198,6 +2201,7 @@ func (r *Replica) tryExecuteWriteBatch(
 			return nil, pErr, proposalNoRetry
 		}
 		lease = status.lease
+		r.recordLoadForSplit(&ba)
 	}
 
 	// Examine the read and write timestamp caches for preceding
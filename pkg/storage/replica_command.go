@@ -3117,6 +3117,13 @@ func (r *Replica) AdminMerge(
 		if rightRng == nil {
 			return reply, roachpb.NewErrorf("ranges not collocated")
 		}
+		// The timestamp cache is per store, so the reads served by the right
+		// hand side are only accounted for after the merge if its lease is
+		// held by this store.
+		if rightLease, _ := rightRng.getLease(); !rightLease.OwnedBy(r.store.StoreID()) {
+			return reply, roachpb.NewErrorf("ranges not collocated: the lease of %s is held by store %d",
+				rightRng, rightLease.Replica.StoreID)
+		}
 
 		updatedLeftDesc.EndKey = rightRng.Desc().EndKey
 		log.Infof(ctx, "initiating a merge of %s into this range", rightRng)
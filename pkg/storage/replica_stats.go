@@ -120,6 +120,41 @@ func (rs *replicaStats) getRequestCounts() (perLocalityCounts, time.Duration) {
 	return counts, now.Sub(rs.mu.lastReset)
 }
 
+// avgQPS returns the average number of requests per second received by the
+// replica and the amount of time over which it was computed. As with
+// getRequestCounts, older windows are given less weight.
+func (rs *replicaStats) avgQPS() (float64, time.Duration) {
+	now := time.Unix(0, rs.clock.PhysicalNow())
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.maybeRotateLocked(now)
+
+	fractionOfRotation := float64(now.Sub(rs.mu.lastRotate)) / float64(replStatsRotateInterval)
+
+	var sum, windowsSeconds float64
+	for i := range rs.mu.requests {
+		requestsIdx := (rs.mu.idx + len(rs.mu.requests) - i) % len(rs.mu.requests)
+		if cur := rs.mu.requests[requestsIdx]; cur != nil {
+			weight := math.Pow(decayFactor, float64(i)+fractionOfRotation)
+			for _, v := range cur {
+				sum += v * weight
+			}
+			// The current window only covers the time since the last rotation.
+			window := replStatsRotateInterval
+			if i == 0 {
+				window = now.Sub(rs.mu.lastRotate)
+			}
+			windowsSeconds += window.Seconds() * weight
+		}
+	}
+	if windowsSeconds == 0 {
+		return 0, 0
+	}
+	return sum / windowsSeconds, now.Sub(rs.mu.lastReset)
+}
+
 func (rs *replicaStats) resetRequestCounts() {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
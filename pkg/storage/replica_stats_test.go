@@ -341,3 +341,40 @@ func TestReplicaStatsDecaySmoothing(t *testing.T) {
 		}
 	}
 }
+
+func TestReplicaStatsAvgQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	manual := hlc.NewManualClock(123)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+	rs := newReplicaStats(clock, func(roachpb.NodeID) string { return "" })
+
+	if qps, dur := rs.avgQPS(); qps != 0 || dur != 0 {
+		t.Errorf("expected no QPS over no time, got %f over %v", qps, dur)
+	}
+
+	for i := 0; i < 30; i++ {
+		rs.record(1)
+	}
+	manual.Increment(int64(replStatsRotateInterval))
+	if qps, dur := rs.avgQPS(); math.Abs(qps-0.1) > 0.00001 || dur != replStatsRotateInterval {
+		t.Errorf("expected 0.1 QPS over %v, got %f over %v", replStatsRotateInterval, qps, dur)
+	}
+
+	// The requests of older windows weigh less in the average.
+	for i := 0; i < 60; i++ {
+		rs.record(1)
+	}
+	manual.Increment(int64(replStatsRotateInterval / 2))
+	recent, old := math.Pow(decayFactor, 0.5), math.Pow(decayFactor, 1.5)
+	expected := (60*recent + 30*old) / (replStatsRotateInterval.Seconds()/2*recent + replStatsRotateInterval.Seconds()*old)
+	if qps, _ := rs.avgQPS(); math.Abs(qps-expected) > 0.00001 {
+		t.Errorf("expected %f QPS, got %f", expected, qps)
+	}
+
+	rs.resetRequestCounts()
+	manual.Increment(1)
+	if qps, _ := rs.avgQPS(); qps != 0 {
+		t.Errorf("expected no QPS after resetting, got %f", qps)
+	}
+}
@@ -175,10 +175,13 @@ func (rq *replicateQueue) shouldQueue(
 	}
 
 	// If the lease is valid, check to see if we should transfer it.
+	var leaseStoreID roachpb.StoreID
 	if lease, _ := repl.getLease(); repl.IsLeaseValid(lease, now) {
+		leaseStoreID = lease.Replica.StoreID
 		if rq.canTransferLease() &&
 			rq.allocator.ShouldTransferLease(
-				ctx, zone.Constraints, desc.Replicas, lease.Replica.StoreID, desc.RangeID, repl.stats) {
+				ctx, zone.Constraints, zone.LeasePreferences, desc.Replicas, lease.Replica.StoreID,
+				desc.RangeID, repl.stats) {
 			if log.V(2) {
 				log.Infof(ctx, "lease transfer needed, enqueuing")
 			}
@@ -193,6 +196,7 @@ func (rq *replicateQueue) shouldQueue(
 		ctx,
 		zone.Constraints,
 		desc.Replicas,
+		leaseStoreID,
 		desc.RangeID,
 	)
 	if err != nil {
@@ -302,6 +306,7 @@ func (rq *replicateQueue) processOneChange(
 			ctx,
 			zone.Constraints,
 			desc.Replicas,
+			repl.store.StoreID(),
 		)
 		if err != nil {
 			return false, err
@@ -399,6 +404,7 @@ func (rq *replicateQueue) processOneChange(
 			ctx,
 			zone.Constraints,
 			desc.Replicas,
+			repl.store.StoreID(),
 			desc.RangeID,
 		)
 		if err != nil {
@@ -443,6 +449,7 @@ func (rq *replicateQueue) transferLease(
 	if target := rq.allocator.TransferLeaseTarget(
 		ctx,
 		zone.Constraints,
+		zone.LeasePreferences,
 		candidates,
 		repl.store.StoreID(),
 		desc.RangeID,
@@ -39,12 +39,46 @@ import (
 // https://www.eecs.harvard.edu/~michaelm/postscripts/mythesis.pdf.
 const allocatorRandomCount = 2
 
-func rebalanceFromConvergesOnMean(sl StoreList, candidate roachpb.StoreDescriptor) bool {
-	return float64(candidate.Capacity.RangeCount) > sl.candidateCount.mean+0.5
+// rebalanceFromConvergesOnMean returns whether removing a replica from the
+// candidate store converges the range counts or the load of the stores to the
+// mean. The QPS of a store only counts the requests received by the replicas
+// it holds the lease of, so load is only considered if the candidate holds the
+// lease of the range, and if removing the replica doesn't make the candidate
+// underfull in terms of range count, which range count rebalancing would undo.
+func rebalanceFromConvergesOnMean(
+	sl StoreList, candidate roachpb.StoreDescriptor, leaseholder bool,
+) bool {
+	rangeCount := float64(candidate.Capacity.RangeCount)
+	underfullThreshold := math.Floor(sl.candidateCount.mean * (1 - baseRebalanceThreshold))
+	return rangeCount > sl.candidateCount.mean+0.5 ||
+		(leaseholder && qpsOverfull(sl, candidate) && rangeCount-1 >= underfullThreshold)
 }
 
-func rebalanceToConvergesOnMean(sl StoreList, candidate roachpb.StoreDescriptor) bool {
-	return float64(candidate.Capacity.RangeCount) < sl.candidateCount.mean-0.5
+// rebalanceToConvergesOnMean is the counterpart of rebalanceFromConvergesOnMean
+// for adding a replica to the candidate store. The load of the range only
+// follows the new replica if its lease is then transferred to it, so load is
+// only considered if the store holding the lease is overfull in terms of QPS.
+func rebalanceToConvergesOnMean(
+	sl StoreList, candidate roachpb.StoreDescriptor, leaseholderQPSOverfull bool,
+) bool {
+	rangeCount := float64(candidate.Capacity.RangeCount)
+	overfullThreshold := math.Ceil(sl.candidateCount.mean * (1 + baseRebalanceThreshold))
+	return rangeCount < sl.candidateCount.mean-0.5 ||
+		(leaseholderQPSOverfull && qpsUnderfull(sl, candidate) && rangeCount+1 <= overfullThreshold)
+}
+
+// qpsOverfull returns whether the store receives significantly more requests
+// than the mean of the stores in the list.
+func qpsOverfull(sl StoreList, store roachpb.StoreDescriptor) bool {
+	qps, mean := store.Capacity.QueriesPerSecond, sl.candidateQPS.mean
+	return qps > mean*(1+QPSRebalanceThreshold.Get()) && qps-mean >= minQPSDifferenceForRebalance
+}
+
+// qpsUnderfull returns whether the store receives significantly fewer requests
+// than the mean of the stores in the list.
+func qpsUnderfull(sl StoreList, store roachpb.StoreDescriptor) bool {
+	qps, mean := store.Capacity.QueriesPerSecond, sl.candidateQPS.mean
+	return qps < mean*(1-QPSRebalanceThreshold.Get()) && mean-qps >= minQPSDifferenceForRebalance
 }
 
 // candidate store for allocation.
@@ -53,15 +87,17 @@ type candidate struct {
 	valid           bool
 	constraintScore float64
 	rangeCount      int
+	qps             float64
 	details         string
 }
 
 func (c candidate) String() string {
-	return fmt.Sprintf("s%d, valid:%t, con:%.2f, ranges:%d, details:(%s)",
-		c.store.StoreID, c.valid, c.constraintScore, c.rangeCount, c.details)
+	return fmt.Sprintf("s%d, valid:%t, con:%.2f, ranges:%d, qps:%.2f, details:(%s)",
+		c.store.StoreID, c.valid, c.constraintScore, c.rangeCount, c.qps, c.details)
 }
 
-// less first compares valid, then constraint scores, then range counts.
+// less first compares valid, then constraint scores, then range counts, then
+// queries-per-second.
 func (c candidate) less(o candidate) bool {
 	if !o.valid {
 		return false
@@ -72,7 +108,10 @@ func (c candidate) less(o candidate) bool {
 	if c.constraintScore != o.constraintScore {
 		return c.constraintScore < o.constraintScore
 	}
-	return c.rangeCount > o.rangeCount
+	if c.rangeCount != o.rangeCount {
+		return c.rangeCount > o.rangeCount
+	}
+	return c.qps > o.qps
 }
 
 type candidateList []candidate
@@ -109,6 +148,7 @@ func (c byScoreAndID) Len() int { return len(c) }
 func (c byScoreAndID) Less(i, j int) bool {
 	if c[i].constraintScore == c[j].constraintScore &&
 		c[i].rangeCount == c[j].rangeCount &&
+		c[i].qps == c[j].qps &&
 		c[i].valid == c[j].valid {
 		return c[i].store.StoreID < c[j].store.StoreID
 	}
@@ -248,6 +288,7 @@ func allocateCandidates(
 			valid:           true,
 			constraintScore: diversityScore + float64(preferredMatched),
 			rangeCount:      int(s.Capacity.RangeCount),
+			qps:             s.Capacity.QueriesPerSecond,
 			details: fmt.Sprintf("diversity=%.2f, preferred=%d",
 				diversityScore, preferredMatched),
 		})
@@ -262,11 +303,13 @@ func allocateCandidates(
 
 // removeCandidates creates a candidate list of all existing replicas' stores
 // ordered from least qualified for removal to most qualified. Stores that are
-// marked as not valid, are in violation of a required criteria.
+// marked as not valid, are in violation of a required criteria. leaseStoreID
+// is the store holding the lease of the range, or 0 if it is unknown.
 func removeCandidates(
 	sl StoreList,
 	constraints config.Constraints,
 	existingNodeLocalities map[roachpb.NodeID]roachpb.Locality,
+	leaseStoreID roachpb.StoreID,
 	deterministic bool,
 ) candidateList {
 	var candidates candidateList
@@ -290,12 +333,12 @@ func removeCandidates(
 		}
 		diversityScore := diversityRemovalScore(s.Node.NodeID, existingNodeLocalities)
 		var convergesScore float64
-		if !rebalanceFromConvergesOnMean(sl, s) {
+		if !rebalanceFromConvergesOnMean(sl, s, s.StoreID == leaseStoreID) {
 			// If removing this candidate replica does not converge the range
-			// counts to the mean, we make it less attractive for removal by
-			// adding 1 to the constraint score. Note that when selecting a
-			// candidate for removal the candidates with the lowest scores are
-			// more likely to be removed.
+			// counts or load to the mean, we make it less attractive for
+			// removal by adding 1 to the constraint score. Note that when
+			// selecting a candidate for removal the candidates with the lowest
+			// scores are more likely to be removed.
 			convergesScore = 1
 		}
 		candidates = append(candidates, candidate{
@@ -303,6 +346,7 @@ func removeCandidates(
 			valid:           true,
 			constraintScore: diversityScore + float64(preferredMatched) + convergesScore,
 			rangeCount:      int(s.Capacity.RangeCount),
+			qps:             s.Capacity.QueriesPerSecond,
 			details: fmt.Sprintf("diversity=%.2f, preferred=%d, converge=%.2f",
 				diversityScore, preferredMatched, convergesScore),
 		})
@@ -318,13 +362,15 @@ func removeCandidates(
 // rebalanceCandidates creates two candidate list. The first contains all
 // existing replica's stores, order from least qualified for rebalancing to
 // most qualified. The second list is of all potential stores that could be
-// used as rebalancing receivers, ordered from best to worst.
+// used as rebalancing receivers, ordered from best to worst. leaseStoreID is
+// the store holding the lease of the range, or 0 if it is unknown.
 func rebalanceCandidates(
 	ctx context.Context,
 	sl StoreList,
 	constraints config.Constraints,
 	existing []roachpb.ReplicaDescriptor,
 	existingNodeLocalities map[roachpb.NodeID]roachpb.Locality,
+	leaseStoreID roachpb.StoreID,
 	deterministic bool,
 ) (candidateList, candidateList) {
 	// Load the exiting storesIDs into a map to eliminate having to loop
@@ -359,11 +405,18 @@ func rebalanceCandidates(
 	}
 
 	constraintsOkStoreList := makeStoreList(constraintsOkStoreDescriptors)
+	var leaseholderQPSOverfull bool
+	for _, s := range constraintsOkStoreList.stores {
+		if s.StoreID == leaseStoreID {
+			leaseholderQPSOverfull = qpsOverfull(constraintsOkStoreList, s)
+			break
+		}
+	}
 	var shouldRebalanceCheck bool
 	if !rebalanceConstraintsCheck {
 		for _, s := range sl.stores {
 			if _, ok := existingStoreIDs[s.StoreID]; ok {
-				if shouldRebalance(ctx, s, constraintsOkStoreList) {
+				if shouldRebalance(ctx, s, constraintsOkStoreList, s.StoreID == leaseStoreID) {
 					shouldRebalanceCheck = true
 					break
 				}
@@ -401,11 +454,11 @@ func rebalanceCandidates(
 			}
 			diversityScore := diversityRemovalScore(s.Node.NodeID, existingNodeLocalities)
 			var convergesScore float64
-			if !rebalanceFromConvergesOnMean(constraintsOkStoreList, s) {
+			if !rebalanceFromConvergesOnMean(constraintsOkStoreList, s, s.StoreID == leaseStoreID) {
 				// Similarly to in removeCandidates, any replica whose removal
-				// would not converge the range counts to the mean is given a
-				// constraint score boost of 1 to make it less attractive for
-				// removal.
+				// would not converge the range counts or load to the mean is
+				// given a constraint score boost of 1 to make it less attractive
+				// for removal.
 				convergesScore = 1
 			}
 			existingCandidates = append(existingCandidates, candidate{
@@ -413,6 +466,7 @@ func rebalanceCandidates(
 				valid:           true,
 				constraintScore: diversityScore + float64(storeInfo.matched) + convergesScore,
 				rangeCount:      int(s.Capacity.RangeCount),
+				qps:             s.Capacity.QueriesPerSecond,
 				details: fmt.Sprintf("diversity=%.2f, preferred=%d, converge=%.2f",
 					diversityScore, storeInfo.matched, convergesScore),
 			})
@@ -421,10 +475,10 @@ func rebalanceCandidates(
 				continue
 			}
 			var convergesScore float64
-			if rebalanceToConvergesOnMean(constraintsOkStoreList, s) {
+			if rebalanceToConvergesOnMean(constraintsOkStoreList, s, leaseholderQPSOverfull) {
 				// This is the counterpart of !rebalanceFromConvergesOnMean from
 				// the existing candidates. Candidates whose addition would
-				// converge towards the range count mean are promoted.
+				// converge towards the range count or load mean are promoted.
 				convergesScore = 1
 			} else if !rebalanceConstraintsCheck {
 				// Only consider this candidate if we must rebalance due to a
@@ -437,6 +491,7 @@ func rebalanceCandidates(
 				valid:           true,
 				constraintScore: diversityScore + float64(storeInfo.matched) + convergesScore,
 				rangeCount:      int(s.Capacity.RangeCount),
+				qps:             s.Capacity.QueriesPerSecond,
 				details: fmt.Sprintf("diversity=%.2f, preferred=%d, converge=%.2f",
 					diversityScore, storeInfo.matched, convergesScore),
 			})
@@ -455,8 +510,12 @@ func rebalanceCandidates(
 }
 
 // shouldRebalance returns whether the specified store is a candidate for
-// having a replica removed from it given the candidate store list.
-func shouldRebalance(ctx context.Context, store roachpb.StoreDescriptor, sl StoreList) bool {
+// having a replica removed from it given the candidate store list. leaseholder
+// is whether the store holds the lease of the range, without which moving the
+// replica doesn't move any of the requests counted in the store's QPS.
+func shouldRebalance(
+	ctx context.Context, store roachpb.StoreDescriptor, sl StoreList, leaseholder bool,
+) bool {
 	// TODO(peter,bram,cuong): The FractionUsed check seems suspicious. When a
 	// node becomes fuller than maxFractionUsedThreshold we will always select it
 	// for rebalancing. This is currently utilized by tests.
@@ -481,15 +540,31 @@ func shouldRebalance(ctx context.Context, store roachpb.StoreDescriptor, sl Stor
 		}
 	}
 
-	shouldRebalance := maxCapacityUsed || rangeCountAboveOverfullThreshold || rebalanceToUnderfullStore
+	// Rebalance if the candidate store holds the lease and receives
+	// significantly more requests than the mean, and there exists another store
+	// that receives significantly fewer requests.
+	var rebalanceToQPSUnderfullStore bool
+	if leaseholder && qpsOverfull(sl, store) {
+		for _, desc := range sl.stores {
+			if qpsUnderfull(sl, desc) {
+				rebalanceToQPSUnderfullStore = true
+				break
+			}
+		}
+	}
+
+	shouldRebalance := maxCapacityUsed || rangeCountAboveOverfullThreshold ||
+		rebalanceToUnderfullStore || rebalanceToQPSUnderfullStore
 	if log.V(2) && shouldRebalance {
 		log.Infof(ctx,
-			"s%d: should-rebalance: fraction-used=%.2f range-count=%d "+
-				"(mean=%.1f, overfull-threshold=%d, fraction-used=%t, "+
-				"above-overfull-threshold=%t, rebalance-to-underfull=%t)",
+			"s%d: should-rebalance: fraction-used=%.2f range-count=%d qps=%.2f "+
+				"(mean=%.1f, mean-qps=%.2f, overfull-threshold=%d, fraction-used=%t, "+
+				"above-overfull-threshold=%t, rebalance-to-underfull=%t, "+
+				"rebalance-to-qps-underfull=%t)",
 			store.StoreID, store.Capacity.FractionUsed(), store.Capacity.RangeCount,
-			sl.candidateCount.mean, overfullThreshold, maxCapacityUsed,
-			rangeCountAboveOverfullThreshold, rebalanceToUnderfullStore)
+			store.Capacity.QueriesPerSecond, sl.candidateCount.mean, sl.candidateQPS.mean,
+			overfullThreshold, maxCapacityUsed, rangeCountAboveOverfullThreshold,
+			rebalanceToUnderfullStore, rebalanceToQPSUnderfullStore)
 	}
 	return shouldRebalance
 }
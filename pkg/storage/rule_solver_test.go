@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/kr/pretty"
+	"golang.org/x/net/context"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -647,3 +648,87 @@ func TestMaxCapacity(t *testing.T) {
 		}
 	}
 }
+
+func TestRebalanceConvergesOnMeanQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	store := func(id roachpb.StoreID, rangeCount int32, qps float64) roachpb.StoreDescriptor {
+		return roachpb.StoreDescriptor{
+			StoreID:  id,
+			Capacity: roachpb.StoreCapacity{RangeCount: rangeCount, QueriesPerSecond: qps},
+		}
+	}
+	// The mean range count is 10 and the mean QPS is 500.
+	hot := store(1, 10, 1100)
+	cold := store(2, 10, 100)
+	busy := store(3, 11, 300)
+	lukewarm := store(4, 9, 500)
+	sl := makeStoreList([]roachpb.StoreDescriptor{hot, cold, busy, lukewarm})
+
+	// The same stores without any requests.
+	withoutQPS := func(s roachpb.StoreDescriptor) roachpb.StoreDescriptor {
+		s.Capacity.QueriesPerSecond = 0
+		return s
+	}
+	slWithoutQPS := makeStoreList([]roachpb.StoreDescriptor{
+		withoutQPS(hot), withoutQPS(cold), withoutQPS(busy), withoutQPS(lukewarm),
+	})
+
+	testCases := []struct {
+		store           roachpb.StoreDescriptor
+		overfull        bool
+		underfull       bool
+		convergesFrom   bool
+		convergesTo     bool
+		shouldRebalance bool
+	}{
+		// The expectations are for a range whose lease is held by a QPS-overfull
+		// store when adding a replica, and by the store itself otherwise.
+		{hot, true, false, true, false, true},
+		{cold, false, true, false, true, false},
+		// Range count rebalancing would undo adding a replica to this store,
+		// but removing one is fine.
+		{busy, false, true, true, false, false},
+		{lukewarm, false, false, false, true, false},
+	}
+	for _, tc := range testCases {
+		if a := qpsOverfull(sl, tc.store); a != tc.overfull {
+			t.Errorf("s%d: expected qpsOverfull %t, got %t", tc.store.StoreID, tc.overfull, a)
+		}
+		if a := qpsUnderfull(sl, tc.store); a != tc.underfull {
+			t.Errorf("s%d: expected qpsUnderfull %t, got %t", tc.store.StoreID, tc.underfull, a)
+		}
+		if a := rebalanceFromConvergesOnMean(sl, tc.store, true /* leaseholder */); a != tc.convergesFrom {
+			t.Errorf("s%d: expected rebalanceFromConvergesOnMean %t, got %t",
+				tc.store.StoreID, tc.convergesFrom, a)
+		}
+		if a := rebalanceToConvergesOnMean(sl, tc.store, true /* leaseholderQPSOverfull */); a != tc.convergesTo {
+			t.Errorf("s%d: expected rebalanceToConvergesOnMean %t, got %t",
+				tc.store.StoreID, tc.convergesTo, a)
+		}
+		if a := shouldRebalance(
+			context.Background(), tc.store, sl, true, /* leaseholder */
+		); a != tc.shouldRebalance {
+			t.Errorf("s%d: expected shouldRebalance %t, got %t", tc.store.StoreID, tc.shouldRebalance, a)
+		}
+
+		// The QPS of a store only counts the requests received by the ranges
+		// it holds the lease of, so it makes no difference when the lease
+		// doesn't move along with the replica.
+		noQPS := withoutQPS(tc.store)
+		if a, e := rebalanceFromConvergesOnMean(sl, tc.store, false /* leaseholder */),
+			rebalanceFromConvergesOnMean(slWithoutQPS, noQPS, true /* leaseholder */); a != e {
+			t.Errorf("s%d: expected rebalanceFromConvergesOnMean %t without the lease, got %t",
+				tc.store.StoreID, e, a)
+		}
+		if a, e := rebalanceToConvergesOnMean(sl, tc.store, false /* leaseholderQPSOverfull */),
+			rebalanceToConvergesOnMean(slWithoutQPS, noQPS, true /* leaseholderQPSOverfull */); a != e {
+			t.Errorf("s%d: expected rebalanceToConvergesOnMean %t without a QPS-overfull leaseholder, got %t",
+				tc.store.StoreID, e, a)
+		}
+		if a, e := shouldRebalance(context.Background(), tc.store, sl, false /* leaseholder */),
+			shouldRebalance(context.Background(), noQPS, slWithoutQPS, true /* leaseholder */); a != e {
+			t.Errorf("s%d: expected shouldRebalance %t without the lease, got %t", tc.store.StoreID, e, a)
+		}
+	}
+}
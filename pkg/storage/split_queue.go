@@ -35,7 +35,7 @@ const (
 	splitQueueTimerDuration = 0 // zero duration to process splits greedily.
 )
 
-// splitQueue manages a queue of ranges slated to be split due to size
+// splitQueue manages a queue of ranges slated to be split due to size, load
 // or along intersecting zone config boundaries.
 type splitQueue struct {
 	*baseQueue
@@ -64,7 +64,9 @@ func newSplitQueue(store *Store, db *client.DB, gossip *gossip.Gossip) *splitQue
 
 // shouldQueue determines whether a range should be queued for
 // splitting. This is true if the range is intersected by a zone config
-// prefix or if the range's size in bytes exceeds the limit for the zone.
+// prefix, if the range's size in bytes exceeds the limit for the zone, or if
+// the range stayed above the QPS threshold of load-based splitting and a key
+// balancing its load was found.
 func (sq *splitQueue) shouldQueue(
 	ctx context.Context, now hlc.Timestamp, repl *Replica, sysCfg config.SystemConfig,
 ) (shouldQ bool, priority float64) {
@@ -81,6 +83,11 @@ func (sq *splitQueue) shouldQueue(
 		priority += ratio
 		shouldQ = true
 	}
+
+	if repl.loadSplitKey(now.GoTime()) != nil {
+		priority++
+		shouldQ = true
+	}
 	return
 }
 
@@ -102,6 +109,22 @@ func (sq *splitQueue) process(ctx context.Context, r *Replica, sysCfg config.Sys
 		return nil
 	}
 
+	// Next handle case of splitting due to load. The data collected so far is
+	// discarded by the split once it succeeds.
+	if splitKey := r.loadSplitKey(r.store.Clock().PhysicalTime()); splitKey != nil {
+		log.Infof(ctx, "splitting at key %v based on load", splitKey)
+		if _, _, pErr := r.adminSplitWithDescriptor(
+			ctx,
+			roachpb.AdminSplitRequest{
+				SplitKey: splitKey,
+			},
+			desc,
+		); pErr != nil {
+			return errors.Wrapf(pErr.GoError(), "unable to split %s at key %q", r, splitKey)
+		}
+		return nil
+	}
+
 	// Next handle case of splitting due to size. Note that we don't perform
 	// size-based splitting if maxBytes is 0 (happens in certain test
 	// situations).
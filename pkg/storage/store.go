@@ -393,6 +393,7 @@ type Store struct {
 	rangeIDAlloc       *idAllocator                // Range ID allocator
 	gcQueue            *gcQueue                    // Garbage collection queue
 	splitQueue         *splitQueue                 // Range splitting queue
+	mergeQueue         *mergeQueue                 // Range merging queue
 	replicateQueue     *replicateQueue             // Replication queue
 	replicaGCQueue     *replicaGCQueue             // Replica GC queue
 	raftLogQueue       *raftLogQueue               // Raft log truncation queue
@@ -777,6 +778,8 @@ type StoreTestingKnobs struct {
 	DisableReplicateQueue bool
 	// DisableSplitQueue disables the split queue.
 	DisableSplitQueue bool
+	// DisableMergeQueue disables the merge queue.
+	DisableMergeQueue bool
 	// DisableTimeSeriesMaintenanceQueue disables the time series maintenance
 	// queue.
 	DisableTimeSeriesMaintenanceQueue bool
@@ -935,13 +938,14 @@ func NewStore(cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescript
 		)
 		s.gcQueue = newGCQueue(s, s.cfg.Gossip)
 		s.splitQueue = newSplitQueue(s, s.db, s.cfg.Gossip)
+		s.mergeQueue = newMergeQueue(s, s.db, s.cfg.Gossip)
 		s.replicateQueue = newReplicateQueue(s, s.cfg.Gossip, s.allocator, s.cfg.Clock)
 		s.replicaGCQueue = newReplicaGCQueue(s, s.db, s.cfg.Gossip)
 		s.raftLogQueue = newRaftLogQueue(s, s.db, s.cfg.Gossip)
 		s.raftSnapshotQueue = newRaftSnapshotQueue(s, s.cfg.Gossip, s.cfg.Clock)
 		s.consistencyQueue = newConsistencyQueue(s, s.cfg.Gossip)
 		s.scanner.AddQueues(
-			s.gcQueue, s.splitQueue, s.replicateQueue, s.replicaGCQueue,
+			s.gcQueue, s.splitQueue, s.mergeQueue, s.replicateQueue, s.replicaGCQueue,
 			s.raftLogQueue, s.raftSnapshotQueue, s.consistencyQueue)
 
 		if s.cfg.TimeSeriesDataStore != nil {
@@ -961,6 +965,9 @@ func NewStore(cfg StoreConfig, eng engine.Engine, nodeDesc *roachpb.NodeDescript
 	if cfg.TestingKnobs.DisableSplitQueue {
 		s.setSplitQueueActive(false)
 	}
+	if cfg.TestingKnobs.DisableMergeQueue {
+		s.setMergeQueueActive(false)
+	}
 	if cfg.TestingKnobs.DisableTimeSeriesMaintenanceQueue {
 		s.setTimeSeriesMaintenanceQueueActive(false)
 	}
@@ -1849,6 +1856,13 @@ func splitPostApply(
 	// Update store stats with difference in stats before and after split.
 	r.store.metrics.addMVCCStats(deltaMS)
 
+	// The load of the LHS is now shared with the RHS, so its request counts no
+	// longer reflect it.
+	if r.stats != nil {
+		r.stats.resetRequestCounts()
+	}
+	r.loadSplitter.reset()
+
 	now := r.store.Clock().Now()
 
 	// While performing the split, zone config changes or a newly created table
@@ -1993,30 +2007,43 @@ func (s *Store) MergeRange(
 	return subsumingRng.setDesc(&copy)
 }
 
-// If the subsuming replica has the range lease, we update its timestamp cache
-// with the entries from the subsumed. Otherwise, then the timestamp cache
-// doesn't matter (in fact it should be empty, to save memory).
+// The timestamp cache is shared by the replicas of a store, so it already
+// holds the reads served by the subsumed range if both leases were held by this
+// store, which AdminMerge checks before merging. If the lease of the subsumed
+// range was moved to another store in the meantime, the reads it served are
+// not in this store's cache: if this store holds the subsuming range's lease,
+// conservatively forward the read timestamps of the subsumed span to a time
+// that no read served by the other store can exceed, given the maximum clock
+// offset.
 func (s *Store) maybeMergeTimestampCaches(
 	ctx context.Context, subsumingRep *Replica, subsumedRep *Replica,
 ) error {
+	now := s.Clock().Now()
+
 	subsumingRep.mu.Lock()
-	defer subsumingRep.mu.Unlock()
-	subsumingLease := subsumingRep.mu.state.Lease
+	subsumingLease := *subsumingRep.mu.state.Lease
+	subsumingRep.mu.Unlock()
 
 	subsumedRep.mu.Lock()
-	defer subsumedRep.mu.Unlock()
 	subsumedLease := *subsumedRep.mu.state.Lease
+	subsumedValid := subsumedRep.isLeaseValidRLocked(subsumedLease, now)
+	subsumedDesc := subsumedRep.mu.state.Desc
+	subsumedRep.mu.Unlock()
 
-	// Merge support is currently incomplete and incorrect. In particular, the
-	// lease holders must be colocated and the subsumed range appropriately
-	// quiesced. See also #2433.
-	now := s.Clock().Now()
-	if subsumedRep.isLeaseValidRLocked(subsumedLease, now) &&
-		subsumingLease.Replica.StoreID != subsumedLease.Replica.StoreID {
-		log.Fatalf(ctx, "cannot merge ranges with non-colocated leases. "+
-			"Subsuming lease: %s. Subsumed lease: %s.", subsumingLease, subsumedLease)
+	if !subsumedValid || subsumingLease.Replica.StoreID == subsumedLease.Replica.StoreID {
+		return nil
 	}
-
+	log.Warningf(ctx, "merging ranges with non-collocated leases. "+
+		"Subsuming lease: %s. Subsumed lease: %s.", subsumingLease, subsumedLease)
+	if !subsumingLease.OwnedBy(s.StoreID()) {
+		return nil
+	}
+	s.tsCacheMu.Lock()
+	defer s.tsCacheMu.Unlock()
+	s.tsCacheMu.cache.add(
+		subsumedDesc.StartKey.AsRawKey(), subsumedDesc.EndKey.AsRawKey(),
+		now.Add(s.Clock().MaxOffset().Nanoseconds(), 0), nil /* txnID */, true, /* readTSCache */
+	)
 	return nil
 }
 
@@ -2269,6 +2296,7 @@ func (s *Store) Capacity() (roachpb.StoreCapacity, error) {
 	if err == nil {
 		capacity.RangeCount = int32(s.ReplicaCount())
 		capacity.LeaseCount = int32(s.LeaseCount())
+		capacity.QueriesPerSecond = s.QueriesPerSecond()
 	}
 	return capacity, err
 }
@@ -2366,6 +2394,23 @@ func (s *Store) LeaseCount() int {
 	return leaseCount
 }
 
+// QueriesPerSecond returns the sum of the average number of requests per
+// second received by the replicas this store holds leases for.
+func (s *Store) QueriesPerSecond() float64 {
+	now := s.cfg.Clock.Now()
+
+	var qps float64
+	newStoreReplicaVisitor(s).Visit(func(r *Replica) bool {
+		if r.stats != nil && r.ownsValidLease(now) {
+			replicaQPS, _ := r.stats.avgQPS()
+			qps += replicaQPS
+		}
+		return true
+	})
+
+	return qps
+}
+
 // Send fetches a range based on the header's replica, assembles method, args &
 // reply into a Raft Cmd struct and executes the command using the fetched
 // range.
@@ -4042,6 +4087,9 @@ func (s *Store) setReplicateQueueActive(active bool) {
 func (s *Store) setSplitQueueActive(active bool) {
 	s.splitQueue.SetDisabled(!active)
 }
+func (s *Store) setMergeQueueActive(active bool) {
+	s.mergeQueue.SetDisabled(!active)
+}
 func (s *Store) setTimeSeriesMaintenanceQueueActive(active bool) {
 	s.tsMaintenanceQueue.SetDisabled(!active)
 }
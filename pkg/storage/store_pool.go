@@ -414,6 +414,10 @@ type StoreList struct {
 	// candidateLeases tracks range lease stats for stores that are eligible to
 	// be rebalance targets.
 	candidateLeases stat
+
+	// candidateQPS tracks queries-per-second stats for stores that are
+	// eligible to be rebalance targets.
+	candidateQPS stat
 }
 
 // Generates a new store list based on the passed in descriptors. It will
@@ -425,18 +429,19 @@ func makeStoreList(descriptors []roachpb.StoreDescriptor) StoreList {
 			sl.candidateCount.update(float64(desc.Capacity.RangeCount))
 		}
 		sl.candidateLeases.update(float64(desc.Capacity.LeaseCount))
+		sl.candidateQPS.update(desc.Capacity.QueriesPerSecond)
 	}
 	return sl
 }
 
 func (sl StoreList) String() string {
 	var buf bytes.Buffer
-	fmt.Fprintf(&buf, "  candidate: avg-ranges=%v avg-leases=%v\n",
-		sl.candidateCount.mean, sl.candidateLeases.mean)
+	fmt.Fprintf(&buf, "  candidate: avg-ranges=%v avg-leases=%v avg-qps=%.2f\n",
+		sl.candidateCount.mean, sl.candidateLeases.mean, sl.candidateQPS.mean)
 	for _, desc := range sl.stores {
-		fmt.Fprintf(&buf, "  %d: ranges=%d leases=%d fraction-used=%.2f\n",
-			desc.StoreID, desc.Capacity.RangeCount,
-			desc.Capacity.LeaseCount, desc.Capacity.FractionUsed())
+		fmt.Fprintf(&buf, "  %d: ranges=%d leases=%d qps=%.2f fraction-used=%.2f\n",
+			desc.StoreID, desc.Capacity.RangeCount, desc.Capacity.LeaseCount,
+			desc.Capacity.QueriesPerSecond, desc.Capacity.FractionUsed())
 	}
 	return buf.String()
 }
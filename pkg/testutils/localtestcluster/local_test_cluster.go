@@ -114,11 +114,12 @@ func (ltc *LocalTestCluster) Start(t testing.TB, baseCtx *base.Config, initSende
 	ltc.DB = client.NewDBWithContext(ltc.Sender, ltc.Clock, *ltc.DBContext)
 	transport := storage.NewDummyRaftTransport()
 	cfg := storage.TestStoreConfig(ltc.Clock)
-	// By default, disable the replica scanner and split queue, which
-	// confuse tests using LocalTestCluster.
+	// By default, disable the replica scanner and split and merge queues,
+	// which confuse tests using LocalTestCluster.
 	if ltc.StoreTestingKnobs == nil {
 		cfg.TestingKnobs.DisableScanner = true
 		cfg.TestingKnobs.DisableSplitQueue = true
+		cfg.TestingKnobs.DisableMergeQueue = true
 	} else {
 		cfg.TestingKnobs = *ltc.StoreTestingKnobs
 	}
@@ -32,6 +32,7 @@ import (
 
 	"github.com/cockroachdb/apd"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 const (
@@ -855,6 +856,9 @@ const (
 	Duration
 	True
 	False
+	UUID
+	JSON
+	Array
 
 	SentinelType Type = 15 // Used in the Value encoding.
 )
@@ -1175,6 +1179,8 @@ func EncodeIntValue(appendTo []byte, colID uint32, i int64) []byte {
 
 const floatValueEncodedLength = uint64AscendingEncodedLength
 
+const uuidValueEncodedLength = 16
+
 // EncodeFloatValue encodes a float value, appends it to the supplied buffer,
 // and returns the final buffer.
 func EncodeFloatValue(appendTo []byte, colID uint32, f float64) []byte {
@@ -1190,6 +1196,30 @@ func EncodeBytesValue(appendTo []byte, colID uint32, data []byte) []byte {
 	return append(appendTo, data...)
 }
 
+// EncodeUUIDValue encodes a uuid.UUID value, appends it to the supplied buffer,
+// and returns the final buffer.
+func EncodeUUIDValue(appendTo []byte, colID uint32, u uuid.UUID) []byte {
+	appendTo = encodeValueTag(appendTo, colID, UUID)
+	return append(appendTo, u.GetBytes()...)
+}
+
+// EncodeJSONValue encodes an already-encoded JSON document, appends it to the
+// supplied buffer, and returns the final buffer.
+func EncodeJSONValue(appendTo []byte, colID uint32, data []byte) []byte {
+	appendTo = encodeValueTag(appendTo, colID, JSON)
+	appendTo = EncodeNonsortingUvarint(appendTo, uint64(len(data)))
+	return append(appendTo, data...)
+}
+
+// EncodeArrayValue encodes an already-encoded array, appends it to the supplied
+// buffer, and returns the final buffer. The encoding of the elements is left
+// to the caller.
+func EncodeArrayValue(appendTo []byte, colID uint32, data []byte) []byte {
+	appendTo = encodeValueTag(appendTo, colID, Array)
+	appendTo = EncodeNonsortingUvarint(appendTo, uint64(len(data)))
+	return append(appendTo, data...)
+}
+
 // EncodeTimeValue encodes a time.Time value, appends it to the supplied buffer,
 // and returns the final buffer.
 func EncodeTimeValue(appendTo []byte, colID uint32, t time.Time) []byte {
@@ -1326,6 +1356,53 @@ func DecodeBytesValue(b []byte) (remaining []byte, data []byte, err error) {
 	return b[int(i):], b[:int(i)], nil
 }
 
+// DecodeUUIDValue decodes a value encoded by EncodeUUIDValue.
+func DecodeUUIDValue(b []byte) (remaining []byte, u uuid.UUID, err error) {
+	b, err = decodeValueTypeAssert(b, UUID)
+	if err != nil {
+		return b, u, err
+	}
+	if len(b) < uuidValueEncodedLength {
+		return b, u, errors.Errorf("uuid value should be exactly %d bytes: %d",
+			uuidValueEncodedLength, len(b))
+	}
+	u, err = uuid.FromBytes(b[:uuidValueEncodedLength])
+	if err != nil {
+		return b, u, err
+	}
+	return b[uuidValueEncodedLength:], u, nil
+}
+
+// DecodeJSONValue decodes a value encoded by EncodeJSONValue, returning the
+// encoded JSON document.
+func DecodeJSONValue(b []byte) (remaining []byte, data []byte, err error) {
+	b, err = decodeValueTypeAssert(b, JSON)
+	if err != nil {
+		return b, nil, err
+	}
+	var i uint64
+	b, _, i, err = DecodeNonsortingUvarint(b)
+	if err != nil {
+		return b, nil, err
+	}
+	return b[int(i):], b[:int(i)], nil
+}
+
+// DecodeArrayValue decodes a value encoded by EncodeArrayValue, returning the
+// encoded elements of the array.
+func DecodeArrayValue(b []byte) (remaining []byte, data []byte, err error) {
+	b, err = decodeValueTypeAssert(b, Array)
+	if err != nil {
+		return b, nil, err
+	}
+	var i uint64
+	b, _, i, err = DecodeNonsortingUvarint(b)
+	if err != nil {
+		return b, nil, err
+	}
+	return b[int(i):], b[:int(i)], nil
+}
+
 // DecodeTimeValue decodes a value encoded by EncodeTimeValue.
 func DecodeTimeValue(b []byte) (remaining []byte, t time.Time, err error) {
 	b, err = decodeValueTypeAssert(b, Time)
@@ -1424,7 +1501,9 @@ func PeekValueLength(b []byte) (typeOffset int, length int, err error) {
 		return typeOffset, dataOffset + n, err
 	case Float:
 		return typeOffset, dataOffset + floatValueEncodedLength, nil
-	case Bytes, Decimal:
+	case UUID:
+		return typeOffset, dataOffset + uuidValueEncodedLength, nil
+	case Bytes, Decimal, JSON, Array:
 		_, n, i, err := DecodeNonsortingUvarint(b)
 		return typeOffset, dataOffset + n + int(i), err
 	case Time:
@@ -1456,6 +1535,8 @@ func UpperBoundValueEncodingSize(colID uint32, typ Type, size int) (int, bool) {
 			return len(encodedTag) + maxVarintSize + size, true
 		}
 		return 0, false
+	case JSON, Array:
+		return 0, false
 	case Decimal:
 		if size > 0 {
 			return len(encodedTag) + maxVarintSize + upperBoundNonsortingDecimalUnscaledSize(size), true
@@ -1465,6 +1546,8 @@ func UpperBoundValueEncodingSize(colID uint32, typ Type, size int) (int, bool) {
 		return len(encodedTag) + 2*maxVarintSize, true
 	case Duration:
 		return len(encodedTag) + 3*maxVarintSize, true
+	case UUID:
+		return len(encodedTag) + uuidValueEncodedLength, true
 	default:
 		panic(fmt.Errorf("unknown type: %s", typ))
 	}
@@ -1534,6 +1617,27 @@ func PrettyPrintValueEncoded(b []byte) ([]byte, string, error) {
 			return b, "", err
 		}
 		return b, d.String(), nil
+	case UUID:
+		var u uuid.UUID
+		b, u, err = DecodeUUIDValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, u.String(), nil
+	case JSON:
+		var data []byte
+		b, data, err = DecodeJSONValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, string(data), nil
+	case Array:
+		var data []byte
+		b, data, err = DecodeArrayValue(b)
+		if err != nil {
+			return b, "", err
+		}
+		return b, hex.EncodeToString(data), nil
 	default:
 		return b, "", errors.Errorf("unknown type %s", typ)
 	}
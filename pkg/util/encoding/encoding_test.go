@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 )
 
 func testBasicEncodeDecode32(
@@ -1382,6 +1383,24 @@ func TestValueEncodeDecodeDuration(t *testing.T) {
 	}
 }
 
+func TestValueEncodeDecodeUUID(t *testing.T) {
+	rng, seed := randutil.NewPseudoRand()
+	tests := make([]uuid.UUID, 1000)
+	for i := range tests {
+		tests[i] = *uuid.NewPopulatedUUID(rng)
+	}
+	for _, test := range tests {
+		buf := EncodeUUIDValue(nil, NoColumnID, test)
+		_, x, err := DecodeUUIDValue(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != test {
+			t.Errorf("seed %d: expected %v got %v", seed, test, x)
+		}
+	}
+}
+
 func BenchmarkEncodeNonsortingVarint(b *testing.B) {
 	bytes := make([]byte, 0, b.N*NonsortingVarintMaxLen)
 	rng, _ := randutil.NewPseudoRand()
@@ -1562,6 +1581,15 @@ func randValueEncode(rd randData, buf []byte, colID uint32, typ Type) ([]byte, i
 	case Duration:
 		x := rd.duration()
 		return EncodeDurationValue(buf, colID, x), x, true
+	case UUID:
+		x := *uuid.NewPopulatedUUID(rd.Rand)
+		return EncodeUUIDValue(buf, colID, x), x, true
+	case JSON:
+		x := randutil.RandBytes(rd.Rand, 100)
+		return EncodeJSONValue(buf, colID, x), x, true
+	case Array:
+		x := randutil.RandBytes(rd.Rand, 100)
+		return EncodeArrayValue(buf, colID, x), x, true
 	default:
 		return buf, nil, false
 	}
@@ -1711,6 +1739,12 @@ func TestValueEncodingRand(t *testing.T) {
 			buf, decoded, err = DecodeTimeValue(buf)
 		case Duration:
 			buf, decoded, err = DecodeDurationValue(buf)
+		case UUID:
+			buf, decoded, err = DecodeUUIDValue(buf)
+		case JSON:
+			buf, decoded, err = DecodeJSONValue(buf)
+		case Array:
+			buf, decoded, err = DecodeArrayValue(buf)
 		default:
 			err = errors.Errorf("unknown type %s", typ)
 		}
@@ -1719,7 +1753,7 @@ func TestValueEncodingRand(t *testing.T) {
 		}
 
 		switch typ {
-		case Bytes:
+		case Bytes, JSON, Array:
 			if !bytes.Equal(decoded.([]byte), value.([]byte)) {
 				t.Fatalf("seed %d: %s got %x expected %x", seed, typ, decoded.([]byte), value.([]byte))
 			}
@@ -1756,6 +1790,9 @@ func TestUpperBoundValueEncodingSize(t *testing.T) {
 		{colID: 0, typ: Duration, size: 28},
 		{colID: 0, typ: Bytes, size: -1},
 		{colID: 0, typ: Bytes, width: 100, size: 110},
+		{colID: 0, typ: UUID, size: 17},
+		{colID: 0, typ: JSON, size: -1},
+		{colID: 0, typ: Array, size: -1},
 
 		{colID: 8, typ: True, size: 2},
 	}
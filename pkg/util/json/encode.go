@@ -0,0 +1,110 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package json
+
+import (
+	"bytes"
+
+	"github.com/cockroachdb/apd"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EncodeJSON appends the encoding of j to appendTo. The encoding is the
+// canonical textual representation of j, which is stable across versions and
+// can be decoded with DecodeJSON.
+func EncodeJSON(appendTo []byte, j JSON) []byte {
+	buf := bytes.NewBuffer(appendTo)
+	j.Format(buf)
+	return buf.Bytes()
+}
+
+// DecodeJSON decodes a value encoded with EncodeJSON.
+func DecodeJSON(b []byte) (JSON, error) {
+	return ParseJSON(string(b))
+}
+
+// The tags used to build inverted index keys. Every step of the path from the
+// root of a document to one of its leaves is prefixed by a tag, so that no
+// key is a prefix of a key belonging to a different path.
+const (
+	objectKeyTag byte = iota + 1
+	arrayElemTag
+	nullTag
+	falseTag
+	trueTag
+	numberTag
+	stringTag
+	emptyArrayTag
+	emptyObjectTag
+)
+
+// EncodeInvertedIndexKeys returns the keys under which j is stored in an
+// inverted index, each prefixed by b. There is one key per path from the root
+// of the document to one of its leaves, which allows containment queries to
+// be answered by looking up the keys of the contained document.
+func EncodeInvertedIndexKeys(b []byte, j JSON) [][]byte {
+	return j.encodeInvertedIndexKeys(b, nil)
+}
+
+func appendLeaf(b []byte, keys [][]byte, tag byte) [][]byte {
+	key := make([]byte, len(b), len(b)+1)
+	copy(key, b)
+	return append(keys, append(key, tag))
+}
+
+func (jsonNull) encodeInvertedIndexKeys(b []byte, keys [][]byte) [][]byte {
+	return appendLeaf(b, keys, nullTag)
+}
+
+func (jsonFalse) encodeInvertedIndexKeys(b []byte, keys [][]byte) [][]byte {
+	return appendLeaf(b, keys, falseTag)
+}
+
+func (jsonTrue) encodeInvertedIndexKeys(b []byte, keys [][]byte) [][]byte {
+	return appendLeaf(b, keys, trueTag)
+}
+
+func (j *jsonNumber) encodeInvertedIndexKeys(b []byte, keys [][]byte) [][]byte {
+	key := append(append([]byte(nil), b...), numberTag)
+	return append(keys, encoding.EncodeDecimalAscending(key, (*apd.Decimal)(j)))
+}
+
+func (j jsonString) encodeInvertedIndexKeys(b []byte, keys [][]byte) [][]byte {
+	key := append(append([]byte(nil), b...), stringTag)
+	return append(keys, encoding.EncodeStringAscending(key, string(j)))
+}
+
+func (j jsonArray) encodeInvertedIndexKeys(b []byte, keys [][]byte) [][]byte {
+	if len(j) == 0 {
+		return appendLeaf(b, keys, emptyArrayTag)
+	}
+	prefix := append(append([]byte(nil), b...), arrayElemTag)
+	for _, elem := range j {
+		keys = elem.encodeInvertedIndexKeys(prefix, keys)
+	}
+	return keys
+}
+
+func (j jsonObject) encodeInvertedIndexKeys(b []byte, keys [][]byte) [][]byte {
+	if len(j) == 0 {
+		return appendLeaf(b, keys, emptyObjectTag)
+	}
+	for _, kv := range j {
+		prefix := append(append([]byte(nil), b...), objectKeyTag)
+		prefix = encoding.EncodeStringAscending(prefix, string(kv.k))
+		keys = kv.v.encodeInvertedIndexKeys(prefix, keys)
+	}
+	return keys
+}
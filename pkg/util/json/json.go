@@ -0,0 +1,734 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package json implements the in-memory representation of the JSON
+// documents stored in JSONB columns, along with the operations the SQL
+// layer performs on them.
+package json
+
+import (
+	"bytes"
+	gojson "encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"unsafe"
+
+	"github.com/pkg/errors"
+
+	"github.com/cockroachdb/apd"
+)
+
+// Type represents a JSON type.
+type Type int
+
+// The JSON types. The order of these constants matches the ordering Postgres
+// uses when comparing JSONB values of different types, with the exception
+// that false and true compare as a single boolean type.
+const (
+	NullJSONType Type = iota
+	StringJSONType
+	NumberJSONType
+	FalseJSONType
+	TrueJSONType
+	ArrayJSONType
+	ObjectJSONType
+)
+
+// JSON represents a JSON value.
+type JSON interface {
+	fmt.Stringer
+
+	// Type returns the type of the JSON value.
+	Type() Type
+
+	// Compare compares the JSON value with other according to the ordering
+	// Postgres defines for JSONB values: Object > Array > Boolean > Number >
+	// String > Null. It returns -1, 0 or 1.
+	Compare(other JSON) int
+
+	// Format writes the canonical textual representation of the JSON value to
+	// buf.
+	Format(buf *bytes.Buffer)
+
+	// Size returns an estimate of the memory used by the JSON value.
+	Size() uintptr
+
+	// FetchValKey returns the value stored under key if the JSON value is an
+	// object containing key, and nil otherwise.
+	FetchValKey(key string) JSON
+
+	// FetchValIdx returns the idx'th element if the JSON value is an array
+	// with enough elements, and nil otherwise. Negative indexes count from the
+	// end of the array.
+	FetchValIdx(idx int) JSON
+
+	// AsText returns the textual representation of the JSON value as used by
+	// the ->> operator: strings are returned without quotes, and JSON null is
+	// returned as a nil string.
+	AsText() *string
+
+	// Exists returns true if key appears as a top-level object key, as a
+	// top-level array string element, or as the value of a string scalar.
+	Exists(key string) bool
+
+	// Contains returns true if other is contained within the JSON value,
+	// following the semantics of the Postgres @> operator.
+	Contains(other JSON) bool
+
+	// Len returns the number of elements of an array, or the number of keys
+	// of an object. It returns 0 for scalars.
+	Len() int
+
+	// encodeInvertedIndexKeys appends the inverted index keys of the JSON
+	// value, all prefixed by b, to keys.
+	encodeInvertedIndexKeys(b []byte, keys [][]byte) [][]byte
+}
+
+type jsonNull struct{}
+type jsonFalse struct{}
+type jsonTrue struct{}
+type jsonNumber apd.Decimal
+type jsonString string
+type jsonArray []JSON
+
+type jsonKeyValuePair struct {
+	k jsonString
+	v JSON
+}
+
+// jsonObject is an object whose pairs are sorted by key, with no duplicate
+// keys.
+type jsonObject []jsonKeyValuePair
+
+var _ JSON = jsonNull{}
+var _ JSON = jsonFalse{}
+var _ JSON = jsonTrue{}
+var _ JSON = &jsonNumber{}
+var _ JSON = jsonString("")
+var _ JSON = jsonArray(nil)
+var _ JSON = jsonObject(nil)
+
+// NullJSONValue is JSON `null`.
+var NullJSONValue = JSON(jsonNull{})
+
+// TrueJSONValue is JSON `true`.
+var TrueJSONValue = JSON(jsonTrue{})
+
+// FalseJSONValue is JSON `false`.
+var FalseJSONValue = JSON(jsonFalse{})
+
+// FromString returns a JSON string.
+func FromString(s string) JSON {
+	return jsonString(s)
+}
+
+// FromBool returns a JSON boolean.
+func FromBool(b bool) JSON {
+	if b {
+		return TrueJSONValue
+	}
+	return FalseJSONValue
+}
+
+// FromInt returns a JSON number.
+func FromInt(i int64) JSON {
+	var d apd.Decimal
+	d.SetInt64(i)
+	return (*jsonNumber)(&d)
+}
+
+// FromDecimal returns a JSON number.
+func FromDecimal(d apd.Decimal) JSON {
+	return (*jsonNumber)(&d)
+}
+
+// FromArray returns a JSON array holding the given elements.
+func FromArray(elems []JSON) JSON {
+	return jsonArray(elems)
+}
+
+// FromMap returns a JSON object holding the given key/value pairs.
+func FromMap(m map[string]JSON) JSON {
+	obj := make(jsonObject, 0, len(m))
+	for k, v := range m {
+		obj = append(obj, jsonKeyValuePair{k: jsonString(k), v: v})
+	}
+	sort.Slice(obj, func(i, j int) bool { return obj[i].k < obj[j].k })
+	return obj
+}
+
+// ParseJSON parses the textual representation of a JSON document.
+func ParseJSON(s string) (JSON, error) {
+	decoder := gojson.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	// Make sure the input does not contain anything after the first document.
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.Errorf("trailing characters after JSON document")
+	}
+	return MakeJSON(v)
+}
+
+// MakeJSON converts the result of decoding a JSON document with the
+// encoding/json package into a JSON value.
+func MakeJSON(d interface{}) (JSON, error) {
+	switch v := d.(type) {
+	case nil:
+		return NullJSONValue, nil
+	case bool:
+		return FromBool(v), nil
+	case string:
+		return FromString(v), nil
+	case gojson.Number:
+		dec, _, err := apd.NewFromString(string(v))
+		if err != nil {
+			return nil, err
+		}
+		return (*jsonNumber)(dec), nil
+	case float64:
+		var dec apd.Decimal
+		if _, err := dec.SetFloat64(v); err != nil {
+			return nil, err
+		}
+		return (*jsonNumber)(&dec), nil
+	case int:
+		return FromInt(int64(v)), nil
+	case int64:
+		return FromInt(v), nil
+	case []interface{}:
+		arr := make(jsonArray, len(v))
+		for i, elem := range v {
+			j, err := MakeJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = j
+		}
+		return arr, nil
+	case map[string]interface{}:
+		m := make(map[string]JSON, len(v))
+		for k, elem := range v {
+			j, err := MakeJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = j
+		}
+		return FromMap(m), nil
+	}
+	return nil, errors.Errorf("unexpected value type %T", d)
+}
+
+// Type implements the JSON interface.
+func (jsonNull) Type() Type { return NullJSONType }
+
+// Type implements the JSON interface.
+func (jsonFalse) Type() Type { return FalseJSONType }
+
+// Type implements the JSON interface.
+func (jsonTrue) Type() Type { return TrueJSONType }
+
+// Type implements the JSON interface.
+func (*jsonNumber) Type() Type { return NumberJSONType }
+
+// Type implements the JSON interface.
+func (jsonString) Type() Type { return StringJSONType }
+
+// Type implements the JSON interface.
+func (jsonArray) Type() Type { return ArrayJSONType }
+
+// Type implements the JSON interface.
+func (jsonObject) Type() Type { return ObjectJSONType }
+
+// typeRank returns the rank of t in the ordering of JSON types. false and
+// true share a rank since they are compared as booleans.
+func typeRank(t Type) int {
+	if t == TrueJSONType {
+		return int(FalseJSONType)
+	}
+	return int(t)
+}
+
+func cmpInt(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// compareTypes compares the types of a and b. If they are of the same type,
+// ok is false and the caller must compare the values themselves.
+func compareTypes(a, b JSON) (c int, ok bool) {
+	ta, tb := a.Type(), b.Type()
+	if ra, rb := typeRank(ta), typeRank(tb); ra != rb {
+		return cmpInt(ra, rb), true
+	}
+	if ta != tb {
+		// One of the values is false and the other is true.
+		if ta == FalseJSONType {
+			return -1, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+// Compare implements the JSON interface.
+func (j jsonNull) Compare(other JSON) int {
+	c, _ := compareTypes(j, other)
+	return c
+}
+
+// Compare implements the JSON interface.
+func (j jsonFalse) Compare(other JSON) int {
+	c, _ := compareTypes(j, other)
+	return c
+}
+
+// Compare implements the JSON interface.
+func (j jsonTrue) Compare(other JSON) int {
+	c, _ := compareTypes(j, other)
+	return c
+}
+
+// Compare implements the JSON interface.
+func (j *jsonNumber) Compare(other JSON) int {
+	if c, ok := compareTypes(j, other); ok {
+		return c
+	}
+	return (*apd.Decimal)(j).Cmp((*apd.Decimal)(other.(*jsonNumber)))
+}
+
+// Compare implements the JSON interface.
+func (j jsonString) Compare(other JSON) int {
+	if c, ok := compareTypes(j, other); ok {
+		return c
+	}
+	o := other.(jsonString)
+	if j < o {
+		return -1
+	}
+	if j > o {
+		return 1
+	}
+	return 0
+}
+
+// Compare implements the JSON interface.
+func (j jsonArray) Compare(other JSON) int {
+	if c, ok := compareTypes(j, other); ok {
+		return c
+	}
+	o := other.(jsonArray)
+	if c := cmpInt(len(j), len(o)); c != 0 {
+		return c
+	}
+	for i := range j {
+		if c := j[i].Compare(o[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Compare implements the JSON interface.
+func (j jsonObject) Compare(other JSON) int {
+	if c, ok := compareTypes(j, other); ok {
+		return c
+	}
+	o := other.(jsonObject)
+	if c := cmpInt(len(j), len(o)); c != 0 {
+		return c
+	}
+	for i := range j {
+		if c := j[i].k.Compare(o[i].k); c != 0 {
+			return c
+		}
+		if c := j[i].v.Compare(o[i].v); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Format implements the JSON interface.
+func (jsonNull) Format(buf *bytes.Buffer) { buf.WriteString("null") }
+
+// Format implements the JSON interface.
+func (jsonFalse) Format(buf *bytes.Buffer) { buf.WriteString("false") }
+
+// Format implements the JSON interface.
+func (jsonTrue) Format(buf *bytes.Buffer) { buf.WriteString("true") }
+
+// Format implements the JSON interface.
+func (j *jsonNumber) Format(buf *bytes.Buffer) {
+	buf.WriteString((*apd.Decimal)(j).ToStandard())
+}
+
+// Format implements the JSON interface.
+func (j jsonString) Format(buf *bytes.Buffer) {
+	encodeJSONString(buf, string(j))
+}
+
+// encodeJSONString writes s to buf as a quoted JSON string.
+func encodeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// Format implements the JSON interface.
+func (j jsonArray) Format(buf *bytes.Buffer) {
+	buf.WriteByte('[')
+	for i, elem := range j {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		elem.Format(buf)
+	}
+	buf.WriteByte(']')
+}
+
+// Format implements the JSON interface.
+func (j jsonObject) Format(buf *bytes.Buffer) {
+	buf.WriteByte('{')
+	for i, kv := range j {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		kv.k.Format(buf)
+		buf.WriteString(": ")
+		kv.v.Format(buf)
+	}
+	buf.WriteByte('}')
+}
+
+func formatToString(j JSON) string {
+	var buf bytes.Buffer
+	j.Format(&buf)
+	return buf.String()
+}
+
+func (j jsonNull) String() string    { return formatToString(j) }
+func (j jsonFalse) String() string   { return formatToString(j) }
+func (j jsonTrue) String() string    { return formatToString(j) }
+func (j *jsonNumber) String() string { return formatToString(j) }
+func (j jsonString) String() string  { return formatToString(j) }
+func (j jsonArray) String() string   { return formatToString(j) }
+func (j jsonObject) String() string  { return formatToString(j) }
+
+// Size implements the JSON interface.
+func (jsonNull) Size() uintptr { return 0 }
+
+// Size implements the JSON interface.
+func (jsonFalse) Size() uintptr { return 0 }
+
+// Size implements the JSON interface.
+func (jsonTrue) Size() uintptr { return 0 }
+
+// Size implements the JSON interface.
+func (j *jsonNumber) Size() uintptr {
+	intVal := j.Coeff
+	return unsafe.Sizeof(*j) + uintptr(cap(intVal.Bits()))*unsafe.Sizeof(big.Word(0))
+}
+
+// Size implements the JSON interface.
+func (j jsonString) Size() uintptr {
+	return unsafe.Sizeof(j) + uintptr(len(j))
+}
+
+// Size implements the JSON interface.
+func (j jsonArray) Size() uintptr {
+	valSize := uintptr(cap(j)) * unsafe.Sizeof(JSON(nil))
+	for _, elem := range j {
+		valSize += elem.Size()
+	}
+	return unsafe.Sizeof(j) + valSize
+}
+
+// Size implements the JSON interface.
+func (j jsonObject) Size() uintptr {
+	valSize := uintptr(cap(j)) * unsafe.Sizeof(jsonKeyValuePair{})
+	for _, kv := range j {
+		valSize += uintptr(len(kv.k)) + kv.v.Size()
+	}
+	return unsafe.Sizeof(j) + valSize
+}
+
+// FetchValKey implements the JSON interface.
+func (jsonNull) FetchValKey(string) JSON { return nil }
+
+// FetchValKey implements the JSON interface.
+func (jsonFalse) FetchValKey(string) JSON { return nil }
+
+// FetchValKey implements the JSON interface.
+func (jsonTrue) FetchValKey(string) JSON { return nil }
+
+// FetchValKey implements the JSON interface.
+func (*jsonNumber) FetchValKey(string) JSON { return nil }
+
+// FetchValKey implements the JSON interface.
+func (jsonString) FetchValKey(string) JSON { return nil }
+
+// FetchValKey implements the JSON interface.
+func (jsonArray) FetchValKey(string) JSON { return nil }
+
+// FetchValKey implements the JSON interface.
+func (j jsonObject) FetchValKey(key string) JSON {
+	i := sort.Search(len(j), func(i int) bool { return string(j[i].k) >= key })
+	if i < len(j) && string(j[i].k) == key {
+		return j[i].v
+	}
+	return nil
+}
+
+// FetchValIdx implements the JSON interface.
+func (jsonNull) FetchValIdx(int) JSON { return nil }
+
+// FetchValIdx implements the JSON interface.
+func (jsonFalse) FetchValIdx(int) JSON { return nil }
+
+// FetchValIdx implements the JSON interface.
+func (jsonTrue) FetchValIdx(int) JSON { return nil }
+
+// FetchValIdx implements the JSON interface.
+func (*jsonNumber) FetchValIdx(int) JSON { return nil }
+
+// FetchValIdx implements the JSON interface.
+func (jsonString) FetchValIdx(int) JSON { return nil }
+
+// FetchValIdx implements the JSON interface.
+func (j jsonArray) FetchValIdx(idx int) JSON {
+	if idx < 0 {
+		idx += len(j)
+	}
+	if idx < 0 || idx >= len(j) {
+		return nil
+	}
+	return j[idx]
+}
+
+// FetchValIdx implements the JSON interface.
+func (jsonObject) FetchValIdx(int) JSON { return nil }
+
+// AsText implements the JSON interface.
+func (jsonNull) AsText() *string { return nil }
+
+// AsText implements the JSON interface.
+func (j jsonFalse) AsText() *string { return asText(j) }
+
+// AsText implements the JSON interface.
+func (j jsonTrue) AsText() *string { return asText(j) }
+
+// AsText implements the JSON interface.
+func (j *jsonNumber) AsText() *string { return asText(j) }
+
+// AsText implements the JSON interface.
+func (j jsonString) AsText() *string {
+	s := string(j)
+	return &s
+}
+
+// AsText implements the JSON interface.
+func (j jsonArray) AsText() *string { return asText(j) }
+
+// AsText implements the JSON interface.
+func (j jsonObject) AsText() *string { return asText(j) }
+
+func asText(j JSON) *string {
+	s := j.String()
+	return &s
+}
+
+// Exists implements the JSON interface.
+func (jsonNull) Exists(string) bool { return false }
+
+// Exists implements the JSON interface.
+func (jsonFalse) Exists(string) bool { return false }
+
+// Exists implements the JSON interface.
+func (jsonTrue) Exists(string) bool { return false }
+
+// Exists implements the JSON interface.
+func (*jsonNumber) Exists(string) bool { return false }
+
+// Exists implements the JSON interface.
+func (j jsonString) Exists(key string) bool { return string(j) == key }
+
+// Exists implements the JSON interface.
+func (j jsonArray) Exists(key string) bool {
+	for _, elem := range j {
+		if s, ok := elem.(jsonString); ok && string(s) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Exists implements the JSON interface.
+func (j jsonObject) Exists(key string) bool {
+	return j.FetchValKey(key) != nil
+}
+
+// Contains implements the JSON interface.
+func (j jsonNull) Contains(other JSON) bool { return j.Compare(other) == 0 }
+
+// Contains implements the JSON interface.
+func (j jsonFalse) Contains(other JSON) bool { return j.Compare(other) == 0 }
+
+// Contains implements the JSON interface.
+func (j jsonTrue) Contains(other JSON) bool { return j.Compare(other) == 0 }
+
+// Contains implements the JSON interface.
+func (j *jsonNumber) Contains(other JSON) bool { return j.Compare(other) == 0 }
+
+// Contains implements the JSON interface.
+func (j jsonString) Contains(other JSON) bool { return j.Compare(other) == 0 }
+
+// Contains implements the JSON interface.
+func (j jsonArray) Contains(other JSON) bool {
+	switch o := other.(type) {
+	case jsonArray:
+		for _, oElem := range o {
+			if !j.containsElem(oElem) {
+				return false
+			}
+		}
+		return true
+	case jsonObject:
+		return false
+	default:
+		// As a special exception, a top-level array contains a scalar if the
+		// scalar is one of its elements.
+		for _, elem := range j {
+			if elem.Compare(o) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// containsElem returns whether some element of the array contains other,
+// where other is itself the element of an array.
+func (j jsonArray) containsElem(other JSON) bool {
+	for _, elem := range j {
+		if _, isArray := other.(jsonArray); !isArray {
+			if _, elemIsArray := elem.(jsonArray); elemIsArray {
+				// The scalar exception only applies at the top level.
+				continue
+			}
+		}
+		if elem.Contains(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains implements the JSON interface.
+func (j jsonObject) Contains(other JSON) bool {
+	o, ok := other.(jsonObject)
+	if !ok {
+		return false
+	}
+	for _, kv := range o {
+		v := j.FetchValKey(string(kv.k))
+		if v == nil {
+			return false
+		}
+		if _, isArray := v.(jsonArray); isArray {
+			if _, otherIsArray := kv.v.(jsonArray); !otherIsArray {
+				return false
+			}
+		}
+		if !v.Contains(kv.v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len implements the JSON interface.
+func (jsonNull) Len() int { return 0 }
+
+// Len implements the JSON interface.
+func (jsonFalse) Len() int { return 0 }
+
+// Len implements the JSON interface.
+func (jsonTrue) Len() int { return 0 }
+
+// Len implements the JSON interface.
+func (*jsonNumber) Len() int { return 0 }
+
+// Len implements the JSON interface.
+func (jsonString) Len() int { return 0 }
+
+// Len implements the JSON interface.
+func (j jsonArray) Len() int { return len(j) }
+
+// Len implements the JSON interface.
+func (j jsonObject) Len() int { return len(j) }
+
+// TypeName returns the name of the type of j, as returned by the Postgres
+// jsonb_typeof function.
+func TypeName(j JSON) string {
+	switch j.Type() {
+	case NullJSONType:
+		return "null"
+	case StringJSONType:
+		return "string"
+	case NumberJSONType:
+		return "number"
+	case FalseJSONType, TrueJSONType:
+		return "boolean"
+	case ArrayJSONType:
+		return "array"
+	case ObjectJSONType:
+		return "object"
+	}
+	panic("unknown JSON type " + strconv.Itoa(int(j.Type())))
+}
@@ -0,0 +1,204 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) JSON {
+	j, err := ParseJSON(s)
+	if err != nil {
+		t.Fatalf("%s: %v", s, err)
+	}
+	return j
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{`1`, `1`},
+		{`-1.50`, `-1.50`},
+		{`1e2`, `100`},
+		{`"a\"b\u0001"`, `"a\"b\u0001"`},
+		{`null`, `null`},
+		{`true`, `true`},
+		{` [1, "a" ,false] `, `[1, "a", false]`},
+		{`{"b": 1, "a": [], "c": {}}`, `{"a": [], "b": 1, "c": {}}`},
+		{`{"a": 1, "a": 2}`, `{"a": 2}`},
+	}
+	for _, tc := range testCases {
+		j := mustParse(t, tc.input)
+		if s := j.String(); s != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.input, tc.expected, s)
+		}
+		decoded, err := DecodeJSON(EncodeJSON(nil, j))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Compare(j) != 0 {
+			t.Errorf("%s: encoding did not round trip: %s", tc.input, decoded)
+		}
+	}
+
+	for _, s := range []string{``, `{`, `[1,]`, `1 2`, `{"a"}`, `nul`} {
+		if _, err := ParseJSON(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestJSONCompare(t *testing.T) {
+	// Each value is smaller than the ones following it.
+	ordered := []string{
+		`null`,
+		`""`,
+		`"a"`,
+		`-1`,
+		`1`,
+		`false`,
+		`true`,
+		`[]`,
+		`[2]`,
+		`[1, 2]`,
+		`{}`,
+		`{"a": 2}`,
+		`{"b": 1}`,
+		`{"a": 1, "b": 1}`,
+	}
+	for i := range ordered {
+		for j := range ordered {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			a, b := mustParse(t, ordered[i]), mustParse(t, ordered[j])
+			if c := a.Compare(b); c != expected {
+				t.Errorf("%s vs %s: expected %d, got %d", a, b, expected, c)
+			}
+		}
+	}
+}
+
+func TestJSONOperators(t *testing.T) {
+	j := mustParse(t, `{"a": [1, "b", {"c": null}], "d": "e"}`)
+
+	if v := j.FetchValKey("d"); v == nil || v.String() != `"e"` {
+		t.Errorf("unexpected value for key d: %v", v)
+	}
+	if v := j.FetchValKey("z"); v != nil {
+		t.Errorf("unexpected value for key z: %v", v)
+	}
+	arr := j.FetchValKey("a")
+	if v := arr.FetchValIdx(-1); v == nil || v.String() != `{"c": null}` {
+		t.Errorf("unexpected value for index -1: %v", v)
+	}
+	if v := arr.FetchValIdx(3); v != nil {
+		t.Errorf("unexpected value for index 3: %v", v)
+	}
+	if s := j.FetchValKey("d").AsText(); s == nil || *s != "e" {
+		t.Errorf("unexpected text for key d: %v", s)
+	}
+	if s := arr.FetchValIdx(2).FetchValKey("c").AsText(); s != nil {
+		t.Errorf("expected nil text for JSON null, got %s", *s)
+	}
+
+	existsCases := []struct {
+		json   string
+		key    string
+		exists bool
+	}{
+		{`{"a": 1}`, "a", true},
+		{`{"a": 1}`, "b", false},
+		{`["a", 1]`, "a", true},
+		{`["b", 1]`, "1", false},
+		{`"a"`, "a", true},
+		{`1`, "1", false},
+	}
+	for _, tc := range existsCases {
+		if e := mustParse(t, tc.json).Exists(tc.key); e != tc.exists {
+			t.Errorf("%s ? %s: expected %t", tc.json, tc.key, tc.exists)
+		}
+	}
+
+	containsCases := []struct {
+		a, b     string
+		contains bool
+	}{
+		{`1`, `1`, true},
+		{`1`, `2`, false},
+		{`[1, 2, [3]]`, `[2, 1]`, true},
+		{`[1, 2, [3]]`, `[[3]]`, true},
+		{`[1, 2, [3]]`, `[3]`, false},
+		{`[1, 2]`, `1`, true},
+		{`[1, 2]`, `[]`, true},
+		{`{"a": {"b": [1, 2]}, "c": 3}`, `{"a": {"b": [2]}}`, true},
+		{`{"a": {"b": [1, 2]}, "c": 3}`, `{"a": {"b": 2}}`, false},
+		{`{"a": 1}`, `{}`, true},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{`{"a": 1}`, `["a"]`, false},
+	}
+	for _, tc := range containsCases {
+		if c := mustParse(t, tc.a).Contains(mustParse(t, tc.b)); c != tc.contains {
+			t.Errorf("%s @> %s: expected %t", tc.a, tc.b, tc.contains)
+		}
+	}
+}
+
+func TestEncodeInvertedIndexKeys(t *testing.T) {
+	prefix := []byte("prefix")
+	keysOf := func(s string) [][]byte {
+		keys := EncodeInvertedIndexKeys(prefix, mustParse(t, s))
+		for _, k := range keys {
+			if !bytes.HasPrefix(k, prefix) {
+				t.Fatalf("%s: key %q is missing the prefix", s, k)
+			}
+		}
+		return keys
+	}
+	contains := func(keys [][]byte, key []byte) bool {
+		for _, k := range keys {
+			if bytes.Equal(k, key) {
+				return true
+			}
+		}
+		return false
+	}
+
+	doc := keysOf(`{"a": [1, {"b": "c"}], "d": null, "e": {}}`)
+	if len(doc) != 4 {
+		t.Fatalf("expected 4 keys, got %d", len(doc))
+	}
+	// Every key of a contained document must be a key of the document.
+	for _, sub := range []string{`{"a": [1]}`, `{"a": [{"b": "c"}]}`, `{"d": null}`, `{"e": {}}`} {
+		for _, k := range keysOf(sub) {
+			if !contains(doc, k) {
+				t.Errorf("%s: key %q not found in document keys", sub, k)
+			}
+		}
+	}
+	for _, notSub := range []string{`{"a": 1}`, `{"a": [{"b": "d"}]}`, `{"d": false}`, `{"e": []}`} {
+		for _, k := range keysOf(notSub) {
+			if contains(doc, k) {
+				t.Errorf("%s: unexpected key %q found in document keys", notSub, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2017 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Random generates a random JSON value. The complexity bounds the number of
+// values the document is made of.
+func Random(complexity int, rng *rand.Rand) JSON {
+	return randomJSON(&complexity, rng)
+}
+
+func randomJSON(complexity *int, rng *rand.Rand) JSON {
+	*complexity--
+	if *complexity <= 0 {
+		return randomScalar(rng)
+	}
+	switch rng.Intn(3) {
+	case 0:
+		n := rng.Intn(*complexity + 1)
+		arr := make([]JSON, 0, n)
+		for i := 0; i < n && *complexity > 0; i++ {
+			arr = append(arr, randomJSON(complexity, rng))
+		}
+		return FromArray(arr)
+	case 1:
+		n := rng.Intn(*complexity + 1)
+		m := make(map[string]JSON, n)
+		for i := 0; i < n && *complexity > 0; i++ {
+			m[randomString(rng)] = randomJSON(complexity, rng)
+		}
+		return FromMap(m)
+	default:
+		return randomScalar(rng)
+	}
+}
+
+func randomScalar(rng *rand.Rand) JSON {
+	switch rng.Intn(5) {
+	case 0:
+		return NullJSONValue
+	case 1:
+		return FromBool(rng.Intn(2) == 0)
+	case 2:
+		return FromInt(rng.Int63n(2000) - 1000)
+	default:
+		return FromString(randomString(rng))
+	}
+}
+
+func randomString(rng *rand.Rand) string {
+	return fmt.Sprintf("%x", rng.Intn(1<<16))
+}